@@ -0,0 +1,226 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cresta/gogithub/reqmeta"
+)
+
+const (
+	// DefaultActionPinBranch is the branch ActionPinner pushes its changes to
+	DefaultActionPinBranch = "pin-actions"
+	// DefaultActionPinTitle is the title of the pull requests ActionPinner opens
+	DefaultActionPinTitle = "Pin GitHub Actions to commit SHAs"
+)
+
+// ActionPin is one uses rewritten from a tag to the commit SHA the tag points at
+type ActionPin struct {
+	Path string
+	ActionReference
+	SHA string
+}
+
+// ActionPinPlan is what ActionPinner would change in a repository
+type ActionPinPlan struct {
+	Repo  RepoRef
+	Pins  []ActionPin
+	Files []FileChange
+}
+
+// ActionPinner pins third party actions referenced by tag or branch to the commit SHA they currently point at, keeping
+// the tag in a comment, and opens a pull request with the change.  A tag can be moved by whoever controls the action,
+// a commit SHA cannot.
+type ActionPinner struct {
+	gh GitHub
+	// TrustedOwners are action owners, besides actions and github, that are left unpinned
+	TrustedOwners []string
+	// Branch is the branch the change is pushed to.  Defaults to DefaultActionPinBranch.
+	Branch string
+	// Title is the pull request title and commit message.  Defaults to DefaultActionPinTitle.
+	Title string
+	// FanOutOptions control how many repositories PinAll updates at once
+	FanOutOptions []FanOutOption
+
+	mu sync.Mutex
+	// resolved caches tag resolutions across repositories, keyed by owner/repo@ref
+	resolved map[string]string
+}
+
+func NewActionPinner(gh GitHub) *ActionPinner {
+	return &ActionPinner{
+		gh:       gh,
+		Branch:   DefaultActionPinBranch,
+		Title:    DefaultActionPinTitle,
+		resolved: make(map[string]string),
+	}
+}
+
+func (p *ActionPinner) resolve(ctx context.Context, a ActionReference) (string, error) {
+	repo := a.Repo()
+	key := repo.String() + "@" + a.Ref
+	p.mu.Lock()
+	sha, ok := p.resolved[key]
+	p.mu.Unlock()
+	if ok {
+		return sha, nil
+	}
+	sha, err := p.gh.ResolveCommitSHA(ctx, repo.Owner, repo.Name, a.Ref)
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	p.resolved[key] = sha
+	p.mu.Unlock()
+	return sha, nil
+}
+
+// Plan finds the unpinned third party actions of every workflow on the default branch of repo and returns the
+// rewritten workflow files
+func (p *ActionPinner) Plan(ctx context.Context, repo RepoRef) (*ActionPinPlan, error) {
+	files, err := p.gh.ListWorkflowFiles(ctx, repo.Owner, repo.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	ret := &ActionPinPlan{Repo: repo}
+	for _, f := range files {
+		summary, err := SummarizeWorkflow(repo, f.Path, f.Content)
+		if err != nil {
+			return nil, err
+		}
+		var pins []ActionPin
+		for _, a := range summary.UnpinnedActions(p.TrustedOwners...) {
+			if a.Ref == "" {
+				continue
+			}
+			sha, err := p.resolve(ctx, a)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %s in %s: %w", a.Uses, f.Path, err)
+			}
+			pins = append(pins, ActionPin{Path: f.Path, ActionReference: a, SHA: sha})
+		}
+		if len(pins) == 0 {
+			continue
+		}
+		content, applied := PinActions(f.Content, pins)
+		if len(applied) == 0 {
+			continue
+		}
+		ret.Pins = append(ret.Pins, applied...)
+		ret.Files = append(ret.Files, FileChange{Path: f.Path, Content: content})
+	}
+	return ret, nil
+}
+
+// Pin opens a pull request pinning the actions of repo and returns its number.  It returns 0 when there is nothing to
+// pin or ctx is a dry run, and the existing pull request if one is already open for the branch.
+func (p *ActionPinner) Pin(ctx context.Context, repo RepoRef) (int64, error) {
+	plan, err := p.Plan(ctx, repo)
+	if err != nil {
+		return 0, err
+	}
+	if len(plan.Files) == 0 || reqmeta.IsDryRun(ctx) {
+		return 0, nil
+	}
+	existing, err := p.gh.FindPRForBranch(ctx, repo.Owner, repo.Name, p.Branch)
+	if err != nil {
+		return 0, err
+	}
+	if existing != 0 {
+		return existing, nil
+	}
+	r, err := p.gh.GetRepository(ctx, repo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get repository: %w", err)
+	}
+	base, err := p.gh.ResolveCommitSHA(ctx, repo.Owner, repo.Name, r.DefaultBranch)
+	if err != nil {
+		return 0, err
+	}
+	// A branch left behind by an earlier run, whose pull request was closed or never opened, starts over from base
+	_, err = p.gh.GetRef(ctx, repo.Owner, repo.Name, "heads/"+p.Branch)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		if err := p.gh.ResetBranch(ctx, repo.Owner, repo.Name, p.Branch, base); err != nil {
+			return 0, err
+		}
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		if err := p.gh.CreateBranch(ctx, repo.Owner, repo.Name, p.Branch, base); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, err
+	}
+	if _, err := p.gh.CommitFiles(ctx, repo.Owner, repo.Name, p.Branch, p.Title, plan.Files); err != nil {
+		return 0, err
+	}
+	return p.gh.CreatePullRequest(ctx, r.ID, r.DefaultBranch, p.Branch, p.Title, actionPinBody(plan.Pins))
+}
+
+// PinAll runs Pin on every repository and returns the pull request opened for each repository that needed one
+func (p *ActionPinner) PinAll(ctx context.Context, repos []RepoRef) (map[RepoRef]int64, error) {
+	var mu sync.Mutex
+	ret := make(map[RepoRef]int64)
+	err := FanOut(ctx, repos, func(ctx context.Context, repo RepoRef) error {
+		number, err := p.Pin(ctx, repo)
+		if err != nil {
+			return err
+		}
+		if number != 0 {
+			mu.Lock()
+			ret[repo] = number
+			mu.Unlock()
+		}
+		return nil
+	}, p.FanOutOptions...)
+	return ret, err
+}
+
+func actionPinBody(pins []ActionPin) string {
+	var sb strings.Builder
+	sb.WriteString("Pins third party actions to the commit their tag points at today, so the tag cannot be moved to ")
+	sb.WriteString("different code under our workflows.\n\n| File | Action | Ref | Commit |\n| --- | --- | --- | --- |\n")
+	for _, p := range pins {
+		fmt.Fprintf(&sb, "| %s:%d | %s | %s | %s |\n", p.Path, p.Line, p.Action, p.Ref, p.SHA)
+	}
+	return sb.String()
+}
+
+// PinActions rewrites the uses of each pin in a workflow file to action@sha followed by a "# ref" comment, replacing
+// any comment already on the line.  It returns the new content and the pins that were applied; a pin whose location
+// does not hold its uses value is skipped.
+func PinActions(content []byte, pins []ActionPin) ([]byte, []ActionPin) {
+	lines := strings.Split(string(content), "\n")
+	var applied []ActionPin
+	for _, pin := range pins {
+		if pin.Line < 1 || pin.Line > len(lines) {
+			continue
+		}
+		line := []rune(lines[pin.Line-1])
+		start := pin.Column - 1
+		if start < 0 || start >= len(line) {
+			continue
+		}
+		quote := ""
+		if line[start] == '"' || line[start] == '\'' {
+			quote = string(line[start])
+		}
+		value := quote + pin.Uses + quote
+		end := start + len([]rune(value))
+		if end > len(line) || string(line[start:end]) != value {
+			continue
+		}
+		rest := string(line[end:])
+		if i := strings.Index(rest, "#"); i >= 0 && strings.TrimSpace(rest[:i]) == "" {
+			rest = ""
+		}
+		lines[pin.Line-1] = string(line[:start]) + quote + pin.Action + "@" + pin.SHA + quote + strings.TrimRight(rest, " ") + " # " + pin.Ref
+		applied = append(applied, pin)
+	}
+	return []byte(strings.Join(lines, "\n")), applied
+}
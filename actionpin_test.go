@@ -0,0 +1,136 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	checkoutSHA = "b4ffde65f46336ab88eb53be808477a3936bae11"
+	buildSHA    = "4a13e500e55cf31b7a5d59a38ab2040ab0f42f56"
+)
+
+func TestPinActions(t *testing.T) {
+	content := []byte("steps:\n  - uses: docker/build-push-action@v5 # old\n  - uses: \"docker/build-push-action@v5\"\n")
+	pins := []ActionPin{
+		{ActionReference: ActionReference{Uses: "docker/build-push-action@v5", Action: "docker/build-push-action", Ref: "v5", Line: 2, Column: 11}, SHA: buildSHA},
+		{ActionReference: ActionReference{Uses: "docker/build-push-action@v5", Action: "docker/build-push-action", Ref: "v5", Line: 3, Column: 11}, SHA: buildSHA},
+		{ActionReference: ActionReference{Uses: "moved@v1", Line: 1, Column: 1}, SHA: buildSHA},
+	}
+	out, applied := PinActions(content, pins)
+	require.Len(t, applied, 2)
+	require.Equal(t, "steps:\n  - uses: docker/build-push-action@"+buildSHA+" # v5\n  - uses: \"docker/build-push-action@"+buildSHA+"\" # v5\n", string(out))
+}
+
+type pinGitHub struct {
+	GitHub
+	files    []WorkflowFile
+	resolved int
+	// existing are the branches that already exist
+	existing map[string]bool
+	branches []string
+	resets   []string
+	commits  [][]FileChange
+	prs      []string
+}
+
+func (f *pinGitHub) ListWorkflowFiles(_ context.Context, _ string, _ string, _ string) ([]WorkflowFile, error) {
+	return f.files, nil
+}
+
+func (f *pinGitHub) ResolveCommitSHA(_ context.Context, owner string, name string, ref string) (string, error) {
+	f.resolved++
+	switch owner + "/" + name + "@" + ref {
+	case "docker/build-push-action@v5":
+		return buildSHA, nil
+	case "o/r@main":
+		return "base", nil
+	}
+	return checkoutSHA, nil
+}
+
+func (f *pinGitHub) FindPRForBranch(_ context.Context, _ string, _ string, _ string) (int64, error) {
+	return 0, nil
+}
+
+func (f *pinGitHub) GetRepository(_ context.Context, ref RepoRef) (*Repository, error) {
+	return &Repository{RepoRef: ref, ID: "R_1", DefaultBranch: "main"}, nil
+}
+
+func (f *pinGitHub) CreateBranch(_ context.Context, _ string, _ string, branch string, sha string) error {
+	f.branches = append(f.branches, branch+"@"+sha)
+	return nil
+}
+
+func (f *pinGitHub) GetRef(_ context.Context, _ string, _ string, ref string) (*GitRef, error) {
+	if f.existing[strings.TrimPrefix(ref, "heads/")] {
+		return &GitRef{Ref: "refs/" + ref, ObjectType: "commit", OID: "stale"}, nil
+	}
+	return nil, &RESTError{StatusCode: http.StatusNotFound}
+}
+
+func (f *pinGitHub) ResetBranch(_ context.Context, _ string, _ string, branch string, sha string) error {
+	f.resets = append(f.resets, branch+"@"+sha)
+	return nil
+}
+
+func (f *pinGitHub) CommitFiles(_ context.Context, _ string, _ string, _ string, _ string, files []FileChange) (string, error) {
+	f.commits = append(f.commits, files)
+	return "c", nil
+}
+
+func (f *pinGitHub) CreatePullRequest(_ context.Context, _ graphql.ID, baseRefName string, remoteRefName string, title string, _ string) (int64, error) {
+	f.prs = append(f.prs, baseRefName+"<-"+remoteRefName+": "+title)
+	return 9, nil
+}
+
+func TestActionPinner_Pin(t *testing.T) {
+	gh := &pinGitHub{files: []WorkflowFile{
+		{Path: ".github/workflows/ci.yaml", Content: []byte(inventoryWorkflow)},
+		{Path: ".github/workflows/pinned.yaml", Content: []byte("on: push\njobs:\n  a:\n    steps:\n      - uses: actions/checkout@v4\n")},
+	}}
+	p := NewActionPinner(gh)
+	p.TrustedOwners = []string{"cresta"}
+	repo := RepoRef{Owner: "o", Name: "r"}
+
+	number, err := p.Pin(reqmeta.WithDryRun(context.Background(), true), repo)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), number)
+	require.Empty(t, gh.branches)
+
+	number, err = p.Pin(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, int64(9), number)
+	require.Equal(t, []string{"pin-actions@base"}, gh.branches)
+	require.Len(t, gh.commits, 1)
+	require.Len(t, gh.commits[0], 1)
+	require.Equal(t, ".github/workflows/ci.yaml", gh.commits[0][0].Path)
+	require.Contains(t, string(gh.commits[0][0].Content), "docker/build-push-action@"+buildSHA+" # v5")
+	require.Equal(t, []string{"main<-pin-actions: " + DefaultActionPinTitle}, gh.prs)
+	// The tag was resolved once for the dry run and then served from the cache, plus the default branch
+	require.Equal(t, 2, gh.resolved)
+}
+
+func TestActionPinner_Pin_ExistingBranch(t *testing.T) {
+	gh := &pinGitHub{
+		files:    []WorkflowFile{{Path: ".github/workflows/ci.yaml", Content: []byte(inventoryWorkflow)}},
+		existing: map[string]bool{DefaultActionPinBranch: true},
+	}
+	p := NewActionPinner(gh)
+	p.TrustedOwners = []string{"cresta"}
+
+	// A branch left by an earlier run without an open pull request is reset to base instead of created
+	number, err := p.Pin(context.Background(), RepoRef{Owner: "o", Name: "r"})
+	require.NoError(t, err)
+	require.Equal(t, int64(9), number)
+	require.Empty(t, gh.branches)
+	require.Equal(t, []string{"pin-actions@base"}, gh.resets)
+	require.Len(t, gh.commits, 1)
+	require.Equal(t, []string{"main<-pin-actions: " + DefaultActionPinTitle}, gh.prs)
+}
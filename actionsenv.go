@@ -0,0 +1,62 @@
+package gogithub
+
+import (
+	"os"
+	"strings"
+)
+
+// ActionsEnvironment is what a GitHub Actions job tells the client about where it runs.  Actions does not export the
+// job token on its own: a workflow passes it as the GITHUB_TOKEN env var, or an action declares it as a token input,
+// which the runner exports as INPUT_TOKEN or INPUT_GITHUB-TOKEN.
+type ActionsEnvironment struct {
+	// Token is the job token, or empty if it was not passed to the step
+	Token string
+	// APIURL is the REST root of the instance the job runs on, https://api.github.com or a GitHub Enterprise Server
+	APIURL string
+	// Repository is the owner/name of the repository of the workflow
+	Repository string
+}
+
+// actionsTokenVars are the env vars the job token can be passed in, by priority
+var actionsTokenVars = []string{"GITHUB_TOKEN", "INPUT_GITHUB_TOKEN", "INPUT_GITHUB-TOKEN", "INPUT_TOKEN"}
+
+// DetectActionsEnvironment reads the Actions environment of the process.  ok is false when it does not run in
+// GitHub Actions.
+func DetectActionsEnvironment() (env ActionsEnvironment, ok bool) {
+	return detectActionsEnvironment(os.Getenv)
+}
+
+func detectActionsEnvironment(getenv func(string) string) (ActionsEnvironment, bool) {
+	if getenv("GITHUB_ACTIONS") != "true" {
+		return ActionsEnvironment{}, false
+	}
+	ret := ActionsEnvironment{
+		APIURL:     strings.TrimSuffix(getenv("GITHUB_API_URL"), "/"),
+		Repository: getenv("GITHUB_REPOSITORY"),
+	}
+	for _, v := range actionsTokenVars {
+		if token := getenv(v); token != "" {
+			ret.Token = token
+			break
+		}
+	}
+	return ret, true
+}
+
+// tokenFromEnv returns GITHUB_TOKEN, or inside GitHub Actions the job token wherever the step received it
+func tokenFromEnv(getenv func(string) string) string {
+	if env, ok := detectActionsEnvironment(getenv); ok {
+		return env.Token
+	}
+	return getenv("GITHUB_TOKEN")
+}
+
+// baseURLFromEnv returns the REST root of the GitHub Enterprise Server an Actions job runs on.  It is empty outside
+// Actions and on github.com, where the default applies.
+func baseURLFromEnv(getenv func(string) string) string {
+	env, ok := detectActionsEnvironment(getenv)
+	if !ok || env.APIURL == defaultRESTBaseURL {
+		return ""
+	}
+	return env.APIURL
+}
@@ -0,0 +1,40 @@
+package gogithub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectActionsEnvironment(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string {
+			return vars[k]
+		}
+	}
+	_, ok := detectActionsEnvironment(env(map[string]string{"GITHUB_TOKEN": "pat"}))
+	require.False(t, ok)
+	require.Equal(t, "pat", tokenFromEnv(env(map[string]string{"GITHUB_TOKEN": "pat"})))
+	require.Equal(t, "", baseURLFromEnv(env(map[string]string{"GITHUB_API_URL": "https://ghe.example.com/api/v3"})))
+
+	ghes := env(map[string]string{
+		"GITHUB_ACTIONS":     "true",
+		"GITHUB_API_URL":     "https://ghe.example.com/api/v3",
+		"GITHUB_REPOSITORY":  "cresta/gogithub",
+		"INPUT_GITHUB-TOKEN": "job-token",
+	})
+	actions, ok := detectActionsEnvironment(ghes)
+	require.True(t, ok)
+	require.Equal(t, ActionsEnvironment{Token: "job-token", APIURL: "https://ghe.example.com/api/v3", Repository: "cresta/gogithub"}, actions)
+	require.Equal(t, "job-token", tokenFromEnv(ghes))
+	require.Equal(t, "https://ghe.example.com/api/v3", baseURLFromEnv(ghes))
+
+	dotcom := env(map[string]string{
+		"GITHUB_ACTIONS": "true",
+		"GITHUB_API_URL": "https://api.github.com",
+		"GITHUB_TOKEN":   "env-token",
+		"INPUT_TOKEN":    "input-token",
+	})
+	require.Equal(t, "env-token", tokenFromEnv(dotcom))
+	require.Equal(t, "", baseURLFromEnv(dotcom))
+}
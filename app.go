@@ -0,0 +1,214 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"go.uber.org/zap"
+)
+
+// ErrNoAppCredentials is returned by NewAppClient when the config has no App ID or private key
+var ErrNoAppCredentials = errors.New("app client needs an App ID and a PEM key")
+
+// AppInstallation is an installation of the App on a user or organization account
+type AppInstallation struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+		// Type is User or Organization
+		Type string `json:"type"`
+	} `json:"account"`
+	// RepositorySelection is all or selected
+	RepositorySelection string            `json:"repository_selection"`
+	Permissions         map[string]string `json:"permissions"`
+	Events              []string          `json:"events"`
+	SuspendedAt         *time.Time        `json:"suspended_at"`
+}
+
+// AppClient authenticates as a GitHub App itself, with a JWT signed by the App's private key, rather than as one of
+// its installations.  Multi tenant Apps use it to find their installations and mint a client for each on demand.
+type AppClient struct {
+	// rest sends the App's own requests.  The apps transport replaces the Authorization header with the JWT.
+	rest   *GithubGraphqlAPI
+	logger *zap.Logger
+	cfg    NewGQLClientConfig
+	// newInstallationTransport returns a transport minting tokens of one installation
+	newInstallationTransport func(installationID int64) *ghinstallation.Transport
+
+	mu       sync.Mutex
+	clients  map[int64]*GithubGraphqlAPI
+	shutdown bool
+	// creating coalesces concurrent creations of the client of one installation
+	creating flightGroup[int64, *GithubGraphqlAPI]
+}
+
+// NewAppClient creates an AppClient from the AppID and PEMKey or PEMKeyLoc of cfg.  InstallationID is ignored.  The
+// rest of cfg, merged with DefaultGQLClientConfig, configures the installation clients.
+func NewAppClient(_ context.Context, logger *zap.Logger, cfg *NewGQLClientConfig) (*AppClient, error) {
+	cfg = mergeGithubConfigs(cfg, &DefaultGQLClientConfig).withConnectionTransport()
+	if cfg.AppID == 0 || (cfg.PEMKey == "" && cfg.PEMKeyLoc == "") {
+		return nil, ErrNoAppCredentials
+	}
+	var atr *ghinstallation.AppsTransport
+	var err error
+	if cfg.PEMKey != "" {
+		atr, err = ghinstallation.NewAppsTransport(cfg.baseTransport(), cfg.AppID, []byte(cfg.PEMKey))
+	} else {
+		atr, err = ghinstallation.NewAppsTransportKeyFromFile(cfg.baseTransport(), cfg.AppID, cfg.PEMKeyLoc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to load app key: %w", err)
+	}
+	atr.BaseURL = cfg.restBaseURL()
+	httpClient := &http.Client{Transport: transportOptionsFromConfig(cfg).wrap(atr, logger)}
+	rest := createGraphqlAPI(nil, httpClient, logger, 0, func(_ context.Context) (string, error) {
+		return "", nil
+	})
+	rest.applyConfig(cfg)
+	return &AppClient{
+		rest:   rest,
+		logger: logger,
+		cfg:    *cfg,
+		newInstallationTransport: func(installationID int64) *ghinstallation.Transport {
+			return ghinstallation.NewFromAppsTransport(atr, installationID)
+		},
+		clients: make(map[int64]*GithubGraphqlAPI),
+	}, nil
+}
+
+// ListInstallations returns every installation of the App
+func (a *AppClient) ListInstallations(ctx context.Context) ([]AppInstallation, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[AppInstallation], err error) {
+		ctx = withOperation(ctx, "ListInstallations")
+		defer annotateError(&err, OperationError{Operation: "ListInstallations"})
+		a.logger.Debug("ListInstallations", zap.String("cursor", cursor))
+		defer a.logger.Debug("Done ListInstallations")
+		var ret []AppInstallation
+		if err := a.rest.doREST(ctx, http.MethodGet, fmt.Sprintf("/app/installations?per_page=100&page=%d", restPageNumber(cursor)), nil, &ret); err != nil {
+			return Page[AppInstallation]{}, fmt.Errorf("failed to list installations: %w", err)
+		}
+		return restPage(ret, cursor, 100), nil
+	}).All(ctx)
+}
+
+func (a *AppClient) getInstallation(ctx context.Context, operation string, path string) (_ *AppInstallation, err error) {
+	ctx = withOperation(ctx, operation)
+	defer annotateError(&err, OperationError{Operation: operation})
+	a.logger.Debug(operation, zap.String("path", path))
+	defer a.logger.Debug("Done " + operation)
+	var ret AppInstallation
+	if err := a.rest.doREST(ctx, http.MethodGet, path, nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get installation: %w", err)
+	}
+	return &ret, nil
+}
+
+// GetOrgInstallation returns the installation of the App on org
+func (a *AppClient) GetOrgInstallation(ctx context.Context, org string) (*AppInstallation, error) {
+	return a.getInstallation(ctx, "GetOrgInstallation", fmt.Sprintf("/orgs/%s/installation", org))
+}
+
+// GetRepoInstallation returns the installation of the App that can access owner/name
+func (a *AppClient) GetRepoInstallation(ctx context.Context, owner string, name string) (*AppInstallation, error) {
+	return a.getInstallation(ctx, "GetRepoInstallation", fmt.Sprintf("/repos/%s/%s/installation", owner, name))
+}
+
+// InstallationClient returns a client authenticated as the installation.  Clients are created once per installation
+// and reused, so their caches and tokens are shared by every caller.  Creating a client mints its first token, which
+// only blocks the callers asking for the same installation.
+func (a *AppClient) InstallationClient(ctx context.Context, installationID int64) (GitHub, error) {
+	c, err := a.cachedInstallationClient(installationID)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		return c, nil
+	}
+	c, _, err = a.creating.Do(ctx, installationID, func(ctx context.Context) (*GithubGraphqlAPI, error) {
+		if c, err := a.cachedInstallationClient(installationID); c != nil || err != nil {
+			return c, err
+		}
+		cfg := a.cfg
+		cfg.InstallationID = installationID
+		// The AppClient owns the transport its installation clients share
+		cfg.ownsTransport = false
+		if cfg.Tenant == "" {
+			cfg.Tenant = fmt.Sprintf("installation-%d", installationID)
+		}
+		c, err := clientFromInstallation(ctx, a.logger.With(zap.Int64("installation_id", installationID)), &cfg, func() *ghinstallation.Transport {
+			return a.newInstallationTransport(installationID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.shutdown {
+			// Shutdown started while the token was minted and could not see this client.  Nothing can be in flight on it.
+			_ = c.Shutdown(ctx)
+			return nil, ErrClientShutdown
+		}
+		a.clients[installationID] = c
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// cachedInstallationClient returns the client already created for the installation, if any
+func (a *AppClient) cachedInstallationClient(installationID int64) (*GithubGraphqlAPI, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.shutdown {
+		return nil, ErrClientShutdown
+	}
+	return a.clients[installationID], nil
+}
+
+// ClientForOrg returns a client of the installation on org
+func (a *AppClient) ClientForOrg(ctx context.Context, org string) (GitHub, error) {
+	inst, err := a.GetOrgInstallation(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	return a.InstallationClient(ctx, inst.ID)
+}
+
+// ClientForRepo returns a client of the installation that can access owner/name
+func (a *AppClient) ClientForRepo(ctx context.Context, owner string, name string) (GitHub, error) {
+	inst, err := a.GetRepoInstallation(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	return a.InstallationClient(ctx, inst.ID)
+}
+
+// Shutdown shuts down every installation client, waiting for their calls in flight until ctx is done, and closes the
+// idle connections of the transport built from TransportTuning, if any.  No new installation client can be created
+// afterwards.
+func (a *AppClient) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	a.shutdown = true
+	clients := make([]*GithubGraphqlAPI, 0, len(a.clients))
+	for _, c := range a.clients {
+		clients = append(clients, c)
+	}
+	a.mu.Unlock()
+	var firstErr error
+	for _, c := range clients {
+		if err := c.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if a.cfg.ownsTransport {
+		closeIdleConnections(a.cfg.Rt)
+	}
+	return firstErr
+}
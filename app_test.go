@@ -0,0 +1,120 @@
+package gogithub
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAppClient_Installations(t *testing.T) {
+	rest := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations":
+			if r.URL.Query().Get("page") == "1" {
+				items := make([]string, 100)
+				for i := range items {
+					items[i] = fmt.Sprintf(`{"id":%d,"account":{"login":"org%d","type":"Organization"}}`, i+1, i+1)
+				}
+				_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id":101,"account":{"login":"someone","type":"User"},"repository_selection":"selected"}]`))
+		case "/repos/o/r/installation":
+			_, _ = w.Write([]byte(`{"id":7,"account":{"login":"o","type":"Organization"},"permissions":{"contents":"write"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	a := &AppClient{rest: rest, logger: zaptest.NewLogger(t)}
+	installations, err := a.ListInstallations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, installations, 101)
+	require.Equal(t, "someone", installations[100].Account.Login)
+	require.Equal(t, "selected", installations[100].RepositorySelection)
+
+	inst, err := a.GetRepoInstallation(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), inst.ID)
+	require.Equal(t, "write", inst.Permissions["contents"])
+
+	_, err = a.GetOrgInstallation(context.Background(), "missing")
+	var restErr *RESTError
+	require.True(t, errors.As(err, &restErr))
+	require.Equal(t, http.StatusNotFound, restErr.StatusCode)
+}
+
+func TestNewAppClient_NoCredentials(t *testing.T) {
+	_, err := NewAppClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{Token: "t"})
+	require.ErrorIs(t, err, ErrNoAppCredentials)
+}
+
+func TestAppClient_InstallationClient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	slowStarted := make(chan struct{})
+	releaseSlow := make(chan struct{})
+	var slowMints atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations/1/access_tokens":
+			if slowMints.Add(1) == 1 {
+				close(slowStarted)
+			}
+			<-releaseSlow
+		case "/app/installations/2/access_tokens":
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"token":"t","expires_at":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer srv.Close()
+	a, err := NewAppClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{AppID: 1, PEMKey: string(pemKey), BaseURL: srv.URL, Rt: http.DefaultTransport})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	slow := make([]GitHub, 2)
+	slowErrs := make([]error, 2)
+	for i := range slow {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slow[i], slowErrs[i] = a.InstallationClient(context.Background(), 1)
+		}(i)
+	}
+	<-slowStarted
+
+	// Another installation is not held up by the token being minted for installation 1
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	other, err := a.InstallationClient(ctx, 2)
+	require.NoError(t, err)
+	again, err := a.InstallationClient(ctx, 2)
+	require.NoError(t, err)
+	require.Same(t, other, again)
+
+	close(releaseSlow)
+	wg.Wait()
+	require.NoError(t, errors.Join(slowErrs...))
+	require.Same(t, slow[0], slow[1])
+	require.Equal(t, int32(1), slowMints.Load())
+
+	require.NoError(t, a.Shutdown(context.Background()))
+	_, err = a.InstallationClient(context.Background(), 3)
+	require.ErrorIs(t, err, ErrClientShutdown)
+}
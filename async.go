@@ -0,0 +1,96 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrStillComputing is returned when GitHub keeps answering 202 Accepted after every retry was used up
+var ErrStillComputing = errors.New("github is still computing the result")
+
+// AcceptedBackoff controls how REST calls answered with 202 Accepted are retried.  Endpoints such as repository
+// statistics, forks and archives return 202 while GitHub computes the result in the background.
+type AcceptedBackoff struct {
+	// InitialDelay is the wait before the first retry.  It doubles on every following retry.
+	InitialDelay time.Duration `yaml:"initialDelay" json:"initialDelay"`
+	// MaxDelay caps the wait between two retries
+	MaxDelay time.Duration `yaml:"maxDelay" json:"maxDelay"`
+	// MaxAttempts is the total number of requests sent, including the first one
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+}
+
+var DefaultAcceptedBackoff = AcceptedBackoff{
+	InitialDelay: time.Second,
+	MaxDelay:     16 * time.Second,
+	MaxAttempts:  8,
+}
+
+// withDefaults fills each zero field from DefaultAcceptedBackoff, so a partial config never retries without waiting
+func (b AcceptedBackoff) withDefaults() AcceptedBackoff {
+	if b.InitialDelay <= 0 {
+		b.InitialDelay = DefaultAcceptedBackoff.InitialDelay
+	}
+	if b.MaxDelay <= 0 {
+		b.MaxDelay = DefaultAcceptedBackoff.MaxDelay
+	}
+	if b.MaxAttempts <= 0 {
+		b.MaxAttempts = DefaultAcceptedBackoff.MaxAttempts
+	}
+	return b
+}
+
+func (b AcceptedBackoff) delay(attempt int) time.Duration {
+	d := b.InitialDelay
+	for i := 0; i < attempt && d < b.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > b.MaxDelay {
+		d = b.MaxDelay
+	}
+	return d
+}
+
+// retry calls try, waiting with backoff between calls, until it reports done or fails.  It returns ErrStillComputing
+// once every attempt was used up.
+func (b AcceptedBackoff) retry(ctx context.Context, try func(ctx context.Context) (done bool, err error)) error {
+	b = b.withDefaults()
+	for attempt := 0; attempt < b.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(b.delay(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		done, err := try(ctx)
+		if err != nil || done {
+			return err
+		}
+	}
+	return ErrStillComputing
+}
+
+// doRESTWaitAccepted is doREST, but retries with backoff for as long as GitHub answers 202 Accepted so callers get
+// the final data
+func (g *GithubGraphqlAPI) doRESTWaitAccepted(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	err := g.acceptedBackoff.retry(ctx, func(ctx context.Context) (bool, error) {
+		status, err := g.sendREST(ctx, method, path, body, out)
+		if err != nil {
+			return false, err
+		}
+		if status == http.StatusAccepted {
+			g.logger(ctx).Debug("result not ready yet, retrying")
+			return false, nil
+		}
+		return true, nil
+	})
+	if errors.Is(err, ErrStillComputing) {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRESTWaitAccepted(t *testing.T) {
+	calls := 0
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"total":3}]`))
+	})
+	g.acceptedBackoff = AcceptedBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 5}
+	var out []struct {
+		Total int `json:"total"`
+	}
+	require.NoError(t, g.doRESTWaitAccepted(context.Background(), http.MethodGet, "/repos/o/r/stats/contributors", nil, &out))
+	require.Equal(t, 3, calls)
+	require.Len(t, out, 1)
+	require.Equal(t, 3, out[0].Total)
+}
+
+func TestDoRESTWaitAccepted_GivesUp(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	g.acceptedBackoff = AcceptedBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 2}
+	err := g.doRESTWaitAccepted(context.Background(), http.MethodGet, "/repos/o/r/stats/contributors", nil, nil)
+	require.True(t, errors.Is(err, ErrStillComputing))
+}
+
+func TestAcceptedBackoff_Delay(t *testing.T) {
+	b := AcceptedBackoff{InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	require.Equal(t, time.Second, b.delay(0))
+	require.Equal(t, 2*time.Second, b.delay(1))
+	require.Equal(t, 4*time.Second, b.delay(2))
+	require.Equal(t, 5*time.Second, b.delay(3))
+}
+
+func TestAcceptedBackoff_WithDefaults(t *testing.T) {
+	// A partial config keeps its own fields and takes the rest from the defaults
+	b := AcceptedBackoff{MaxAttempts: 20}.withDefaults()
+	require.Equal(t, AcceptedBackoff{InitialDelay: DefaultAcceptedBackoff.InitialDelay, MaxDelay: DefaultAcceptedBackoff.MaxDelay, MaxAttempts: 20}, b)
+	require.Equal(t, DefaultAcceptedBackoff, AcceptedBackoff{}.withDefaults())
+	b = AcceptedBackoff{InitialDelay: time.Millisecond}.withDefaults()
+	require.Equal(t, DefaultAcceptedBackoff.MaxAttempts, b.MaxAttempts)
+	require.Equal(t, DefaultAcceptedBackoff.MaxDelay, b.MaxDelay)
+}
@@ -0,0 +1,98 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditLogEvent is an entry of an organization audit log
+type AuditLogEvent struct {
+	// DocumentID uniquely identifies the event, for deduplicating ingestion
+	DocumentID string
+	// Action is for example repo.create or org.add_member
+	Action    string
+	Actor     string
+	User      string
+	Org       string
+	Repo      string
+	Timestamp time.Time
+	// Fields are every field of the event as GitHub sent it, most of which depend on Action
+	Fields map[string]interface{}
+}
+
+func (e *AuditLogEvent) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		DocumentID string `json:"_document_id"`
+		Action     string `json:"action"`
+		Actor      string `json:"actor"`
+		User       string `json:"user"`
+		Org        string `json:"org"`
+		Repo       string `json:"repo"`
+		// Timestamp is in milliseconds since the epoch
+		Timestamp int64 `json:"@timestamp"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	*e = AuditLogEvent{
+		DocumentID: raw.DocumentID,
+		Action:     raw.Action,
+		Actor:      raw.Actor,
+		User:       raw.User,
+		Org:        raw.Org,
+		Repo:       raw.Repo,
+		Timestamp:  time.UnixMilli(raw.Timestamp).UTC(),
+		Fields:     fields,
+	}
+	return nil
+}
+
+// auditLogPhrase adds a lower bound on the event time to phrase, an audit log search phrase
+func auditLogPhrase(phrase string, since time.Time) string {
+	if since.IsZero() {
+		return phrase
+	}
+	return strings.TrimSpace(phrase + " created:>=" + since.UTC().Format(time.RFC3339))
+}
+
+// AuditLogEvents pages through the audit log events of org matching phrase, for example action:repo.create, that
+// happened at or after since, oldest first.  A zero since lists the whole retained log.  The audit log API needs
+// GitHub Enterprise Cloud and an owner of org, or an App with the organization administration permission.
+func (g *GithubGraphqlAPI) AuditLogEvents(org string, phrase string, since time.Time, opts ...PaginatorOption) *Paginator[AuditLogEvent] {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[AuditLogEvent], err error) {
+		ctx = withOperation(ctx, "AuditLogEvents")
+		defer annotateError(&err, OperationError{Operation: "AuditLogEvents", Owner: org})
+		g.logger(ctx).Debug("AuditLogEvents", zap.String("org", org), zap.String("phrase", phrase), zap.Time("since", since), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done AuditLogEvents")
+		q := url.Values{}
+		if p := auditLogPhrase(phrase, since); p != "" {
+			q.Set("phrase", p)
+		}
+		q.Set("include", "all")
+		q.Set("order", "asc")
+		q.Set("per_page", "100")
+		q.Set("page", fmt.Sprint(restPageNumber(cursor)))
+		var ret []AuditLogEvent
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/orgs/%s/audit-log?%s", org, q.Encode()), nil, &ret); err != nil {
+			return Page[AuditLogEvent]{}, fmt.Errorf("failed to list audit log events: %w", err)
+		}
+		return restPage(ret, cursor, 100), nil
+	}, opts...)
+}
+
+// ListAuditLogEvents returns the audit log events of org matching phrase that happened at or after since, oldest
+// first
+func (g *GithubGraphqlAPI) ListAuditLogEvents(ctx context.Context, org string, phrase string, since time.Time) ([]AuditLogEvent, error) {
+	return g.AuditLogEvents(org, phrase, since).All(ctx)
+}
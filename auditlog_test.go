@@ -0,0 +1,51 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogEvents(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/orgs/acme/audit-log" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		require.Equal(t, "action:repo.create created:>=2024-03-01T12:00:00Z", r.URL.Query().Get("phrase"))
+		require.Equal(t, "asc", r.URL.Query().Get("order"))
+		switch r.URL.Query().Get("page") {
+		case "1":
+			events := make([]string, 100)
+			for i := range events {
+				events[i] = fmt.Sprintf(`{"_document_id":"d%d","action":"repo.create","@timestamp":1709294400000}`, i)
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(events, ",") + "]"))
+		case "2":
+			_, _ = w.Write([]byte(`[{"_document_id":"last","action":"repo.create","actor":"alice","repo":"acme/new",
+				"@timestamp":1709298000000,"visibility":"private"}]`))
+		default:
+			t.Fatalf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	})
+	events, err := g.ListAuditLogEvents(context.Background(), "acme", "action:repo.create", since)
+	require.NoError(t, err)
+	require.Len(t, events, 101)
+	last := events[100]
+	require.Equal(t, "last", last.DocumentID)
+	require.Equal(t, "alice", last.Actor)
+	require.Equal(t, "acme/new", last.Repo)
+	require.Equal(t, time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC), last.Timestamp)
+	require.Equal(t, "private", last.Fields["visibility"])
+}
+
+func TestAuditLogPhrase(t *testing.T) {
+	require.Equal(t, "", auditLogPhrase("", time.Time{}))
+	require.Equal(t, "actor:bob", auditLogPhrase("actor:bob", time.Time{}))
+	require.Equal(t, "created:>=2024-01-01T00:00:00Z", auditLogPhrase("", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,189 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateBudget is a token bucket shared by the workers of one or more BulkRunners, so org-wide changes spread their
+// calls instead of spending the whole rate limit in a burst.  It is safe for concurrent use.
+type RateBudget struct {
+	capacity float64
+	// perToken is how long one token takes to refill
+	perToken time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateBudget returns a budget allowing n items per interval, starting full so the first n start right away
+func NewRateBudget(n int, interval time.Duration) *RateBudget {
+	if n < 1 {
+		n = 1
+	}
+	return &RateBudget{
+		capacity: float64(n),
+		perToken: interval / time.Duration(n),
+		tokens:   float64(n),
+		last:     time.Now(),
+	}
+}
+
+// Take waits for a token of the budget, or for ctx to be done
+func (b *RateBudget) Take(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		if b.perToken > 0 {
+			b.tokens += float64(now.Sub(b.last)) / float64(b.perToken)
+		} else {
+			b.tokens = b.capacity
+		}
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) * float64(b.perToken))
+		b.mu.Unlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// BulkItemError is the failure of one item of a BulkRunner
+type BulkItemError[T any] struct {
+	// Index is the position of Item in the items given to Run
+	Index int
+	Item  T
+	Err   error
+}
+
+// BulkError collects the items a BulkRunner failed for, in the order they were given.  Items not listed succeeded,
+// unless the run was stopped early.
+type BulkError[T any] struct {
+	Errors []BulkItemError[T]
+	Total  int
+}
+
+func (e *BulkError[T]) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", itemErr.Item, itemErr.Err))
+	}
+	return fmt.Sprintf("%d of %d items failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *BulkError[T]) Unwrap() []error {
+	ret := make([]error, 0, len(e.Errors))
+	for _, itemErr := range e.Errors {
+		ret = append(ret, itemErr.Err)
+	}
+	return ret
+}
+
+// BulkProgress reports an item a BulkRunner finished
+type BulkProgress[T any] struct {
+	Item T
+	// Err is the error of Item, or nil if it succeeded
+	Err error
+	// Done counts the finished items, including Item
+	Done   int
+	Failed int
+	Total  int
+}
+
+// BulkRunner runs a function across many items, such as repositories or pull requests, with a bounded worker pool.
+// A failing item does not stop the others unless StopOnError is set: every failure is collected into the returned
+// *BulkError.
+type BulkRunner[T any] struct {
+	// Concurrency is how many items are processed at once.  The default is 10.
+	Concurrency int
+	// Budget, if set, is taken once before each item starts.  Share one budget across runners to cap them together.
+	Budget *RateBudget
+	// StopOnError cancels the remaining items after the first failure
+	StopOnError bool
+	// Progress, if set, is called after each item finishes.  Calls are serialized.
+	Progress func(BulkProgress[T])
+}
+
+// Run runs fn for every item.  It returns a *BulkError if any item failed, and nil otherwise.  Items that never
+// started because ctx was done, or the run was stopped, fail with the context error.
+func (r *BulkRunner[T]) Run(ctx context.Context, items []T, fn func(ctx context.Context, item T) error) error {
+	concurrency := r.Concurrency
+	if concurrency < 1 {
+		concurrency = 10
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []BulkItemError[T]
+	done := 0
+	finish := func(index int, item T, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if err != nil {
+			errs = append(errs, BulkItemError[T]{Index: index, Item: item, Err: err})
+			if r.StopOnError {
+				cancel()
+			}
+		}
+		if r.Progress != nil {
+			r.Progress(BulkProgress[T]{Item: item, Err: err, Done: done, Failed: len(errs), Total: len(items)})
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			finish(i, item, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(index int, item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := ctx.Err(); err != nil {
+				finish(index, item, err)
+				return
+			}
+			if r.Budget != nil {
+				if err := r.Budget.Take(ctx); err != nil {
+					finish(index, item, err)
+					return
+				}
+			}
+			finish(index, item, fn(ctx, item))
+		}(i, item)
+	}
+	wg.Wait()
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Index < errs[j].Index
+	})
+	return &BulkError[T]{
+		Errors: errs,
+		Total:  len(items),
+	}
+}
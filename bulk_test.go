@@ -0,0 +1,75 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkRunner(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+	var running, maxRunning int32
+	var progress []BulkProgress[int]
+	runner := BulkRunner[int]{
+		Concurrency: 2,
+		Progress: func(p BulkProgress[int]) {
+			progress = append(progress, p)
+		},
+	}
+	err := runner.Run(context.Background(), items, func(ctx context.Context, item int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		if item%3 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	var bulkErr *BulkError[int]
+	require.True(t, errors.As(err, &bulkErr))
+	require.Len(t, bulkErr.Errors, 2)
+	require.Equal(t, 3, bulkErr.Errors[0].Item)
+	require.Equal(t, 2, bulkErr.Errors[0].Index)
+	require.Equal(t, 6, bulkErr.Errors[1].Item)
+	require.Equal(t, "2 of 6 items failed: 3: boom; 6: boom", err.Error())
+	require.LessOrEqual(t, maxRunning, int32(2))
+	require.Len(t, progress, 6)
+	require.Equal(t, 6, progress[5].Done)
+	require.Equal(t, 2, progress[5].Failed)
+}
+
+func TestBulkRunner_StopOnError(t *testing.T) {
+	runner := BulkRunner[string]{Concurrency: 1, StopOnError: true}
+	var ran []string
+	err := runner.Run(context.Background(), []string{"a", "b", "c"}, func(ctx context.Context, item string) error {
+		ran = append(ran, item)
+		return errors.New("boom")
+	})
+	require.Equal(t, []string{"a"}, ran)
+	var bulkErr *BulkError[string]
+	require.True(t, errors.As(err, &bulkErr))
+	require.Len(t, bulkErr.Errors, 3)
+	require.ErrorIs(t, bulkErr.Errors[2].Err, context.Canceled)
+}
+
+func TestRateBudget(t *testing.T) {
+	b := NewRateBudget(2, 100*time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Take(context.Background()))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, b.Take(ctx), context.Canceled)
+}
@@ -0,0 +1,103 @@
+package gogithub
+
+import (
+	"context"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+)
+
+// CacheName identifies one of the client's lookup caches
+type CacheName string
+
+const (
+	// CachePullRequests holds FindPRForBranch results
+	CachePullRequests CacheName = "pullRequests"
+	// CacheRepositoryInfo holds RepositoryInfo results
+	CacheRepositoryInfo CacheName = "repositoryInfo"
+	// CacheSelf holds the Self result
+	CacheSelf CacheName = "self"
+)
+
+// cacheGet reads key from cache unless ctx asks to bypass or refresh it with reqmeta.WithCacheControl
+func cacheGet[K comparable, V any](ctx context.Context, cache *ExpireCache[K, V], key K) (V, bool) {
+	if reqmeta.CacheControl(ctx) != reqmeta.CacheDefault {
+		var ret V
+		return ret, false
+	}
+	return cache.Get(key)
+}
+
+// cacheSet stores value in cache unless ctx asks to bypass it
+func cacheSet[K comparable, V any](ctx context.Context, cache *ExpireCache[K, V], key K, value V) {
+	if reqmeta.CacheControl(ctx) == reqmeta.CacheBypass {
+		return
+	}
+	cache.Set(key, value)
+}
+
+// cacheSetWithTTL is cacheSet storing value for ttl
+func cacheSetWithTTL[K comparable, V any](ctx context.Context, cache *ExpireCache[K, V], key K, value V, ttl time.Duration) {
+	if reqmeta.CacheControl(ctx) == reqmeta.CacheBypass {
+		return
+	}
+	cache.SetWithTTL(key, value, ttl)
+}
+
+// CacheInvalidation describes entries dropped from a cache.  Empty Owner and Name mean the whole cache was cleared,
+// an empty Branch means every entry of the repository was dropped.
+type CacheInvalidation struct {
+	Cache  CacheName
+	Owner  string
+	Name   string
+	Branch string
+}
+
+func (g *GithubGraphqlAPI) notifyInvalidate(ev CacheInvalidation) {
+	if g.onCacheInvalidate != nil {
+		g.onCacheInvalidate(ev)
+	}
+}
+
+// clearPRCache drops every FindPRForBranch result.  Mutations call it since they may change which PR a branch has.
+func (g *GithubGraphqlAPI) clearPRCache() {
+	g.findPrCache.Clear()
+	g.notifyInvalidate(CacheInvalidation{Cache: CachePullRequests})
+}
+
+// InvalidateBranch drops the cached FindPRForBranch result of a single branch, so the next lookup sees PRs created
+// outside this client
+func (g *GithubGraphqlAPI) InvalidateBranch(owner string, name string, branch string) {
+	g.findPrCache.Delete(findPrKey{
+		owner:  owner,
+		name:   name,
+		branch: branch,
+	})
+	g.notifyInvalidate(CacheInvalidation{Cache: CachePullRequests, Owner: owner, Name: name, Branch: branch})
+}
+
+// InvalidateRepositoryInfo drops the cached RepositoryInfo of a repository.  Call it when the default branch changes.
+func (g *GithubGraphqlAPI) InvalidateRepositoryInfo(owner string, name string) {
+	g.repoInfoCache.Delete(repoKey{
+		owner: owner,
+		name:  name,
+	})
+	g.notifyInvalidate(CacheInvalidation{Cache: CacheRepositoryInfo, Owner: owner, Name: name})
+}
+
+// InvalidateSelf drops the cached Self results.  Results are cached per token, so a new token is looked up anyway; call
+// it when the identity behind an unchanged token may have changed, such as a renamed user.
+func (g *GithubGraphqlAPI) InvalidateSelf() {
+	g.selfCache.Clear()
+	g.notifyInvalidate(CacheInvalidation{Cache: CacheSelf})
+}
+
+// InvalidateRepository drops every cached lookup of a repository.  Webhook driven services call it when an event
+// reports a change made outside this client, keeping the cache coherent without waiting for entries to expire.
+func (g *GithubGraphqlAPI) InvalidateRepository(owner string, name string) {
+	g.findPrCache.DeleteFunc(func(k findPrKey) bool {
+		return k.owner == owner && k.name == name
+	})
+	g.notifyInvalidate(CacheInvalidation{Cache: CachePullRequests, Owner: owner, Name: name})
+	g.InvalidateRepositoryInfo(owner, name)
+}
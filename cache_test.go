@@ -0,0 +1,134 @@
+package gogithub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGithubGraphqlAPI_InvalidateRepository(t *testing.T) {
+	g := createGraphqlAPI(nil, nil, zaptest.NewLogger(t), time.Hour, func(_ context.Context) (string, error) {
+		return "", nil
+	})
+	var events []CacheInvalidation
+	g.onCacheInvalidate = func(ev CacheInvalidation) {
+		events = append(events, ev)
+	}
+	g.findPrCache.Set(findPrKey{owner: "cresta", name: "a", branch: "x"}, findPrValue{prs: []BranchPullRequest{{Number: 1}}})
+	g.findPrCache.Set(findPrKey{owner: "cresta", name: "b", branch: "x"}, findPrValue{prs: []BranchPullRequest{{Number: 2}}})
+	g.repoInfoCache.Set(repoKey{owner: "cresta", name: "a"}, &RepositoryInfo{})
+
+	g.InvalidateRepository("cresta", "a")
+	_, exists := g.findPrCache.Get(findPrKey{owner: "cresta", name: "a", branch: "x"})
+	require.False(t, exists)
+	_, exists = g.findPrCache.Get(findPrKey{owner: "cresta", name: "b", branch: "x"})
+	require.True(t, exists)
+	_, exists = g.repoInfoCache.Get(repoKey{owner: "cresta", name: "a"})
+	require.False(t, exists)
+	require.Equal(t, []CacheInvalidation{
+		{Cache: CachePullRequests, Owner: "cresta", Name: "a"},
+		{Cache: CacheRepositoryInfo, Owner: "cresta", Name: "a"},
+	}, events)
+}
+
+func TestGithubGraphqlAPI_InvalidateBranch(t *testing.T) {
+	g, queries := newBranchPRsClient(t, map[string]string{
+		"x": `{"number":1,"baseRefName":"main"}`,
+		"y": `{"number":2,"baseRefName":"main"}`,
+	})
+	var events []CacheInvalidation
+	g.onCacheInvalidate = func(ev CacheInvalidation) {
+		events = append(events, ev)
+	}
+	ctx := context.Background()
+	for _, branch := range []string{"x", "y", "x", "y"} {
+		_, err := g.FindPRForBranch(ctx, "o", "r", branch)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, *queries)
+
+	// Only the invalidated branch is queried again
+	g.InvalidateBranch("o", "r", "x")
+	for _, branch := range []string{"x", "y", "x"} {
+		_, err := g.FindPRForBranch(ctx, "o", "r", branch)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, *queries)
+	require.Equal(t, []CacheInvalidation{{Cache: CachePullRequests, Owner: "o", Name: "r", Branch: "x"}}, events)
+}
+
+func TestGithubGraphqlAPI_PRNegativeCacheTTL(t *testing.T) {
+	g := createGraphqlAPI(nil, nil, zaptest.NewLogger(t), time.Hour, func(_ context.Context) (string, error) {
+		return "", nil
+	})
+	g.findPrNegativeTTL = time.Millisecond
+	ctx := context.Background()
+	g.cachePRsForBranch(ctx, findPrKey{owner: "cresta", name: "a", branch: "none"}, []BranchPullRequest{})
+	g.cachePRsForBranch(ctx, findPrKey{owner: "cresta", name: "a", branch: "x"}, []BranchPullRequest{{Number: 1}})
+	time.Sleep(5 * time.Millisecond)
+	_, exists := g.findPrCache.Get(findPrKey{owner: "cresta", name: "a", branch: "none"})
+	require.False(t, exists)
+	_, exists = g.findPrCache.Get(findPrKey{owner: "cresta", name: "a", branch: "x"})
+	require.True(t, exists)
+}
+
+func TestCacheControl(t *testing.T) {
+	c := &ExpireCache[string, int]{DefaultExpiry: time.Hour}
+	ctx := context.Background()
+	cacheSet(ctx, c, "a", 1)
+	v, exists := cacheGet(ctx, c, "a")
+	require.True(t, exists)
+	require.Equal(t, 1, v)
+
+	bypass := reqmeta.WithCacheControl(ctx, reqmeta.CacheBypass)
+	_, exists = cacheGet(bypass, c, "a")
+	require.False(t, exists)
+	cacheSet(bypass, c, "a", 2)
+	v, _ = cacheGet(ctx, c, "a")
+	require.Equal(t, 1, v)
+
+	refresh := reqmeta.WithCacheControl(ctx, reqmeta.CacheRefresh)
+	_, exists = cacheGet(refresh, c, "a")
+	require.False(t, exists)
+	cacheSet(refresh, c, "a", 3)
+	v, _ = cacheGet(ctx, c, "a")
+	require.Equal(t, 3, v)
+}
+
+func TestGithubGraphqlAPI_SelfCached(t *testing.T) {
+	token := "token-a"
+	g := createGraphqlAPI(nil, nil, zaptest.NewLogger(t), time.Hour, func(_ context.Context) (string, error) {
+		return token, nil
+	})
+	g.selfCache.Set(newSelfKey("token-a"), ViewerIdentity{Login: "bot", DatabaseID: 7})
+	login, err := g.Self(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "bot", login)
+	viewer, err := g.Viewer(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(7), viewer.DatabaseID)
+
+	token = "token-b"
+	_, exists := g.selfCache.Get(newSelfKey(token))
+	require.False(t, exists)
+
+	var events []CacheInvalidation
+	g.onCacheInvalidate = func(ev CacheInvalidation) {
+		events = append(events, ev)
+	}
+	g.InvalidateSelf()
+	_, exists = g.selfCache.Get(newSelfKey("token-a"))
+	require.False(t, exists)
+	require.Equal(t, []CacheInvalidation{{Cache: CacheSelf}}, events)
+}
+
+func TestGithubGraphqlAPI_SelfCacheTTL(t *testing.T) {
+	g := createGraphqlAPI(nil, nil, zaptest.NewLogger(t), time.Minute, nil)
+	require.Equal(t, DefaultSelfCacheTTL, g.selfCacheTTL(&TokenInfo{Token: "pat"}))
+	ttl := g.selfCacheTTL(&TokenInfo{Token: "installation", ExpiresAt: time.Now().Add(time.Hour)})
+	require.True(t, ttl > 59*time.Minute && ttl <= time.Hour)
+}
@@ -0,0 +1,157 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxAnnotationsPerRequest is how many annotations GitHub accepts per check run create or update call
+const maxAnnotationsPerRequest = 50
+
+type CheckRunStatus string
+
+const (
+	CheckRunQueued     CheckRunStatus = "queued"
+	CheckRunInProgress CheckRunStatus = "in_progress"
+	CheckRunCompleted  CheckRunStatus = "completed"
+)
+
+type CheckRunConclusion string
+
+const (
+	CheckRunSuccess        CheckRunConclusion = "success"
+	CheckRunFailure        CheckRunConclusion = "failure"
+	CheckRunNeutral        CheckRunConclusion = "neutral"
+	CheckRunCancelled      CheckRunConclusion = "cancelled"
+	CheckRunSkipped        CheckRunConclusion = "skipped"
+	CheckRunTimedOut       CheckRunConclusion = "timed_out"
+	CheckRunActionRequired CheckRunConclusion = "action_required"
+)
+
+type AnnotationLevel string
+
+const (
+	AnnotationNotice  AnnotationLevel = "notice"
+	AnnotationWarning AnnotationLevel = "warning"
+	AnnotationFailure AnnotationLevel = "failure"
+)
+
+// CheckRunAnnotation points at lines of a file in the check run results
+type CheckRunAnnotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// StartColumn and EndColumn are only allowed when StartLine equals EndLine
+	StartColumn     int             `json:"start_column,omitempty"`
+	EndColumn       int             `json:"end_column,omitempty"`
+	AnnotationLevel AnnotationLevel `json:"annotation_level"`
+	Message         string          `json:"message"`
+	Title           string          `json:"title,omitempty"`
+	RawDetails      string          `json:"raw_details,omitempty"`
+}
+
+// CheckRunOutput is the rich result shown on the checks tab.  Summary and Text are Markdown.
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text,omitempty"`
+	// Annotations can be any number.  They are sent 50 at a time, as GitHub requires.
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// CheckRunInput describes a check run to create, or the fields to change on update.  Empty fields are left out.
+type CheckRunInput struct {
+	// Name and HeadSHA are required on create
+	Name        string             `json:"name,omitempty"`
+	HeadSHA     string             `json:"head_sha,omitempty"`
+	DetailsURL  string             `json:"details_url,omitempty"`
+	ExternalID  string             `json:"external_id,omitempty"`
+	Status      CheckRunStatus     `json:"status,omitempty"`
+	Conclusion  CheckRunConclusion `json:"conclusion,omitempty"`
+	StartedAt   *time.Time         `json:"started_at,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	Output      *CheckRunOutput    `json:"output,omitempty"`
+}
+
+type CheckRun struct {
+	ID         int64              `json:"id"`
+	Name       string             `json:"name"`
+	HeadSHA    string             `json:"head_sha"`
+	Status     CheckRunStatus     `json:"status"`
+	Conclusion CheckRunConclusion `json:"conclusion"`
+	HTMLURL    string             `json:"html_url"`
+	DetailsURL string             `json:"details_url"`
+}
+
+// splitAnnotations returns a copy of input carrying the first batch of annotations, and the remaining annotations
+func (c CheckRunInput) splitAnnotations() (CheckRunInput, []CheckRunAnnotation) {
+	if c.Output == nil || len(c.Output.Annotations) <= maxAnnotationsPerRequest {
+		return c, nil
+	}
+	output := *c.Output
+	rest := output.Annotations[maxAnnotationsPerRequest:]
+	output.Annotations = output.Annotations[:maxAnnotationsPerRequest]
+	c.Output = &output
+	return c, rest
+}
+
+// CreateCheckRun creates a check run.  It requires GitHub App credentials.
+func (g *GithubGraphqlAPI) CreateCheckRun(ctx context.Context, owner string, name string, input CheckRunInput) (_ *CheckRun, err error) {
+	ctx = withOperation(ctx, "CreateCheckRun")
+	defer annotateError(&err, OperationError{Operation: "CreateCheckRun", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateCheckRun", zap.String("owner", owner), zap.String("name", name), zap.String("check", input.Name), zap.String("sha", input.HeadSHA))
+	defer g.logger(ctx).Debug("Done CreateCheckRun")
+	first, rest := input.splitAnnotations()
+	var ret CheckRun
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/check-runs", owner, name), first, &ret); err != nil {
+		return nil, fmt.Errorf("unable to create check run: %w", err)
+	}
+	if err := g.appendAnnotations(ctx, owner, name, ret.ID, input.Output, rest); err != nil {
+		return &ret, err
+	}
+	return &ret, nil
+}
+
+// UpdateCheckRun changes a check run, for example to complete it with a conclusion.  Annotations are added to the
+// existing ones.
+func (g *GithubGraphqlAPI) UpdateCheckRun(ctx context.Context, owner string, name string, checkRunID int64, input CheckRunInput) (_ *CheckRun, err error) {
+	ctx = withOperation(ctx, "UpdateCheckRun")
+	defer annotateError(&err, OperationError{Operation: "UpdateCheckRun", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateCheckRun", zap.String("owner", owner), zap.String("name", name), zap.Int64("checkRunID", checkRunID))
+	defer g.logger(ctx).Debug("Done UpdateCheckRun")
+	first, rest := input.splitAnnotations()
+	var ret CheckRun
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/check-runs/%d", owner, name, checkRunID), first, &ret); err != nil {
+		return nil, fmt.Errorf("unable to update check run: %w", err)
+	}
+	if err := g.appendAnnotations(ctx, owner, name, checkRunID, input.Output, rest); err != nil {
+		return &ret, err
+	}
+	return &ret, nil
+}
+
+// appendAnnotations sends the annotations of output that did not fit the first request in batches.  GitHub requires the title and summary with every output update.
+func (g *GithubGraphqlAPI) appendAnnotations(ctx context.Context, owner string, name string, checkRunID int64, output *CheckRunOutput, annotations []CheckRunAnnotation) error {
+	for len(annotations) > 0 {
+		batch := annotations
+		if len(batch) > maxAnnotationsPerRequest {
+			batch = batch[:maxAnnotationsPerRequest]
+		}
+		annotations = annotations[len(batch):]
+		body := CheckRunInput{
+			Output: &CheckRunOutput{
+				Title:       output.Title,
+				Summary:     output.Summary,
+				Annotations: batch,
+			},
+		}
+		if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/check-runs/%d", owner, name, checkRunID), body, nil); err != nil {
+			return fmt.Errorf("unable to add check run annotations: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCheckRun_BatchesAnnotations(t *testing.T) {
+	var batches []int
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body CheckRunInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.NotNil(t, body.Output)
+		require.Equal(t, "lint", body.Output.Title)
+		batches = append(batches, len(body.Output.Annotations))
+		if r.Method == http.MethodPost {
+			require.Equal(t, "/repos/o/r/check-runs", r.URL.Path)
+			require.Equal(t, "abc", body.HeadSHA)
+		} else {
+			require.Equal(t, "/repos/o/r/check-runs/42", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"id":42,"name":"lint","status":"completed","conclusion":"failure"}`))
+	})
+	annotations := make([]CheckRunAnnotation, 120)
+	for i := range annotations {
+		annotations[i] = CheckRunAnnotation{Path: "main.go", StartLine: i + 1, EndLine: i + 1, AnnotationLevel: AnnotationWarning, Message: "unused"}
+	}
+	run, err := g.CreateCheckRun(context.Background(), "o", "r", CheckRunInput{
+		Name:       "lint",
+		HeadSHA:    "abc",
+		Status:     CheckRunCompleted,
+		Conclusion: CheckRunFailure,
+		Output:     &CheckRunOutput{Title: "lint", Summary: "120 warnings", Annotations: annotations},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), run.ID)
+	require.Equal(t, CheckRunFailure, run.Conclusion)
+	require.Equal(t, []int{50, 50, 20}, batches)
+}
+
+func TestUpdateCheckRun_NoOutput(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		_, _ = w.Write([]byte(`{"id":7,"status":"in_progress"}`))
+	})
+	run, err := g.UpdateCheckRun(context.Background(), "o", "r", 7, CheckRunInput{Status: CheckRunInProgress})
+	require.NoError(t, err)
+	require.Equal(t, CheckRunInProgress, run.Status)
+}
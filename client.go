@@ -0,0 +1,230 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type clientOptions struct {
+	config NewGQLClientConfig
+	logger *zap.Logger
+}
+
+// Option configures a client created by NewClient
+type Option func(*clientOptions)
+
+// WithToken authenticates with a personal access token or any other OAuth token
+func WithToken(token string) Option {
+	return func(o *clientOptions) {
+		o.config.Token = token
+	}
+}
+
+// WithAppAuth authenticates as a GitHub App installation using the PEM encoded private key
+func WithAppAuth(appID int64, installationID int64, pemKey []byte) Option {
+	return func(o *clientOptions) {
+		o.config.AppID = appID
+		o.config.InstallationID = installationID
+		o.config.PEMKey = string(pemKey)
+	}
+}
+
+// WithAppAuthFromFile authenticates as a GitHub App installation using the private key stored at pemKeyLoc
+func WithAppAuthFromFile(appID int64, installationID int64, pemKeyLoc string) Option {
+	return func(o *clientOptions) {
+		o.config.AppID = appID
+		o.config.InstallationID = installationID
+		o.config.PEMKeyLoc = pemKeyLoc
+	}
+}
+
+// WithTokenProvider authenticates with the tokens of provider
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(o *clientOptions) {
+		o.config.TokenProvider = provider
+	}
+}
+
+// WithDeviceFlow logs the user in with the OAuth device flow of the OAuth or GitHub App clientID
+func WithDeviceFlow(clientID string, scopes ...string) Option {
+	return func(o *clientOptions) {
+		o.config.DeviceFlow = &DeviceFlow{
+			ClientID: clientID,
+			Scopes:   scopes,
+		}
+	}
+}
+
+// WithBaseURL points the client at a GitHub Enterprise Server REST root, such as https://ghe.example.com/api/v3
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.config.BaseURL = baseURL
+	}
+}
+
+// WithLogger sets the logger.  Requests are logged when it has debug enabled.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithTransport sends every request through rt, below authentication, retries and caching.  It replaces the tuned
+// default transport and WithTransportTuning.  The client builds its own http.Client around rt, so client level settings
+// such as Timeout, Jar and CheckRedirect do not apply; use context deadlines and WithTimeouts instead.  A nil rt keeps
+// the default transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		if rt != nil {
+			o.config.Rt = rt
+		}
+	}
+}
+
+// WithTransportTuning builds the base transport with the given connection pooling settings
+func WithTransportTuning(tuning TransportTuning) Option {
+	return func(o *clientOptions) {
+		o.config.TransportTuning = &tuning
+	}
+}
+
+// WithCacheTTL sets how long lookups such as FindPRForBranch are cached
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.CacheTTL = ttl
+	}
+}
+
+// WithPRCacheTTL sets how long FindPRForBranch results are cached
+func WithPRCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.PRCacheTTL = ttl
+	}
+}
+
+// WithPRNegativeCacheTTL sets how long FindPRForBranch remembers that a branch has no PR
+func WithPRNegativeCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.PRNegativeCacheTTL = ttl
+	}
+}
+
+// WithoutPRCache makes every FindPRForBranch call query GitHub
+func WithoutPRCache() Option {
+	return func(o *clientOptions) {
+		o.config.DisablePRCache = true
+	}
+}
+
+// WithRepositoryCacheTTL sets how long RepositoryInfo results are cached
+func WithRepositoryCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.RepositoryCacheTTL = ttl
+	}
+}
+
+// WithSelfCacheTTL caps how long the Self result of a token is cached
+func WithSelfCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.SelfCacheTTL = ttl
+	}
+}
+
+// WithCacheMaxEntries bounds each lookup cache to n entries
+func WithCacheMaxEntries(n int) Option {
+	return func(o *clientOptions) {
+		o.config.CacheMaxEntries = n
+	}
+}
+
+// WithTokenRefreshMargin renews installation tokens once they have less than margin left to live
+func WithTokenRefreshMargin(margin time.Duration) Option {
+	return func(o *clientOptions) {
+		o.config.TokenRefreshMargin = margin
+	}
+}
+
+// WithBackgroundTokenRefresh renews expiring tokens in the background ahead of calls, and mints the first one
+// asynchronously.  See NewGQLClientConfig.BackgroundTokenRefresh.
+func WithBackgroundTokenRefresh() Option {
+	return func(o *clientOptions) {
+		o.config.BackgroundTokenRefresh = true
+	}
+}
+
+// WithScheduler sends the client's requests through scheduler as tenant, sharing its concurrency cap fairly with
+// the other tenants
+func WithScheduler(scheduler *FairScheduler, tenant string) Option {
+	return func(o *clientOptions) {
+		o.config.Scheduler = scheduler
+		o.config.Tenant = tenant
+	}
+}
+
+// WithMaxConcurrentRequests caps the requests the client has in flight at once.  Waiting calls are served by their
+// reqmeta.Priority.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(o *clientOptions) {
+		o.config.MaxConcurrentRequests = n
+	}
+}
+
+// WithTimeouts bounds the calls made with a context without deadline, by whether they read, write or dispatch a
+// workflow
+func WithTimeouts(policy TimeoutPolicy) Option {
+	return func(o *clientOptions) {
+		o.config.Timeouts = policy
+	}
+}
+
+// WithRequestIDHeader sends the reqmeta.RequestID of calls in header instead of DefaultRequestIDHeader
+func WithRequestIDHeader(header string) Option {
+	return func(o *clientOptions) {
+		o.config.RequestIDHeader = header
+	}
+}
+
+// WithCacheInvalidationHook calls hook whenever cached lookups are dropped
+func WithCacheInvalidationHook(hook func(CacheInvalidation)) Option {
+	return func(o *clientOptions) {
+		o.config.OnCacheInvalidate = hook
+	}
+}
+
+// WithMetrics reports every request the client sends to metrics
+func WithMetrics(metrics Metrics) Option {
+	return func(o *clientOptions) {
+		o.config.Metrics = metrics
+	}
+}
+
+// WithSecretSealer encrypts Actions secret values with sealer instead of DefaultSecretSealer, for example to seal them
+// in an HSM
+func WithSecretSealer(sealer SecretSealer) Option {
+	return func(o *clientOptions) {
+		o.config.SecretSealer = sealer
+	}
+}
+
+// WithConditionalCache revalidates REST GET responses stored in cache instead of downloading them again.  Use
+// NewMemoryConditionalCache for an in memory cache.
+func WithConditionalCache(cache ConditionalCache) Option {
+	return func(o *clientOptions) {
+		o.config.ConditionalCache = cache
+	}
+}
+
+// NewClient creates a GitHub client configured by opts.  Anything not set by an option falls back to
+// DefaultGQLClientConfig, the same way NewGQLClient does.
+func NewClient(ctx context.Context, opts ...Option) (GitHub, error) {
+	o := clientOptions{
+		logger: zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return NewGQLClient(ctx, o.logger, &o.config)
+}
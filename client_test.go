@@ -0,0 +1,43 @@
+package gogithub
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeGithubConfigs(t *testing.T) {
+	defaults := &NewGQLClientConfig{
+		Token:    "env-token",
+		PEMKey:   "env-pem",
+		CacheTTL: time.Minute,
+	}
+	ret := mergeGithubConfigs(&NewGQLClientConfig{AppID: 1, PEMKey: "my-pem"}, defaults)
+	require.Equal(t, "", ret.Token)
+	require.Equal(t, "my-pem", ret.PEMKey)
+	require.Equal(t, time.Minute, ret.CacheTTL)
+
+	ret = mergeGithubConfigs(&NewGQLClientConfig{CacheTTL: time.Second}, defaults)
+	require.Equal(t, "env-token", ret.Token)
+	require.Equal(t, "env-pem", ret.PEMKey)
+	require.Equal(t, time.Second, ret.CacheTTL)
+}
+
+func TestNewGQLClientConfig_URLs(t *testing.T) {
+	cfg := &NewGQLClientConfig{}
+	require.Equal(t, "https://api.github.com", cfg.restBaseURL())
+	require.Equal(t, "https://api.github.com/graphql", cfg.graphqlURL())
+	cfg.BaseURL = "https://ghe.example.com/api/v3/"
+	require.Equal(t, "https://ghe.example.com/api/v3", cfg.restBaseURL())
+	require.Equal(t, "https://ghe.example.com/api/graphql", cfg.graphqlURL())
+}
+
+func TestWithTransport(t *testing.T) {
+	var o clientOptions
+	WithTransport(nil)(&o)
+	require.Nil(t, o.config.Rt)
+	WithTransport(http.DefaultTransport)(&o)
+	require.Equal(t, http.DefaultTransport, o.config.Rt)
+}
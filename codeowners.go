@@ -0,0 +1,156 @@
+package gogithub
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// codeownersLocations are where GitHub looks for a CODEOWNERS file, in order
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is one line of a CODEOWNERS file
+type CodeownersRule struct {
+	Pattern string
+	// Owners are @user, @org/team or email owners.  Empty means matching files have no owner.
+	Owners []string
+	// Line is the 1 based line number of the rule
+	Line int
+	re   *regexp.Regexp
+}
+
+// Codeowners is a parsed CODEOWNERS file
+type Codeowners struct {
+	// Path is where the file was found, or empty if the repository has none
+	Path  string
+	Rules []CodeownersRule
+}
+
+// ParseCodeowners parses a CODEOWNERS file.  Like GitHub, it skips lines it cannot understand, such as ! negations
+// and [ ] ranges.
+func ParseCodeowners(b []byte) *Codeowners {
+	var ret Codeowners
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		re, ok := codeownersRegexp(fields[0])
+		if !ok {
+			continue
+		}
+		rule := CodeownersRule{Pattern: fields[0], Line: line, re: re}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		ret.Rules = append(ret.Rules, rule)
+	}
+	return &ret
+}
+
+// codeownersRegexp translates a CODEOWNERS pattern, which follows .gitignore rules, into a regexp over slash
+// separated repository paths
+func codeownersRegexp(pattern string) (*regexp.Regexp, bool) {
+	if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]\\") {
+		return nil, false
+	}
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, false
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case p[i:] == "**":
+			sb.WriteString(".*")
+			i++
+		case p[i] == '*':
+			sb.WriteString("[^/]*")
+		case p[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	lastSegment := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		// A directory pattern owns everything beneath the directory
+		sb.WriteString("/.*")
+	case !strings.Contains(lastSegment, "*"):
+		// The pattern may name a directory, which owns everything beneath it.  docs/* however only owns the files
+		// directly in docs.
+		sb.WriteString("(?:/.*)?")
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	return re, err == nil
+}
+
+// Matches is true if the rule's pattern matches path
+func (r CodeownersRule) Matches(path string) bool {
+	return r.re != nil && r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// OwnersOf returns the owners of path.  As on GitHub, the last matching rule wins.  Nil means path has no owner.
+func (c *Codeowners) OwnersOf(path string) []string {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].Matches(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// ResolveOwnersForPaths returns the owners of each of paths.  Every path is a key; unowned paths map to nil.
+func (c *Codeowners) ResolveOwnersForPaths(paths []string) map[string][]string {
+	ret := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		ret[p] = c.OwnersOf(p)
+	}
+	return ret
+}
+
+// GetCodeowners fetches and parses the CODEOWNERS file of a repository at ref, looking where GitHub does.  ref empty
+// means the default branch.  A repository without a CODEOWNERS file returns a Codeowners with no rules.
+func (g *GithubGraphqlAPI) GetCodeowners(ctx context.Context, owner string, name string, ref string) (_ *Codeowners, err error) {
+	ctx = withOperation(ctx, "GetCodeowners")
+	defer annotateError(&err, OperationError{Operation: "GetCodeowners", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetCodeowners", zap.String("owner", owner), zap.String("name", name), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done GetCodeowners")
+	for _, path := range codeownersLocations {
+		b, err := g.GetFileContents(ctx, owner, name, path, ref)
+		var restErr *RESTError
+		switch {
+		case err == nil:
+			ret := ParseCodeowners(b)
+			ret.Path = path
+			return ret, nil
+		case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		default:
+			return nil, fmt.Errorf("failed to get CODEOWNERS: %w", err)
+		}
+	}
+	return &Codeowners{}, nil
+}
@@ -0,0 +1,88 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeownersPatterns(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*", "a/b/c.go", true},
+		{"*.js", "web/app.js", true},
+		{"*.js", "web/app.ts", false},
+		{"/build/logs/", "build/logs/x/y.log", true},
+		{"/build/logs/", "src/build/logs/y.log", false},
+		{"apps/", "src/apps/main.go", true},
+		{"docs/*", "docs/intro.md", true},
+		{"docs/*", "docs/guides/setup.md", false},
+		{"docs/*", "src/docs/intro.md", false},
+		{"/scripts", "scripts/deploy.sh", true},
+		{"**/logs", "deep/er/logs/a.log", true},
+		{"**/logs", "logs/a.log", true},
+		{"docs/**", "docs/guides/setup.md", true},
+		{"src/**/test", "src/a/b/test/x.go", true},
+		{"src/**/test", "src/test/x.go", true},
+		{"README.md", "sub/README.md", true},
+		{"?.go", "a.go", true},
+		{"?.go", "ab.go", false},
+	}
+	for _, c := range cases {
+		re, ok := codeownersRegexp(c.pattern)
+		require.True(t, ok, c.pattern)
+		require.Equal(t, c.match, re.MatchString(c.path), "%s ~ %s", c.pattern, c.path)
+	}
+	for _, p := range []string{"!docs", "[ab].go", "/"} {
+		_, ok := codeownersRegexp(p)
+		require.False(t, ok, p)
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	c := ParseCodeowners([]byte(`# Default owners
+*       @acme/platform
+
+*.go    @acme/backend @alice # Go code
+/docs/  docs@example.com
+!skip   @nobody
+/docs/generated/
+`))
+	require.Len(t, c.Rules, 4)
+	require.Equal(t, CodeownersRule{Pattern: "*.go", Owners: []string{"@acme/backend", "@alice"}, Line: 4}, withoutRegexp(c.Rules[1]))
+	require.Equal(t, map[string][]string{
+		"main.go":               {"@acme/backend", "@alice"},
+		"Makefile":              {"@acme/platform"},
+		"docs/index.md":         {"docs@example.com"},
+		"docs/generated/api.md": nil,
+	}, c.ResolveOwnersForPaths([]string{"main.go", "Makefile", "docs/index.md", "docs/generated/api.md"}))
+}
+
+func withoutRegexp(r CodeownersRule) CodeownersRule {
+	r.re = nil
+	return r
+}
+
+func TestGetCodeowners(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/contents/.github/CODEOWNERS":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "/repos/o/r/contents/CODEOWNERS":
+			require.Equal(t, "release", r.URL.Query().Get("ref"))
+			_, _ = w.Write([]byte(`{"type":"file","encoding":"base64","content":"KiBAYWxpY2UK\n"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	c, err := g.GetCodeowners(context.Background(), "o", "r", "release")
+	require.NoError(t, err)
+	require.Equal(t, "CODEOWNERS", c.Path)
+	require.Equal(t, []string{"@alice"}, c.OwnersOf("any/file"))
+}
@@ -0,0 +1,79 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// RepositoryPermission is a repository role, as accepted by the REST API
+type RepositoryPermission string
+
+const (
+	PermissionRead     RepositoryPermission = "pull"
+	PermissionTriage   RepositoryPermission = "triage"
+	PermissionWrite    RepositoryPermission = "push"
+	PermissionMaintain RepositoryPermission = "maintain"
+	PermissionAdmin    RepositoryPermission = "admin"
+)
+
+// AddTeamToRepository grants the team slug of org permission on owner/name, or changes the permission it has
+func (g *GithubGraphqlAPI) AddTeamToRepository(ctx context.Context, org string, slug string, owner string, name string, permission RepositoryPermission) (err error) {
+	ctx = withOperation(ctx, "AddTeamToRepository")
+	defer annotateError(&err, OperationError{Operation: "AddTeamToRepository", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("AddTeamToRepository", zap.String("org", org), zap.String("slug", slug), zap.String("owner", owner), zap.String("name", name), zap.String("permission", string(permission)))
+	defer g.logger(ctx).Debug("Done AddTeamToRepository")
+	path := fmt.Sprintf("/orgs/%s/teams/%s/repos/%s/%s", org, slug, owner, name)
+	if err := g.doREST(ctx, http.MethodPut, path, map[string]RepositoryPermission{"permission": permission}, nil); err != nil {
+		return fmt.Errorf("failed to add team to repository: %w", err)
+	}
+	return nil
+}
+
+// AddCollaborator grants user permission on owner/name.  Users outside the organization receive an invitation and only
+// get access once they accept it.
+func (g *GithubGraphqlAPI) AddCollaborator(ctx context.Context, owner string, name string, user string, permission RepositoryPermission) (err error) {
+	ctx = withOperation(ctx, "AddCollaborator")
+	defer annotateError(&err, OperationError{Operation: "AddCollaborator", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("AddCollaborator", zap.String("owner", owner), zap.String("name", name), zap.String("user", user), zap.String("permission", string(permission)))
+	defer g.logger(ctx).Debug("Done AddCollaborator")
+	path := fmt.Sprintf("/repos/%s/%s/collaborators/%s", owner, name, user)
+	if err := g.doREST(ctx, http.MethodPut, path, map[string]RepositoryPermission{"permission": permission}, nil); err != nil {
+		return fmt.Errorf("failed to add collaborator: %w", err)
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) RemoveCollaborator(ctx context.Context, owner string, name string, user string) (err error) {
+	ctx = withOperation(ctx, "RemoveCollaborator")
+	defer annotateError(&err, OperationError{Operation: "RemoveCollaborator", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("RemoveCollaborator", zap.String("owner", owner), zap.String("name", name), zap.String("user", user))
+	defer g.logger(ctx).Debug("Done RemoveCollaborator")
+	if err := g.doREST(ctx, http.MethodDelete, fmt.Sprintf("/repos/%s/%s/collaborators/%s", owner, name, user), nil, nil); err != nil {
+		return fmt.Errorf("failed to remove collaborator: %w", err)
+	}
+	return nil
+}
+
+// GetRepositoryPermission returns the role user has on owner/name through any team, collaboration or organization
+// membership: admin, maintain, write, triage, read, or none.
+func (g *GithubGraphqlAPI) GetRepositoryPermission(ctx context.Context, owner string, name string, user string) (_ string, err error) {
+	ctx = withOperation(ctx, "GetRepositoryPermission")
+	defer annotateError(&err, OperationError{Operation: "GetRepositoryPermission", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetRepositoryPermission", zap.String("owner", owner), zap.String("name", name), zap.String("user", user))
+	defer g.logger(ctx).Debug("Done GetRepositoryPermission")
+	var resp struct {
+		Permission string `json:"permission"`
+		RoleName   string `json:"role_name"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/collaborators/%s/permission", owner, name, user), nil, &resp); err != nil {
+		return "", fmt.Errorf("failed to get repository permission: %w", err)
+	}
+	// role_name distinguishes maintain and triage, which permission folds into write and read
+	if resp.RoleName != "" {
+		return resp.RoleName, nil
+	}
+	return resp.Permission, nil
+}
@@ -0,0 +1,32 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTeamToRepository(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "/orgs/cresta/teams/sre/repos/cresta/api", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "maintain", body["permission"])
+		w.WriteHeader(http.StatusNoContent)
+	})
+	require.NoError(t, g.AddTeamToRepository(context.Background(), "cresta", "sre", "cresta", "api", PermissionMaintain))
+}
+
+func TestGetRepositoryPermission(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/collaborators/alice/permission", r.URL.Path)
+		_, _ = w.Write([]byte(`{"permission":"write","role_name":"maintain"}`))
+	})
+	permission, err := g.GetRepositoryPermission(context.Background(), "o", "r", "alice")
+	require.NoError(t, err)
+	require.Equal(t, "maintain", permission)
+}
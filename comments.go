@@ -0,0 +1,167 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// IssueComment is a comment in the conversation of an issue or pull request
+type IssueComment struct {
+	ID int64
+	// NodeID is the GraphQL ID of the comment, the subject AddReaction takes
+	NodeID    githubv4.ID
+	Author    string
+	Body      string
+	HTMLURL   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type restIssueComment struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c *restIssueComment) toIssueComment() IssueComment {
+	return IssueComment{
+		ID:        c.ID,
+		NodeID:    githubv4.ID(c.NodeID),
+		Author:    c.User.Login,
+		Body:      c.Body,
+		HTMLURL:   c.HTMLURL,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// ListPRComments returns every conversation comment of a pull request, oldest first.  Review comments on the diff
+// are not included; see ListReviewThreads.
+func (g *GithubGraphqlAPI) ListPRComments(ctx context.Context, owner string, name string, number int64) ([]IssueComment, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[IssueComment], err error) {
+		ctx = withOperation(ctx, "ListPRComments")
+		defer annotateError(&err, OperationError{Operation: "ListPRComments", Owner: owner, Repo: name, Number: number})
+		g.logger(ctx).Debug("ListPRComments", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListPRComments")
+		var comments []restIssueComment
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/issues/%d/comments?per_page=100&page=%d", owner, name, number, restPageNumber(cursor)), nil, &comments); err != nil {
+			return Page[IssueComment]{}, fmt.Errorf("failed to list comments: %w", err)
+		}
+		items := make([]IssueComment, 0, len(comments))
+		for i := range comments {
+			items = append(items, comments[i].toIssueComment())
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// UpdateComment replaces the body of an issue or pull request comment
+func (g *GithubGraphqlAPI) UpdateComment(ctx context.Context, owner string, name string, commentID int64, body string) (_ *IssueComment, err error) {
+	ctx = withOperation(ctx, "UpdateComment")
+	defer annotateError(&err, OperationError{Operation: "UpdateComment", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateComment", zap.String("owner", owner), zap.String("name", name), zap.Int64("commentID", commentID))
+	defer g.logger(ctx).Debug("Done UpdateComment")
+	var resp restIssueComment
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/issues/comments/%d", owner, name, commentID), map[string]string{"body": body}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update comment %d: %w", commentID, err)
+	}
+	ret := resp.toIssueComment()
+	return &ret, nil
+}
+
+// DeleteComment deletes an issue or pull request comment
+func (g *GithubGraphqlAPI) DeleteComment(ctx context.Context, owner string, name string, commentID int64) (err error) {
+	ctx = withOperation(ctx, "DeleteComment")
+	defer annotateError(&err, OperationError{Operation: "DeleteComment", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("DeleteComment", zap.String("owner", owner), zap.String("name", name), zap.Int64("commentID", commentID))
+	defer g.logger(ctx).Debug("Done DeleteComment")
+	if err := g.doREST(ctx, http.MethodDelete, fmt.Sprintf("/repos/%s/%s/issues/comments/%d", owner, name, commentID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete comment %d: %w", commentID, err)
+	}
+	return nil
+}
+
+// AddReaction reacts with content to subjectID, the node ID of an issue, pull request or comment.  Reacting twice
+// with the same content is not an error.
+func (g *GithubGraphqlAPI) AddReaction(ctx context.Context, subjectID githubv4.ID, content githubv4.ReactionContent) (err error) {
+	ctx = withOperation(ctx, "AddReaction")
+	defer annotateError(&err, OperationError{Operation: "AddReaction"})
+	g.logger(ctx).Debug("AddReaction", zap.Any("subjectID", subjectID), zap.String("content", string(content)))
+	defer g.logger(ctx).Debug("Done AddReaction")
+	var ret struct {
+		AddReaction struct {
+			Reaction struct {
+				Content githubv4.ReactionContent
+			}
+		} `graphql:"addReaction(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.AddReactionInput{
+		SubjectID: subjectID,
+		Content:   content,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// commentMarker is the hidden HTML comment UpsertPRComment tags its comments with
+func commentMarker(marker string) string {
+	return fmt.Sprintf("<!-- %s -->", marker)
+}
+
+// UpsertPRComment keeps a single "sticky" comment per marker on a pull request: it edits the oldest comment
+// containing the hidden marker to body, or creates the comment if there is none.  The marker, such as
+// "terraform-plan", is appended to body as an HTML comment.  Only comments written by the client's own account match,
+// so a marker pasted by someone else is never overwritten.
+func (g *GithubGraphqlAPI) UpsertPRComment(ctx context.Context, owner string, name string, number int64, marker string, body string) (_ *IssueComment, err error) {
+	ctx = withOperation(ctx, "UpsertPRComment")
+	defer annotateError(&err, OperationError{Operation: "UpsertPRComment", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("UpsertPRComment", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("marker", marker))
+	defer g.logger(ctx).Debug("Done UpsertPRComment")
+	tag := commentMarker(marker)
+	if !strings.Contains(body, tag) {
+		body = body + "\n\n" + tag
+	}
+	self, err := g.Self(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the commenter: %w", err)
+	}
+	comments, err := g.ListPRComments(ctx, owner, name, number)
+	if err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		// Anyone can paste the marker into a comment; only a comment of ours is the one to edit
+		if !strings.Contains(comments[i].Body, tag) || !sameLogin(comments[i].Author, self) {
+			continue
+		}
+		if comments[i].Body == body {
+			return &comments[i], nil
+		}
+		return g.UpdateComment(ctx, owner, name, comments[i].ID, body)
+	}
+	var resp restIssueComment
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, name, number), map[string]string{"body": body}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	ret := resp.toIssueComment()
+	return &ret, nil
+}
+
+// sameLogin reports whether two logins are the same account.  REST names GitHub Apps with a [bot] suffix that the
+// GraphQL viewer login lacks.
+func sameLogin(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "[bot]"), strings.TrimSuffix(b, "[bot]"))
+}
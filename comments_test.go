@@ -0,0 +1,105 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComments(t *testing.T) {
+	var patched map[string]string
+	var deleted bool
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/issues/7/comments":
+			require.Equal(t, "1", r.URL.Query().Get("page"))
+			_, _ = w.Write([]byte(`[{"id":11,"node_id":"IC_11","user":{"login":"bot"},"body":"hello","created_at":"2024-05-01T10:00:00Z"}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/issues/comments/11":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			_, _ = w.Write([]byte(`{"id":11,"node_id":"IC_11","user":{"login":"bot"},"body":"updated"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/o/r/issues/comments/11":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	comments, err := g.ListPRComments(ctx, "o", "r", 7)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	require.Equal(t, int64(11), comments[0].ID)
+	require.Equal(t, githubv4.ID("IC_11"), comments[0].NodeID)
+	require.Equal(t, "bot", comments[0].Author)
+
+	c, err := g.UpdateComment(ctx, "o", "r", 11, "updated")
+	require.NoError(t, err)
+	require.Equal(t, "updated", c.Body)
+	require.Equal(t, map[string]string{"body": "updated"}, patched)
+
+	require.NoError(t, g.DeleteComment(ctx, "o", "r", 11))
+	require.True(t, deleted)
+}
+
+func TestUpsertPRComment(t *testing.T) {
+	existing := `[{"id":1,"body":"unrelated"}]`
+	var created, patched map[string]string
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql":
+			_, _ = w.Write([]byte(`{"data":{"viewer":{"login":"ci-app","id":"BOT_1","databaseId":5}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/issues/7/comments":
+			_, _ = w.Write([]byte(existing))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/issues/7/comments":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"id":2,"body":"plan"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/issues/comments/2":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+			_, _ = w.Write([]byte(`{"id":2}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	c, err := g.UpsertPRComment(ctx, "o", "r", 7, "terraform-plan", "plan: 1 to add")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), c.ID)
+	require.Equal(t, "plan: 1 to add\n\n<!-- terraform-plan -->", created["body"])
+	require.Nil(t, patched)
+
+	existing = `[{"id":1,"body":"unrelated"},{"id":2,"user":{"login":"ci-app[bot]"},"body":"plan: 1 to add\n\n<!-- terraform-plan -->"}]`
+	c, err = g.UpsertPRComment(ctx, "o", "r", 7, "terraform-plan", "plan: 1 to add")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), c.ID)
+	require.Nil(t, patched)
+
+	_, err = g.UpsertPRComment(ctx, "o", "r", 7, "terraform-plan", "plan: no changes")
+	require.NoError(t, err)
+	require.Equal(t, "plan: no changes\n\n<!-- terraform-plan -->", patched["body"])
+}
+
+func TestUpsertPRComment_OtherAuthor(t *testing.T) {
+	var created map[string]string
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql":
+			_, _ = w.Write([]byte(`{"data":{"viewer":{"login":"ci-app","id":"BOT_1","databaseId":5}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/issues/7/comments":
+			_, _ = w.Write([]byte(`[{"id":1,"user":{"login":"someone"},"body":"copied\n\n<!-- terraform-plan -->"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/issues/7/comments":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"id":2}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	// A marker in a comment of someone else is left alone and a new comment is created
+	c, err := g.UpsertPRComment(context.Background(), "o", "r", 7, "terraform-plan", "plan: 1 to add")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), c.ID)
+	require.Equal(t, "plan: 1 to add\n\n<!-- terraform-plan -->", created["body"])
+}
@@ -0,0 +1,301 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// GitRef is a Git reference and the object it points at
+type GitRef struct {
+	// Ref is the full name of the reference, such as refs/heads/main
+	Ref string
+	// ObjectType is commit, or tag for annotated tags
+	ObjectType string
+	// OID is the SHA of the object.  For annotated tags it is the tag object; ResolveCommitSHA peels it to the commit.
+	OID string
+}
+
+type restGitRef struct {
+	Ref    string `json:"ref"`
+	Object struct {
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+	} `json:"object"`
+}
+
+// GetRef returns the reference ref, given by its full name like refs/tags/v1.0.0, or without the refs/ prefix like
+// heads/main.  A missing reference is a *RESTError with status 404.
+func (g *GithubGraphqlAPI) GetRef(ctx context.Context, owner string, name string, ref string) (_ *GitRef, err error) {
+	ctx = withOperation(ctx, "GetRef")
+	defer annotateError(&err, OperationError{Operation: "GetRef", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetRef", zap.String("owner", owner), zap.String("name", name), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done GetRef")
+	var ret restGitRef
+	path := fmt.Sprintf("/repos/%s/%s/git/ref/%s", owner, name, escapePath(strings.TrimPrefix(ref, "refs/")))
+	if err := g.doREST(ctx, http.MethodGet, path, nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get ref %s: %w", ref, err)
+	}
+	return &GitRef{
+		Ref:        ret.Ref,
+		ObjectType: ret.Object.Type,
+		OID:        ret.Object.SHA,
+	}, nil
+}
+
+// CommitFile is one file changed by a commit or a comparison
+type CommitFile = PullRequestFile
+
+// Commit is a commit with the changes it makes
+type Commit struct {
+	OID     string
+	Message string
+	// Author is the git author.  Login is empty when the email is not linked to a GitHub user.
+	Author        CommitAuthor
+	AuthoredDate  time.Time
+	Committer     CommitAuthor
+	CommittedDate time.Time
+	// Parents are the SHAs of the parent commits, more than one for merge commits
+	Parents   []string
+	Additions int
+	Deletions int
+	// Files are the files the commit changes.  GitHub lists at most 300 files, and none for the commits of
+	// CompareCommits.
+	Files []CommitFile
+}
+
+type restCommitPerson struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+type restCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message   string           `json:"message"`
+		Author    restCommitPerson `json:"author"`
+		Committer restCommitPerson `json:"committer"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Committer *struct {
+		Login string `json:"login"`
+	} `json:"committer"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	Files []CommitFile `json:"files"`
+}
+
+func (c *restCommit) toCommit() Commit {
+	ret := Commit{
+		OID:           c.SHA,
+		Message:       c.Commit.Message,
+		Author:        CommitAuthor{Name: c.Commit.Author.Name, Email: c.Commit.Author.Email},
+		AuthoredDate:  c.Commit.Author.Date,
+		Committer:     CommitAuthor{Name: c.Commit.Committer.Name, Email: c.Commit.Committer.Email},
+		CommittedDate: c.Commit.Committer.Date,
+		Parents:       make([]string, 0, len(c.Parents)),
+		Additions:     c.Stats.Additions,
+		Deletions:     c.Stats.Deletions,
+		Files:         c.Files,
+	}
+	if c.Author != nil {
+		ret.Author.Login = c.Author.Login
+	}
+	if c.Committer != nil {
+		ret.Committer.Login = c.Committer.Login
+	}
+	for _, p := range c.Parents {
+		ret.Parents = append(ret.Parents, p.SHA)
+	}
+	return ret
+}
+
+// GetCommit returns the commit ref points at, with its changed files.  ref is a SHA, a branch or a tag.
+func (g *GithubGraphqlAPI) GetCommit(ctx context.Context, owner string, name string, ref string) (_ *Commit, err error) {
+	ctx = withOperation(ctx, "GetCommit")
+	defer annotateError(&err, OperationError{Operation: "GetCommit", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetCommit", zap.String("owner", owner), zap.String("name", name), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done GetCommit")
+	var ret restCommit
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits/%s", owner, name, escapePath(ref)), nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", ref, err)
+	}
+	commit := ret.toCommit()
+	return &commit, nil
+}
+
+// CommitComparison is how head differs from base
+type CommitComparison struct {
+	// Status is ahead, behind, identical or diverged, describing head relative to base
+	Status   string
+	AheadBy  int
+	BehindBy int
+	// MergeBaseOID is the SHA of the best common ancestor of base and head
+	MergeBaseOID string
+	// Commits are the commits reachable from head but not from base, oldest first
+	Commits []Commit
+	// Files are the files changed between the merge base and head.  GitHub lists at most 300 files.
+	Files []CommitFile
+}
+
+type restCommitComparison struct {
+	Status          string       `json:"status"`
+	AheadBy         int          `json:"ahead_by"`
+	BehindBy        int          `json:"behind_by"`
+	TotalCommits    int          `json:"total_commits"`
+	MergeBaseCommit restCommit   `json:"merge_base_commit"`
+	Commits         []restCommit `json:"commits"`
+	Files           []CommitFile `json:"files"`
+}
+
+const compareCommitsPerPage = 100
+
+// CompareCommits compares head to base, which are SHAs, branches or tags, following pagination to list every
+// commit.  Release notes between two tags are the Commits of CompareCommits(previousTag, tag).
+func (g *GithubGraphqlAPI) CompareCommits(ctx context.Context, owner string, name string, base string, head string) (_ *CommitComparison, err error) {
+	ctx = withOperation(ctx, "CompareCommits")
+	defer annotateError(&err, OperationError{Operation: "CompareCommits", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CompareCommits", zap.String("owner", owner), zap.String("name", name), zap.String("base", base), zap.String("head", head))
+	defer g.logger(ctx).Debug("Done CompareCommits")
+	// The comparison header comes from the first page; later pages only add commits
+	var ret *CommitComparison
+	listed := 0
+	commits, err := newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[Commit], error) {
+		var cmp restCommitComparison
+		path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s?per_page=%d&page=%d", owner, name, escapePath(base), escapePath(head), compareCommitsPerPage, restPageNumber(cursor))
+		if err := g.doREST(ctx, http.MethodGet, path, nil, &cmp); err != nil {
+			return Page[Commit]{}, fmt.Errorf("failed to compare %s...%s: %w", base, head, err)
+		}
+		if ret == nil {
+			ret = &CommitComparison{
+				Status:       cmp.Status,
+				AheadBy:      cmp.AheadBy,
+				BehindBy:     cmp.BehindBy,
+				MergeBaseOID: cmp.MergeBaseCommit.SHA,
+				Files:        cmp.Files,
+			}
+		}
+		items := make([]Commit, 0, len(cmp.Commits))
+		for i := range cmp.Commits {
+			items = append(items, cmp.Commits[i].toCommit())
+		}
+		listed += len(items)
+		page := restPage(items, cursor, compareCommitsPerPage)
+		page.HasNext = page.HasNext && listed < cmp.TotalCommits
+		return page, nil
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret.Commits = commits
+	if ret.Commits == nil {
+		ret.Commits = []Commit{}
+	}
+	return ret, nil
+}
+
+// CommitSignature is the signature of a commit and whether GitHub verified it
+type CommitSignature struct {
+	// Signed is false for unsigned commits, in which case the other fields are empty
+	Signed bool
+	// Verified is whether GitHub verified the signature against a key of the signer
+	Verified bool
+	// State explains Verified, for example VALID, UNKNOWN_KEY, EXPIRED_KEY or NOT_SIGNING_KEY
+	State string
+	// Kind is GpgSignature, SshSignature or SmimeSignature
+	Kind string
+	// KeyID is the GPG key ID, the SSH key fingerprint, or the issuer of an S/MIME certificate
+	KeyID string
+	// Signer is the login of the user the key belongs to, when GitHub knows it
+	Signer string
+	// SignedByGitHub is set for commits GitHub signed itself, such as merges and web edits
+	SignedByGitHub bool
+}
+
+type commitSignatureQuery struct {
+	Typename          string `graphql:"__typename"`
+	IsValid           bool
+	State             string
+	WasSignedByGitHub bool
+	Signer            *struct {
+		Login string
+	}
+	GpgSignature struct {
+		KeyID string `graphql:"keyId"`
+	} `graphql:"... on GpgSignature"`
+	SshSignature struct {
+		KeyFingerprint string
+	} `graphql:"... on SshSignature"`
+	SmimeSignature struct {
+		Issuer struct {
+			CommonName string
+		}
+	} `graphql:"... on SmimeSignature"`
+}
+
+func (s *commitSignatureQuery) toCommitSignature() *CommitSignature {
+	if s == nil {
+		return &CommitSignature{State: "UNSIGNED"}
+	}
+	ret := &CommitSignature{
+		Signed:         true,
+		Verified:       s.IsValid && s.State == "VALID",
+		State:          s.State,
+		Kind:           s.Typename,
+		SignedByGitHub: s.WasSignedByGitHub,
+	}
+	switch s.Typename {
+	case "GpgSignature":
+		ret.KeyID = s.GpgSignature.KeyID
+	case "SshSignature":
+		ret.KeyID = s.SshSignature.KeyFingerprint
+	case "SmimeSignature":
+		ret.KeyID = s.SmimeSignature.Issuer.CommonName
+	}
+	if s.Signer != nil {
+		ret.Signer = s.Signer.Login
+	}
+	return ret
+}
+
+// GetCommitSignatureStatus returns whether the commit oid is signed, verified, and with which key
+func (g *GithubGraphqlAPI) GetCommitSignatureStatus(ctx context.Context, owner string, name string, oid string) (_ *CommitSignature, err error) {
+	ctx = withOperation(ctx, "GetCommitSignatureStatus")
+	defer annotateError(&err, OperationError{Operation: "GetCommitSignatureStatus", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetCommitSignatureStatus", zap.String("owner", owner), zap.String("name", name), zap.String("oid", oid))
+	defer g.logger(ctx).Debug("Done GetCommitSignatureStatus")
+	var query struct {
+		Repository struct {
+			Object *struct {
+				Commit struct {
+					Signature *commitSignatureQuery
+				} `graphql:"... on Commit"`
+			} `graphql:"object(oid: $oid)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+		"oid":   githubv4.GitObjectID(oid),
+	}
+	if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query commit signature: %w", err)
+	}
+	if query.Repository.Object == nil {
+		return nil, fmt.Errorf("failed to find commit %s", oid)
+	}
+	return query.Repository.Object.Commit.Signature.toCommitSignature(), nil
+}
@@ -0,0 +1,93 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRef(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/git/ref/tags/v1.0.0", r.URL.Path)
+		_, _ = w.Write([]byte(`{"ref":"refs/tags/v1.0.0","object":{"type":"tag","sha":"abc"}}`))
+	})
+	ref, err := g.GetRef(context.Background(), "o", "r", "refs/tags/v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, &GitRef{Ref: "refs/tags/v1.0.0", ObjectType: "tag", OID: "abc"}, ref)
+}
+
+func TestGetCommit(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/commits/main", r.URL.Path)
+		_, _ = w.Write([]byte(`{
+			"sha": "c2",
+			"commit": {
+				"message": "fix: thing",
+				"author": {"name": "A", "email": "a@example.com", "date": "2024-01-02T03:04:05Z"},
+				"committer": {"name": "GitHub", "email": "noreply@github.com", "date": "2024-01-03T03:04:05Z"}
+			},
+			"author": {"login": "a"},
+			"committer": null,
+			"parents": [{"sha": "c1"}],
+			"stats": {"additions": 3, "deletions": 1},
+			"files": [{"filename": "main.go", "status": "modified", "additions": 3, "deletions": 1, "changes": 4}]
+		}`))
+	})
+	c, err := g.GetCommit(context.Background(), "o", "r", "main")
+	require.NoError(t, err)
+	require.Equal(t, "c2", c.OID)
+	require.Equal(t, CommitAuthor{Name: "A", Email: "a@example.com", Login: "a"}, c.Author)
+	require.Equal(t, "", c.Committer.Login)
+	require.Equal(t, 2024, c.AuthoredDate.Year())
+	require.Equal(t, []string{"c1"}, c.Parents)
+	require.Equal(t, 3, c.Additions)
+	require.Equal(t, []CommitFile{{Path: "main.go", Status: "modified", Additions: 3, Deletions: 1, Changes: 4}}, c.Files)
+}
+
+func TestCompareCommits(t *testing.T) {
+	var pages []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/compare/v1.0.0...v1.1.0", r.URL.Path)
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := compareCommitsPerPage
+		if page == "2" {
+			n = 5
+		}
+		commits := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			commits = append(commits, fmt.Sprintf(`{"sha":"p%s-%d","commit":{"message":"m"}}`, page, i))
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"ahead","ahead_by":105,"behind_by":0,"total_commits":105,"merge_base_commit":{"sha":"base"},"commits":[%s],"files":[{"filename":"a"}]}`, strings.Join(commits, ","))
+	})
+	cmp, err := g.CompareCommits(context.Background(), "o", "r", "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, pages)
+	require.Equal(t, "ahead", cmp.Status)
+	require.Equal(t, 105, cmp.AheadBy)
+	require.Equal(t, "base", cmp.MergeBaseOID)
+	require.Len(t, cmp.Commits, 105)
+	require.Equal(t, "p2-4", cmp.Commits[104].OID)
+	require.Len(t, cmp.Files, 1)
+}
+
+func TestCommitSignatureQuery_ToCommitSignature(t *testing.T) {
+	var unsigned *commitSignatureQuery
+	require.Equal(t, &CommitSignature{State: "UNSIGNED"}, unsigned.toCommitSignature())
+
+	gpg := &commitSignatureQuery{Typename: "GpgSignature", IsValid: true, State: "VALID"}
+	gpg.GpgSignature.KeyID = "4AEE18F83AFDEB23"
+	gpg.Signer = &struct{ Login string }{Login: "octocat"}
+	require.Equal(t, &CommitSignature{Signed: true, Verified: true, State: "VALID", Kind: "GpgSignature", KeyID: "4AEE18F83AFDEB23", Signer: "octocat"}, gpg.toCommitSignature())
+
+	ssh := &commitSignatureQuery{Typename: "SshSignature", IsValid: false, State: "UNKNOWN_KEY"}
+	ssh.SshSignature.KeyFingerprint = "SHA256:abc"
+	sig := ssh.toCommitSignature()
+	require.True(t, sig.Signed)
+	require.False(t, sig.Verified)
+	require.Equal(t, "SHA256:abc", sig.KeyID)
+}
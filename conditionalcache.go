@@ -0,0 +1,170 @@
+package gogithub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultConditionalCacheTTL is how long NewMemoryConditionalCache keeps a response.  Entries are revalidated on every
+// request, so this only bounds how long an unused entry takes memory.
+const DefaultConditionalCacheTTL = 24 * time.Hour
+
+// DefaultConditionalCacheMaxEntries is how many responses NewMemoryConditionalCache keeps
+const DefaultConditionalCacheMaxEntries = 1000
+
+// DefaultConditionalCacheMaxBodySize is the largest response body ConditionalCacheTransport keeps.  Larger responses,
+// such as archives and logs, pass through uncached.
+const DefaultConditionalCacheMaxBodySize = 1 << 20
+
+// FromCacheHeader is set on responses ConditionalCacheTransport served from its cache after a 304
+const FromCacheHeader = "X-From-Cache"
+
+// CachedResponse is a successful GET response kept for revalidation
+type CachedResponse struct {
+	Header http.Header
+	Body   []byte
+}
+
+// ConditionalCache stores responses for ConditionalCacheTransport.  It must be safe for concurrent use.  An
+// *ExpireCache[string, *CachedResponse] is one.
+type ConditionalCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// NewMemoryConditionalCache returns an in memory ConditionalCache that drops entries unused for ttl, keeping at most
+// DefaultConditionalCacheMaxEntries.  If ttl is 0, DefaultConditionalCacheTTL is used.
+func NewMemoryConditionalCache(ttl time.Duration) *ExpireCache[string, *CachedResponse] {
+	if ttl == 0 {
+		ttl = DefaultConditionalCacheTTL
+	}
+	return &ExpireCache[string, *CachedResponse]{
+		DefaultExpiry: ttl,
+		MaxEntries:    DefaultConditionalCacheMaxEntries,
+	}
+}
+
+// ConditionalCacheTransport remembers the ETag and Last-Modified of GET responses and revalidates them with
+// If-None-Match and If-Modified-Since.  GitHub does not count 304 responses against the rate limit, so pollers that
+// mostly see unchanged resources become nearly free.  GraphQL requests are POSTs and always pass through.
+//
+// It must sit below the authenticating transport: responses are keyed by the Authorization header, so callers with
+// different credentials never see each other's data.
+type ConditionalCacheTransport struct {
+	Base  http.RoundTripper
+	Cache ConditionalCache
+	// MaxBodySize is the largest body cached, in bytes.  Defaults to DefaultConditionalCacheMaxBodySize.
+	MaxBodySize int64
+}
+
+func (c *ConditionalCacheTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet || request.Header.Get("Range") != "" || hasConditionalHeaders(request) {
+		return c.Base.RoundTrip(request)
+	}
+	key := conditionalCacheKey(request)
+	cached, hit := c.Cache.Get(key)
+	if hit {
+		request = request.Clone(request.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			request.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			request.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+	resp, err := c.Base.RoundTrip(request)
+	if err != nil {
+		return resp, err
+	}
+	if hit && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		// Keep the entry alive for as long as it is used
+		c.Cache.Set(key, cached)
+		return cached.response(request, resp), nil
+	}
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+	maxBodySize := c.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultConditionalCacheMaxBodySize
+	}
+	if resp.ContentLength > maxBodySize {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > maxBodySize {
+		// Without a Content-Length the size only shows while reading; hand back what was read and the rest unread
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.Cache.Set(key, &CachedResponse{
+		Header: resp.Header.Clone(),
+		Body:   body,
+	})
+	return resp, nil
+}
+
+// response rebuilds the cached response, taking fresh headers such as the rate limit from the 304 GitHub sent
+func (c *CachedResponse) response(request *http.Request, notModified *http.Response) *http.Response {
+	header := c.Header.Clone()
+	for k, v := range notModified.Header {
+		header[k] = v
+	}
+	header.Set(FromCacheHeader, "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       request,
+	}
+}
+
+// hasConditionalHeaders reports whether the caller already made the request conditional, in which case it wants to
+// see the 304 itself
+func hasConditionalHeaders(request *http.Request) bool {
+	return request.Header.Get("If-None-Match") != "" || request.Header.Get("If-Modified-Since") != ""
+}
+
+// conditionalCacheKey identifies a response by URL, the headers GitHub varies it on, and a hash of the credentials
+func conditionalCacheKey(request *http.Request) string {
+	auth := sha256.Sum256([]byte(request.Header.Get("Authorization")))
+	return request.URL.String() + "\x00" + request.Header.Get("Accept") + "\x00" + hex.EncodeToString(auth[:])
+}
+
+// ConditionalCachedTransport wraps base so GET responses are revalidated against cache.  It returns base unchanged
+// if cache is nil.
+func ConditionalCachedTransport(base http.RoundTripper, cache ConditionalCache) http.RoundTripper {
+	if cache == nil {
+		return base
+	}
+	return &ConditionalCacheTransport{
+		Base:  base,
+		Cache: cache,
+	}
+}
+
+var (
+	_ http.RoundTripper = &ConditionalCacheTransport{}
+	_ ConditionalCache  = &ExpireCache[string, *CachedResponse]{}
+)
@@ -0,0 +1,102 @@
+package gogithub
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalCacheTransport(t *testing.T) {
+	var calls, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"name":"gogithub"}`))
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Transport: ConditionalCachedTransport(http.DefaultTransport, NewMemoryConditionalCache(0))}
+	get := func(auth string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/repos/o/r", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", auth)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+	resp := get("token a")
+	require.Empty(t, resp.Header.Get(FromCacheHeader))
+	_ = resp.Body.Close()
+
+	resp = get("token a")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(FromCacheHeader))
+	require.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"gogithub"}`, string(body))
+	_ = resp.Body.Close()
+	require.Equal(t, 1, notModified)
+
+	// Other credentials never share cached responses
+	resp = get("token b")
+	require.Empty(t, resp.Header.Get(FromCacheHeader))
+	_ = resp.Body.Close()
+	require.Equal(t, 3, calls)
+	require.Equal(t, 1, notModified)
+}
+
+func TestConditionalCacheTransport_SkipsUncacheable(t *testing.T) {
+	cache := NewMemoryConditionalCache(0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/etag" {
+			w.Header().Set("ETag", `"v1"`)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Transport: ConditionalCachedTransport(http.DefaultTransport, cache)}
+	resp, err := client.Get(srv.URL + "/plain")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	resp, err = client.Post(srv.URL+"/etag", "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Empty(t, cache.cache)
+}
+
+func TestConditionalCacheTransport_SkipsLargeBodies(t *testing.T) {
+	cache := NewMemoryConditionalCache(0)
+	body := `{"name":"a much larger body"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Path == "/streamed" {
+			// Flushing before the body is written leaves the length unknown
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	client := &http.Client{Transport: &ConditionalCacheTransport{Base: http.DefaultTransport, Cache: cache, MaxBodySize: 10}}
+	for _, path := range []string{"/sized", "/streamed"} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		got, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, body, string(got), path)
+	}
+	require.Empty(t, cache.cache)
+}
+
+func TestConditionalCachedTransport_Nil(t *testing.T) {
+	require.Equal(t, http.DefaultTransport, ConditionalCachedTransport(http.DefaultTransport, nil))
+}
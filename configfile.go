@@ -0,0 +1,195 @@
+package gogithub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AuthFileConfig are the credentials of a FileConfig.  Set Token, or AppID, InstallationID and a PEM key.
+type AuthFileConfig struct {
+	Token          string `yaml:"token" json:"token"`
+	AppID          int64  `yaml:"appId" json:"appId"`
+	InstallationID int64  `yaml:"installationId" json:"installationId"`
+	// PEMKeyFile is the path of the private key of the App
+	PEMKeyFile string `yaml:"pemKeyFile" json:"pemKeyFile"`
+	PEMKey     string `yaml:"pemKey" json:"pemKey"`
+}
+
+func (a AuthFileConfig) isZero() bool {
+	return a == AuthFileConfig{}
+}
+
+// CacheFileConfig are the lookup cache settings of a FileConfig
+type CacheFileConfig struct {
+	TTL            time.Duration `yaml:"ttl" json:"ttl"`
+	PRTTL          time.Duration `yaml:"prTtl" json:"prTtl"`
+	PRNegativeTTL  time.Duration `yaml:"prNegativeTtl" json:"prNegativeTtl"`
+	RepositoryTTL  time.Duration `yaml:"repositoryTtl" json:"repositoryTtl"`
+	SelfTTL        time.Duration `yaml:"selfTtl" json:"selfTtl"`
+	MaxEntries     int           `yaml:"maxEntries" json:"maxEntries"`
+	DisablePRCache bool          `yaml:"disablePrCache" json:"disablePrCache"`
+}
+
+// OrgFileConfig overrides the settings of a FileConfig for the repositories of one organization, such as an org on
+// a GitHub Enterprise Server or with its own App installation
+type OrgFileConfig struct {
+	// Auth replaces the credentials of the FileConfig when any of its fields is set
+	Auth    AuthFileConfig `yaml:"auth" json:"auth"`
+	BaseURL string         `yaml:"baseUrl" json:"baseUrl"`
+}
+
+// FileConfig is the client settings LoadConfig reads from a file.  Durations are written like "30s" or "5m".
+type FileConfig struct {
+	Auth AuthFileConfig `yaml:"auth" json:"auth"`
+	// BaseURL is the root of the REST API.  See NewGQLClientConfig.BaseURL.
+	BaseURL                string          `yaml:"baseUrl" json:"baseUrl"`
+	Cache                  CacheFileConfig `yaml:"cache" json:"cache"`
+	MaxConcurrentRequests  int             `yaml:"maxConcurrentRequests" json:"maxConcurrentRequests"`
+	Tenant                 string          `yaml:"tenant" json:"tenant"`
+	Timeouts               TimeoutPolicy   `yaml:"timeouts" json:"timeouts"`
+	AcceptedBackoff        AcceptedBackoff `yaml:"acceptedBackoff" json:"acceptedBackoff"`
+	TokenRefreshMargin     time.Duration   `yaml:"tokenRefreshMargin" json:"tokenRefreshMargin"`
+	BackgroundTokenRefresh bool            `yaml:"backgroundTokenRefresh" json:"backgroundTokenRefresh"`
+	RequestIDHeader        string          `yaml:"requestIdHeader" json:"requestIdHeader"`
+	// Orgs routes the calls about an organization to other credentials or another GitHub instance.  Keys are org
+	// logins.
+	Orgs map[string]OrgFileConfig `yaml:"orgs" json:"orgs"`
+}
+
+// configEnvOverrides are the env vars overriding FileConfig settings, so deployments can change them, or keep secrets
+// out of the file, without editing it
+var configEnvOverrides = []struct {
+	name  string
+	apply func(c *FileConfig, v string) error
+}{
+	{"GITHUB_TOKEN", func(c *FileConfig, v string) error { c.Auth.Token = v; return nil }},
+	{"GITHUB_APP_ID", func(c *FileConfig, v string) error { return parseEnvInt(v, &c.Auth.AppID) }},
+	{"GITHUB_INSTALLATION_ID", func(c *FileConfig, v string) error { return parseEnvInt(v, &c.Auth.InstallationID) }},
+	{"GITHUB_PEM_KEY_LOC", func(c *FileConfig, v string) error { c.Auth.PEMKeyFile = v; return nil }},
+	{"GITHUB_PEM_KEY", func(c *FileConfig, v string) error { c.Auth.PEMKey = v; return nil }},
+	{"GOGITHUB_BASE_URL", func(c *FileConfig, v string) error { c.BaseURL = v; return nil }},
+	{"GOGITHUB_CACHE_TTL", func(c *FileConfig, v string) error { return parseEnvDuration(v, &c.Cache.TTL) }},
+	{"GOGITHUB_MAX_CONCURRENT_REQUESTS", func(c *FileConfig, v string) error {
+		var n int64
+		err := parseEnvInt(v, &n)
+		c.MaxConcurrentRequests = int(n)
+		return err
+	}},
+	{"GOGITHUB_QUERY_TIMEOUT", func(c *FileConfig, v string) error { return parseEnvDuration(v, &c.Timeouts.Query) }},
+	{"GOGITHUB_MUTATION_TIMEOUT", func(c *FileConfig, v string) error { return parseEnvDuration(v, &c.Timeouts.Mutation) }},
+	{"GOGITHUB_DISPATCH_TIMEOUT", func(c *FileConfig, v string) error { return parseEnvDuration(v, &c.Timeouts.Dispatch) }},
+	{"GOGITHUB_TENANT", func(c *FileConfig, v string) error { c.Tenant = v; return nil }},
+}
+
+func parseEnvInt(v string, into *int64) error {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return err
+	}
+	*into = n
+	return nil
+}
+
+func parseEnvDuration(v string, into *time.Duration) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*into = d
+	return nil
+}
+
+// LoadConfig reads client settings from the YAML or JSON file at path.  The env vars of configEnvOverrides, such as
+// GITHUB_TOKEN or GOGITHUB_MAX_CONCURRENT_REQUESTS, override the file when set.
+func LoadConfig(path string) (*FileConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return parseConfig(b, os.Getenv)
+}
+
+func parseConfig(b []byte, getenv func(string) string) (*FileConfig, error) {
+	var ret FileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&ret); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	for _, o := range configEnvOverrides {
+		v := getenv(o.name)
+		if v == "" {
+			continue
+		}
+		if err := o.apply(&ret, v); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", o.name, err)
+		}
+	}
+	return &ret, nil
+}
+
+// ClientConfig returns the settings of the file as a NewGQLClientConfig.  Settings the file leaves out get their
+// defaults when the client is created.
+func (f *FileConfig) ClientConfig() *NewGQLClientConfig {
+	return &NewGQLClientConfig{
+		Token:                  f.Auth.Token,
+		AppID:                  f.Auth.AppID,
+		InstallationID:         f.Auth.InstallationID,
+		PEMKeyLoc:              f.Auth.PEMKeyFile,
+		PEMKey:                 f.Auth.PEMKey,
+		BaseURL:                f.BaseURL,
+		CacheTTL:               f.Cache.TTL,
+		PRCacheTTL:             f.Cache.PRTTL,
+		PRNegativeCacheTTL:     f.Cache.PRNegativeTTL,
+		RepositoryCacheTTL:     f.Cache.RepositoryTTL,
+		SelfCacheTTL:           f.Cache.SelfTTL,
+		CacheMaxEntries:        f.Cache.MaxEntries,
+		DisablePRCache:         f.Cache.DisablePRCache,
+		MaxConcurrentRequests:  f.MaxConcurrentRequests,
+		Tenant:                 f.Tenant,
+		Timeouts:               f.Timeouts,
+		AcceptedBackoff:        f.AcceptedBackoff,
+		TokenRefreshMargin:     f.TokenRefreshMargin,
+		BackgroundTokenRefresh: f.BackgroundTokenRefresh,
+		RequestIDHeader:        f.RequestIDHeader,
+	}
+}
+
+// ForOrg is ClientConfig with the overrides of org applied.  Orgs without overrides get ClientConfig.
+func (f *FileConfig) ForOrg(org string) *NewGQLClientConfig {
+	ret := f.ClientConfig()
+	o, ok := f.Orgs[org]
+	if !ok {
+		for k, v := range f.Orgs {
+			if strings.EqualFold(k, org) {
+				o, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return ret
+	}
+	if !o.Auth.isZero() {
+		ret.Token = o.Auth.Token
+		ret.AppID = o.Auth.AppID
+		ret.InstallationID = o.Auth.InstallationID
+		ret.PEMKeyLoc = o.Auth.PEMKeyFile
+		ret.PEMKey = o.Auth.PEMKey
+	}
+	if o.BaseURL != "" {
+		ret.BaseURL = o.BaseURL
+	}
+	if ret.Tenant == "" {
+		ret.Tenant = org
+	}
+	return ret
+}
@@ -0,0 +1,83 @@
+package gogithub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+auth:
+  appId: 12
+  installationId: 34
+  pemKeyFile: /etc/github/key.pem
+baseUrl: https://ghe.example.com/api/v3
+cache:
+  ttl: 2m
+  disablePrCache: true
+maxConcurrentRequests: 8
+timeouts:
+  query: 30s
+  mutation: 1m
+acceptedBackoff:
+  initialDelay: 2s
+  maxDelay: 10s
+  maxAttempts: 4
+orgs:
+  cresta-oss:
+    auth:
+      token: oss-token
+    baseUrl: https://api.github.com
+`
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := parseConfig([]byte(testConfigYAML), func(string) string { return "" })
+	require.NoError(t, err)
+	c := cfg.ClientConfig()
+	require.Equal(t, int64(12), c.AppID)
+	require.Equal(t, int64(34), c.InstallationID)
+	require.Equal(t, "/etc/github/key.pem", c.PEMKeyLoc)
+	require.Equal(t, "https://ghe.example.com/api/v3", c.BaseURL)
+	require.Equal(t, 2*time.Minute, c.CacheTTL)
+	require.True(t, c.DisablePRCache)
+	require.Equal(t, 8, c.MaxConcurrentRequests)
+	require.Equal(t, TimeoutPolicy{Query: 30 * time.Second, Mutation: time.Minute}, c.Timeouts)
+	require.Equal(t, AcceptedBackoff{InitialDelay: 2 * time.Second, MaxDelay: 10 * time.Second, MaxAttempts: 4}, c.AcceptedBackoff)
+
+	oss := cfg.ForOrg("Cresta-OSS")
+	require.Equal(t, "oss-token", oss.Token)
+	require.Equal(t, int64(0), oss.AppID)
+	require.Equal(t, "https://api.github.com", oss.BaseURL)
+	require.Equal(t, "Cresta-OSS", oss.Tenant)
+	require.Equal(t, c, cfg.ForOrg("cresta"))
+}
+
+func TestParseConfig_EnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"GITHUB_TOKEN":                     "env-token",
+		"GOGITHUB_MAX_CONCURRENT_REQUESTS": "3",
+		"GOGITHUB_QUERY_TIMEOUT":           "5s",
+	}
+	cfg, err := parseConfig([]byte(`{"maxConcurrentRequests": 8, "auth": {"token": "file-token"}}`), func(k string) string { return env[k] })
+	require.NoError(t, err)
+	require.Equal(t, "env-token", cfg.Auth.Token)
+	require.Equal(t, 3, cfg.MaxConcurrentRequests)
+	require.Equal(t, 5*time.Second, cfg.Timeouts.Query)
+
+	env["GOGITHUB_CACHE_TTL"] = "soon"
+	_, err = parseConfig(nil, func(k string) string { return env[k] })
+	require.ErrorContains(t, err, "invalid GOGITHUB_CACHE_TTL")
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogithub.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  ttll: 1m\n"), 0o600))
+	_, err := LoadConfig(path)
+	require.ErrorContains(t, err, "ttll")
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
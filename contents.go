@@ -0,0 +1,68 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// escapePath escapes each segment of a repository file path for use in a REST URL
+func escapePath(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// ErrNotAFile is returned by GetFileContents when path is a directory, symlink or submodule
+var ErrNotAFile = errors.New("path is not a file")
+
+// fileContents is the part of a contents API response GetFileContents needs
+type fileContents struct {
+	Type     string `json:"type"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+// GetFileContents returns the decoded content of the file at path.  ref is a branch, tag or commit SHA; empty means
+// the default branch.  A path that is not a regular file returns ErrNotAFile.
+func (g *GithubGraphqlAPI) GetFileContents(ctx context.Context, owner string, name string, path string, ref string) (_ []byte, err error) {
+	ctx = withOperation(ctx, "GetFileContents")
+	defer annotateError(&err, OperationError{Operation: "GetFileContents", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetFileContents", zap.String("owner", owner), zap.String("name", name), zap.String("path", path), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done GetFileContents")
+	u := fmt.Sprintf("/repos/%s/%s/contents/%s", owner, name, escapePath(path))
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+	var raw json.RawMessage
+	if err := g.doREST(ctx, http.MethodGet, u, nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to get contents of %s: %w", path, err)
+	}
+	// Directories are listed as an array of entries
+	var f fileContents
+	if err := json.Unmarshal(raw, &f); err != nil || f.Type != "file" {
+		return nil, fmt.Errorf("failed to get contents of %s: %w", path, ErrNotAFile)
+	}
+	if f.Encoding != "base64" {
+		// Files over 1MB come without inline content
+		b, err := g.doRESTRaw(ctx, http.MethodGet, u, "application/vnd.github.raw")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contents of %s: %w", path, err)
+		}
+		return b, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode contents of %s: %w", path, err)
+	}
+	return b, nil
+}
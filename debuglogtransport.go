@@ -5,41 +5,132 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
+	"github.com/cresta/gogithub/reqmeta"
 	"go.uber.org/zap"
 )
 
+// DefaultRedactHeaders are the headers whose values are never logged by the debug transport
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultMaxBodyLogSize is how many bytes of a request or response body the debug transport logs
+const DefaultMaxBodyLogSize = 4096
+
+const redacted = "REDACTED"
+
 type Zaptransport struct {
 	Base   http.RoundTripper
 	Logger *zap.Logger
+	// RedactHeaders are header names (case insensitive) whose values are replaced before logging.  If nil,
+	// DefaultRedactHeaders is used.
+	RedactHeaders []string
+	// MaxBodyLogSize caps how many bytes of each body are logged.  If 0, DefaultMaxBodyLogSize is used.  If negative,
+	// bodies are not logged.
+	MaxBodyLogSize int
+}
+
+// DebugLogOption customizes the transport returned by DebugLogTransport
+type DebugLogOption func(*Zaptransport)
+
+// WithRedactedHeaders replaces the default list of redacted headers
+func WithRedactedHeaders(headers ...string) DebugLogOption {
+	return func(z *Zaptransport) {
+		z.RedactHeaders = headers
+	}
+}
+
+// WithMaxBodyLogSize sets how many bytes of each body are logged.  Negative disables body logging.
+func WithMaxBodyLogSize(size int) DebugLogOption {
+	return func(z *Zaptransport) {
+		z.MaxBodyLogSize = size
+	}
+}
+
+func (z *Zaptransport) redactHeaders(h http.Header) http.Header {
+	redactList := z.RedactHeaders
+	if redactList == nil {
+		redactList = DefaultRedactHeaders
+	}
+	ret := h.Clone()
+	for k := range ret {
+		for _, r := range redactList {
+			if strings.EqualFold(k, r) {
+				ret[k] = []string{redacted}
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func (z *Zaptransport) maxBodyLogSize() int {
+	if z.MaxBodyLogSize == 0 {
+		return DefaultMaxBodyLogSize
+	}
+	return z.MaxBodyLogSize
+}
+
+// peekBody reads up to the log limit from body and returns the read prefix with a body that still yields the full
+// content
+func (z *Zaptransport) peekBody(body io.ReadCloser) (string, io.ReadCloser, error) {
+	limit := z.maxBodyLogSize()
+	if body == nil || body == http.NoBody || limit < 0 {
+		return "", body, nil
+	}
+	var prefix bytes.Buffer
+	_, err := io.CopyN(&prefix, body, int64(limit)+1)
+	rebuilt := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix.Bytes()), body), body}
+	if err != nil && err != io.EOF {
+		return "", rebuilt, err
+	}
+	logged := prefix.Bytes()
+	if len(logged) > limit {
+		logged = logged[:limit]
+	}
+	return string(logged), rebuilt, nil
 }
 
 func (z *Zaptransport) RoundTrip(request *http.Request) (*http.Response, error) {
 	if z.Logger == nil {
 		return z.Base.RoundTrip(request)
 	}
-	var bodyReadAsBuffer bytes.Buffer
-	if _, err := io.Copy(&bodyReadAsBuffer, request.Body); err != nil {
+	requestBody, newBody, err := z.peekBody(request.Body)
+	if err != nil {
 		return nil, fmt.Errorf("error reading request body: %w", err)
 	}
-	request.Body = io.NopCloser(&bodyReadAsBuffer)
-	z.Logger.Debug("staring request", zap.String("url", request.URL.String()), zap.String("method", request.Method), zap.Any("header", request.Header), zap.Any("body", bodyReadAsBuffer.String()))
-	defer z.Logger.Debug("ending request", zap.String("url", request.URL.String()))
+	request.Body = newBody
+	ctx := request.Context()
+	logger := reqmeta.Logger(ctx, z.Logger).With(append(reqmeta.Fields(ctx), zap.String("operation", reqmeta.Operation(ctx)))...)
+	logger.Debug("staring request", zap.String("url", request.URL.String()), zap.String("method", request.Method), zap.Any("header", z.redactHeaders(request.Header)), zap.String("body", requestBody))
+	defer logger.Debug("ending request", zap.String("url", request.URL.String()))
 	resp, err := z.Base.RoundTrip(request)
 	if err != nil {
-		z.Logger.Debug("response error", zap.Error(err))
-	} else {
-		z.Logger.Debug("response", zap.Any("header", resp.Header), zap.Any("body", resp.Body))
+		logger.Debug("response error", zap.Error(err))
+		return resp, err
+	}
+	responseBody, newBody, peekErr := z.peekBody(resp.Body)
+	if peekErr != nil {
+		logger.Debug("unable to read response body", zap.Error(peekErr))
 	}
+	resp.Body = newBody
+	logger.Debug("response", zap.Int("status", resp.StatusCode), zap.Any("header", z.redactHeaders(resp.Header)), zap.String("body", responseBody))
 	return resp, err
 }
 
-func DebugLogTransport(base http.RoundTripper, logger *zap.Logger) http.RoundTripper {
+func DebugLogTransport(base http.RoundTripper, logger *zap.Logger, opts ...DebugLogOption) http.RoundTripper {
 	if logger.Core().Enabled(zap.DebugLevel) {
-		return &Zaptransport{
+		ret := &Zaptransport{
 			Base:   base,
 			Logger: logger,
 		}
+		for _, opt := range opts {
+			opt(ret)
+		}
+		return ret
 	}
 	return base
 }
@@ -0,0 +1,55 @@
+package gogithub
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestZaptransport_RedactHeaders(t *testing.T) {
+	z := &Zaptransport{}
+	h := http.Header{}
+	h.Set("Authorization", "token secret")
+	h.Set("Accept", "application/json")
+	out := z.redactHeaders(h)
+	require.Equal(t, redacted, out.Get("Authorization"))
+	require.Equal(t, "application/json", out.Get("Accept"))
+	require.Equal(t, "token secret", h.Get("Authorization"))
+
+	z.RedactHeaders = []string{"accept"}
+	out = z.redactHeaders(h)
+	require.Equal(t, "token secret", out.Get("Authorization"))
+	require.Equal(t, redacted, out.Get("Accept"))
+}
+
+func TestZaptransport_PreservesBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: DebugLogTransport(http.DefaultTransport, zaptest.NewLogger(t), WithMaxBodyLogSize(4))}
+	payload := strings.Repeat("abcdefgh", 10)
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(b))
+}
+
+func TestZaptransport_PeekBody(t *testing.T) {
+	z := &Zaptransport{MaxBodyLogSize: 3}
+	logged, body, err := z.peekBody(io.NopCloser(strings.NewReader("hello")))
+	require.NoError(t, err)
+	require.Equal(t, "hel", logged)
+	b, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(b))
+}
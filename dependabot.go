@@ -0,0 +1,147 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DependabotAlertFilter narrows ListDependabotAlerts.  The zero value lists every alert.
+type DependabotAlertFilter struct {
+	// States are open, dismissed, fixed or auto_dismissed
+	States []string
+	// Severities are low, medium, high or critical
+	Severities []string
+	// Ecosystems are for example npm, pip or go
+	Ecosystems []string
+	// Packages are package names
+	Packages []string
+}
+
+func (f DependabotAlertFilter) query() string {
+	q := url.Values{}
+	for k, v := range map[string][]string{"state": f.States, "severity": f.Severities, "ecosystem": f.Ecosystems, "package": f.Packages} {
+		if len(v) > 0 {
+			q.Set(k, strings.Join(v, ","))
+		}
+	}
+	q.Set("per_page", "100")
+	return q.Encode()
+}
+
+// DependabotPackage is a package in an ecosystem
+type DependabotPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// DependabotAdvisory is the security advisory behind an alert
+type DependabotAdvisory struct {
+	GHSAID   string `json:"ghsa_id"`
+	CVEID    string `json:"cve_id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+}
+
+// DependabotAlert is a vulnerable dependency found by Dependabot
+type DependabotAlert struct {
+	Number int64  `json:"number"`
+	State  string `json:"state"`
+	// Repository is the full name (owner/name) of the repository of the alert
+	Repository string `json:"-"`
+	Dependency struct {
+		Package      DependabotPackage `json:"package"`
+		ManifestPath string            `json:"manifest_path"`
+		// Scope is runtime or development
+		Scope string `json:"scope"`
+	} `json:"dependency"`
+	SecurityAdvisory      DependabotAdvisory `json:"security_advisory"`
+	SecurityVulnerability struct {
+		Severity               string `json:"severity"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		FirstPatchedVersion    *struct {
+			Identifier string `json:"identifier"`
+		} `json:"first_patched_version"`
+	} `json:"security_vulnerability"`
+	HTMLURL          string     `json:"html_url"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DismissedAt      *time.Time `json:"dismissed_at"`
+	DismissedReason  string     `json:"dismissed_reason"`
+	DismissedComment string     `json:"dismissed_comment"`
+	FixedAt          *time.Time `json:"fixed_at"`
+}
+
+type restDependabotAlert struct {
+	DependabotAlert
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// DependabotDismissReason is why a Dependabot alert is dismissed
+type DependabotDismissReason string
+
+const (
+	DependabotDismissFixStarted    DependabotDismissReason = "fix_started"
+	DependabotDismissInaccurate    DependabotDismissReason = "inaccurate"
+	DependabotDismissNoBandwidth   DependabotDismissReason = "no_bandwidth"
+	DependabotDismissNotUsed       DependabotDismissReason = "not_used"
+	DependabotDismissTolerableRisk DependabotDismissReason = "tolerable_risk"
+)
+
+// ListDependabotAlerts returns the Dependabot alerts of a repository matching filter.  name empty lists the alerts of
+// every repository of the organization owner.  Pages are followed through the after cursor of the Link header, which
+// both endpoints support.
+func (g *GithubGraphqlAPI) ListDependabotAlerts(ctx context.Context, owner string, name string, filter DependabotAlertFilter) ([]DependabotAlert, error) {
+	path := fmt.Sprintf("/repos/%s/%s/dependabot/alerts", owner, name)
+	if name == "" {
+		path = fmt.Sprintf("/orgs/%s/dependabot/alerts", owner)
+	}
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[DependabotAlert], err error) {
+		ctx = withOperation(ctx, "ListDependabotAlerts")
+		defer annotateError(&err, OperationError{Operation: "ListDependabotAlerts", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListDependabotAlerts", zap.String("owner", owner), zap.String("name", name), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListDependabotAlerts")
+		u := cursor
+		if u == "" {
+			u = path + "?" + filter.query()
+		}
+		var alerts []restDependabotAlert
+		next, err := g.doRESTLinkPage(ctx, u, &alerts)
+		if err != nil {
+			return Page[DependabotAlert]{}, fmt.Errorf("failed to list dependabot alerts: %w", err)
+		}
+		items := make([]DependabotAlert, 0, len(alerts))
+		for _, a := range alerts {
+			a.DependabotAlert.Repository = a.Repository.FullName
+			if a.DependabotAlert.Repository == "" {
+				a.DependabotAlert.Repository = owner + "/" + name
+			}
+			items = append(items, a.DependabotAlert)
+		}
+		return linkPage(items, next), nil
+	}).All(ctx)
+}
+
+// DismissDependabotAlert dismisses an open Dependabot alert for reason, with an optional comment
+func (g *GithubGraphqlAPI) DismissDependabotAlert(ctx context.Context, owner string, name string, number int64, reason DependabotDismissReason, comment string) (_ *DependabotAlert, err error) {
+	ctx = withOperation(ctx, "DismissDependabotAlert")
+	defer annotateError(&err, OperationError{Operation: "DismissDependabotAlert", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("DismissDependabotAlert", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("reason", string(reason)))
+	defer g.logger(ctx).Debug("Done DismissDependabotAlert")
+	body := map[string]string{"state": "dismissed", "dismissed_reason": string(reason)}
+	if comment != "" {
+		body["dismissed_comment"] = comment
+	}
+	var ret restDependabotAlert
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/dependabot/alerts/%d", owner, name, number), body, &ret); err != nil {
+		return nil, fmt.Errorf("failed to dismiss dependabot alert: %w", err)
+	}
+	ret.DependabotAlert.Repository = owner + "/" + name
+	return &ret.DependabotAlert, nil
+}
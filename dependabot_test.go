@@ -0,0 +1,76 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependabotAlerts(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/orgs/acme/dependabot/alerts":
+			require.Equal(t, "high,critical", r.URL.Query().Get("severity"))
+			require.Equal(t, "open", r.URL.Query().Get("state"))
+			require.Equal(t, "", r.URL.Query().Get("package"))
+			_, _ = w.Write([]byte(`[{"number":4,"state":"open","repository":{"full_name":"acme/api"},
+				"dependency":{"package":{"ecosystem":"npm","name":"lodash"},"manifest_path":"package-lock.json","scope":"runtime"},
+				"security_advisory":{"ghsa_id":"GHSA-1","severity":"high"},
+				"security_vulnerability":{"severity":"high","first_patched_version":{"identifier":"4.17.21"}}}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/api/dependabot/alerts/4":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"state": "dismissed", "dismissed_reason": "not_used", "dismissed_comment": "test only"}, body)
+			_, _ = w.Write([]byte(`{"number":4,"state":"dismissed","dismissed_reason":"not_used"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	alerts, err := g.ListDependabotAlerts(ctx, "acme", "", DependabotAlertFilter{States: []string{"open"}, Severities: []string{"high", "critical"}})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "acme/api", alerts[0].Repository)
+	require.Equal(t, DependabotPackage{Ecosystem: "npm", Name: "lodash"}, alerts[0].Dependency.Package)
+	require.Equal(t, "4.17.21", alerts[0].SecurityVulnerability.FirstPatchedVersion.Identifier)
+
+	a, err := g.DismissDependabotAlert(ctx, "acme", "api", 4, DependabotDismissNotUsed, "test only")
+	require.NoError(t, err)
+	require.Equal(t, "dismissed", a.State)
+	require.Equal(t, "acme/api", a.Repository)
+}
+
+func TestListDependabotAlerts_LinkPages(t *testing.T) {
+	var afters []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/orgs/acme/dependabot/alerts", r.URL.Path)
+		require.Empty(t, r.URL.Query().Get("page"), "the org endpoint has no page parameter")
+		require.Equal(t, "fixed", r.URL.Query().Get("state"))
+		after := r.URL.Query().Get("after")
+		afters = append(afters, after)
+		first, n := 1, 100
+		if after != "" {
+			require.Equal(t, "Y3Vyc29yOjEwMA==", after)
+			first, n = 101, 2
+		} else {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/acme/dependabot/alerts?per_page=100&state=fixed&after=Y3Vyc29yOjEwMA%%3D%%3D>; rel="next"`, r.Host))
+		}
+		items := make([]string, 0, n)
+		for i := first; i < first+n; i++ {
+			items = append(items, fmt.Sprintf(`{"number":%d,"repository":{"full_name":"acme/api"}}`, i))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	})
+	alerts, err := g.ListDependabotAlerts(context.Background(), "acme", "", DependabotAlertFilter{States: []string{"fixed"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "Y3Vyc29yOjEwMA=="}, afters)
+	require.Len(t, alerts, 102)
+	for i, a := range alerts {
+		require.Equal(t, int64(i+1), a.Number)
+	}
+}
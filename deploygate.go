@@ -0,0 +1,87 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultDeployGateName is the check run name DeployGate publishes
+const DefaultDeployGateName = "deploy-gate"
+
+// DeployGate lets an external system, such as a deploy pipeline or a change management tool, gate merges through a
+// check run on pull request head commits.  Require the check name in branch protection, then call Pending when the
+// PR is opened or pushed and Pass or Fail once the system decided.  It requires GitHub App credentials.
+type DeployGate struct {
+	gh GitHub
+	// Name is the check run name.  Defaults to DefaultDeployGateName.
+	Name string
+	// DetailsURL, if set, is linked from the check as "Details"
+	DetailsURL string
+}
+
+func NewDeployGate(gh GitHub) *DeployGate {
+	return &DeployGate{
+		gh:   gh,
+		Name: DefaultDeployGateName,
+	}
+}
+
+// Pending marks the gate as in progress on the head commit of the PR, which blocks merging while the check is required
+func (d *DeployGate) Pending(ctx context.Context, owner string, name string, number int64, summary string) (*CheckRun, error) {
+	return d.publish(ctx, owner, name, number, CheckRunInput{
+		Status: CheckRunInProgress,
+		Output: &CheckRunOutput{
+			Title:   "Waiting for the deploy gate",
+			Summary: summary,
+		},
+	})
+}
+
+// Pass opens the gate on the head commit of the PR
+func (d *DeployGate) Pass(ctx context.Context, owner string, name string, number int64, summary string) (*CheckRun, error) {
+	return d.complete(ctx, owner, name, number, CheckRunSuccess, "Deploy gate passed", summary)
+}
+
+// Fail closes the gate on the head commit of the PR
+func (d *DeployGate) Fail(ctx context.Context, owner string, name string, number int64, summary string) (*CheckRun, error) {
+	return d.complete(ctx, owner, name, number, CheckRunFailure, "Deploy gate failed", summary)
+}
+
+func (d *DeployGate) complete(ctx context.Context, owner string, name string, number int64, conclusion CheckRunConclusion, title string, summary string) (*CheckRun, error) {
+	now := time.Now()
+	return d.publish(ctx, owner, name, number, CheckRunInput{
+		Status:      CheckRunCompleted,
+		Conclusion:  conclusion,
+		CompletedAt: &now,
+		Output: &CheckRunOutput{
+			Title:   title,
+			Summary: summary,
+		},
+	})
+}
+
+// publish creates a new check run on the current head commit.  Branch protection only looks at the newest run of a
+// name, so there is no need to find and update the previous one, and a push since then is gated again.
+func (d *DeployGate) publish(ctx context.Context, owner string, name string, number int64, input CheckRunInput) (*CheckRun, error) {
+	var pr struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := d.gh.DoREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to find PR head: %w", err)
+	}
+	input.Name = d.Name
+	if input.Name == "" {
+		input.Name = DefaultDeployGateName
+	}
+	input.HeadSHA = pr.Head.SHA
+	input.DetailsURL = d.DetailsURL
+	run, err := d.gh.CreateCheckRun(ctx, owner, name, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish %s: %w", input.Name, err)
+	}
+	return run, nil
+}
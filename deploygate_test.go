@@ -0,0 +1,41 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployGate(t *testing.T) {
+	var published []CheckRunInput
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/pulls/5":
+			_, _ = w.Write([]byte(`{"head":{"sha":"abc"}}`))
+		case "/repos/o/r/check-runs":
+			var in CheckRunInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			published = append(published, in)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	})
+	gate := NewDeployGate(g)
+	gate.DetailsURL = "https://deploy.example.com/5"
+	_, err := gate.Pending(context.Background(), "o", "r", 5, "canary running")
+	require.NoError(t, err)
+	_, err = gate.Fail(context.Background(), "o", "r", 5, "canary failed")
+	require.NoError(t, err)
+	require.Len(t, published, 2)
+	require.Equal(t, DefaultDeployGateName, published[0].Name)
+	require.Equal(t, "abc", published[0].HeadSHA)
+	require.Equal(t, CheckRunInProgress, published[0].Status)
+	require.Equal(t, "https://deploy.example.com/5", published[0].DetailsURL)
+	require.Equal(t, CheckRunCompleted, published[1].Status)
+	require.Equal(t, CheckRunFailure, published[1].Conclusion)
+	require.Equal(t, "canary failed", published[1].Output.Summary)
+}
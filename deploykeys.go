@@ -0,0 +1,68 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeployKey is an SSH key granting access to a single repository
+type DeployKey struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Key is the public key
+	Key       string    `json:"key"`
+	ReadOnly  bool      `json:"read_only"`
+	Verified  bool      `json:"verified"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListDeployKeys returns every deploy key of a repository
+func (g *GithubGraphqlAPI) ListDeployKeys(ctx context.Context, owner string, name string) ([]DeployKey, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[DeployKey], err error) {
+		ctx = withOperation(ctx, "ListDeployKeys")
+		defer annotateError(&err, OperationError{Operation: "ListDeployKeys", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListDeployKeys", zap.String("owner", owner), zap.String("name", name), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListDeployKeys")
+		var keys []DeployKey
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/keys?per_page=100&page=%d", owner, name, restPageNumber(cursor)), nil, &keys); err != nil {
+			return Page[DeployKey]{}, fmt.Errorf("failed to list deploy keys: %w", err)
+		}
+		return restPage(keys, cursor, 100), nil
+	}).All(ctx)
+}
+
+// AddDeployKey adds the public key to a repository.  A key that is not readOnly can push.  GitHub refuses a key
+// already used by another repository or user.
+func (g *GithubGraphqlAPI) AddDeployKey(ctx context.Context, owner string, name string, title string, key string, readOnly bool) (_ *DeployKey, err error) {
+	ctx = withOperation(ctx, "AddDeployKey")
+	defer annotateError(&err, OperationError{Operation: "AddDeployKey", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("AddDeployKey", zap.String("owner", owner), zap.String("name", name), zap.String("title", title), zap.Bool("readOnly", readOnly))
+	defer g.logger(ctx).Debug("Done AddDeployKey")
+	body := map[string]interface{}{
+		"title":     title,
+		"key":       key,
+		"read_only": readOnly,
+	}
+	var ret DeployKey
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/keys", owner, name), body, &ret); err != nil {
+		return nil, fmt.Errorf("failed to add deploy key: %w", err)
+	}
+	return &ret, nil
+}
+
+// DeleteDeployKey removes a deploy key from a repository.  Deploy keys cannot be changed; rotate one by adding the
+// new key, then deleting the old one.
+func (g *GithubGraphqlAPI) DeleteDeployKey(ctx context.Context, owner string, name string, keyID int64) (err error) {
+	ctx = withOperation(ctx, "DeleteDeployKey")
+	defer annotateError(&err, OperationError{Operation: "DeleteDeployKey", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("DeleteDeployKey", zap.String("owner", owner), zap.String("name", name), zap.Int64("keyID", keyID))
+	defer g.logger(ctx).Debug("Done DeleteDeployKey")
+	if err := g.doREST(ctx, http.MethodDelete, fmt.Sprintf("/repos/%s/%s/keys/%d", owner, name, keyID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete deploy key %d: %w", keyID, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployKeys(t *testing.T) {
+	var added map[string]interface{}
+	var deleted bool
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/keys":
+			_, _ = w.Write([]byte(`[{"id":1,"title":"ci","key":"ssh-ed25519 AAAA","read_only":true,"verified":true}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/keys":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&added))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":2,"title":"ci-2","key":"ssh-ed25519 BBBB","read_only":false}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/o/r/keys/1":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	keys, err := g.ListDeployKeys(ctx, "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, []DeployKey{{ID: 1, Title: "ci", Key: "ssh-ed25519 AAAA", ReadOnly: true, Verified: true}}, keys)
+
+	key, err := g.AddDeployKey(ctx, "o", "r", "ci-2", "ssh-ed25519 BBBB", false)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), key.ID)
+	require.Equal(t, map[string]interface{}{"title": "ci-2", "key": "ssh-ed25519 BBBB", "read_only": false}, added)
+
+	require.NoError(t, g.DeleteDeployKey(ctx, "o", "r", 1))
+	require.True(t, deleted)
+}
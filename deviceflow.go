@@ -0,0 +1,232 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrDeviceFlowExpired is returned when the user did not authorize the device before its code expired
+	ErrDeviceFlowExpired = errors.New("device code expired before it was authorized")
+	// ErrDeviceFlowDenied is returned when the user declined the authorization request
+	ErrDeviceFlowDenied = errors.New("device authorization was denied")
+)
+
+// DeviceCode is what the user needs to authorize a device flow login
+type DeviceCode struct {
+	// UserCode is the code the user types at VerificationURI
+	UserCode        string
+	VerificationURI string
+	ExpiresAt       time.Time
+}
+
+// DeviceFlow logs in with the OAuth device flow: it shows the user a code to enter on GitHub and polls until they
+// authorize it.  Tokens of GitHub Apps that expire are renewed with their refresh token, without asking the user again.
+type DeviceFlow struct {
+	// ClientID is the client ID of the OAuth App or GitHub App, which must have device flow enabled
+	ClientID string
+	// Scopes are the OAuth scopes requested.  GitHub Apps ignore them.
+	Scopes []string
+	// Prompt shows the code to the user.  Defaults to printing it on stderr.
+	Prompt func(ctx context.Context, code DeviceCode) error
+	// BaseURL is the root of the GitHub website, such as https://ghe.example.com.  Defaults to the website of the
+	// client's BaseURL.
+	BaseURL string
+	// HTTPClient sends the device flow requests.  Defaults to a client on the config's base transport.
+	HTTPClient *http.Client
+
+	// intervalUnit scales the polling interval GitHub asks for.  Tests shorten it.
+	intervalUnit time.Duration
+	// refreshToken renews the current token when it expires
+	refreshToken          string
+	refreshTokenExpiresAt time.Time
+}
+
+type deviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type deviceTokenResponse struct {
+	AccessToken           string `json:"access_token"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
+	Error                 string `json:"error"`
+	ErrorDescription      string `json:"error_description"`
+}
+
+func defaultDevicePrompt(_ context.Context, code DeviceCode) error {
+	_, err := fmt.Fprintf(os.Stderr, "First copy your one-time code: %s\nThen open %s in your browser to authorize this device\n", code.UserCode, code.VerificationURI)
+	return err
+}
+
+// mint returns a new token, renewing the previous one when it has a usable refresh token and running the device flow
+// otherwise.  Calls must be serialized, which refreshingToken does.
+func (d *DeviceFlow) mint(ctx context.Context) (TokenInfo, error) {
+	if d.refreshToken != "" && (d.refreshTokenExpiresAt.IsZero() || time.Now().Before(d.refreshTokenExpiresAt)) {
+		info, err := d.exchange(ctx, url.Values{
+			"client_id":     {d.ClientID},
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {d.refreshToken},
+		})
+		if err == nil {
+			return info, nil
+		}
+		// Only a refresh token GitHub rejected needs a new login.  Network errors, 5xx and an ended ctx are returned
+		// as is so that a later mint can retry the refresh instead of prompting.
+		var oauthErr *deviceFlowError
+		if !errors.As(err, &oauthErr) || (oauthErr.Code != "bad_refresh_token" && oauthErr.Code != "invalid_grant") {
+			return TokenInfo{}, err
+		}
+		d.refreshToken = ""
+	}
+	return d.login(ctx)
+}
+
+func (d *DeviceFlow) login(ctx context.Context) (TokenInfo, error) {
+	var code deviceCodeResponse
+	if err := d.post(ctx, "/login/device/code", url.Values{
+		"client_id": {d.ClientID},
+		"scope":     {strings.Join(d.Scopes, " ")},
+	}, &code); err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to request device code: %w", err)
+	}
+	expiresAt := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	prompt := d.Prompt
+	if prompt == nil {
+		prompt = defaultDevicePrompt
+	}
+	if err := prompt(ctx, DeviceCode{UserCode: code.UserCode, VerificationURI: code.VerificationURI, ExpiresAt: expiresAt}); err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to prompt for device code: %w", err)
+	}
+	unit := d.intervalUnit
+	if unit == 0 {
+		unit = time.Second
+	}
+	interval := code.Interval
+	if interval < 1 {
+		interval = 5
+	}
+	for {
+		if time.Now().After(expiresAt) {
+			return TokenInfo{}, ErrDeviceFlowExpired
+		}
+		timer := time.NewTimer(time.Duration(interval) * unit)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return TokenInfo{}, ctx.Err()
+		case <-timer.C:
+		}
+		info, err := d.exchange(ctx, url.Values{
+			"client_id":   {d.ClientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		})
+		var oauthErr *deviceFlowError
+		switch {
+		case err == nil:
+			return info, nil
+		case !errors.As(err, &oauthErr):
+			return TokenInfo{}, err
+		case oauthErr.Code == "authorization_pending":
+		case oauthErr.Code == "slow_down":
+			interval += 5
+		case oauthErr.Code == "expired_token":
+			return TokenInfo{}, ErrDeviceFlowExpired
+		case oauthErr.Code == "access_denied":
+			return TokenInfo{}, ErrDeviceFlowDenied
+		default:
+			return TokenInfo{}, err
+		}
+	}
+}
+
+type deviceFlowError struct {
+	Code        string
+	Description string
+}
+
+func (e *deviceFlowError) Error() string {
+	return fmt.Sprintf("oauth error %s: %s", e.Code, e.Description)
+}
+
+// exchange asks for an access token and remembers the refresh token that comes with it
+func (d *DeviceFlow) exchange(ctx context.Context, form url.Values) (TokenInfo, error) {
+	var resp deviceTokenResponse
+	if err := d.post(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to request access token: %w", err)
+	}
+	if resp.Error != "" {
+		return TokenInfo{}, &deviceFlowError{Code: resp.Error, Description: resp.ErrorDescription}
+	}
+	now := time.Now()
+	ret := TokenInfo{Token: resp.AccessToken}
+	if resp.ExpiresIn > 0 {
+		ret.ExpiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	d.refreshToken = resp.RefreshToken
+	d.refreshTokenExpiresAt = time.Time{}
+	if resp.RefreshTokenExpiresIn > 0 {
+		d.refreshTokenExpiresAt = now.Add(time.Duration(resp.RefreshTokenExpiresIn) * time.Second)
+	}
+	return ret, nil
+}
+
+func (d *DeviceFlow) post(ctx context.Context, path string, form url.Values, into interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(d.BaseURL, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// withDefaults returns a copy of d with its website and HTTP client filled from cfg
+func (d *DeviceFlow) withDefaults(cfg *NewGQLClientConfig) *DeviceFlow {
+	ret := *d
+	if ret.BaseURL == "" {
+		ret.BaseURL = cfg.webBaseURL()
+	}
+	if ret.HTTPClient == nil {
+		ret.HTTPClient = &http.Client{Transport: cfg.baseTransport()}
+	}
+	return &ret
+}
+
+func clientFromDeviceFlow(ctx context.Context, logger *zap.Logger, cfg *NewGQLClientConfig) (GitHub, error) {
+	flow := cfg.DeviceFlow.withDefaults(cfg)
+	return clientFromRefreshingToken(ctx, logger, cfg, newRefreshingToken(cfg.TokenRefreshMargin, flow.mint))
+}
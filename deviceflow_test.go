@@ -0,0 +1,146 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client", r.PostForm.Get("client_id"))
+		switch r.URL.Path {
+		case "/login/device/code":
+			require.Equal(t, "repo read:org", r.PostForm.Get("scope"))
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`))
+		case "/login/oauth/access_token":
+			switch r.PostForm.Get("grant_type") {
+			case "refresh_token":
+				require.Equal(t, "refresh-1", r.PostForm.Get("refresh_token"))
+				_, _ = w.Write([]byte(`{"access_token":"token-2","expires_in":28800,"refresh_token":"refresh-2"}`))
+				return
+			}
+			require.Equal(t, "dc", r.PostForm.Get("device_code"))
+			polls++
+			switch polls {
+			case 1:
+				_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+			case 2:
+				_, _ = w.Write([]byte(`{"error":"slow_down"}`))
+			default:
+				_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":28800,"refresh_token":"refresh-1","refresh_token_expires_in":15811200}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	var prompted DeviceCode
+	flow := &DeviceFlow{
+		ClientID: "client",
+		Scopes:   []string{"repo", "read:org"},
+		Prompt: func(_ context.Context, code DeviceCode) error {
+			prompted = code
+			return nil
+		},
+		BaseURL:      srv.URL,
+		HTTPClient:   srv.Client(),
+		intervalUnit: time.Millisecond,
+	}
+	info, err := flow.mint(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ABCD-1234", prompted.UserCode)
+	require.Equal(t, "https://github.com/login/device", prompted.VerificationURI)
+	require.Equal(t, 3, polls)
+	require.Equal(t, "token-1", info.Token)
+	require.True(t, info.ValidFor(7*time.Hour))
+
+	// The refresh token renews the login without prompting again
+	prompted = DeviceCode{}
+	info, err = flow.mint(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-2", info.Token)
+	require.Equal(t, "", prompted.UserCode)
+}
+
+func TestDeviceFlow_Denied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login/device/code" {
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"error":"access_denied"}`))
+	}))
+	defer srv.Close()
+	flow := &DeviceFlow{
+		ClientID:     "client",
+		Prompt:       func(_ context.Context, _ DeviceCode) error { return nil },
+		BaseURL:      srv.URL,
+		HTTPClient:   srv.Client(),
+		intervalUnit: time.Millisecond,
+	}
+	_, err := flow.mint(context.Background())
+	require.ErrorIs(t, err, ErrDeviceFlowDenied)
+}
+
+func TestNewGQLClientConfig_WebBaseURL(t *testing.T) {
+	require.Equal(t, "https://github.com", (&NewGQLClientConfig{}).webBaseURL())
+	require.Equal(t, "https://ghe.example.com", (&NewGQLClientConfig{BaseURL: "https://ghe.example.com/api/v3/"}).webBaseURL())
+}
+
+func TestDeviceFlow_RefreshErrors(t *testing.T) {
+	var status int
+	var body string
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch {
+		case r.URL.Path == "/login/device/code":
+			logins++
+			_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`))
+		case r.PostForm.Get("grant_type") == "refresh_token":
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		default:
+			_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":28800,"refresh_token":"refresh-1"}`))
+		}
+	}))
+	defer srv.Close()
+	flow := &DeviceFlow{
+		ClientID:     "client",
+		Prompt:       func(_ context.Context, _ DeviceCode) error { return nil },
+		BaseURL:      srv.URL,
+		HTTPClient:   srv.Client(),
+		intervalUnit: time.Millisecond,
+		refreshToken: "refresh-0",
+	}
+
+	// A server error keeps the refresh token and does not prompt
+	status, body = http.StatusBadGateway, `bad gateway`
+	_, err := flow.mint(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 0, logins)
+	require.Equal(t, "refresh-0", flow.refreshToken)
+
+	// An ended ctx does not prompt either
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = flow.mint(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 0, logins)
+	require.Equal(t, "refresh-0", flow.refreshToken)
+
+	// A rejected refresh token falls back to a new login
+	status, body = http.StatusOK, `{"error":"bad_refresh_token","error_description":"The refresh token passed is incorrect or expired."}`
+	info, err := flow.mint(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, logins)
+	require.Equal(t, "token-1", info.Token)
+	require.Equal(t, "refresh-1", flow.refreshToken)
+}
@@ -0,0 +1,256 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// DigestOptions selects what BuildDigest reports on
+type DigestOptions struct {
+	Repos []RepoRef
+	// Since and Until bound the reporting window.  Until defaults to now and Since to a week before Until.
+	Since time.Time
+	Until time.Time
+	// Authors restricts pull requests to these logins, for example the members of a team.  Empty means everyone.
+	Authors []string
+	// StaleAfter is how long a branch can go without commits before it is reported as stale.  Defaults to 30 days.
+	StaleAfter time.Duration
+	// Concurrency is how many repositories are queried at once.  Defaults to 5.
+	Concurrency int
+}
+
+// DigestReport is an engineering digest over a time window
+type DigestReport struct {
+	Since              time.Time
+	Until              time.Time
+	MergedPullRequests []DigestPullRequest
+	// ReviewDebt are open, non draft pull requests still waiting for an approving review
+	ReviewDebt       []DigestPullRequest
+	FailingWorkflows []DigestWorkflowRun
+	StaleBranches    []DigestBranch
+}
+
+type DigestPullRequest struct {
+	Repo      RepoRef
+	Number    int64
+	Title     string
+	URL       string
+	Author    string
+	CreatedAt time.Time
+	MergedAt  time.Time
+}
+
+type DigestWorkflowRun struct {
+	Repo      RepoRef
+	Name      string
+	Branch    string
+	URL       string
+	CreatedAt time.Time
+}
+
+type DigestBranch struct {
+	Repo         RepoRef
+	Name         string
+	LastCommitAt time.Time
+}
+
+type digestPRNode struct {
+	Number int64
+	Title  string
+	URL    string `graphql:"url"`
+	Author struct {
+		Login string
+	}
+	CreatedAt      githubv4.DateTime
+	MergedAt       githubv4.DateTime
+	IsDraft        bool
+	ReviewDecision string
+}
+
+type digestRefNode struct {
+	Name   string
+	Target struct {
+		Commit struct {
+			CommittedDate githubv4.DateTime
+		} `graphql:"... on Commit"`
+	}
+}
+
+type digestQuery struct {
+	Repository struct {
+		Merged struct {
+			Nodes []digestPRNode
+		} `graphql:"merged: pullRequests(states: [MERGED], first: 100, orderBy: {field: UPDATED_AT, direction: DESC})"`
+		Open struct {
+			Nodes []digestPRNode
+		} `graphql:"open: pullRequests(states: [OPEN], first: 100, orderBy: {field: CREATED_AT, direction: ASC})"`
+		DefaultBranchRef struct {
+			Name string
+		}
+		Refs struct {
+			Nodes []digestRefNode
+		} `graphql:"refs(refPrefix: \"refs/heads/\", first: 100)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type workflowRunsResponse struct {
+	WorkflowRuns []struct {
+		Name       string    `json:"name"`
+		HeadBranch string    `json:"head_branch"`
+		HTMLURL    string    `json:"html_url"`
+		CreatedAt  time.Time `json:"created_at"`
+	} `json:"workflow_runs"`
+}
+
+func (o DigestOptions) withDefaults() DigestOptions {
+	if o.Until.IsZero() {
+		o.Until = time.Now()
+	}
+	if o.Since.IsZero() {
+		o.Since = o.Until.Add(-7 * 24 * time.Hour)
+	}
+	if o.StaleAfter == 0 {
+		o.StaleAfter = 30 * 24 * time.Hour
+	}
+	if o.Concurrency == 0 {
+		o.Concurrency = 5
+	}
+	return o
+}
+
+func (o DigestOptions) includesAuthor(login string) bool {
+	if len(o.Authors) == 0 {
+		return true
+	}
+	for _, a := range o.Authors {
+		if strings.EqualFold(a, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildDigest aggregates merged pull requests, open review debt, failing workflows and stale branches of opts.Repos.
+// A repository that fails does not abort the report: the partial report is returned along with a *FanOutError.
+func BuildDigest(ctx context.Context, gh GitHub, opts DigestOptions) (*DigestReport, error) {
+	opts = opts.withDefaults()
+	report := &DigestReport{
+		Since: opts.Since,
+		Until: opts.Until,
+	}
+	var mu sync.Mutex
+	err := FanOut(ctx, opts.Repos, func(ctx context.Context, repo RepoRef) error {
+		var q digestQuery
+		if err := gh.QueryRaw(ctx, &q, map[string]interface{}{
+			"owner": githubv4.String(repo.Owner),
+			"name":  githubv4.String(repo.Name),
+		}); err != nil {
+			return fmt.Errorf("failed to query pull requests: %w", err)
+		}
+		var runs workflowRunsResponse
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs?status=failure&per_page=100&created=%s..%s", repo.Owner, repo.Name, opts.Since.UTC().Format(time.RFC3339), opts.Until.UTC().Format(time.RFC3339))
+		if err := gh.DoREST(ctx, http.MethodGet, path, nil, &runs); err != nil {
+			return fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		report.addRepo(repo, &q, &runs, opts)
+		return nil
+	}, FanOutConcurrency(opts.Concurrency))
+	report.sort()
+	return report, err
+}
+
+func (r *DigestReport) addRepo(repo RepoRef, q *digestQuery, runs *workflowRunsResponse, opts DigestOptions) {
+	toPR := func(n digestPRNode) DigestPullRequest {
+		return DigestPullRequest{
+			Repo:      repo,
+			Number:    n.Number,
+			Title:     n.Title,
+			URL:       n.URL,
+			Author:    n.Author.Login,
+			CreatedAt: n.CreatedAt.Time,
+			MergedAt:  n.MergedAt.Time,
+		}
+	}
+	for _, n := range q.Repository.Merged.Nodes {
+		if n.MergedAt.Before(opts.Since) || !n.MergedAt.Before(opts.Until) || !opts.includesAuthor(n.Author.Login) {
+			continue
+		}
+		r.MergedPullRequests = append(r.MergedPullRequests, toPR(n))
+	}
+	for _, n := range q.Repository.Open.Nodes {
+		if n.IsDraft || ReviewDecision(n.ReviewDecision).IsApproved() || !opts.includesAuthor(n.Author.Login) {
+			continue
+		}
+		r.ReviewDebt = append(r.ReviewDebt, toPR(n))
+	}
+	for _, run := range runs.WorkflowRuns {
+		r.FailingWorkflows = append(r.FailingWorkflows, DigestWorkflowRun{
+			Repo:      repo,
+			Name:      run.Name,
+			Branch:    run.HeadBranch,
+			URL:       run.HTMLURL,
+			CreatedAt: run.CreatedAt,
+		})
+	}
+	staleBefore := opts.Until.Add(-opts.StaleAfter)
+	for _, ref := range q.Repository.Refs.Nodes {
+		last := ref.Target.Commit.CommittedDate.Time
+		if ref.Name == q.Repository.DefaultBranchRef.Name || last.IsZero() || !last.Before(staleBefore) {
+			continue
+		}
+		r.StaleBranches = append(r.StaleBranches, DigestBranch{
+			Repo:         repo,
+			Name:         ref.Name,
+			LastCommitAt: last,
+		})
+	}
+}
+
+func (r *DigestReport) sort() {
+	sort.Slice(r.MergedPullRequests, func(i, j int) bool {
+		return r.MergedPullRequests[i].MergedAt.Before(r.MergedPullRequests[j].MergedAt)
+	})
+	sort.Slice(r.ReviewDebt, func(i, j int) bool {
+		return r.ReviewDebt[i].CreatedAt.Before(r.ReviewDebt[j].CreatedAt)
+	})
+	sort.Slice(r.FailingWorkflows, func(i, j int) bool {
+		return r.FailingWorkflows[i].CreatedAt.Before(r.FailingWorkflows[j].CreatedAt)
+	})
+	sort.Slice(r.StaleBranches, func(i, j int) bool {
+		return r.StaleBranches[i].LastCommitAt.Before(r.StaleBranches[j].LastCommitAt)
+	})
+}
+
+// Markdown renders the report for posting in an issue, discussion or chat
+func (r *DigestReport) Markdown() string {
+	const day = "2006-01-02"
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Weekly digest %s to %s\n", r.Since.Format(day), r.Until.Format(day))
+
+	fmt.Fprintf(&sb, "\n## Merged pull requests (%d)\n\n", len(r.MergedPullRequests))
+	for _, pr := range r.MergedPullRequests {
+		fmt.Fprintf(&sb, "- %s [#%d %s](%s) by @%s\n", pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author)
+	}
+	fmt.Fprintf(&sb, "\n## Waiting for review (%d)\n\n", len(r.ReviewDebt))
+	for _, pr := range r.ReviewDebt {
+		fmt.Fprintf(&sb, "- %s [#%d %s](%s) by @%s, open for %d days\n", pr.Repo, pr.Number, pr.Title, pr.URL, pr.Author, int(r.Until.Sub(pr.CreatedAt).Hours()/24))
+	}
+	fmt.Fprintf(&sb, "\n## Failing workflows (%d)\n\n", len(r.FailingWorkflows))
+	for _, run := range r.FailingWorkflows {
+		fmt.Fprintf(&sb, "- %s [%s](%s) on `%s`\n", run.Repo, run.Name, run.URL, run.Branch)
+	}
+	fmt.Fprintf(&sb, "\n## Stale branches (%d)\n\n", len(r.StaleBranches))
+	for _, b := range r.StaleBranches {
+		fmt.Fprintf(&sb, "- %s `%s`, last commit %s\n", b.Repo, b.Name, b.LastCommitAt.Format(day))
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package gogithub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestReport_AddRepo(t *testing.T) {
+	until := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC)
+	opts := DigestOptions{Until: until, Authors: []string{"alice"}}.withDefaults()
+	repo := RepoRef{Owner: "cresta", Name: "gogithub"}
+	var q digestQuery
+	q.Repository.Merged.Nodes = []digestPRNode{
+		{Number: 1, Title: "in window", MergedAt: githubv4.DateTime{Time: until.Add(-time.Hour)}},
+		{Number: 2, Title: "too old", MergedAt: githubv4.DateTime{Time: until.Add(-30 * 24 * time.Hour)}},
+	}
+	q.Repository.Merged.Nodes[0].Author.Login = "alice"
+	q.Repository.Merged.Nodes[1].Author.Login = "alice"
+	q.Repository.Open.Nodes = []digestPRNode{
+		{Number: 3, Title: "needs review", CreatedAt: githubv4.DateTime{Time: until.Add(-48 * time.Hour)}, ReviewDecision: "REVIEW_REQUIRED"},
+		{Number: 4, Title: "approved", ReviewDecision: "APPROVED"},
+		{Number: 5, Title: "someone else"},
+	}
+	q.Repository.Open.Nodes[0].Author.Login = "alice"
+	q.Repository.Open.Nodes[1].Author.Login = "alice"
+	q.Repository.Open.Nodes[2].Author.Login = "bob"
+	q.Repository.DefaultBranchRef.Name = "main"
+	q.Repository.Refs.Nodes = make([]digestRefNode, 2)
+	q.Repository.Refs.Nodes[0].Name = "main"
+	q.Repository.Refs.Nodes[0].Target.Commit.CommittedDate.Time = until.Add(-90 * 24 * time.Hour)
+	q.Repository.Refs.Nodes[1].Name = "old-feature"
+	q.Repository.Refs.Nodes[1].Target.Commit.CommittedDate.Time = until.Add(-90 * 24 * time.Hour)
+
+	r := &DigestReport{Since: opts.Since, Until: opts.Until}
+	r.addRepo(repo, &q, &workflowRunsResponse{}, opts)
+	require.Len(t, r.MergedPullRequests, 1)
+	require.Equal(t, int64(1), r.MergedPullRequests[0].Number)
+	require.Len(t, r.ReviewDebt, 1)
+	require.Equal(t, int64(3), r.ReviewDebt[0].Number)
+	require.Len(t, r.StaleBranches, 1)
+	require.Equal(t, "old-feature", r.StaleBranches[0].Name)
+
+	md := r.Markdown()
+	require.Contains(t, md, "# Weekly digest 2024-06-01 to 2024-06-08")
+	require.Contains(t, md, "## Merged pull requests (1)")
+	require.Contains(t, md, "cresta/gogithub [#3 needs review]() by @alice, open for 2 days")
+	require.Contains(t, md, "cresta/gogithub `old-feature`, last commit 2024-03-10")
+}
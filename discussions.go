@@ -0,0 +1,182 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// DiscussionCategory is a category of the discussions of a repository, such as Announcements
+type DiscussionCategory struct {
+	ID          githubv4.ID
+	Name        string
+	Slug        string
+	Emoji       string
+	Description string
+	// IsAnswerable is set for Q&A categories, whose discussions can be marked answered
+	IsAnswerable bool
+}
+
+// Discussion is a discussion of a repository
+type Discussion struct {
+	ID        githubv4.ID
+	Number    int64
+	Title     string
+	Body      string
+	URL       string
+	Category  string
+	Author    string
+	CreatedAt time.Time
+}
+
+// DiscussionComment is a comment of a discussion
+type DiscussionComment struct {
+	ID  githubv4.ID
+	URL string
+}
+
+type discussionNode struct {
+	ID       githubv4.ID
+	Number   int64
+	Title    string
+	Body     string
+	URL      string `graphql:"url"`
+	Category struct {
+		Name string
+	}
+	Author struct {
+		Login string
+	}
+	CreatedAt githubv4.DateTime
+}
+
+func (d *discussionNode) toDiscussion() Discussion {
+	return Discussion{
+		ID:        d.ID,
+		Number:    d.Number,
+		Title:     d.Title,
+		Body:      d.Body,
+		URL:       d.URL,
+		Category:  d.Category.Name,
+		Author:    d.Author.Login,
+		CreatedAt: d.CreatedAt.Time,
+	}
+}
+
+// maxDiscussionCategories is the most categories a repository can have
+const maxDiscussionCategories = 25
+
+// ListDiscussionCategories returns the discussion categories of a repository.  Discussions must be enabled on it.
+func (g *GithubGraphqlAPI) ListDiscussionCategories(ctx context.Context, owner string, name string) (_ []DiscussionCategory, err error) {
+	ctx = withOperation(ctx, "ListDiscussionCategories")
+	defer annotateError(&err, OperationError{Operation: "ListDiscussionCategories", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ListDiscussionCategories", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done ListDiscussionCategories")
+	var query struct {
+		Repository struct {
+			DiscussionCategories struct {
+				Nodes []DiscussionCategory
+			} `graphql:"discussionCategories(first: $first)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+		"first": githubv4.Int(maxDiscussionCategories),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query discussion categories: %w", err)
+	}
+	return query.Repository.DiscussionCategories.Nodes, nil
+}
+
+// ListDiscussions pages through the discussions of a repository, newest first.  A nil categoryID lists every category.
+func (g *GithubGraphqlAPI) ListDiscussions(ctx context.Context, owner string, name string, categoryID githubv4.ID) ([]Discussion, error) {
+	var category *githubv4.ID
+	if categoryID != nil {
+		category = &categoryID
+	}
+	return NewPaginator(func(ctx context.Context, cursor string, pageSize int) (_ Page[Discussion], err error) {
+		ctx = withOperation(ctx, "ListDiscussions")
+		defer annotateError(&err, OperationError{Operation: "ListDiscussions", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListDiscussions", zap.String("owner", owner), zap.String("name", name), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListDiscussions")
+		var query struct {
+			Repository struct {
+				Discussions struct {
+					Nodes    []discussionNode
+					PageInfo GraphQLPageInfo
+				} `graphql:"discussions(first: $first, after: $cursor, categoryId: $categoryId, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+			"owner":      githubv4.String(owner),
+			"name":       githubv4.String(name),
+			"first":      githubv4.Int(pageSize),
+			"cursor":     graphqlCursor(cursor),
+			"categoryId": category,
+		}); err != nil {
+			return Page[Discussion]{}, fmt.Errorf("failed to query discussions: %w", err)
+		}
+		items := make([]Discussion, 0, len(query.Repository.Discussions.Nodes))
+		for i := range query.Repository.Discussions.Nodes {
+			items = append(items, query.Repository.Discussions.Nodes[i].toDiscussion())
+		}
+		return graphqlPage(items, query.Repository.Discussions.PageInfo), nil
+	}).All(ctx)
+}
+
+// CreateDiscussion starts a discussion in categoryID, one of ListDiscussionCategories, of a repository
+func (g *GithubGraphqlAPI) CreateDiscussion(ctx context.Context, owner string, name string, categoryID githubv4.ID, title string, body string) (_ *Discussion, err error) {
+	ctx = withOperation(ctx, "CreateDiscussion")
+	defer annotateError(&err, OperationError{Operation: "CreateDiscussion", Owner: owner, Repo: name})
+	repo, err := g.RepositoryInfo(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository: %w", err)
+	}
+	g.logger(ctx).Debug("CreateDiscussion", zap.String("owner", owner), zap.String("name", name), zap.String("title", title))
+	defer g.logger(ctx).Debug("Done CreateDiscussion")
+	var ret struct {
+		CreateDiscussion struct {
+			Discussion discussionNode
+		} `graphql:"createDiscussion(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.CreateDiscussionInput{
+		RepositoryID: repo.Repository.ID,
+		CategoryID:   categoryID,
+		Title:        githubv4.String(title),
+		Body:         githubv4.String(body),
+	}, nil); err != nil {
+		return nil, fmt.Errorf("failed to create discussion: %w", err)
+	}
+	d := ret.CreateDiscussion.Discussion.toDiscussion()
+	return &d, nil
+}
+
+// AddDiscussionComment comments on a discussion
+func (g *GithubGraphqlAPI) AddDiscussionComment(ctx context.Context, discussionID githubv4.ID, body string) (_ *DiscussionComment, err error) {
+	ctx = withOperation(ctx, "AddDiscussionComment")
+	defer annotateError(&err, OperationError{Operation: "AddDiscussionComment"})
+	g.logger(ctx).Debug("AddDiscussionComment", zap.Any("discussionID", discussionID))
+	defer g.logger(ctx).Debug("Done AddDiscussionComment")
+	var ret struct {
+		AddDiscussionComment struct {
+			Comment struct {
+				ID  githubv4.ID
+				URL string `graphql:"url"`
+			}
+		} `graphql:"addDiscussionComment(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.AddDiscussionCommentInput{
+		DiscussionID: discussionID,
+		Body:         githubv4.String(body),
+	}, nil); err != nil {
+		return nil, fmt.Errorf("failed to add discussion comment: %w", err)
+	}
+	return &DiscussionComment{
+		ID:  ret.AddDiscussionComment.Comment.ID,
+		URL: ret.AddDiscussionComment.Comment.URL,
+	}, nil
+}
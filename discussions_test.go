@@ -0,0 +1,122 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDiscussions(t *testing.T) {
+	g, requests := newPagedGraphQLClient(t, "discussions(first: $first, after: $cursor, categoryId: $categoryId", map[string]string{
+		"": `{"data":{"repository":{"discussions":{
+			"nodes":[
+				{"id":"D_3","number":3,"title":"Release 1.2","body":"notes","url":"https://github.com/o/r/discussions/3","category":{"name":"Announcements"},"author":{"login":"alice"},"createdAt":"2024-05-03T00:00:00Z"},
+				{"id":"D_2","number":2,"title":"Release 1.1","category":{"name":"Announcements"},"author":{"login":"bob"},"createdAt":"2024-05-02T00:00:00Z"}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}`,
+		"C1": `{"data":{"repository":{"discussions":{
+			"nodes":[{"id":"D_1","number":1,"title":"Release 1.0","category":{"name":"Announcements"},"author":{"login":"alice"},"createdAt":"2024-05-01T00:00:00Z"}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"C2"}}}}}`,
+	})
+	discussions, err := g.ListDiscussions(context.Background(), "o", "r", "DIC_1")
+	require.NoError(t, err)
+	require.Len(t, discussions, 3)
+	require.Equal(t, Discussion{
+		ID:        "D_3",
+		Number:    3,
+		Title:     "Release 1.2",
+		Body:      "notes",
+		URL:       "https://github.com/o/r/discussions/3",
+		Category:  "Announcements",
+		Author:    "alice",
+		CreatedAt: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+	}, discussions[0])
+	require.Equal(t, []int64{3, 2, 1}, []int64{discussions[0].Number, discussions[1].Number, discussions[2].Number})
+	require.Len(t, *requests, 2)
+	require.Equal(t, "DIC_1", (*requests)[0].Variables["categoryId"])
+	require.Equal(t, "C1", (*requests)[1].Variables["cursor"])
+
+	g, requests = newPagedGraphQLClient(t, "discussions(", map[string]string{
+		"": `{"data":{"repository":{"discussions":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}}`,
+	})
+	discussions, err = g.ListDiscussions(context.Background(), "o", "r", nil)
+	require.NoError(t, err)
+	require.Empty(t, discussions)
+	require.Nil(t, (*requests)[0].Variables["categoryId"])
+}
+
+func TestCreateDiscussion(t *testing.T) {
+	var input map[string]interface{}
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		switch {
+		case strings.Contains(req.Query, "discussionCategories(first: $first)"):
+			require.Equal(t, float64(maxDiscussionCategories), req.Variables["first"])
+			_, _ = w.Write([]byte(`{"data":{"repository":{"discussionCategories":{"nodes":[
+				{"id":"DIC_1","name":"Announcements","slug":"announcements","emoji":":mega:"},
+				{"id":"DIC_2","name":"Q&A","slug":"q-a","isAnswerable":true}]}}}}`))
+		case strings.Contains(req.Query, "createDiscussion(input: $input)"):
+			input = req.Variables["input"].(map[string]interface{})
+			_, _ = w.Write([]byte(`{"data":{"createDiscussion":{"discussion":{"id":"D_9","number":9,"title":"How do I?","body":"...","url":"https://github.com/o/r/discussions/9","category":{"name":"Q&A"},"author":{"login":"bot"},"createdAt":"2024-05-01T00:00:00Z"}}}}`))
+		case strings.Contains(req.Query, "repository(owner: $owner, name: $name)"):
+			require.Equal(t, map[string]interface{}{"owner": "o", "name": "r"}, req.Variables)
+			_, _ = w.Write([]byte(`{"data":{"repository":{"id":"R_1","defaultBranchRef":{"name":"main","id":"REF_1"}}}}`))
+		default:
+			t.Fatalf("unexpected query %s", req.Query)
+		}
+	})
+	categories, err := g.ListDiscussionCategories(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	require.True(t, categories[1].IsAnswerable)
+	require.Equal(t, "q-a", categories[1].Slug)
+
+	d, err := g.CreateDiscussion(context.Background(), "o", "r", categories[1].ID, "How do I?", "...")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"repositoryId": "R_1",
+		"categoryId":   "DIC_2",
+		"title":        "How do I?",
+		"body":         "...",
+	}, input)
+	require.Equal(t, int64(9), d.Number)
+	require.Equal(t, "Q&A", d.Category)
+	require.Equal(t, "https://github.com/o/r/discussions/9", d.URL)
+}
+
+func TestCreateDiscussion_RepositoryNotFound(t *testing.T) {
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.NotContains(t, req.Query, "createDiscussion", "nothing is created without a repository ID")
+		_, _ = w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository with the name 'o/missing'."}]}`))
+	})
+	_, err := g.CreateDiscussion(context.Background(), "o", "missing", githubv4.ID("DIC_1"), "t", "b")
+	require.ErrorContains(t, err, "failed to find repository")
+	require.ErrorContains(t, err, "Could not resolve to a Repository")
+}
+
+func TestAddDiscussionComment(t *testing.T) {
+	var input map[string]interface{}
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.Contains(t, req.Query, "addDiscussionComment(input: $input)")
+		input = req.Variables["input"].(map[string]interface{})
+		if input["discussionId"] == "D_locked" {
+			_, _ = w.Write([]byte(`{"data":{"addDiscussionComment":null},"errors":[{"message":"Discussion is locked"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"addDiscussionComment":{"comment":{"id":"DC_1","url":"https://github.com/o/r/discussions/9#discussioncomment-1"}}}}`))
+	})
+	c, err := g.AddDiscussionComment(context.Background(), "D_9", "thanks")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"discussionId": "D_9", "body": "thanks"}, input)
+	require.Equal(t, &DiscussionComment{ID: "DC_1", URL: "https://github.com/o/r/discussions/9#discussioncomment-1"}, c)
+
+	_, err = g.AddDiscussionComment(context.Background(), "D_locked", "thanks")
+	require.ErrorContains(t, err, "failed to add discussion comment")
+	require.ErrorContains(t, err, "Discussion is locked")
+}
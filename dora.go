@@ -0,0 +1,283 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// DORAOptions selects the repository, environment and window ExtractDORAMetrics looks at
+type DORAOptions struct {
+	Repo RepoRef
+	// Environment is the deployment environment that counts as production
+	Environment string
+	// Since and Until bound the window.  Until defaults to now and Since to 30 days before Until.
+	Since time.Time
+	Until time.Time
+	// RollbackLabel marks merged pull requests that roll back a change.  Pull requests GitHub generated with the
+	// "Revert" button are always counted.  Defaults to "rollback".
+	RollbackLabel string
+}
+
+// DORAMetrics are the DORA delivery metrics of one repository and environment
+type DORAMetrics struct {
+	Since time.Time
+	Until time.Time
+	// Deployments is how many successful deployments happened in the window
+	Deployments int
+	// DeploymentsPerDay is Deployments divided by the window length in days
+	DeploymentsPerDay float64
+	// LeadTimes has one entry per merged pull request that reached the environment
+	LeadTimes []ChangeLeadTime
+	// MedianLeadTime is the median of LeadTimes, from pull request creation to deployment
+	MedianLeadTime time.Duration
+	// FailedDeployments counts deployments whose final status is failure or error
+	FailedDeployments int
+	// Rollbacks counts merged reverts and pull requests labeled as rollbacks
+	Rollbacks int
+	// ChangeFailureRate is (FailedDeployments + Rollbacks) / all deployments
+	ChangeFailureRate float64
+}
+
+// ChangeLeadTime tracks one pull request from creation to deployment
+type ChangeLeadTime struct {
+	Number     int64
+	OpenedAt   time.Time
+	MergedAt   time.Time
+	DeployedAt time.Time
+}
+
+// LeadTime is the time from opening the pull request to deploying it
+func (c ChangeLeadTime) LeadTime() time.Duration {
+	return c.DeployedAt.Sub(c.OpenedAt)
+}
+
+type doraDeployment struct {
+	ID        int64     `json:"id"`
+	SHA       string    `json:"sha"`
+	CreatedAt time.Time `json:"created_at"`
+	state     string
+}
+
+type doraDeploymentStatus struct {
+	State string `json:"state"`
+}
+
+type doraPRNode struct {
+	Number      int64
+	Title       string
+	CreatedAt   githubv4.DateTime
+	UpdatedAt   githubv4.DateTime
+	MergedAt    githubv4.DateTime
+	MergeCommit struct {
+		Oid string
+	}
+	Labels struct {
+		Nodes []struct {
+			Name string
+		}
+	} `graphql:"labels(first: 20)"`
+}
+
+func (o DORAOptions) withDefaults() DORAOptions {
+	if o.Until.IsZero() {
+		o.Until = time.Now()
+	}
+	if o.Since.IsZero() {
+		o.Since = o.Until.Add(-30 * 24 * time.Hour)
+	}
+	if o.RollbackLabel == "" {
+		o.RollbackLabel = "rollback"
+	}
+	return o
+}
+
+// ExtractDORAMetrics computes lead time for changes, deployment frequency and change failure rate from the deployments
+// API and merged pull requests
+func ExtractDORAMetrics(ctx context.Context, gh GitHub, opts DORAOptions) (*DORAMetrics, error) {
+	opts = opts.withDefaults()
+	deployments, err := doraDeployments(ctx, gh, opts)
+	if err != nil {
+		return nil, err
+	}
+	prs, err := doraMergedPullRequests(ctx, gh, opts)
+	if err != nil {
+		return nil, err
+	}
+	ret := &DORAMetrics{
+		Since: opts.Since,
+		Until: opts.Until,
+	}
+	var successful []doraDeployment
+	for _, d := range deployments {
+		switch d.state {
+		case "success":
+			successful = append(successful, d)
+		case "failure", "error":
+			ret.FailedDeployments++
+		}
+	}
+	ret.Deployments = len(successful)
+	if days := opts.Until.Sub(opts.Since).Hours() / 24; days > 0 {
+		ret.DeploymentsPerDay = float64(ret.Deployments) / days
+	}
+	for _, pr := range prs {
+		if isRollback(pr, opts.RollbackLabel) {
+			ret.Rollbacks++
+		}
+		deployedAt, err := firstDeploymentContaining(ctx, gh, opts.Repo, pr, successful)
+		if err != nil {
+			return nil, err
+		}
+		if deployedAt.IsZero() {
+			continue
+		}
+		ret.LeadTimes = append(ret.LeadTimes, ChangeLeadTime{
+			Number:     pr.Number,
+			OpenedAt:   pr.CreatedAt.Time,
+			MergedAt:   pr.MergedAt.Time,
+			DeployedAt: deployedAt,
+		})
+	}
+	ret.MedianLeadTime = medianLeadTime(ret.LeadTimes)
+	if total := ret.Deployments + ret.FailedDeployments; total > 0 {
+		ret.ChangeFailureRate = float64(ret.FailedDeployments+ret.Rollbacks) / float64(total)
+	}
+	return ret, nil
+}
+
+// doraDeployments returns the deployments of the window, oldest first, with their final state
+func doraDeployments(ctx context.Context, gh GitHub, opts DORAOptions) ([]doraDeployment, error) {
+	var ret []doraDeployment
+	for page := 1; ; page++ {
+		var batch []doraDeployment
+		path := fmt.Sprintf("/repos/%s/%s/deployments?environment=%s&per_page=100&page=%d", opts.Repo.Owner, opts.Repo.Name, url.QueryEscape(opts.Environment), page)
+		if err := gh.DoREST(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		done := len(batch) < 100
+		for _, d := range batch {
+			if d.CreatedAt.Before(opts.Since) {
+				done = true
+				continue
+			}
+			if !d.CreatedAt.Before(opts.Until) {
+				continue
+			}
+			var statuses []doraDeploymentStatus
+			if err := gh.DoREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/deployments/%d/statuses?per_page=1", opts.Repo.Owner, opts.Repo.Name, d.ID), nil, &statuses); err != nil {
+				return nil, fmt.Errorf("failed to list deployment statuses: %w", err)
+			}
+			if len(statuses) > 0 {
+				d.state = statuses[0].State
+			}
+			ret = append(ret, d)
+		}
+		if done {
+			break
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].CreatedAt.Before(ret[j].CreatedAt)
+	})
+	return ret, nil
+}
+
+func doraMergedPullRequests(ctx context.Context, gh GitHub, opts DORAOptions) ([]doraPRNode, error) {
+	var query struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes    []doraPRNode
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   githubv4.String
+				}
+			} `graphql:"pullRequests(states: [MERGED], first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC})"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(opts.Repo.Owner),
+		"name":   githubv4.String(opts.Repo.Name),
+		"cursor": (*githubv4.String)(nil),
+	}
+	var ret []doraPRNode
+	for {
+		if err := gh.QueryRaw(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query merged pull requests: %w", err)
+		}
+		olderThanWindow := false
+		for _, n := range query.Repository.PullRequests.Nodes {
+			// Ordered by update time and merging updates a pull request, so nothing after this merged in the window
+			if n.UpdatedAt.Before(opts.Since) {
+				olderThanWindow = true
+				break
+			}
+			if !n.MergedAt.Before(opts.Since) && n.MergedAt.Before(opts.Until) {
+				ret = append(ret, n)
+			}
+		}
+		if olderThanWindow || !query.Repository.PullRequests.PageInfo.HasNextPage {
+			return ret, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Repository.PullRequests.PageInfo.EndCursor)
+	}
+}
+
+func isRollback(pr doraPRNode, label string) bool {
+	if strings.HasPrefix(pr.Title, "Revert \"") {
+		return true
+	}
+	for _, l := range pr.Labels.Nodes {
+		if strings.EqualFold(l.Name, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstDeploymentContaining returns when the merge commit of pr was first deployed, or the zero time if it never was
+func firstDeploymentContaining(ctx context.Context, gh GitHub, repo RepoRef, pr doraPRNode, deployments []doraDeployment) (time.Time, error) {
+	if pr.MergeCommit.Oid == "" {
+		return time.Time{}, nil
+	}
+	for _, d := range deployments {
+		if d.CreatedAt.Before(pr.MergedAt.Time) {
+			continue
+		}
+		var compare struct {
+			Status string `json:"status"`
+		}
+		path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", repo.Owner, repo.Name, pr.MergeCommit.Oid, d.SHA)
+		if err := gh.DoREST(ctx, http.MethodGet, path, nil, &compare); err != nil {
+			return time.Time{}, fmt.Errorf("failed to compare commits: %w", err)
+		}
+		if compare.Status == "ahead" || compare.Status == "identical" {
+			return d.CreatedAt, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+func medianLeadTime(leadTimes []ChangeLeadTime) time.Duration {
+	if len(leadTimes) == 0 {
+		return 0
+	}
+	durations := make([]time.Duration, 0, len(leadTimes))
+	for _, l := range leadTimes {
+		durations = append(durations, l.LeadTime())
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	mid := len(durations) / 2
+	if len(durations)%2 == 1 {
+		return durations[mid]
+	}
+	return (durations[mid-1] + durations[mid]) / 2
+}
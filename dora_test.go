@@ -0,0 +1,26 @@
+package gogithub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedianLeadTime(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	leadTime := func(hours int) ChangeLeadTime {
+		return ChangeLeadTime{OpenedAt: start, DeployedAt: start.Add(time.Duration(hours) * time.Hour)}
+	}
+	require.Equal(t, time.Duration(0), medianLeadTime(nil))
+	require.Equal(t, 2*time.Hour, medianLeadTime([]ChangeLeadTime{leadTime(5), leadTime(1), leadTime(2)}))
+	require.Equal(t, 3*time.Hour, medianLeadTime([]ChangeLeadTime{leadTime(4), leadTime(1), leadTime(2), leadTime(10)}))
+}
+
+func TestIsRollback(t *testing.T) {
+	require.True(t, isRollback(doraPRNode{Title: `Revert "Add feature"`}, "rollback"))
+	require.False(t, isRollback(doraPRNode{Title: "Reverting to old behavior is not a revert"}, "rollback"))
+	labeled := doraPRNode{Title: "Fix outage"}
+	labeled.Labels.Nodes = append(labeled.Labels.Nodes, struct{ Name string }{Name: "Rollback"})
+	require.True(t, isRollback(labeled, "rollback"))
+}
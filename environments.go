@@ -0,0 +1,232 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+type Environment struct {
+	ID      int64
+	Name    string
+	HTMLURL string
+	// WaitTimer is how many minutes a deployment waits before it starts
+	WaitTimer         int
+	PreventSelfReview bool
+	Reviewers         []EnvironmentReviewer
+	// DeploymentBranchPolicy is nil when any branch can deploy
+	DeploymentBranchPolicy *DeploymentBranchPolicy
+}
+
+// EnvironmentReviewer is a user or team that can approve deployments
+type EnvironmentReviewer struct {
+	// Type is User or Team
+	Type string
+	ID   int64
+	// Name is the user login or the team slug.  It is only set when reading.
+	Name string
+}
+
+// DeploymentBranchPolicy restricts which branches can deploy.  Exactly one field must be true.
+type DeploymentBranchPolicy struct {
+	ProtectedBranches    bool `json:"protected_branches"`
+	CustomBranchPolicies bool `json:"custom_branch_policies"`
+}
+
+// EnvironmentInput is the configuration CreateOrUpdateEnvironment applies
+type EnvironmentInput struct {
+	WaitTimer         int
+	PreventSelfReview bool
+	// Reviewers need Type and ID
+	Reviewers              []EnvironmentReviewer
+	DeploymentBranchPolicy *DeploymentBranchPolicy
+	// BranchPatterns are the branch name patterns allowed to deploy when DeploymentBranchPolicy.CustomBranchPolicies is
+	// set.  Patterns missing on the environment are added and the others removed.
+	BranchPatterns []string
+}
+
+type environmentJSON struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name"`
+	HTMLURL         string `json:"html_url"`
+	ProtectionRules []struct {
+		Type              string `json:"type"`
+		WaitTimer         int    `json:"wait_timer"`
+		PreventSelfReview bool   `json:"prevent_self_review"`
+		Reviewers         []struct {
+			Type     string `json:"type"`
+			Reviewer struct {
+				ID    int64  `json:"id"`
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"reviewer"`
+		} `json:"reviewers"`
+	} `json:"protection_rules"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deployment_branch_policy"`
+}
+
+func (e *environmentJSON) toEnvironment() Environment {
+	ret := Environment{
+		ID:                     e.ID,
+		Name:                   e.Name,
+		HTMLURL:                e.HTMLURL,
+		DeploymentBranchPolicy: e.DeploymentBranchPolicy,
+	}
+	for _, rule := range e.ProtectionRules {
+		switch rule.Type {
+		case "wait_timer":
+			ret.WaitTimer = rule.WaitTimer
+		case "required_reviewers":
+			ret.PreventSelfReview = rule.PreventSelfReview
+			for _, r := range rule.Reviewers {
+				name := r.Reviewer.Login
+				if r.Type == "Team" {
+					name = r.Reviewer.Slug
+				}
+				ret.Reviewers = append(ret.Reviewers, EnvironmentReviewer{Type: r.Type, ID: r.Reviewer.ID, Name: name})
+			}
+		}
+	}
+	return ret
+}
+
+func (g *GithubGraphqlAPI) ListEnvironments(ctx context.Context, owner string, name string) ([]Environment, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[Environment], err error) {
+		ctx = withOperation(ctx, "ListEnvironments")
+		defer annotateError(&err, OperationError{Operation: "ListEnvironments", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListEnvironments", zap.String("owner", owner), zap.String("name", name), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListEnvironments")
+		var resp struct {
+			Environments []environmentJSON `json:"environments"`
+		}
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/environments?per_page=100&page=%d", owner, name, restPageNumber(cursor)), nil, &resp); err != nil {
+			return Page[Environment]{}, fmt.Errorf("failed to list environments: %w", err)
+		}
+		items := make([]Environment, 0, len(resp.Environments))
+		for i := range resp.Environments {
+			items = append(items, resp.Environments[i].toEnvironment())
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// CreateOrUpdateEnvironment creates the environment if needed and sets its protection rules to input
+func (g *GithubGraphqlAPI) CreateOrUpdateEnvironment(ctx context.Context, owner string, name string, environment string, input EnvironmentInput) (_ *Environment, err error) {
+	ctx = withOperation(ctx, "CreateOrUpdateEnvironment")
+	defer annotateError(&err, OperationError{Operation: "CreateOrUpdateEnvironment", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateOrUpdateEnvironment", zap.String("owner", owner), zap.String("name", name), zap.String("environment", environment))
+	defer g.logger(ctx).Debug("Done CreateOrUpdateEnvironment")
+	reviewers := make([]map[string]interface{}, 0, len(input.Reviewers))
+	for _, r := range input.Reviewers {
+		reviewers = append(reviewers, map[string]interface{}{"type": r.Type, "id": r.ID})
+	}
+	body := map[string]interface{}{
+		"wait_timer":               input.WaitTimer,
+		"prevent_self_review":      input.PreventSelfReview,
+		"reviewers":                reviewers,
+		"deployment_branch_policy": input.DeploymentBranchPolicy,
+	}
+	envPath := fmt.Sprintf("/repos/%s/%s/environments/%s", owner, name, url.PathEscape(environment))
+	var resp environmentJSON
+	if err := g.doREST(ctx, http.MethodPut, envPath, body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update environment: %w", err)
+	}
+	if input.DeploymentBranchPolicy != nil && input.DeploymentBranchPolicy.CustomBranchPolicies {
+		if err := g.syncBranchPolicies(ctx, envPath, input.BranchPatterns); err != nil {
+			return nil, err
+		}
+	}
+	ret := resp.toEnvironment()
+	return &ret, nil
+}
+
+func (g *GithubGraphqlAPI) syncBranchPolicies(ctx context.Context, envPath string, patterns []string) error {
+	var existing struct {
+		BranchPolicies []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"branch_policies"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, envPath+"/deployment-branch-policies?per_page=100", nil, &existing); err != nil {
+		return fmt.Errorf("failed to list deployment branch policies: %w", err)
+	}
+	want := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		want[p] = true
+	}
+	for _, p := range existing.BranchPolicies {
+		if want[p.Name] {
+			delete(want, p.Name)
+			continue
+		}
+		if err := g.doREST(ctx, http.MethodDelete, fmt.Sprintf("%s/deployment-branch-policies/%d", envPath, p.ID), nil, nil); err != nil {
+			return fmt.Errorf("failed to delete deployment branch policy %s: %w", p.Name, err)
+		}
+	}
+	for _, p := range patterns {
+		if !want[p] {
+			continue
+		}
+		if err := g.doREST(ctx, http.MethodPost, envPath+"/deployment-branch-policies", map[string]string{"name": p, "type": "branch"}, nil); err != nil {
+			return fmt.Errorf("failed to create deployment branch policy %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// ApprovePendingDeployment approves the deployments of a workflow run waiting on review for the named environments
+func (g *GithubGraphqlAPI) ApprovePendingDeployment(ctx context.Context, owner string, name string, runID int64, environments []string, comment string) (err error) {
+	ctx = withOperation(ctx, "ApprovePendingDeployment")
+	defer annotateError(&err, OperationError{Operation: "ApprovePendingDeployment", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ApprovePendingDeployment", zap.String("owner", owner), zap.String("name", name), zap.Int64("runID", runID), zap.Strings("environments", environments))
+	defer g.logger(ctx).Debug("Done ApprovePendingDeployment")
+	return g.reviewPendingDeployment(ctx, owner, name, runID, environments, "approved", comment)
+}
+
+// RejectPendingDeployment rejects the deployments of a workflow run waiting on review for the named environments
+func (g *GithubGraphqlAPI) RejectPendingDeployment(ctx context.Context, owner string, name string, runID int64, environments []string, comment string) (err error) {
+	ctx = withOperation(ctx, "RejectPendingDeployment")
+	defer annotateError(&err, OperationError{Operation: "RejectPendingDeployment", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("RejectPendingDeployment", zap.String("owner", owner), zap.String("name", name), zap.Int64("runID", runID), zap.Strings("environments", environments))
+	defer g.logger(ctx).Debug("Done RejectPendingDeployment")
+	return g.reviewPendingDeployment(ctx, owner, name, runID, environments, "rejected", comment)
+}
+
+func (g *GithubGraphqlAPI) reviewPendingDeployment(ctx context.Context, owner string, name string, runID int64, environments []string, state string, comment string) error {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/pending_deployments", owner, name, runID)
+	var pending []struct {
+		Environment struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"environment"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, path, nil, &pending); err != nil {
+		return fmt.Errorf("failed to list pending deployments: %w", err)
+	}
+	ids := make([]int64, 0, len(environments))
+	for _, env := range environments {
+		found := false
+		for _, p := range pending {
+			if p.Environment.Name == env {
+				ids = append(ids, p.Environment.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no deployment to %s is pending on run %d", env, runID)
+		}
+	}
+	body := map[string]interface{}{
+		"environment_ids": ids,
+		"state":           state,
+		"comment":         comment,
+	}
+	if err := g.doREST(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to review pending deployment: %w", err)
+	}
+	return nil
+}
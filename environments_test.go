@@ -0,0 +1,70 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEnvironments(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"environments":[{"id":1,"name":"production","protection_rules":[{"type":"wait_timer","wait_timer":30},{"type":"required_reviewers","reviewers":[{"type":"User","reviewer":{"id":5,"login":"alice"}},{"type":"Team","reviewer":{"id":6,"slug":"sre"}}]}],"deployment_branch_policy":{"protected_branches":true,"custom_branch_policies":false}}]}`))
+	})
+	envs, err := g.ListEnvironments(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	require.Equal(t, 30, envs[0].WaitTimer)
+	require.Equal(t, []EnvironmentReviewer{{Type: "User", ID: 5, Name: "alice"}, {Type: "Team", ID: 6, Name: "sre"}}, envs[0].Reviewers)
+	require.True(t, envs[0].DeploymentBranchPolicy.ProtectedBranches)
+}
+
+func TestCreateOrUpdateEnvironment_SyncsBranchPatterns(t *testing.T) {
+	var calls []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"branch_policies":[{"id":1,"name":"main"},{"id":2,"name":"old/*"}]}`))
+		case r.Method == http.MethodPost:
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "release/*", body["name"])
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":1,"name":"production"}`))
+		}
+	})
+	_, err := g.CreateOrUpdateEnvironment(context.Background(), "o", "r", "production", EnvironmentInput{
+		DeploymentBranchPolicy: &DeploymentBranchPolicy{CustomBranchPolicies: true},
+		BranchPatterns:         []string{"main", "release/*"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"PUT /repos/o/r/environments/production",
+		"GET /repos/o/r/environments/production/deployment-branch-policies",
+		"DELETE /repos/o/r/environments/production/deployment-branch-policies/2",
+		"POST /repos/o/r/environments/production/deployment-branch-policies",
+	}, calls)
+}
+
+func TestApprovePendingDeployment(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[{"environment":{"id":11,"name":"staging"}},{"environment":{"id":12,"name":"production"}}]`))
+			return
+		}
+		var body struct {
+			EnvironmentIDs []int64 `json:"environment_ids"`
+			State          string  `json:"state"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, []int64{12}, body.EnvironmentIDs)
+		require.Equal(t, "approved", body.State)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	require.NoError(t, g.ApprovePendingDeployment(context.Background(), "o", "r", 99, []string{"production"}, "ship it"))
+	require.Error(t, g.ApprovePendingDeployment(context.Background(), "o", "r", 99, []string{"qa"}, ""))
+}
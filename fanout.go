@@ -0,0 +1,115 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RepoRef identifies a repository by owner and name
+type RepoRef struct {
+	Owner string
+	Name  string
+}
+
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// FanOutError collects the repositories an operation failed for.  Repositories not listed succeeded, unless the
+// fan-out was stopped early.
+type FanOutError struct {
+	Errors map[RepoRef]error
+	Total  int
+}
+
+func (e *FanOutError) Error() string {
+	keys := make([]RepoRef, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", k, e.Errors[k]))
+	}
+	return fmt.Sprintf("%d of %d repositories failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *FanOutError) Unwrap() []error {
+	ret := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		ret = append(ret, err)
+	}
+	return ret
+}
+
+type fanOutConfig struct {
+	concurrency int
+	stopOnError bool
+	progress    func(done int, total int, repo RepoRef, err error)
+}
+
+// FanOutOption configures FanOut
+type FanOutOption func(*fanOutConfig)
+
+// FanOutConcurrency sets how many repositories are processed at once.  The default is 10.
+func FanOutConcurrency(n int) FanOutOption {
+	return func(c *fanOutConfig) {
+		c.concurrency = n
+	}
+}
+
+// FanOutStopOnError cancels the remaining repositories after the first failure instead of running them all
+func FanOutStopOnError() FanOutOption {
+	return func(c *fanOutConfig) {
+		c.stopOnError = true
+	}
+}
+
+// FanOutProgress calls progress after each repository finishes.  Calls are serialized.
+func FanOutProgress(progress func(done int, total int, repo RepoRef, err error)) FanOutOption {
+	return func(c *fanOutConfig) {
+		c.progress = progress
+	}
+}
+
+// FanOut runs fn for every repository with bounded concurrency.  A failing repository does not stop the others
+// (unless FanOutStopOnError is given): every failure is collected into the returned *FanOutError, which is nil when all
+// repositories succeed.
+func FanOut(ctx context.Context, repos []RepoRef, fn func(ctx context.Context, repo RepoRef) error, opts ...FanOutOption) error {
+	cfg := fanOutConfig{
+		concurrency: 10,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+	runner := BulkRunner[RepoRef]{
+		Concurrency: cfg.concurrency,
+		StopOnError: cfg.stopOnError,
+	}
+	if cfg.progress != nil {
+		runner.Progress = func(p BulkProgress[RepoRef]) {
+			cfg.progress(p.Done, p.Total, p.Item, p.Err)
+		}
+	}
+	err := runner.Run(ctx, repos, fn)
+	if err == nil {
+		return nil
+	}
+	bulkErr := err.(*BulkError[RepoRef])
+	errs := make(map[RepoRef]error, len(bulkErr.Errors))
+	for _, itemErr := range bulkErr.Errors {
+		errs[itemErr.Item] = itemErr.Err
+	}
+	return &FanOutError{
+		Errors: errs,
+		Total:  len(repos),
+	}
+}
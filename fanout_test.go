@@ -0,0 +1,46 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanOut(t *testing.T) {
+	repos := []RepoRef{{"cresta", "a"}, {"cresta", "b"}, {"cresta", "c"}, {"cresta", "d"}}
+	var running, maxRunning int32
+	progressCalls := 0
+	err := FanOut(context.Background(), repos, func(ctx context.Context, repo RepoRef) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		if repo.Name == "b" {
+			return errors.New("boom")
+		}
+		return nil
+	}, FanOutConcurrency(2), FanOutProgress(func(done int, total int, repo RepoRef, err error) {
+		progressCalls++
+		require.Equal(t, 4, total)
+	}))
+	var fanErr *FanOutError
+	require.True(t, errors.As(err, &fanErr))
+	require.Len(t, fanErr.Errors, 1)
+	require.EqualError(t, fanErr.Errors[RepoRef{"cresta", "b"}], "boom")
+	require.Equal(t, 4, progressCalls)
+	require.LessOrEqual(t, maxRunning, int32(2))
+	require.Contains(t, err.Error(), "1 of 4 repositories failed: cresta/b: boom")
+}
+
+func TestFanOut_AllSucceed(t *testing.T) {
+	require.NoError(t, FanOut(context.Background(), []RepoRef{{"cresta", "a"}}, func(ctx context.Context, repo RepoRef) error {
+		return nil
+	}))
+}
@@ -0,0 +1,93 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// DefaultFreezeContext is the required status context FreezeManager adds to freeze merges
+const DefaultFreezeContext = "merge-freeze"
+
+// FreezeManager freezes merges across repositories by requiring a status context on their default branch that nothing
+// ever reports.  Every branch must already be protected with required status checks enabled.
+type FreezeManager struct {
+	gh GitHub
+	// Context is the status context required while frozen.  Defaults to DefaultFreezeContext.
+	Context string
+	// FanOutOptions control how many repositories are updated at once
+	FanOutOptions []FanOutOption
+}
+
+func NewFreezeManager(gh GitHub) *FreezeManager {
+	return &FreezeManager{
+		gh:      gh,
+		Context: DefaultFreezeContext,
+	}
+}
+
+func (f *FreezeManager) contextsPath(ctx context.Context, repo RepoRef) (string, error) {
+	r, err := f.gh.GetRepository(ctx, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	return fmt.Sprintf("/repos/%s/%s/branches/%s/protection/required_status_checks/contexts", repo.Owner, repo.Name, url.PathEscape(r.DefaultBranch)), nil
+}
+
+// Freeze blocks merges into the default branch of every repo.  Repos already frozen are left alone.
+func (f *FreezeManager) Freeze(ctx context.Context, repos []RepoRef) error {
+	return FanOut(ctx, repos, func(ctx context.Context, repo RepoRef) error {
+		path, err := f.contextsPath(ctx, repo)
+		if err != nil {
+			return err
+		}
+		if err := f.gh.DoREST(ctx, http.MethodPost, path, map[string][]string{"contexts": {f.Context}}, nil); err != nil {
+			return fmt.Errorf("failed to require %s: %w", f.Context, err)
+		}
+		return nil
+	}, f.FanOutOptions...)
+}
+
+// Unfreeze allows merges again by removing the freeze context from the required checks of every repo
+func (f *FreezeManager) Unfreeze(ctx context.Context, repos []RepoRef) error {
+	return FanOut(ctx, repos, func(ctx context.Context, repo RepoRef) error {
+		path, err := f.contextsPath(ctx, repo)
+		if err != nil {
+			return err
+		}
+		if err := f.gh.DoREST(ctx, http.MethodDelete, path, map[string][]string{"contexts": {f.Context}}, nil); err != nil {
+			return fmt.Errorf("failed to stop requiring %s: %w", f.Context, err)
+		}
+		return nil
+	}, f.FanOutOptions...)
+}
+
+// State reports which repos are frozen.  Repos that failed are missing from the map and listed in the error.
+func (f *FreezeManager) State(ctx context.Context, repos []RepoRef) (map[RepoRef]bool, error) {
+	var mu sync.Mutex
+	ret := make(map[RepoRef]bool, len(repos))
+	err := FanOut(ctx, repos, func(ctx context.Context, repo RepoRef) error {
+		path, err := f.contextsPath(ctx, repo)
+		if err != nil {
+			return err
+		}
+		var contexts []string
+		if err := f.gh.DoREST(ctx, http.MethodGet, path, nil, &contexts); err != nil {
+			return fmt.Errorf("failed to list required contexts: %w", err)
+		}
+		frozen := false
+		for _, c := range contexts {
+			if c == f.Context {
+				frozen = true
+				break
+			}
+		}
+		mu.Lock()
+		ret[repo] = frozen
+		mu.Unlock()
+		return nil
+	}, f.FanOutOptions...)
+	return ret, err
+}
@@ -0,0 +1,40 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeManager(t *testing.T) {
+	contexts := []string{"build"}
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/branches/main/protection/required_status_checks/contexts", r.URL.Path)
+		switch r.Method {
+		case http.MethodPost:
+			contexts = append(contexts, DefaultFreezeContext)
+		case http.MethodDelete:
+			contexts = contexts[:1]
+		}
+		_, _ = w.Write([]byte(`["` + contexts[len(contexts)-1] + `"]`))
+	})
+	g.repoInfoCache.DefaultExpiry = time.Hour
+	info := &RepositoryInfo{}
+	info.Repository.DefaultBranchRef.Name = "main"
+	g.repoInfoCache.Set(repoKey{owner: "o", name: "r"}, info)
+	repo := RepoRef{Owner: "o", Name: "r"}
+	f := NewFreezeManager(g)
+
+	require.NoError(t, f.Freeze(context.Background(), []RepoRef{repo}))
+	state, err := f.State(context.Background(), []RepoRef{repo})
+	require.NoError(t, err)
+	require.True(t, state[repo])
+
+	require.NoError(t, f.Unfreeze(context.Background(), []RepoRef{repo}))
+	state, err = f.State(context.Background(), []RepoRef{repo})
+	require.NoError(t, err)
+	require.False(t, state[repo])
+}
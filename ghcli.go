@@ -0,0 +1,152 @@
+package gogithub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultGithubHost = "github.com"
+
+// githubCLI finds the token the `gh` CLI is logged in with.  Its environment is injectable for tests.
+type githubCLI struct {
+	getenv  func(string) string
+	homeDir func() (string, error)
+	// authToken runs `gh auth token`, which reads tokens gh keeps in the system keyring.  nil never runs gh.
+	authToken func(ctx context.Context, host string) (string, error)
+}
+
+func newGithubCLI(allowExec bool) githubCLI {
+	ret := githubCLI{
+		getenv:  os.Getenv,
+		homeDir: os.UserHomeDir,
+	}
+	if allowExec {
+		ret.authToken = runGithubCLIAuthToken
+	}
+	return ret
+}
+
+func runGithubCLIAuthToken(ctx context.Context, host string) (string, error) {
+	path, err := exec.LookPath("gh")
+	if err != nil {
+		return "", fmt.Errorf("gh CLI not found: %w", err)
+	}
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "auth", "token", "--hostname", host)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run gh auth token: %w", err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// configDir follows gh: GH_CONFIG_DIR, then XDG_CONFIG_HOME/gh, then ~/.config/gh
+func (c githubCLI) configDir() string {
+	if dir := c.getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := c.getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, err := c.homeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// host is GH_HOST, or the host of the configured BaseURL
+func (c githubCLI) host(cfg *NewGQLClientConfig) string {
+	if host := c.getenv("GH_HOST"); host != "" {
+		return host
+	}
+	if u, err := url.Parse(cfg.webBaseURL()); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return defaultGithubHost
+}
+
+// ghHostsFile is hosts.yml.  gh versions supporting several accounts per host list them under users, and leave
+// oauth_token empty when the tokens are in the system keyring.
+type ghHostsFile map[string]ghHostConfig
+
+type ghHostConfig struct {
+	Token string `yaml:"oauth_token"`
+	// User is the active account
+	User  string `yaml:"user"`
+	Users map[string]struct {
+		Token string `yaml:"oauth_token"`
+	} `yaml:"users"`
+}
+
+func (h ghHostsFile) lookup(host string) (ghHostConfig, bool) {
+	for k, v := range h {
+		if strings.EqualFold(k, host) {
+			return v, true
+		}
+	}
+	return ghHostConfig{}, false
+}
+
+func (h ghHostConfig) token() string {
+	if h.Token != "" {
+		return h.Token
+	}
+	return h.Users[h.User].Token
+}
+
+// token returns the token gh uses for host: from its env vars, then hosts.yml, then the keyring through `gh auth
+// token`.  It is empty when gh is not logged in to host.
+func (c githubCLI) token(ctx context.Context, host string) string {
+	envVars := []string{"GH_TOKEN"}
+	if !strings.EqualFold(host, defaultGithubHost) {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, v := range envVars {
+		if token := c.getenv(v); token != "" {
+			return token
+		}
+	}
+	dir := c.configDir()
+	if dir == "" {
+		return ""
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	var hosts ghHostsFile
+	if err := yaml.Unmarshal(b, &hosts); err != nil {
+		return ""
+	}
+	auth, ok := hosts.lookup(host)
+	if !ok {
+		return ""
+	}
+	if token := auth.token(); token != "" {
+		return token
+	}
+	if c.authToken == nil {
+		return ""
+	}
+	token, err := c.authToken(ctx, host)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// tokenFromGithubCLI returns the token gh is logged in with and the host it is for
+func tokenFromGithubCLI(ctx context.Context, cfg *NewGQLClientConfig) (token string, host string) {
+	cli := newGithubCLI(!cfg.DisableGithubCLIExec)
+	host = cli.host(cfg)
+	return cli.token(ctx, host), host
+}
@@ -0,0 +1,63 @@
+package gogithub
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubCLI_Token(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(`
+Github.com:
+    oauth_token: legacy-token
+ghe.example.com:
+    user: alice
+    users:
+        alice:
+            oauth_token: alice-token
+keyring.example.com:
+    user: bob
+    users:
+        bob: {}
+`), 0o600))
+	env := map[string]string{"GH_CONFIG_DIR": dir}
+	var ranFor string
+	cli := githubCLI{
+		getenv:  func(k string) string { return env[k] },
+		homeDir: func() (string, error) { return "/nonexistent", nil },
+		authToken: func(_ context.Context, host string) (string, error) {
+			ranFor = host
+			return "keyring-token", nil
+		},
+	}
+	ctx := context.Background()
+	require.Equal(t, "legacy-token", cli.token(ctx, "github.com"))
+	require.Equal(t, "alice-token", cli.token(ctx, "ghe.example.com"))
+	require.Equal(t, "", ranFor)
+	require.Equal(t, "keyring-token", cli.token(ctx, "keyring.example.com"))
+	require.Equal(t, "keyring.example.com", ranFor)
+	require.Equal(t, "", cli.token(ctx, "unknown.example.com"))
+
+	cli.authToken = nil
+	require.Equal(t, "", cli.token(ctx, "keyring.example.com"))
+
+	env["GH_ENTERPRISE_TOKEN"] = "enterprise-env"
+	require.Equal(t, "enterprise-env", cli.token(ctx, "ghe.example.com"))
+	require.Equal(t, "legacy-token", cli.token(ctx, "github.com"))
+}
+
+func TestGithubCLI_Host(t *testing.T) {
+	env := map[string]string{}
+	cli := githubCLI{getenv: func(k string) string { return env[k] }}
+	require.Equal(t, "github.com", cli.host(&NewGQLClientConfig{}))
+	require.Equal(t, "ghe.example.com", cli.host(&NewGQLClientConfig{BaseURL: "https://ghe.example.com/api/v3"}))
+	env["GH_HOST"] = "other.example.com"
+	require.Equal(t, "other.example.com", cli.host(&NewGQLClientConfig{}))
+
+	env = map[string]string{"XDG_CONFIG_HOME": "/xdg"}
+	require.Equal(t, filepath.Join("/xdg", "gh"), cli.configDir())
+}
@@ -0,0 +1,140 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GistFile is a file of a gist
+type GistFile struct {
+	Filename string `json:"filename"`
+	Language string `json:"language"`
+	Size     int    `json:"size"`
+	RawURL   string `json:"raw_url"`
+	// Content is cut to one megabyte when Truncated is set; download RawURL for the whole file
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated"`
+}
+
+// Gist is a gist with its files, keyed by file name
+type Gist struct {
+	ID          string
+	HTMLURL     string
+	Description string
+	Public      bool
+	Owner       string
+	Files       map[string]GistFile
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// GistUpdate lists the changes UpdateGist applies.  Files not listed are left untouched.
+type GistUpdate struct {
+	// Description, if set, replaces the description
+	Description *string
+	// Files are the contents of files to create or replace, keyed by file name
+	Files map[string]string
+	// DeleteFiles are the names of files to remove
+	DeleteFiles []string
+}
+
+type gistJSON struct {
+	ID          string `json:"id"`
+	HTMLURL     string `json:"html_url"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+	Owner       *struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Files     map[string]GistFile `json:"files"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func (g *gistJSON) toGist() *Gist {
+	ret := &Gist{
+		ID:          g.ID,
+		HTMLURL:     g.HTMLURL,
+		Description: g.Description,
+		Public:      g.Public,
+		Files:       g.Files,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+	if g.Owner != nil {
+		ret.Owner = g.Owner.Login
+	}
+	return ret
+}
+
+type gistFileContent struct {
+	Content string `json:"content"`
+}
+
+// CreateGist creates a gist of files, keyed by file name, owned by the authenticated user.  Secret gists, with public
+// false, are unlisted but readable by anyone with the URL.  GitHub App installation tokens cannot create gists.
+func (g *GithubGraphqlAPI) CreateGist(ctx context.Context, description string, public bool, files map[string]string) (_ *Gist, err error) {
+	ctx = withOperation(ctx, "CreateGist")
+	defer annotateError(&err, OperationError{Operation: "CreateGist"})
+	g.logger(ctx).Debug("CreateGist", zap.String("description", description), zap.Bool("public", public), zap.Int("files", len(files)))
+	defer g.logger(ctx).Debug("Done CreateGist")
+	body := map[string]interface{}{
+		"description": description,
+		"public":      public,
+		"files":       gistFilesBody(files, nil),
+	}
+	var resp gistJSON
+	if err := g.doREST(ctx, http.MethodPost, "/gists", body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create gist: %w", err)
+	}
+	return resp.toGist(), nil
+}
+
+// UpdateGist changes the description and files of a gist
+func (g *GithubGraphqlAPI) UpdateGist(ctx context.Context, id string, update GistUpdate) (_ *Gist, err error) {
+	ctx = withOperation(ctx, "UpdateGist")
+	defer annotateError(&err, OperationError{Operation: "UpdateGist"})
+	g.logger(ctx).Debug("UpdateGist", zap.String("id", id), zap.Int("files", len(update.Files)), zap.Strings("deleteFiles", update.DeleteFiles))
+	defer g.logger(ctx).Debug("Done UpdateGist")
+	body := map[string]interface{}{
+		"files": gistFilesBody(update.Files, update.DeleteFiles),
+	}
+	if update.Description != nil {
+		body["description"] = *update.Description
+	}
+	var resp gistJSON
+	if err := g.doREST(ctx, http.MethodPatch, "/gists/"+url.PathEscape(id), body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update gist %s: %w", id, err)
+	}
+	return resp.toGist(), nil
+}
+
+// GetGist returns a gist with the content of its files
+func (g *GithubGraphqlAPI) GetGist(ctx context.Context, id string) (_ *Gist, err error) {
+	ctx = withOperation(ctx, "GetGist")
+	defer annotateError(&err, OperationError{Operation: "GetGist"})
+	g.logger(ctx).Debug("GetGist", zap.String("id", id))
+	defer g.logger(ctx).Debug("Done GetGist")
+	var resp gistJSON
+	if err := g.doREST(ctx, http.MethodGet, "/gists/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get gist %s: %w", id, err)
+	}
+	return resp.toGist(), nil
+}
+
+// gistFilesBody encodes files to write and, as null, files to delete
+func gistFilesBody(files map[string]string, deleteFiles []string) map[string]*gistFileContent {
+	ret := make(map[string]*gistFileContent, len(files)+len(deleteFiles))
+	for name, content := range files {
+		ret[name] = &gistFileContent{Content: content}
+	}
+	for _, name := range deleteFiles {
+		ret[name] = nil
+	}
+	return ret
+}
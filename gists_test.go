@@ -0,0 +1,49 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGists(t *testing.T) {
+	var created, updated map[string]interface{}
+	gist := `{"id":"aa5a315d","html_url":"https://gist.github.com/aa5a315d","description":"build log","public":false,"owner":{"login":"bot"},"files":{"build.log":{"filename":"build.log","size":5,"content":"hello","truncated":false}}}`
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/gists":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(gist))
+		case r.Method == http.MethodPatch && r.URL.Path == "/gists/aa5a315d":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(gist))
+		case r.Method == http.MethodGet && r.URL.Path == "/gists/aa5a315d":
+			_, _ = w.Write([]byte(gist))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	newGist, err := g.CreateGist(ctx, "build log", false, map[string]string{"build.log": "hello"})
+	require.NoError(t, err)
+	require.Equal(t, "aa5a315d", newGist.ID)
+	require.Equal(t, "bot", newGist.Owner)
+	require.Equal(t, map[string]interface{}{
+		"description": "build log",
+		"public":      false,
+		"files":       map[string]interface{}{"build.log": map[string]interface{}{"content": "hello"}},
+	}, created)
+
+	_, err = g.UpdateGist(ctx, "aa5a315d", GistUpdate{Files: map[string]string{"test.log": "ok"}, DeleteFiles: []string{"old.log"}})
+	require.NoError(t, err)
+	require.NotContains(t, updated, "description")
+	require.Equal(t, map[string]interface{}{"test.log": map[string]interface{}{"content": "ok"}, "old.log": nil}, updated["files"])
+
+	got, err := g.GetGist(ctx, "aa5a315d")
+	require.NoError(t, err)
+	require.Equal(t, "hello", got.Files["build.log"].Content)
+}
@@ -0,0 +1,122 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// FileChange is the new content of a file committed by CommitFiles
+type FileChange struct {
+	Path    string
+	Content []byte
+}
+
+// ResolveCommitSHA returns the commit SHA a branch, tag or abbreviated SHA points at.  Annotated tags are peeled to
+// their commit.
+func (g *GithubGraphqlAPI) ResolveCommitSHA(ctx context.Context, owner string, name string, ref string) (_ string, err error) {
+	ctx = withOperation(ctx, "ResolveCommitSHA")
+	defer annotateError(&err, OperationError{Operation: "ResolveCommitSHA", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ResolveCommitSHA", zap.String("owner", owner), zap.String("name", name), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done ResolveCommitSHA")
+	b, err := g.doRESTRaw(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits/%s", owner, name, escapePath(ref)), "application/vnd.github.sha")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// CreateBranch creates branch pointing at sha.  It fails if the branch already exists.
+func (g *GithubGraphqlAPI) CreateBranch(ctx context.Context, owner string, name string, branch string, sha string) (err error) {
+	ctx = withOperation(ctx, "CreateBranch")
+	defer annotateError(&err, OperationError{Operation: "CreateBranch", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateBranch", zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch), zap.String("sha", sha))
+	defer g.logger(ctx).Debug("Done CreateBranch")
+	body := map[string]string{
+		"ref": "refs/heads/" + branch,
+		"sha": sha,
+	}
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/git/refs", owner, name), body, nil); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// ResetBranch force moves an existing branch to sha, dropping any commits on it that sha does not contain
+func (g *GithubGraphqlAPI) ResetBranch(ctx context.Context, owner string, name string, branch string, sha string) (err error) {
+	ctx = withOperation(ctx, "ResetBranch")
+	defer annotateError(&err, OperationError{Operation: "ResetBranch", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ResetBranch", zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch), zap.String("sha", sha))
+	defer g.logger(ctx).Debug("Done ResetBranch")
+	body := map[string]interface{}{
+		"sha":   sha,
+		"force": true,
+	}
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/git/refs/heads/%s", owner, name, escapePath(branch)), body, nil); err != nil {
+		return fmt.Errorf("failed to reset branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+type gitTreeEntry struct {
+	Path    string `json:"path"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// CommitFiles commits files on top of branch as a single commit and moves the branch to it.  It returns the SHA of
+// the new commit.  Files are written as regular, non executable files.
+func (g *GithubGraphqlAPI) CommitFiles(ctx context.Context, owner string, name string, branch string, message string, files []FileChange) (_ string, err error) {
+	ctx = withOperation(ctx, "CommitFiles")
+	defer annotateError(&err, OperationError{Operation: "CommitFiles", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CommitFiles", zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch), zap.Int("files", len(files)))
+	defer g.logger(ctx).Debug("Done CommitFiles")
+	refPath := fmt.Sprintf("/repos/%s/%s/git/refs/heads/%s", owner, name, escapePath(branch))
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, refPath, nil, &ref); err != nil {
+		return "", fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+	var parent struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/git/commits/%s", owner, name, ref.Object.SHA), nil, &parent); err != nil {
+		return "", fmt.Errorf("failed to get head commit: %w", err)
+	}
+	entries := make([]gitTreeEntry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, gitTreeEntry{Path: f.Path, Mode: "100644", Type: "blob", Content: string(f.Content)})
+	}
+	var tree struct {
+		SHA string `json:"sha"`
+	}
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/git/trees", owner, name), map[string]interface{}{
+		"base_tree": parent.Tree.SHA,
+		"tree":      entries,
+	}, &tree); err != nil {
+		return "", fmt.Errorf("failed to create tree: %w", err)
+	}
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/git/commits", owner, name), map[string]interface{}{
+		"message": message,
+		"tree":    tree.SHA,
+		"parents": []string{ref.Object.SHA},
+	}, &commit); err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+	if err := g.doREST(ctx, http.MethodPatch, refPath, map[string]string{"sha": commit.SHA}, nil); err != nil {
+		return "", fmt.Errorf("failed to move branch %s: %w", branch, err)
+	}
+	return commit.SHA, nil
+}
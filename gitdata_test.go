@@ -0,0 +1,76 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCommitSHA(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/actions/checkout/commits/v4", r.URL.Path)
+		require.Equal(t, "application/vnd.github.sha", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte("b4ffde65f46336ab88eb53be808477a3936bae11"))
+	})
+	sha, err := g.ResolveCommitSHA(context.Background(), "actions", "checkout", "v4")
+	require.NoError(t, err)
+	require.Equal(t, "b4ffde65f46336ab88eb53be808477a3936bae11", sha)
+}
+
+func TestResetBranch(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/repos/o/r/git/refs/heads/pin-actions", r.URL.Path)
+		var body struct {
+			SHA   string `json:"sha"`
+			Force bool   `json:"force"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "base", body.SHA)
+		require.True(t, body.Force)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	require.NoError(t, g.ResetBranch(context.Background(), "o", "r", "pin-actions", "base"))
+}
+
+func TestCommitFiles(t *testing.T) {
+	var steps []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		steps = append(steps, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/o/r/git/refs/heads/pin":
+			_, _ = w.Write([]byte(`{"object":{"sha":"head"}}`))
+		case "GET /repos/o/r/git/commits/head":
+			_, _ = w.Write([]byte(`{"tree":{"sha":"basetree"}}`))
+		case "POST /repos/o/r/git/trees":
+			var body struct {
+				BaseTree string         `json:"base_tree"`
+				Tree     []gitTreeEntry `json:"tree"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "basetree", body.BaseTree)
+			require.Equal(t, []gitTreeEntry{{Path: "a.yaml", Mode: "100644", Type: "blob", Content: "x"}}, body.Tree)
+			_, _ = w.Write([]byte(`{"sha":"newtree"}`))
+		case "POST /repos/o/r/git/commits":
+			var body struct {
+				Tree    string   `json:"tree"`
+				Parents []string `json:"parents"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "newtree", body.Tree)
+			require.Equal(t, []string{"head"}, body.Parents)
+			_, _ = w.Write([]byte(`{"sha":"newcommit"}`))
+		case "PATCH /repos/o/r/git/refs/heads/pin":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	sha, err := g.CommitFiles(context.Background(), "o", "r", "pin", "msg", []FileChange{{Path: "a.yaml", Content: []byte("x")}})
+	require.NoError(t, err)
+	require.Equal(t, "newcommit", sha)
+	require.Len(t, steps, 5)
+}
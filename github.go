@@ -1,14 +1,13 @@
 package gogithub
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
@@ -16,33 +15,442 @@ import (
 	"github.com/shurcooL/graphql"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
-	"gopkg.in/yaml.v3"
 )
 
+// GitHub is the full client.  Consumers that only need part of it should depend on one of the smaller interfaces it
+// embeds, which are also easier to fake in tests.
 type GitHub interface {
+	PullRequests
+	Reviews
+	Repositories
+	Workflows
+	Checks
+	Environments
+	Rulesets
+	Webhooks
+	ActionsSecrets
+	Organizations
+	Collaborators
+	Searcher
+	Gists
+	Discussions
+	Milestones
+	Notifications
+	Users
+	SecurityAlerts
+	GitData
+	Auth
+	RESTClient
+	GraphQLClient
+	Lifecycle
+}
+
+// Lifecycle controls the client itself
+type Lifecycle interface {
+	// Shutdown refuses new calls, waits for the calls in flight until ctx is done, and closes the idle connections of a
+	// transport the client built itself
+	Shutdown(ctx context.Context) error
+}
+
+// PullRequests creates, inspects and acts on pull requests
+type PullRequests interface {
 	// CreatePullRequest creates a PR of your current branch.  It assumes there is a remote branch with the
-	// exact same name.  It will fail if you're already on master or main.
+	// exact same name.  It will fail if you're already on master or main.  ParseRemote and GetRepository turn the
+	// local git remote into remoteRepositoryId, and localgit.Client does the whole flow for a checkout.  For a branch
+	// of a fork in the same network, pass remoteRefName as "owner:branch", or use CreateForkPullRequest.
 	CreatePullRequest(ctx context.Context, remoteRepositoryId graphql.ID, baseRefName string, remoteRefName string, title string, body string) (int64, error)
-	// RepositoryInfo returns special information about a remote repository
-	RepositoryInfo(ctx context.Context, owner string, name string) (*RepositoryInfo, error)
-	// FindPRForBranch returns the PR for this branch
+	// CreateForkPullRequest creates a PR into remoteRepositoryId of the branch headRefName of the fork
+	// headRepositoryId, such as one returned by ForkRepository
+	CreateForkPullRequest(ctx context.Context, remoteRepositoryId graphql.ID, headRepositoryId graphql.ID, baseRefName string, headRefName string, title string, body string) (int64, error)
+	// FindPRForBranch returns the PR for this branch, or 0 if there is none.  It fails if the branch has several PRs.
 	FindPRForBranch(ctx context.Context, owner string, name string, branch string) (int64, error)
-	// Self returns the current user
-	Self(ctx context.Context) (string, error)
+	// FindPRsForBranch returns every open PR for this branch, which can have PRs into several bases
+	FindPRsForBranch(ctx context.Context, owner string, name string, branch string) ([]BranchPullRequest, error)
 	// AcceptPullRequest approves a PR
 	AcceptPullRequest(ctx context.Context, approvalmessage string, owner string, name string, number int64) error
-	// MergePullRequest merges in a PR and closes it, but only if it's approved
+	// MergePullRequest merges in a PR and closes it, but only if it's approved.  On a branch with a merge queue, use
+	// EnqueuePullRequest instead.
 	MergePullRequest(ctx context.Context, owner string, name string, number int64) error
+	// MergePullRequestWithMethod merges a PR with method and returns the SHA of the commit it created
+	MergePullRequestWithMethod(ctx context.Context, owner string, name string, number int64, method githubv4.PullRequestMergeMethod) (string, error)
 	// EnablePullRequestAutoMerge enables auto-merge for the specified pull request
 	EnablePullRequestAutoMerge(ctx context.Context, owner string, name string, number int64) error
-	// FindPullRequest returns basic information for the specified pull request
+	// DisablePullRequestAutoMerge cancels auto-merge on the specified pull request
+	DisablePullRequestAutoMerge(ctx context.Context, owner string, name string, number int64) error
+	// GetMergeQueue returns the merge queue of a branch, or nil if the branch does not use one
+	GetMergeQueue(ctx context.Context, owner string, name string, branch string) (*MergeQueue, error)
+	// EnqueuePullRequest adds a PR to the merge queue of its base branch
+	EnqueuePullRequest(ctx context.Context, owner string, name string, number int64, opts EnqueueOptions) (*MergeQueueEntry, error)
+	// DequeuePullRequest removes a PR from the merge queue
+	DequeuePullRequest(ctx context.Context, owner string, name string, number int64) error
+	// FindPullRequest returns the specified pull request with its author, labels, merge state and auto-merge request
 	FindPullRequest(ctx context.Context, owner string, name string, number int64) (*PullRequest, error)
+	// GetPullRequestFull returns the pull request with its reviews, check rollup, labels, files and linked issues in a
+	// single query
+	GetPullRequestFull(ctx context.Context, owner string, name string, number int64) (*PullRequestFull, error)
+	// ListPullRequestCommits returns every commit of a PR, oldest first, with its check rollup
+	ListPullRequestCommits(ctx context.Context, owner string, name string, number int64) ([]PullRequestCommit, error)
 	// AddPRComment adds a comment to the specified pull request
 	AddPRComment(ctx context.Context, owner string, name string, number int64, body string) error
+	// ListPRComments returns the conversation comments of a PR, oldest first
+	ListPRComments(ctx context.Context, owner string, name string, number int64) ([]IssueComment, error)
+	// UpdateComment replaces the body of an issue or PR comment
+	UpdateComment(ctx context.Context, owner string, name string, commentID int64, body string) (*IssueComment, error)
+	// DeleteComment deletes an issue or PR comment
+	DeleteComment(ctx context.Context, owner string, name string, commentID int64) error
+	// UpsertPRComment edits the PR comment the client wrote tagged with marker to body, or creates it if there is none
+	UpsertPRComment(ctx context.Context, owner string, name string, number int64, marker string, body string) (*IssueComment, error)
+	// AddReaction reacts to an issue, PR or comment given by its node ID
+	AddReaction(ctx context.Context, subjectID githubv4.ID, content githubv4.ReactionContent) error
 	// FindPullRequestOid returns the OID of the PR
 	FindPullRequestOid(ctx context.Context, owner string, name string, number int64) (githubv4.ID, error)
-	GetAccessToken(ctx context.Context) (string, error)
+	// UpdatePullRequest changes the title, body, base branch or draft state of a PR
+	UpdatePullRequest(ctx context.Context, owner string, name string, number int64, updates PullRequestUpdate) error
+	// UpdatePullRequestBranch brings the PR head up to date with its base branch by merging or rebasing, like the
+	// "Update branch" button.  It returns ErrPullRequestHeadChanged if the head moved during the update.
+	UpdatePullRequestBranch(ctx context.Context, owner string, name string, number int64, method githubv4.PullRequestBranchUpdateMethod) error
+	// ClosePullRequest closes a PR without merging it
+	ClosePullRequest(ctx context.Context, owner string, name string, number int64) error
+	// ReopenPullRequest reopens a closed, unmerged PR
+	ReopenPullRequest(ctx context.Context, owner string, name string, number int64) error
+	// ListPullRequestFiles returns every file changed by a PR, with its patch
+	ListPullRequestFiles(ctx context.Context, owner string, name string, number int64) ([]PullRequestFile, error)
+	// GetPullRequestDiff returns the unified diff of a PR
+	GetPullRequestDiff(ctx context.Context, owner string, name string, number int64) (string, error)
+	// InvalidateBranch drops the cached FindPRForBranch result of a single branch
+	InvalidateBranch(owner string, name string, branch string)
+}
+
+// Reviews reads and writes pull request reviews
+type Reviews interface {
+	// CreateReview submits a review with any number of inline comments as one review
+	CreateReview(ctx context.Context, owner string, name string, number int64, review ReviewInput) (githubv4.ID, error)
+	// AddReviewComment comments on a single line of a file in the PR diff
+	AddReviewComment(ctx context.Context, owner string, name string, number int64, path string, line int, side githubv4.DiffSide, body string) error
+	// ListReviewThreads returns the review threads of a PR with their resolution state and comments
+	ListReviewThreads(ctx context.Context, owner string, name string, number int64) ([]ReviewThread, error)
+	// ResolveReviewThread marks a review thread as resolved
+	ResolveReviewThread(ctx context.Context, threadID githubv4.ID) error
+	// UnresolveReviewThread reopens a resolved review thread
+	UnresolveReviewThread(ctx context.Context, threadID githubv4.ID) error
+	// ListReviews returns every review of a PR, oldest first
+	ListReviews(ctx context.Context, owner string, name string, number int64) ([]PullRequestReview, error)
+	// DismissReview dismisses a review, for example a stale approval after a force push
+	DismissReview(ctx context.Context, reviewID githubv4.ID, message string) error
+	// RequestReviewers requests, or re-requests, a review from each login
+	RequestReviewers(ctx context.Context, owner string, name string, number int64, logins []string) error
+}
+
+// Repositories reads repository level information
+type Repositories interface {
+	// RepositoryInfo returns special information about a remote repository.  The result is cached; see
+	// reqmeta.WithCacheControl to bypass or refresh it.
+	RepositoryInfo(ctx context.Context, owner string, name string) (*RepositoryInfo, error)
+	// GetRepository returns the typed ID and default branch of a repository
+	GetRepository(ctx context.Context, ref RepoRef) (*Repository, error)
+	// ForkRepository forks a repository into intoOrg, or into the client's account when intoOrg is empty, and waits
+	// until the fork is ready
+	ForkRepository(ctx context.Context, owner string, name string, intoOrg string) (*Repository, error)
+	// GetTopics returns the topics of a repository
+	GetTopics(ctx context.Context, owner string, name string) ([]string, error)
+	// ReplaceTopics sets the topics of a repository, removing the ones not listed
+	ReplaceTopics(ctx context.Context, owner string, name string, topics []string) error
+	// UpdateRepositoryDescription sets the description of a repository
+	UpdateRepositoryDescription(ctx context.Context, owner string, name string, description string) error
+	// UpdateRepositoryHomepage sets the homepage URL of a repository
+	UpdateRepositoryHomepage(ctx context.Context, owner string, name string, homepage string) error
+	// ListDeployKeys returns the deploy keys of a repository
+	ListDeployKeys(ctx context.Context, owner string, name string) ([]DeployKey, error)
+	// AddDeployKey adds a deploy key, which can push unless readOnly
+	AddDeployKey(ctx context.Context, owner string, name string, title string, key string, readOnly bool) (*DeployKey, error)
+	// DeleteDeployKey removes a deploy key
+	DeleteDeployKey(ctx context.Context, owner string, name string, keyID int64) error
+	// GetFileContents returns the decoded content of a file on ref, or on the default branch if ref is empty
+	GetFileContents(ctx context.Context, owner string, name string, path string, ref string) ([]byte, error)
+	// GetCodeowners fetches and parses the CODEOWNERS file on ref, or on the default branch if ref is empty
+	GetCodeowners(ctx context.Context, owner string, name string, ref string) (*Codeowners, error)
+	// ExportSBOM returns the SPDX SBOM of the dependency graph of a repository
+	ExportSBOM(ctx context.Context, owner string, name string) (*SBOM, error)
+	// GetRepositoryTraffic returns the views, clones and top referrers of a repository over the last 14 days
+	GetRepositoryTraffic(ctx context.Context, owner string, name string) (*RepositoryTraffic, error)
+	// GetContributorStats returns the weekly activity of every contributor to a repository, waiting for GitHub to
+	// compute it
+	GetContributorStats(ctx context.Context, owner string, name string) ([]ContributorStats, error)
+	// InvalidateRepositoryInfo drops the cached RepositoryInfo, for example after the default branch changed
+	InvalidateRepositoryInfo(owner string, name string)
+	// InvalidateRepository drops every cached lookup of a repository, for example when a webhook reports a change
+	InvalidateRepository(owner string, name string)
+}
+
+// GitData reads and writes refs and commits through the Git database API
+type GitData interface {
+	// ResolveCommitSHA returns the commit SHA a branch, tag or abbreviated SHA points at
+	ResolveCommitSHA(ctx context.Context, owner string, name string, ref string) (string, error)
+	// CreateBranch creates a branch pointing at sha
+	CreateBranch(ctx context.Context, owner string, name string, branch string, sha string) error
+	// ResetBranch force moves an existing branch to sha
+	ResetBranch(ctx context.Context, owner string, name string, branch string, sha string) error
+	// CommitFiles commits files on top of branch as a single commit and returns its SHA
+	CommitFiles(ctx context.Context, owner string, name string, branch string, message string, files []FileChange) (string, error)
+	// GetRef returns the object a reference such as refs/heads/main or tags/v1.0.0 points at
+	GetRef(ctx context.Context, owner string, name string, ref string) (*GitRef, error)
+	// GetCommit returns a commit with its message, authors, parents and changed files
+	GetCommit(ctx context.Context, owner string, name string, ref string) (*Commit, error)
+	// CompareCommits lists the commits and files between base and head, and how far apart they are
+	CompareCommits(ctx context.Context, owner string, name string, base string, head string) (*CommitComparison, error)
+	// GetCommitSignatureStatus returns whether a commit is signed and verified, and by what key
+	GetCommitSignatureStatus(ctx context.Context, owner string, name string, oid string) (*CommitSignature, error)
+}
+
+// Workflows drives GitHub Actions
+type Workflows interface {
+	// TriggerWorkflow dispatches a workflow_dispatch event for workflow_id on ref
 	TriggerWorkflow(ctx context.Context, owner string, repo string, workflow_id string, ref string, inputs map[string]string) error
+	// ValidateWorkflowFile fetches a workflow file and reports structural problems, such as unknown triggers, invalid
+	// inputs or needs cycles
+	ValidateWorkflowFile(ctx context.Context, owner string, name string, path string, ref string) ([]WorkflowProblem, error)
+	// ListWorkflowFiles returns the content of every workflow file of a repository with a single query
+	ListWorkflowFiles(ctx context.Context, owner string, name string, ref string) ([]WorkflowFile, error)
+	// ListWorkflowJobs returns the jobs of the latest attempt of a workflow run with their steps and runners
+	ListWorkflowJobs(ctx context.Context, owner string, name string, runID int64) ([]WorkflowJob, error)
+	// GetJobLogs returns the plain text log of a workflow job
+	GetJobLogs(ctx context.Context, owner string, name string, jobID int64) ([]byte, error)
+}
+
+// Organizations enumerates the repositories, members and teams of an organization
+type Organizations interface {
+	// ListOrgRepositories returns the repositories of org matching filter, ordered by name
+	ListOrgRepositories(ctx context.Context, org string, filter OrgRepositoryFilter) ([]OrgRepository, error)
+	// OrgRepositories pages through the repositories of org matching filter, for orgs too large to list at once
+	OrgRepositories(org string, filter OrgRepositoryFilter, opts ...PaginatorOption) *Paginator[OrgRepository]
+	// ListOrgMembers returns every member of org with their role
+	ListOrgMembers(ctx context.Context, org string) ([]OrgMember, error)
+	// ListTeams returns every team of org visible to the client
+	ListTeams(ctx context.Context, org string) ([]Team, error)
+	// GetTeamBySlug returns a team of org
+	GetTeamBySlug(ctx context.Context, org string, slug string) (*Team, error)
+	// ListTeamRepositories returns the repositories a team can access with its permission on each
+	ListTeamRepositories(ctx context.Context, org string, slug string) ([]TeamRepository, error)
+	// GetOrgPlan returns the plan and seat usage of org.  Only owners can see it; others get ErrOrgPlanNotVisible.
+	GetOrgPlan(ctx context.Context, org string) (*OrgPlan, error)
+	// OrgMemberActivity returns the last activity of login in org since since, from the audit log when available
+	// and from their contributions otherwise
+	OrgMemberActivity(ctx context.Context, org string, login string, since time.Time) (*MemberActivity, error)
+	// ListAuditLogEvents returns the audit log events of org matching phrase since since, oldest first
+	ListAuditLogEvents(ctx context.Context, org string, phrase string, since time.Time) ([]AuditLogEvent, error)
+	// AuditLogEvents pages through the audit log events of org matching phrase since since, for logs too large to
+	// list at once
+	AuditLogEvents(org string, phrase string, since time.Time, opts ...PaginatorOption) *Paginator[AuditLogEvent]
+}
+
+// Collaborators grants and inspects access to repositories
+type Collaborators interface {
+	// AddTeamToRepository grants a team permission on a repository
+	AddTeamToRepository(ctx context.Context, org string, slug string, owner string, name string, permission RepositoryPermission) error
+	// AddCollaborator grants, or invites, a user with permission on a repository
+	AddCollaborator(ctx context.Context, owner string, name string, user string, permission RepositoryPermission) error
+	// RemoveCollaborator removes a user's direct access to a repository
+	RemoveCollaborator(ctx context.Context, owner string, name string, user string) error
+	// GetRepositoryPermission returns the effective role of user on a repository
+	GetRepositoryPermission(ctx context.Context, owner string, name string, user string) (string, error)
+}
+
+// Searcher runs GitHub searches
+type Searcher interface {
+	// Search runs a search query and returns typed, paged through, results
+	Search(ctx context.Context, query string, searchType githubv4.SearchType, opts SearchOptions) (*SearchResults, error)
+}
+
+// Environments manages deployment environments and their protection rules
+type Environments interface {
+	// ListEnvironments returns every environment of a repository with its protection rules
+	ListEnvironments(ctx context.Context, owner string, name string) ([]Environment, error)
+	// CreateOrUpdateEnvironment sets the reviewers, wait timer and branch policy of an environment
+	CreateOrUpdateEnvironment(ctx context.Context, owner string, name string, environment string, input EnvironmentInput) (*Environment, error)
+	// ApprovePendingDeployment approves a workflow run waiting on review to deploy to environments
+	ApprovePendingDeployment(ctx context.Context, owner string, name string, runID int64, environments []string, comment string) error
+	// RejectPendingDeployment rejects a workflow run waiting on review to deploy to environments
+	RejectPendingDeployment(ctx context.Context, owner string, name string, runID int64, environments []string, comment string) error
+}
+
+// Rulesets manages the rulesets of repositories, which replace classic branch protection
+type Rulesets interface {
+	// ListRulesets returns the rulesets of a repository, without their rules
+	ListRulesets(ctx context.Context, owner string, name string, includeParents bool) ([]Ruleset, error)
+	// GetRuleset returns a ruleset with its conditions, rules and bypass actors
+	GetRuleset(ctx context.Context, owner string, name string, id int64) (*Ruleset, error)
+	// CreateRepositoryRuleset creates a ruleset on a repository
+	CreateRepositoryRuleset(ctx context.Context, owner string, name string, input RulesetInput) (*Ruleset, error)
+	// UpdateRuleset replaces the configuration of a ruleset
+	UpdateRuleset(ctx context.Context, owner string, name string, id int64, input RulesetInput) (*Ruleset, error)
+}
+
+// Webhooks manages the webhooks of repositories
+type Webhooks interface {
+	// ListWebhooks returns the webhooks of a repository
+	ListWebhooks(ctx context.Context, owner string, name string) ([]Webhook, error)
+	// CreateWebhook adds a webhook to a repository
+	CreateWebhook(ctx context.Context, owner string, name string, input WebhookInput) (*Webhook, error)
+	// UpdateWebhook replaces the configuration of a webhook
+	UpdateWebhook(ctx context.Context, owner string, name string, hookID int64, input WebhookInput) (*Webhook, error)
+	// DeleteWebhook removes a webhook
+	DeleteWebhook(ctx context.Context, owner string, name string, hookID int64) error
+	// PingWebhook delivers a ping event to a webhook
+	PingWebhook(ctx context.Context, owner string, name string, hookID int64) error
+}
+
+// Gists publishes and reads gists of the authenticated user
+type Gists interface {
+	// CreateGist creates a gist of files keyed by file name
+	CreateGist(ctx context.Context, description string, public bool, files map[string]string) (*Gist, error)
+	// UpdateGist changes the description and files of a gist
+	UpdateGist(ctx context.Context, id string, update GistUpdate) (*Gist, error)
+	// GetGist returns a gist with its files
+	GetGist(ctx context.Context, id string) (*Gist, error)
+}
+
+// Discussions reads and posts repository discussions
+type Discussions interface {
+	// ListDiscussionCategories returns the discussion categories of a repository
+	ListDiscussionCategories(ctx context.Context, owner string, name string) ([]DiscussionCategory, error)
+	// ListDiscussions returns the discussions of a repository, of one category unless categoryID is nil
+	ListDiscussions(ctx context.Context, owner string, name string, categoryID githubv4.ID) ([]Discussion, error)
+	// CreateDiscussion starts a discussion in a category of a repository
+	CreateDiscussion(ctx context.Context, owner string, name string, categoryID githubv4.ID, title string, body string) (*Discussion, error)
+	// AddDiscussionComment comments on a discussion
+	AddDiscussionComment(ctx context.Context, discussionID githubv4.ID, body string) (*DiscussionComment, error)
+}
+
+// Milestones manages milestones and the issues and pull requests in them
+type Milestones interface {
+	// CreateMilestone creates a milestone in a repository
+	CreateMilestone(ctx context.Context, owner string, name string, input MilestoneInput) (*Milestone, error)
+	// ListMilestones returns the milestones of a repository in state, open, closed or all
+	ListMilestones(ctx context.Context, owner string, name string, state string) ([]Milestone, error)
+	// SetIssueMilestone puts an issue in a milestone, or out of its milestone for 0
+	SetIssueMilestone(ctx context.Context, owner string, name string, number int64, milestoneNumber int64) error
+	// SetPullRequestMilestone puts a PR in a milestone, or out of its milestone for 0
+	SetPullRequestMilestone(ctx context.Context, owner string, name string, number int64, milestoneNumber int64) error
+}
+
+// Notifications reads the authenticated user's notification inbox and manages their subscriptions
+type Notifications interface {
+	// ListNotifications returns the notification threads of the authenticated user
+	ListNotifications(ctx context.Context, opts NotificationOptions) ([]Notification, error)
+	// MarkThreadRead marks a notification thread as read
+	MarkThreadRead(ctx context.Context, threadID string) error
+	// SetRepositorySubscription watches, ignores or resets the subscription to a repository
+	SetRepositorySubscription(ctx context.Context, owner string, name string, subscription Subscription) error
+	// SetThreadSubscription watches, ignores or resets the subscription to a notification thread
+	SetThreadSubscription(ctx context.Context, threadID string, subscription Subscription) error
+}
+
+// Users looks up accounts and their organization memberships
+type Users interface {
+	// GetUser returns the account login
+	GetUser(ctx context.Context, login string) (*User, error)
+	// GetUserOrgMembership returns the membership of login in org, or nil if login is not a member
+	GetUserOrgMembership(ctx context.Context, org string, login string) (*OrgMembership, error)
+	// ListUserRepositories returns the repositories owned by login
+	ListUserRepositories(ctx context.Context, login string) ([]UserRepository, error)
+}
+
+// SecurityAlerts reads and triages the security alerts of repositories and organizations, and turns on the features
+// that raise them
+type SecurityAlerts interface {
+	// ListDependabotAlerts returns the Dependabot alerts of a repository, or of an organization if name is empty
+	ListDependabotAlerts(ctx context.Context, owner string, name string, filter DependabotAlertFilter) ([]DependabotAlert, error)
+	// DismissDependabotAlert dismisses an open Dependabot alert
+	DismissDependabotAlert(ctx context.Context, owner string, name string, number int64, reason DependabotDismissReason, comment string) (*DependabotAlert, error)
+	// ListCodeScanningAlerts returns the code scanning alerts of a repository, or of an organization if name is empty
+	ListCodeScanningAlerts(ctx context.Context, owner string, name string, filter CodeScanningAlertFilter) ([]CodeScanningAlert, error)
+	// UpdateCodeScanningAlert dismisses or reopens a code scanning alert
+	UpdateCodeScanningAlert(ctx context.Context, owner string, name string, number int64, update CodeScanningAlertUpdate) (*CodeScanningAlert, error)
+	// ListSecretScanningAlerts returns the secret scanning alerts of a repository, or of an organization if name is
+	// empty
+	ListSecretScanningAlerts(ctx context.Context, owner string, name string, filter SecretScanningAlertFilter) ([]SecretScanningAlert, error)
+	// EnableVulnerabilityAlerts turns on Dependabot alerts for a repository
+	EnableVulnerabilityAlerts(ctx context.Context, owner string, name string) error
+	// DisableVulnerabilityAlerts turns off Dependabot alerts for a repository
+	DisableVulnerabilityAlerts(ctx context.Context, owner string, name string) error
+	// VulnerabilityAlertsEnabled is true if Dependabot alerts are on for a repository
+	VulnerabilityAlertsEnabled(ctx context.Context, owner string, name string) (bool, error)
+	// EnableAutomatedSecurityFixes turns on Dependabot security updates for a repository
+	EnableAutomatedSecurityFixes(ctx context.Context, owner string, name string) error
+	// DisableAutomatedSecurityFixes turns off Dependabot security updates for a repository
+	DisableAutomatedSecurityFixes(ctx context.Context, owner string, name string) error
+	// AutomatedSecurityFixesEnabled is true if Dependabot security updates are on for a repository
+	AutomatedSecurityFixesEnabled(ctx context.Context, owner string, name string) (bool, error)
+}
+
+// ActionsSecrets manages GitHub Actions secrets and variables of organizations, repositories and environments
+type ActionsSecrets interface {
+	// ListSecrets returns the names and dates of the secrets in scope
+	ListSecrets(ctx context.Context, scope SecretScope) ([]ActionsSecret, error)
+	// GetSecretPublicKey returns the key secrets in scope must be sealed with
+	GetSecretPublicKey(ctx context.Context, scope SecretScope) (*SecretPublicKey, error)
+	// SetSecret seals value with the scope public key and creates or updates the secret
+	SetSecret(ctx context.Context, scope SecretScope, name string, value string) error
+	// SetEncryptedSecret creates or updates a secret with an already sealed, base64 encoded, value
+	SetEncryptedSecret(ctx context.Context, scope SecretScope, name string, keyID string, encryptedValue string) error
+	// DeleteSecret deletes a secret
+	DeleteSecret(ctx context.Context, scope SecretScope, name string) error
+	// SetRepoSecret seals value and creates or updates a repository secret
+	SetRepoSecret(ctx context.Context, owner string, repo string, name string, value string) error
+	// DeleteRepoSecret deletes a repository secret
+	DeleteRepoSecret(ctx context.Context, owner string, repo string, name string) error
+	// ListVariables returns the variables in scope with their values
+	ListVariables(ctx context.Context, scope SecretScope) ([]ActionsVariable, error)
+	// SetVariable creates or updates a variable
+	SetVariable(ctx context.Context, scope SecretScope, name string, value string) error
+	// DeleteVariable deletes a variable
+	DeleteVariable(ctx context.Context, scope SecretScope, name string) error
+}
+
+// Checks reports commit statuses and check runs
+type Checks interface {
+	// CreateCommitStatus sets the state of a status context on a commit
+	CreateCommitStatus(ctx context.Context, owner string, name string, sha string, state CommitStatusState, statusContext string, description string, targetURL string) error
+	// CreateCheckRun creates a check run with its output and annotations.  It requires GitHub App credentials.
+	CreateCheckRun(ctx context.Context, owner string, name string, input CheckRunInput) (*CheckRun, error)
+	// UpdateCheckRun changes a check run, for example to complete it, and adds annotations
+	UpdateCheckRun(ctx context.Context, owner string, name string, checkRunID int64, input CheckRunInput) (*CheckRun, error)
+	// GetRequiredStatusChecks returns the checks required to merge into baseBranch by branch protection and rulesets
+	GetRequiredStatusChecks(ctx context.Context, owner string, name string, baseBranch string) (*RequiredStatusChecks, error)
+	// IsPullRequestBlockedBy returns the required checks missing, pending or failing on the head of a PR
+	IsPullRequestBlockedBy(ctx context.Context, owner string, name string, number int64) ([]BlockingCheck, error)
+}
+
+// RESTClient is the escape hatch for REST v3 endpoints the package does not wrap yet
+type RESTClient interface {
+	// DoREST sends method to path with body JSON encoded and decodes the response into out
+	DoREST(ctx context.Context, method string, path string, body interface{}, out interface{}) error
+}
+
+// GraphQLClient is the escape hatch for custom GraphQL against the authenticated client
+type GraphQLClient interface {
+	// QueryRaw runs q, a githubv4 style query struct, with variables
+	QueryRaw(ctx context.Context, q interface{}, variables map[string]interface{}) error
+	// MutateRaw runs m, a githubv4 style mutation struct, with input as $input
+	MutateRaw(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error
+	// GetNode fetches any node by its global ID into a struct selecting fields with inline fragments
+	GetNode(ctx context.Context, id githubv4.ID, into interface{}) error
+}
+
+// Auth exposes the identity and credentials the client runs with
+type Auth interface {
+	// Self returns the current user.  The result is cached per token; see reqmeta.WithCacheControl to bypass or
+	// refresh it.
+	Self(ctx context.Context) (string, error)
+	// Viewer is Self with the node and database IDs of the current user, and shares its cache
+	Viewer(ctx context.Context) (*ViewerIdentity, error)
+	// InvalidateSelf drops the cached Self results
+	InvalidateSelf()
+	// GetAccessToken returns a token valid for the client's identity, for example to hand to git.  Installation
+	// tokens are renewed ahead of expiry, so the token stays valid for at least the configured TokenRefreshMargin.
+	GetAccessToken(ctx context.Context) (string, error)
+	// GetTokenInfo is GetAccessToken with the expiry of the token
+	GetTokenInfo(ctx context.Context) (*TokenInfo, error)
 }
 
 type RepositoryInfo struct {
@@ -69,17 +477,56 @@ type PullRequest struct {
 	HeadRefOid githubv4.ID
 	// Body as Markdown.
 	Body string
-	// Closed is true if the pull request is closed.
+	// State is whether the pull request is open, closed or merged.
 	State PullRequestState
+	Title string
+	// URL is the HTTP URL of the pull request.
+	URL     string `graphql:"url"`
+	IsDraft bool
+	// Author.Login is empty when the account was deleted.
+	Author struct {
+		Login string
+	}
+	CreatedAt githubv4.DateTime
+	// MergedAt is zero unless the pull request was merged.
+	MergedAt githubv4.DateTime
+	// Mergeable is whether the pull request can be merged without conflicts.
+	Mergeable MergeableState
+	// MergeStateStatus is whether the pull request can be merged right now, including branch protection.
+	MergeStateStatus MergeStateStatus
+	// Labels are the first labels of the pull request.
+	Labels PullRequestLabels `graphql:"labels(first: 50)"`
+	// ReviewDecision is empty when the base branch requires no reviews.
+	ReviewDecision ReviewDecision
+	// AutoMergeRequest is set while auto-merge is enabled on the pull request.
+	AutoMergeRequest *AutoMergeRequest
 }
 
-type PullRequestState string
+// PullRequestLabels are the labels of a pull request
+type PullRequestLabels struct {
+	Nodes []struct {
+		Name string
+	}
+}
 
-const (
-	PullRequstClosed PullRequestState = "CLOSED"
-	PullRequstMerged PullRequestState = "MERGED"
-	PullRequstOpen   PullRequestState = "OPEN"
-)
+// Names returns the label names
+func (l PullRequestLabels) Names() []string {
+	ret := make([]string, 0, len(l.Nodes))
+	for _, n := range l.Nodes {
+		ret = append(ret, n.Name)
+	}
+	return ret
+}
+
+// AutoMergeRequest is the auto-merge enabled on a pull request
+type AutoMergeRequest struct {
+	EnabledAt githubv4.DateTime
+	// EnabledBy is who enabled auto-merge, and whose permissions the merge is made with
+	EnabledBy struct {
+		Login string
+	}
+	MergeMethod githubv4.PullRequestMergeMethod
+}
 
 type createPullRequest struct {
 	CreatePullRequest struct {
@@ -95,8 +542,28 @@ type GithubGraphqlAPI struct {
 	ClientV4      *githubv4.Client
 	Logger        *zap.Logger
 	tokenFunction func(ctx context.Context) (string, error)
-	findPrCache   ExpireCache[findPrKey, findPrValue]
-	HttpClient    *http.Client
+	// tokenInfoFunction, if set, returns the token with its expiry.  Without it tokens have no known expiry.
+	tokenInfoFunction func(ctx context.Context) (TokenInfo, error)
+	findPrCache       ExpireCache[findPrKey, findPrValue]
+	// findPrNegativeTTL, if set, is how long a branch without PR is cached
+	findPrNegativeTTL time.Duration
+	repoInfoCache     ExpireCache[repoKey, *RepositoryInfo]
+	selfCache         ExpireCache[selfKey, ViewerIdentity]
+	// findPrFlight and repoInfoFlight share one query between concurrent lookups of the same key
+	findPrFlight   flightGroup[findPrKey, []BranchPullRequest]
+	repoInfoFlight flightGroup[repoKey, *RepositoryInfo]
+	// onCacheInvalidate is called whenever cached entries are dropped
+	onCacheInvalidate func(CacheInvalidation)
+	HttpClient        *http.Client
+	restBaseURL       string
+	acceptedBackoff   AcceptedBackoff
+	secretSealer      SecretSealer
+	// drain tracks the requests in flight for Shutdown.  connections, if set, is the transport this client created,
+	// whose idle connections Shutdown closes.
+	drain       *drainTransport
+	connections http.RoundTripper
+	// stopTokenRefresh, if set, stops the background token refresh
+	stopTokenRefresh func()
 }
 
 type triggerWorkflowBody struct {
@@ -104,36 +571,18 @@ type triggerWorkflowBody struct {
 	Inputs map[string]string `json:"inputs"`
 }
 
-func (g *GithubGraphqlAPI) TriggerWorkflow(ctx context.Context, owner string, repo string, workflow_id string, ref string, inputs map[string]string) error {
-	g.Logger.Debug("TriggerWorkflow", zap.String("owner", owner), zap.String("repo", repo), zap.String("workflow_id", workflow_id), zap.String("ref", ref), zap.Any("inputs", inputs))
-	defer g.Logger.Debug("Done TriggerWorkflow")
-	token, err := g.GetAccessToken(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get access token: %w", err)
-	}
+func (g *GithubGraphqlAPI) TriggerWorkflow(ctx context.Context, owner string, repo string, workflow_id string, ref string, inputs map[string]string) (err error) {
+	ctx = withOperation(ctx, "TriggerWorkflow")
+	defer annotateError(&err, OperationError{Operation: "TriggerWorkflow", Owner: owner, Repo: repo, WorkflowID: workflow_id})
+	g.logger(ctx).Debug("TriggerWorkflow", zap.String("owner", owner), zap.String("repo", repo), zap.String("workflow_id", workflow_id), zap.String("ref", ref), zap.Any("inputs", inputs))
+	defer g.logger(ctx).Debug("Done TriggerWorkflow")
 	body := triggerWorkflowBody{
 		Ref:    ref,
 		Inputs: inputs,
 	}
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/dispatches", owner, repo, workflow_id)
-	encodedBody, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("failed to encode request body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(encodedBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Authorization", "token "+token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	resp, err := g.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to trigger workflow: %s", resp.Status)
+	path := fmt.Sprintf("/repos/%s/%s/actions/workflows/%s/dispatches", owner, repo, workflow_id)
+	if err := g.doREST(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to trigger workflow: %w", err)
 	}
 	return nil
 }
@@ -145,29 +594,55 @@ type findPrKey struct {
 }
 
 type findPrValue struct {
-	number int64
+	prs []BranchPullRequest
+}
+
+type repoKey struct {
+	owner string
+	name  string
 }
 
 func (g *GithubGraphqlAPI) GetAccessToken(ctx context.Context) (string, error) {
 	return g.tokenFunction(ctx)
 }
 
-func (g *GithubGraphqlAPI) FindPullRequestOid(ctx context.Context, owner string, name string, number int64) (githubv4.ID, error) {
-	g.Logger.Debug("FindPullRequestOid", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
-	defer g.Logger.Debug("Done FindPullRequestOid")
-	var query struct {
-		Repository struct {
-			PullRequest struct {
-				ID githubv4.ID
-			} `graphql:"pullRequest(number: $number)"`
-		} `graphql:"repository(owner: $owner, name: $name)"`
+func (g *GithubGraphqlAPI) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	if g.tokenInfoFunction != nil {
+		info, err := g.tokenInfoFunction(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &info, nil
 	}
-	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(name),
-		"number": githubv4.Int(number),
+	token, err := g.tokenFunction(ctx)
+	if err != nil {
+		return nil, err
 	}
-	err := g.ClientV4.Query(ctx, &query, variables)
+	return &TokenInfo{Token: token}, nil
+}
+
+type findPullRequestOidQuery struct {
+	Repository struct {
+		PullRequest struct {
+			ID githubv4.ID
+		} `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+func (g *GithubGraphqlAPI) FindPullRequestOid(ctx context.Context, owner string, name string, number int64) (_ githubv4.ID, err error) {
+	ctx = withOperation(ctx, "FindPullRequestOid")
+	defer annotateError(&err, OperationError{Operation: "FindPullRequestOid", Owner: owner, Repo: name, Number: number})
+	if ce := g.logger(ctx).Check(zap.DebugLevel, "FindPullRequestOid"); ce != nil {
+		ce.Write(zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	}
+	defer g.logger(ctx).Debug("Done FindPullRequestOid")
+	var query findPullRequestOidQuery
+	variables := getVariables()
+	defer putVariables(variables)
+	variables["owner"] = githubv4.String(owner)
+	variables["name"] = githubv4.String(name)
+	variables["number"] = githubv4.Int(number)
+	err = g.ClientV4.Query(ctx, &query, variables)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query for PRs: %w", err)
 	}
@@ -177,14 +652,16 @@ func (g *GithubGraphqlAPI) FindPullRequestOid(ctx context.Context, owner string,
 	return query.Repository.PullRequest.ID, nil
 }
 
-func (g *GithubGraphqlAPI) AcceptPullRequest(ctx context.Context, approvalmessage string, owner string, name string, number int64) error {
-	defer g.findPrCache.Clear()
+func (g *GithubGraphqlAPI) AcceptPullRequest(ctx context.Context, approvalmessage string, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "AcceptPullRequest")
+	defer annotateError(&err, OperationError{Operation: "AcceptPullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
 	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
 	if err != nil {
 		return fmt.Errorf("failed to find PR: %w", err)
 	}
-	g.Logger.Debug("AcceptPullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
-	defer g.Logger.Debug("Done AcceptPullRequest")
+	g.logger(ctx).Debug("AcceptPullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done AcceptPullRequest")
 	event := githubv4.PullRequestReviewEventApprove
 	body := githubv4.String(approvalmessage)
 	var ret struct {
@@ -204,85 +681,170 @@ func (g *GithubGraphqlAPI) AcceptPullRequest(ctx context.Context, approvalmessag
 	return nil
 }
 
-func (g *GithubGraphqlAPI) MergePullRequest(ctx context.Context, owner string, name string, number int64) error {
-	defer g.findPrCache.Clear()
+func (g *GithubGraphqlAPI) MergePullRequest(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "MergePullRequest")
+	defer annotateError(&err, OperationError{Operation: "MergePullRequest", Owner: owner, Repo: name, Number: number})
+	_, err = g.mergePullRequest(ctx, owner, name, number, githubv4.PullRequestMergeMethodSquash)
+	return err
+}
+
+// MergePullRequestWithMethod merges a pull request with method, for example MERGE to keep its commits, and returns the
+// SHA of the merge, squash or last rebased commit
+func (g *GithubGraphqlAPI) MergePullRequestWithMethod(ctx context.Context, owner string, name string, number int64, method githubv4.PullRequestMergeMethod) (_ string, err error) {
+	ctx = withOperation(ctx, "MergePullRequestWithMethod")
+	defer annotateError(&err, OperationError{Operation: "MergePullRequestWithMethod", Owner: owner, Repo: name, Number: number})
+	return g.mergePullRequest(ctx, owner, name, number, method)
+}
+
+func (g *GithubGraphqlAPI) mergePullRequest(ctx context.Context, owner string, name string, number int64, method githubv4.PullRequestMergeMethod) (string, error) {
+	defer g.clearPRCache()
 	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
 	if err != nil {
-		return fmt.Errorf("failed to find PR: %w", err)
+		return "", fmt.Errorf("failed to find PR: %w", err)
 	}
-	g.Logger.Debug("MergePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
-	defer g.Logger.Debug("Done MergePullRequest")
+	g.logger(ctx).Debug("MergePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid), zap.String("method", string(method)))
+	defer g.logger(ctx).Debug("Done MergePullRequest")
 	var ret struct {
 		MergePullRequest struct {
 			PullRequest struct {
-				ID githubv4.ID
+				ID          githubv4.ID
+				MergeCommit struct {
+					Oid githubv4.GitObjectID
+				}
 			}
 		} `graphql:"mergePullRequest(input: $input)"`
 	}
-	mergeMethod := githubv4.PullRequestMergeMethodSquash
 	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.MergePullRequestInput{
 		PullRequestID: prid,
-		MergeMethod:   &mergeMethod,
+		MergeMethod:   &method,
 	}, nil); err != nil {
-		return fmt.Errorf("uanble to add PR review: %w", err)
+		return "", fmt.Errorf("unable to merge PR: %w", err)
 	}
-	return nil
+	return string(ret.MergePullRequest.PullRequest.MergeCommit.Oid), nil
 }
 
 type GraphQLPRQueryNode struct {
-	Number githubv4.Int
+	Number      githubv4.Int
+	BaseRefName string
+}
+
+type findPRForBranchQuery struct {
+	Repository struct {
+		PullRequests struct {
+			Nodes []GraphQLPRQueryNode `graphql:"nodes"`
+		} `graphql:"pullRequests(states: [OPEN], first: 50, headRefName: $branch)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// BranchPullRequest is an open pull request of a branch
+type BranchPullRequest struct {
+	Number      int64
+	BaseRefName string
+}
+
+// FindPRForBranch returns the number of the open PR of branch, or 0 if there is none.  It fails if the branch has PRs
+// into more than one base; use FindPRsForBranch for those.  Results are cached, the absence of a PR only for
+// PRNegativeCacheTTL; see reqmeta.WithCacheControl to bypass the cache and InvalidateBranch to drop an entry.
+func (g *GithubGraphqlAPI) FindPRForBranch(ctx context.Context, owner string, name string, branch string) (_ int64, err error) {
+	ctx = withOperation(ctx, "FindPRForBranch")
+	defer annotateError(&err, OperationError{Operation: "FindPRForBranch", Owner: owner, Repo: name, Target: "branch " + branch})
+	prs, err := g.findPRsForBranch(ctx, owner, name, branch)
+	if err != nil {
+		return 0, err
+	}
+	switch len(prs) {
+	case 0:
+		return 0, nil
+	case 1:
+		return prs[0].Number, nil
+	default:
+		return 0, fmt.Errorf("found multiple PRs for branch %s", branch)
+	}
 }
 
-func (g *GithubGraphqlAPI) FindPRForBranch(ctx context.Context, owner string, name string, branch string) (int64, error) {
-	g.Logger.Debug("FindPRForBranch", zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch))
-	defer g.Logger.Debug("Done FindPRForBranch")
+// FindPRsForBranch returns every open PR of branch, such as the PRs of a stacked branch into several bases, ordered by
+// number.  It shares the cache of FindPRForBranch.
+func (g *GithubGraphqlAPI) FindPRsForBranch(ctx context.Context, owner string, name string, branch string) (_ []BranchPullRequest, err error) {
+	ctx = withOperation(ctx, "FindPRsForBranch")
+	defer annotateError(&err, OperationError{Operation: "FindPRsForBranch", Owner: owner, Repo: name, Target: "branch " + branch})
+	prs, err := g.findPRsForBranch(ctx, owner, name, branch)
+	if err != nil {
+		return nil, err
+	}
+	return append([]BranchPullRequest(nil), prs...), nil
+}
+
+// findPRsForBranch returns the cached PRs of branch, which callers must not modify
+func (g *GithubGraphqlAPI) findPRsForBranch(ctx context.Context, owner string, name string, branch string) ([]BranchPullRequest, error) {
+	if ce := g.logger(ctx).Check(zap.DebugLevel, "FindPRsForBranch"); ce != nil {
+		ce.Write(zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch))
+	}
+	defer g.logger(ctx).Debug("Done FindPRsForBranch")
 	cacheKey := findPrKey{
 		owner:  owner,
 		name:   name,
 		branch: branch,
 	}
-	prNum, exists := g.findPrCache.Get(cacheKey)
+	cached, exists := cacheGet(ctx, &g.findPrCache, cacheKey)
 	if exists {
-		g.Logger.Debug("pr cached value", zap.Int64("prNum", prNum.number))
-		return prNum.number, nil
+		if ce := g.logger(ctx).Check(zap.DebugLevel, "pr cached value"); ce != nil {
+			ce.Write(zap.Int("prCount", len(cached.prs)))
+		}
+		return cached.prs, nil
 	}
 
-	var query struct {
-		Repository struct {
-			PullRequests struct {
-				Nodes []GraphQLPRQueryNode `graphql:"nodes"`
-			} `graphql:"pullRequests(states: [OPEN], first: 10, headRefName: $branch)"`
-		} `graphql:"repository(owner: $owner, name: $name)"`
-	}
-	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(name),
-		"branch": githubv4.String(branch),
+	prs, shared, err := g.findPrFlight.Do(ctx, cacheKey, func(ctx context.Context) ([]BranchPullRequest, error) {
+		return g.queryPRsForBranch(ctx, cacheKey)
+	})
+	if shared {
+		g.logger(ctx).Debug("shared in flight FindPRsForBranch query")
 	}
+	return prs, err
+}
+
+func (g *GithubGraphqlAPI) queryPRsForBranch(ctx context.Context, cacheKey findPrKey) ([]BranchPullRequest, error) {
+	var query findPRForBranchQuery
+	variables := getVariables()
+	defer putVariables(variables)
+	variables["owner"] = githubv4.String(cacheKey.owner)
+	variables["name"] = githubv4.String(cacheKey.name)
+	variables["branch"] = githubv4.String(cacheKey.branch)
 	err := g.ClientV4.Query(ctx, &query, variables)
 	if err != nil {
-		return 0, fmt.Errorf("failed to query for PRs: %w", err)
+		return nil, fmt.Errorf("failed to query for PRs: %w", err)
 	}
 	if len(query.Repository.PullRequests.Nodes) == 0 {
-		g.Logger.Debug("No PRs found")
-		g.findPrCache.Set(cacheKey, findPrValue{number: int64(0)})
-		return 0, nil
+		g.logger(ctx).Debug("No PRs found")
 	}
-	if len(query.Repository.PullRequests.Nodes) > 1 {
-		return 0, fmt.Errorf("found multiple PRs for branch %s", branch)
+	prs := make([]BranchPullRequest, 0, len(query.Repository.PullRequests.Nodes))
+	for _, n := range query.Repository.PullRequests.Nodes {
+		prs = append(prs, BranchPullRequest{Number: int64(n.Number), BaseRefName: n.BaseRefName})
 	}
-	pr := query.Repository.PullRequests.Nodes[0]
-	g.findPrCache.Set(cacheKey, findPrValue{number: int64(pr.Number)})
-	return int64(pr.Number), nil
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].Number < prs[j].Number
+	})
+	g.cachePRsForBranch(ctx, cacheKey, prs)
+	return prs, nil
 }
 
-func (g *GithubGraphqlAPI) EnablePullRequestAutoMerge(ctx context.Context, owner string, name string, number int64) error {
+// cachePRsForBranch caches the PRs of a branch, for findPrNegativeTTL only if there are none
+func (g *GithubGraphqlAPI) cachePRsForBranch(ctx context.Context, cacheKey findPrKey, prs []BranchPullRequest) {
+	if len(prs) == 0 && g.findPrNegativeTTL > 0 && g.findPrNegativeTTL < g.findPrCache.DefaultExpiry {
+		cacheSetWithTTL(ctx, &g.findPrCache, cacheKey, findPrValue{prs: prs}, g.findPrNegativeTTL)
+		return
+	}
+	cacheSet(ctx, &g.findPrCache, cacheKey, findPrValue{prs: prs})
+}
+
+func (g *GithubGraphqlAPI) EnablePullRequestAutoMerge(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "EnablePullRequestAutoMerge")
+	defer annotateError(&err, OperationError{Operation: "EnablePullRequestAutoMerge", Owner: owner, Repo: name, Number: number})
 	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
 	if err != nil {
 		return fmt.Errorf("failed to find PR: %w", err)
 	}
-	g.Logger.Debug("EnablePullRequestAutoMerge", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
-	defer g.Logger.Debug("Done EnablePullRequestAutoMerge")
+	g.logger(ctx).Debug("EnablePullRequestAutoMerge", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done EnablePullRequestAutoMerge")
 	var ret struct {
 		AutoMergRequest struct {
 			PullRequest struct {
@@ -300,20 +862,51 @@ func (g *GithubGraphqlAPI) EnablePullRequestAutoMerge(ctx context.Context, owner
 	return nil
 }
 
-func (g *GithubGraphqlAPI) FindPullRequest(ctx context.Context, owner string, name string, number int64) (*PullRequest, error) {
-	g.Logger.Debug("FindPullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
-	defer g.Logger.Debug("Done FindPullRequest")
-	var query struct {
-		Repository struct {
-			PullRequest PullRequest `graphql:"pullRequest(number: $number)"`
-		} `graphql:"repository(owner: $owner, name: $name)"`
+// DisablePullRequestAutoMerge cancels the auto-merge of a pull request.  It fails if auto-merge is not enabled.
+func (g *GithubGraphqlAPI) DisablePullRequestAutoMerge(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "DisablePullRequestAutoMerge")
+	defer annotateError(&err, OperationError{Operation: "DisablePullRequestAutoMerge", Owner: owner, Repo: name, Number: number})
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
 	}
-	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(name),
-		"number": githubv4.Int(number),
+	g.logger(ctx).Debug("DisablePullRequestAutoMerge", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done DisablePullRequestAutoMerge")
+	var ret struct {
+		DisablePullRequestAutoMerge struct {
+			PullRequest struct {
+				ID githubv4.ID
+			}
+		} `graphql:"disablePullRequestAutoMerge(input: $input)"`
 	}
-	err := g.ClientV4.Query(ctx, &query, variables)
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.DisablePullRequestAutoMergeInput{
+		PullRequestID: prid,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to disable PR auto-merge: %w", err)
+	}
+	return nil
+}
+
+type findPullRequestQuery struct {
+	Repository struct {
+		PullRequest PullRequest `graphql:"pullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+func (g *GithubGraphqlAPI) FindPullRequest(ctx context.Context, owner string, name string, number int64) (_ *PullRequest, err error) {
+	ctx = withOperation(ctx, "FindPullRequest")
+	defer annotateError(&err, OperationError{Operation: "FindPullRequest", Owner: owner, Repo: name, Number: number})
+	if ce := g.logger(ctx).Check(zap.DebugLevel, "FindPullRequest"); ce != nil {
+		ce.Write(zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	}
+	defer g.logger(ctx).Debug("Done FindPullRequest")
+	var query findPullRequestQuery
+	variables := getVariables()
+	defer putVariables(variables)
+	variables["owner"] = githubv4.String(owner)
+	variables["name"] = githubv4.String(name)
+	variables["number"] = githubv4.Int(number)
+	err = g.ClientV4.Query(ctx, &query, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query for PRs: %w", err)
 	}
@@ -323,13 +916,15 @@ func (g *GithubGraphqlAPI) FindPullRequest(ctx context.Context, owner string, na
 	return &query.Repository.PullRequest, nil
 }
 
-func (g *GithubGraphqlAPI) AddPRComment(ctx context.Context, owner string, name string, number int64, body string) error {
+func (g *GithubGraphqlAPI) AddPRComment(ctx context.Context, owner string, name string, number int64, body string) (err error) {
+	ctx = withOperation(ctx, "AddPRComment")
+	defer annotateError(&err, OperationError{Operation: "AddPRComment", Owner: owner, Repo: name, Number: number})
 	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
 	if err != nil {
 		return fmt.Errorf("failed to find PR: %w", err)
 	}
-	g.Logger.Debug("AddPRComment", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
-	defer g.Logger.Debug("Done AddPRComment")
+	g.logger(ctx).Debug("AddPRComment", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done AddPRComment")
 	var ret struct {
 		AddCommentRequest struct {
 			ClientMutationId githubv4.String
@@ -352,18 +947,93 @@ type NewGQLClientConfig struct {
 	Token          string
 	PEMKey         string
 	CacheTTL       time.Duration
+	// PRCacheTTL is how long FindPRForBranch results are cached.  Defaults to CacheTTL.
+	PRCacheTTL time.Duration
+	// PRNegativeCacheTTL is how long FindPRForBranch remembers that a branch has no PR, so a PR opened outside this
+	// client shows up quickly.  Defaults to DefaultPRNegativeCacheTTL, and is capped at PRCacheTTL.
+	PRNegativeCacheTTL time.Duration
+	// DisablePRCache makes every FindPRForBranch call query GitHub
+	DisablePRCache bool
+	// OnCacheInvalidate, if set, is called whenever cached lookups are dropped
+	OnCacheInvalidate func(CacheInvalidation)
+	// RepositoryCacheTTL is how long RepositoryInfo results are cached.  Defaults to CacheTTL.
+	RepositoryCacheTTL time.Duration
+	// SelfCacheTTL caps how long the Self result of a token is cached.  It is otherwise cached for the lifetime of the
+	// token, or DefaultSelfCacheTTL for tokens without known expiry.
+	SelfCacheTTL time.Duration
+	// CacheMaxEntries bounds each lookup cache, evicting the least recently used entries.  Defaults to
+	// DefaultCacheMaxEntries.
+	CacheMaxEntries int
+	// BaseURL is the root of the REST API, for example https://ghe.example.com/api/v3 for GitHub Enterprise Server.
+	// The GraphQL endpoint is derived from it.  Defaults to https://api.github.com
+	BaseURL string
+	// TransportTuning configures connection pooling of the base transport.  It is ignored when Rt is set.
+	TransportTuning *TransportTuning
+	// ownsTransport is set when Rt was built for this config from TransportTuning, rather than given or shared
+	ownsTransport bool
+	// Metrics, if set, is notified of every request the client sends
+	Metrics Metrics
+	// DebugLogOptions customize the request logging done when the logger has debug enabled
+	DebugLogOptions []DebugLogOption
+	// SecretSealer encrypts Actions secret values.  Defaults to DefaultSecretSealer.
+	SecretSealer SecretSealer
+	// ConditionalCache, if set, revalidates REST GET responses with their ETag so unchanged resources are served
+	// locally and do not use rate limit
+	ConditionalCache ConditionalCache
+	// TokenRefreshMargin is how long before expiry installation tokens are renewed.  Defaults to
+	// DefaultTokenRefreshMargin.
+	TokenRefreshMargin time.Duration
+	// BackgroundTokenRefresh renews expiring tokens, such as installation tokens, from a background goroutine before
+	// calls need to, and mints the first one asynchronously instead of while creating the client.  Invalid credentials
+	// are then reported by the first call instead of the constructor.  Shutdown stops the goroutine.
+	BackgroundTokenRefresh bool
+	// DeviceFlow, if set, logs the user in with the OAuth device flow when the client is created.  It is used when
+	// no Token or PEM key is configured.
+	DeviceFlow *DeviceFlow
+	// TokenProvider, if set, supplies the tokens of the client.  It is used when no Token or PEM key is configured.
+	TokenProvider TokenProvider
+	// DisableGithubCLIExec stops the client from running `gh auth token` when the gh CLI keeps its token in the system
+	// keyring
+	DisableGithubCLIExec bool
+	// Scheduler, if set, is shared by the clients of several tenants to cap their requests in flight and serve the
+	// tenants fairly
+	Scheduler *FairScheduler
+	// Tenant names the client for the Scheduler.  Clients of the same tenant share its turn.
+	Tenant string
+	// MaxConcurrentRequests, if set and there is no Scheduler, caps the requests in flight of this client alone.
+	// Calls then wait for a slot in the order of their reqmeta.Priority.
+	MaxConcurrentRequests int
+	// Timeouts bounds the requests made with a context without deadline.  The zero value bounds none.
+	Timeouts TimeoutPolicy
+	// RequestIDHeader is the header outbound requests carry their reqmeta.RequestID in.  Defaults to
+	// DefaultRequestIDHeader.
+	RequestIDHeader string
+	// AcceptedBackoff is how calls answered with 202 Accepted are retried.  Zero fields default to those of
+	// DefaultAcceptedBackoff.
+	AcceptedBackoff AcceptedBackoff
 }
 
 var DefaultGQLClientConfig = NewGQLClientConfig{
-	Rt:             http.DefaultTransport,
-	AppID:          intFromOsEnv("GITHUB_APP_ID"),
-	InstallationID: intFromOsEnv("GITHUB_INSTALLATION_ID"),
-	PEMKeyLoc:      os.Getenv("GITHUB_PEM_KEY_LOC"),
-	PEMKey:         os.Getenv("GITHUB_PEM_KEY"),
-	Token:          os.Getenv("GITHUB_TOKEN"),
-	CacheTTL:       time.Minute,
+	Rt:                 defaultTunedTransport,
+	AppID:              intFromOsEnv("GITHUB_APP_ID"),
+	InstallationID:     intFromOsEnv("GITHUB_INSTALLATION_ID"),
+	PEMKeyLoc:          os.Getenv("GITHUB_PEM_KEY_LOC"),
+	PEMKey:             os.Getenv("GITHUB_PEM_KEY"),
+	Token:              tokenFromEnv(os.Getenv),
+	BaseURL:            baseURLFromEnv(os.Getenv),
+	CacheTTL:           time.Minute,
+	CacheMaxEntries:    DefaultCacheMaxEntries,
+	TokenRefreshMargin: DefaultTokenRefreshMargin,
+	RequestIDHeader:    DefaultRequestIDHeader,
+	PRNegativeCacheTTL: DefaultPRNegativeCacheTTL,
 }
 
+// DefaultPRNegativeCacheTTL is how long FindPRForBranch remembers a branch without PR by default
+const DefaultPRNegativeCacheTTL = 5 * time.Second
+
+// DefaultCacheMaxEntries is how many entries each lookup cache keeps by default
+const DefaultCacheMaxEntries = 10000
+
 func intFromOsEnv(s string) int64 {
 	v := os.Getenv(s)
 	if v == "" {
@@ -376,6 +1046,38 @@ func intFromOsEnv(s string) int64 {
 	return i
 }
 
+func (c *NewGQLClientConfig) restBaseURL() string {
+	if c.BaseURL == "" {
+		return defaultRESTBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+// webBaseURL derives the root of the GitHub website from the REST base URL
+func (c *NewGQLClientConfig) webBaseURL() string {
+	if c.BaseURL == "" {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(c.restBaseURL(), "/api/v3")
+}
+
+// graphqlURL derives the GraphQL endpoint from the REST base URL.  GitHub Enterprise Server serves REST under /api/v3
+// and GraphQL under /api/graphql, while github.com serves GraphQL at /graphql.
+func (c *NewGQLClientConfig) graphqlURL() string {
+	base := c.restBaseURL()
+	if strings.HasSuffix(base, "/api/v3") {
+		return strings.TrimSuffix(base, "/v3") + "/graphql"
+	}
+	return base + "/graphql"
+}
+
+func (c *NewGQLClientConfig) newGraphqlClient(httpClient *http.Client) *githubv4.Client {
+	if c.BaseURL == "" {
+		return githubv4.NewClient(httpClient)
+	}
+	return githubv4.NewEnterpriseClient(c.graphqlURL(), httpClient)
+}
+
 func createGraphqlAPI(gql *githubv4.Client, httpClient *http.Client, logger *zap.Logger, cacheTtl time.Duration, tokenFunction func(context.Context) (string, error)) *GithubGraphqlAPI {
 	return &GithubGraphqlAPI{
 		HttpClient:    httpClient,
@@ -385,95 +1087,164 @@ func createGraphqlAPI(gql *githubv4.Client, httpClient *http.Client, logger *zap
 		findPrCache: ExpireCache[findPrKey, findPrValue]{
 			DefaultExpiry: cacheTtl,
 		},
+		repoInfoCache: ExpireCache[repoKey, *RepositoryInfo]{
+			DefaultExpiry: cacheTtl,
+		},
+		selfCache: ExpireCache[selfKey, ViewerIdentity]{
+			DefaultExpiry: DefaultSelfCacheTTL,
+		},
 	}
 }
 
-func clientFromToken(_ context.Context, logger *zap.Logger, token string, cacheTtl time.Duration) (GitHub, error) {
+// applyConfig sets the client settings that createGraphqlAPI does not take
+func (g *GithubGraphqlAPI) applyConfig(cfg *NewGQLClientConfig) {
+	g.restBaseURL = cfg.restBaseURL()
+	if cfg.PRCacheTTL != 0 {
+		g.findPrCache.DefaultExpiry = cfg.PRCacheTTL
+	}
+	g.findPrNegativeTTL = cfg.PRNegativeCacheTTL
+	g.findPrCache.Disabled = cfg.DisablePRCache
+	g.onCacheInvalidate = cfg.OnCacheInvalidate
+	if cfg.RepositoryCacheTTL != 0 {
+		g.repoInfoCache.DefaultExpiry = cfg.RepositoryCacheTTL
+	}
+	if cfg.SelfCacheTTL != 0 {
+		g.selfCache.DefaultExpiry = cfg.SelfCacheTTL
+	}
+	g.findPrCache.MaxEntries = cfg.CacheMaxEntries
+	g.repoInfoCache.MaxEntries = cfg.CacheMaxEntries
+	g.secretSealer = cfg.SecretSealer
+	g.acceptedBackoff = cfg.AcceptedBackoff
+	g.connections = nil
+	if cfg.ownsTransport {
+		g.connections = cfg.Rt
+	}
+}
+
+// transportOptions are the cross-cutting layers wrapped around the authenticated transport
+type transportOptions struct {
+	metrics   Metrics
+	debugLog  []DebugLogOption
+	scheduler *FairScheduler
+	tenant    string
+	timeouts  TimeoutPolicy
+	// requestIDHeader is the header of reqmeta.RequestID
+	requestIDHeader string
+}
+
+// wrap layers the options around rt.  Requests wait for their scheduler slot before the metrics clock starts, but
+// their timeout includes that wait.
+func (o transportOptions) wrap(rt http.RoundTripper, logger *zap.Logger) http.RoundTripper {
+	scheduled := ScheduledTransport(InstrumentedTransport(rt, o.metrics), o.scheduler, o.tenant)
+	return DebugLogTransport(RequestIDHeaderTransport(DeadlineTransport(scheduled, o.timeouts), o.requestIDHeader), logger, o.debugLog...)
+}
+
+func transportOptionsFromConfig(cfg *NewGQLClientConfig) transportOptions {
+	scheduler := cfg.Scheduler
+	if scheduler == nil && cfg.MaxConcurrentRequests > 0 {
+		scheduler = NewFairScheduler(cfg.MaxConcurrentRequests)
+	}
+	return transportOptions{
+		metrics:         cfg.Metrics,
+		debugLog:        cfg.DebugLogOptions,
+		scheduler:       scheduler,
+		tenant:          cfg.Tenant,
+		timeouts:        cfg.Timeouts,
+		requestIDHeader: cfg.RequestIDHeader,
+	}
+}
+
+func clientFromToken(_ context.Context, logger *zap.Logger, token string, cfg *NewGQLClientConfig) (GitHub, error) {
+	baseRoundTripper := cfg.baseTransport()
 	src := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	httpClient.Transport = DebugLogTransport(httpClient.Transport, logger)
-	gql := githubv4.NewClient(httpClient)
-	return createGraphqlAPI(gql, httpClient, logger, cacheTtl, func(_ context.Context) (string, error) {
+	drain := newDrainTransport(transportOptionsFromConfig(cfg).wrap(&oauth2.Transport{Source: src, Base: baseRoundTripper}, logger))
+	httpClient := &http.Client{Transport: drain}
+	ret := createGraphqlAPI(cfg.newGraphqlClient(httpClient), httpClient, logger, cfg.CacheTTL, func(_ context.Context) (string, error) {
 		return token, nil
-	}), nil
+	})
+	ret.applyConfig(cfg)
+	ret.drain = drain
+	return ret, nil
 }
 
-func clientFromPEM(ctx context.Context, logger *zap.Logger, baseRoundTripper http.RoundTripper, appID int64, installID int64, pemLoc string, pemKey string, cacheTtl time.Duration) (GitHub, error) {
-	if baseRoundTripper == nil {
-		baseRoundTripper = http.DefaultTransport
-	}
-	var trans *ghinstallation.Transport
+func clientFromPEM(ctx context.Context, logger *zap.Logger, cfg *NewGQLClientConfig) (GitHub, error) {
+	baseRoundTripper := cfg.baseTransport()
+	var atr *ghinstallation.AppsTransport
 	var err error
-	if pemKey != "" {
-		trans, err = ghinstallation.New(baseRoundTripper, appID, installID, []byte(pemKey))
+	if cfg.PEMKey != "" {
+		atr, err = ghinstallation.NewAppsTransport(baseRoundTripper, cfg.AppID, []byte(cfg.PEMKey))
 	} else {
-		trans, err = ghinstallation.NewKeyFromFile(baseRoundTripper, appID, installID, pemLoc)
+		atr, err = ghinstallation.NewAppsTransportKeyFromFile(baseRoundTripper, cfg.AppID, cfg.PEMKeyLoc)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to find key file: %w", err)
 	}
-	_, err = trans.Token(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to validate token: %w", err)
-	}
-	client := &http.Client{Transport: DebugLogTransport(trans, logger)}
-	gql := githubv4.NewClient(client)
-	return createGraphqlAPI(gql, client, logger, cacheTtl, trans.Token), nil
+	atr.BaseURL = cfg.restBaseURL()
+	return clientFromInstallation(ctx, logger, cfg, func() *ghinstallation.Transport {
+		return ghinstallation.NewFromAppsTransport(atr, cfg.InstallationID)
+	})
 }
 
-func tokenFromGithubCLI() string {
-	s, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-	configPath := filepath.Join(s, ".config", "gh", "hosts.yml")
-	b, err := os.ReadFile(configPath)
-	if err != nil {
-		return ""
-	}
-	var out map[string]configFileAuths
-	if err := yaml.Unmarshal(b, &out); err != nil {
-		return ""
-	}
-	return tokenForAny(out, "github.com", "Github.com")
+// clientFromInstallation builds a client authenticated as the installation the transports of newTransport mint
+// tokens for
+func clientFromInstallation(ctx context.Context, logger *zap.Logger, cfg *NewGQLClientConfig, newTransport func() *ghinstallation.Transport) (*GithubGraphqlAPI, error) {
+	return clientFromRefreshingToken(ctx, logger, cfg, newRefreshingToken(cfg.TokenRefreshMargin, installationTokenMinter(newTransport)))
 }
 
-func tokenForAny(m map[string]configFileAuths, hosts ...string) string {
-	for _, host := range hosts {
-		if auth, exists := m[host]; exists {
-			return auth.Token
+// clientFromRefreshingToken builds a client authenticated with the tokens of token, minting the first one right away
+func clientFromRefreshingToken(ctx context.Context, logger *zap.Logger, cfg *NewGQLClientConfig, token *refreshingToken) (*GithubGraphqlAPI, error) {
+	if !cfg.BackgroundTokenRefresh {
+		if _, err := token.Token(ctx); err != nil {
+			return nil, fmt.Errorf("unable to validate token: %w", err)
 		}
 	}
-	return ""
-}
-
-type configFileAuths struct {
-	Token string `yaml:"oauth_token"`
+	trans := &TokenTransport{Base: cfg.baseTransport(), Token: token.Token}
+	drain := newDrainTransport(transportOptionsFromConfig(cfg).wrap(trans, logger))
+	client := &http.Client{Transport: drain}
+	ret := createGraphqlAPI(cfg.newGraphqlClient(client), client, logger, cfg.CacheTTL, token.Token)
+	ret.tokenInfoFunction = token.TokenInfo
+	ret.applyConfig(cfg)
+	ret.drain = drain
+	if cfg.BackgroundTokenRefresh {
+		ret.stopTokenRefresh = token.refreshInBackground(logger)
+	}
+	return ret, nil
 }
 
 // NewGQLClient generates a new GraphQL github client
 func NewGQLClient(ctx context.Context, logger *zap.Logger, cfg *NewGQLClientConfig) (GitHub, error) {
-	cfg = mergeGithubConfigs(cfg, &DefaultGQLClientConfig)
+	cfg = mergeGithubConfigs(cfg, &DefaultGQLClientConfig).withConnectionTransport()
 	if cfg != nil && cfg.Token != "" {
-		return clientFromToken(ctx, logger, cfg.Token, cfg.CacheTTL)
+		return clientFromToken(ctx, logger, cfg.Token, cfg)
 	}
 	if cfg != nil && (cfg.PEMKeyLoc != "" || cfg.PEMKey != "") {
-		return clientFromPEM(ctx, logger, cfg.Rt, cfg.AppID, cfg.InstallationID, cfg.PEMKeyLoc, cfg.PEMKey, cfg.CacheTTL)
+		return clientFromPEM(ctx, logger, cfg)
 	}
-	if token := tokenFromGithubCLI(); token != "" {
-		return clientFromToken(ctx, logger, token, cfg.CacheTTL)
+	if cfg != nil && cfg.TokenProvider != nil {
+		return clientFromRefreshingToken(ctx, logger, cfg, newRefreshingToken(cfg.TokenRefreshMargin, providerTokenMinter(cfg.TokenProvider)))
+	}
+	if cfg != nil && cfg.DeviceFlow != nil {
+		return clientFromDeviceFlow(ctx, logger, cfg)
+	}
+	if token, host := tokenFromGithubCLI(ctx, cfg); token != "" {
+		if cfg.BaseURL == "" && !strings.EqualFold(host, defaultGithubHost) {
+			cfg.BaseURL = "https://" + host + "/api/v3"
+		}
+		return clientFromToken(ctx, logger, token, cfg)
 	}
-	return nil, fmt.Errorf("no token provided: I need either GITHUB_TOKEN env, existing auth via the `gh` CLI, or a PEM key")
+	return nil, fmt.Errorf("no token provided: I need either GITHUB_TOKEN env, the job token inside GitHub Actions, existing auth via the `gh` CLI, or a PEM key")
 }
 
+// mergeGithubConfigs fills unset fields of cfg from config.  Credentials are only taken from config when cfg has
+// none of its own, so an explicit PEM key is never shadowed by a GITHUB_TOKEN from the environment.
 func mergeGithubConfigs(cfg *NewGQLClientConfig, config *NewGQLClientConfig) *NewGQLClientConfig {
 	if cfg == nil {
 		return config
 	}
 	ret := *cfg
-	if ret.Rt == nil {
+	if ret.Rt == nil && ret.TransportTuning == nil {
 		ret.Rt = config.Rt
 	}
 	if ret.AppID == 0 {
@@ -482,58 +1253,142 @@ func mergeGithubConfigs(cfg *NewGQLClientConfig, config *NewGQLClientConfig) *Ne
 	if ret.InstallationID == 0 {
 		ret.InstallationID = config.InstallationID
 	}
-	if ret.PEMKeyLoc == "" {
+	if ret.Token == "" && ret.PEMKeyLoc == "" && ret.PEMKey == "" && ret.DeviceFlow == nil && ret.TokenProvider == nil {
+		ret.Token = config.Token
 		ret.PEMKeyLoc = config.PEMKeyLoc
+		ret.PEMKey = config.PEMKey
 	}
-	if ret.Token == "" {
-		ret.Token = config.Token
+	if ret.CacheTTL == 0 {
+		ret.CacheTTL = config.CacheTTL
 	}
-	return &ret
-}
-
-func (g *GithubGraphqlAPI) Self(ctx context.Context) (string, error) {
-	g.Logger.Debug("fetching self")
-	defer g.Logger.Debug("done fetching self")
-	var q struct {
-		Viewer struct {
-			Login githubv4.String
-			ID    githubv4.ID
-		}
+	if ret.PRCacheTTL == 0 {
+		ret.PRCacheTTL = config.PRCacheTTL
+	}
+	if ret.PRNegativeCacheTTL == 0 {
+		ret.PRNegativeCacheTTL = config.PRNegativeCacheTTL
 	}
-	if err := g.ClientV4.Query(ctx, &q, nil); err != nil {
-		return "", fmt.Errorf("unable to run graphql query self: %w", err)
+	if ret.RepositoryCacheTTL == 0 {
+		ret.RepositoryCacheTTL = config.RepositoryCacheTTL
 	}
-	return string(q.Viewer.Login), nil
+	if ret.SelfCacheTTL == 0 {
+		ret.SelfCacheTTL = config.SelfCacheTTL
+	}
+	if ret.CacheMaxEntries == 0 {
+		ret.CacheMaxEntries = config.CacheMaxEntries
+	}
+	if ret.BaseURL == "" {
+		ret.BaseURL = config.BaseURL
+	}
+	if ret.OnCacheInvalidate == nil {
+		ret.OnCacheInvalidate = config.OnCacheInvalidate
+	}
+	if ret.Metrics == nil {
+		ret.Metrics = config.Metrics
+	}
+	if ret.DebugLogOptions == nil {
+		ret.DebugLogOptions = config.DebugLogOptions
+	}
+	if ret.SecretSealer == nil {
+		ret.SecretSealer = config.SecretSealer
+	}
+	if ret.ConditionalCache == nil {
+		ret.ConditionalCache = config.ConditionalCache
+	}
+	if ret.Scheduler == nil {
+		ret.Scheduler = config.Scheduler
+	}
+	if ret.MaxConcurrentRequests == 0 {
+		ret.MaxConcurrentRequests = config.MaxConcurrentRequests
+	}
+	if ret.TokenRefreshMargin == 0 {
+		ret.TokenRefreshMargin = config.TokenRefreshMargin
+	}
+	if ret.AcceptedBackoff == (AcceptedBackoff{}) {
+		ret.AcceptedBackoff = config.AcceptedBackoff
+	}
+	if ret.RequestIDHeader == "" {
+		ret.RequestIDHeader = config.RequestIDHeader
+	}
+	if ret.Timeouts.isZero() {
+		ret.Timeouts = config.Timeouts
+	}
+	return &ret
 }
 
-func (g *GithubGraphqlAPI) CreatePullRequest(ctx context.Context, remoteRepositoryId graphql.ID, baseRefName string, remoteRefName string, title string, body string) (int64, error) {
-	defer g.findPrCache.Clear()
-	g.Logger.Debug("creating pull request", zap.Any("remoteRepositoryId", remoteRepositoryId), zap.String("baseRefName", baseRefName), zap.String("remoteRefName", remoteRefName), zap.String("title", title), zap.String("body", body))
-	defer g.Logger.Debug("done creating pull request")
-	var ret createPullRequest
-	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.CreatePullRequestInput{
+func (g *GithubGraphqlAPI) CreatePullRequest(ctx context.Context, remoteRepositoryId graphql.ID, baseRefName string, remoteRefName string, title string, body string) (_ int64, err error) {
+	ctx = withOperation(ctx, "CreatePullRequest")
+	defer annotateError(&err, OperationError{Operation: "CreatePullRequest", Target: fmt.Sprintf("repository %v base %s head %s", remoteRepositoryId, baseRefName, remoteRefName)})
+	defer g.clearPRCache()
+	g.logger(ctx).Debug("creating pull request", zap.Any("remoteRepositoryId", remoteRepositoryId), zap.String("baseRefName", baseRefName), zap.String("remoteRefName", remoteRefName), zap.String("title", title), zap.String("body", body))
+	defer g.logger(ctx).Debug("done creating pull request")
+	return g.createPullRequest(ctx, githubv4.CreatePullRequestInput{
 		RepositoryID: remoteRepositoryId,
 		BaseRefName:  githubv4.String(baseRefName),
 		HeadRefName:  githubv4.String(remoteRefName),
 		Title:        githubv4.String(title),
 		Body:         githubv4.NewString(githubv4.String(body)),
-	}, nil); err != nil {
+	})
+}
+
+func (g *GithubGraphqlAPI) CreateForkPullRequest(ctx context.Context, remoteRepositoryId graphql.ID, headRepositoryId graphql.ID, baseRefName string, headRefName string, title string, body string) (_ int64, err error) {
+	ctx = withOperation(ctx, "CreateForkPullRequest")
+	defer annotateError(&err, OperationError{Operation: "CreateForkPullRequest", Target: fmt.Sprintf("repository %v base %s head %v:%s", remoteRepositoryId, baseRefName, headRepositoryId, headRefName)})
+	defer g.clearPRCache()
+	g.logger(ctx).Debug("creating fork pull request", zap.Any("remoteRepositoryId", remoteRepositoryId), zap.Any("headRepositoryId", headRepositoryId), zap.String("baseRefName", baseRefName), zap.String("headRefName", headRefName), zap.String("title", title))
+	defer g.logger(ctx).Debug("done creating fork pull request")
+	headRepositoryID := githubv4.ID(headRepositoryId)
+	return g.createPullRequest(ctx, githubv4.CreatePullRequestInput{
+		RepositoryID:     remoteRepositoryId,
+		HeadRepositoryID: &headRepositoryID,
+		BaseRefName:      githubv4.String(baseRefName),
+		HeadRefName:      githubv4.String(headRefName),
+		Title:            githubv4.String(title),
+		Body:             githubv4.NewString(githubv4.String(body)),
+	})
+}
+
+func (g *GithubGraphqlAPI) createPullRequest(ctx context.Context, input githubv4.CreatePullRequestInput) (int64, error) {
+	var ret createPullRequest
+	if err := g.ClientV4.Mutate(ctx, &ret, input, nil); err != nil {
 		return 0, fmt.Errorf("failed to create pull request: %w", err)
 	}
 	return int64(ret.CreatePullRequest.PullRequest.Number), nil
 }
 
-func (g *GithubGraphqlAPI) RepositoryInfo(ctx context.Context, owner string, name string) (*RepositoryInfo, error) {
-	g.Logger.Debug("fetching repository info", zap.String("owner", owner), zap.String("name", name))
-	defer g.Logger.Debug("done fetching repository info")
-	var repoInfo RepositoryInfo
-	if err := g.ClientV4.Query(ctx, &repoInfo, map[string]interface{}{
-		"owner": githubv4.String(owner),
-		"name":  githubv4.String(name),
-	}); err != nil {
-		return nil, fmt.Errorf("unable to query graphql for repository info: %w", err)
+func (g *GithubGraphqlAPI) RepositoryInfo(ctx context.Context, owner string, name string) (_ *RepositoryInfo, err error) {
+	ctx = withOperation(ctx, "RepositoryInfo")
+	defer annotateError(&err, OperationError{Operation: "RepositoryInfo", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("fetching repository info", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("done fetching repository info")
+	cacheKey := repoKey{
+		owner: owner,
+		name:  name,
+	}
+	if cached, exists := cacheGet(ctx, &g.repoInfoCache, cacheKey); exists {
+		g.logger(ctx).Debug("repository info cached value")
+		ret := *cached
+		return &ret, nil
+	}
+	info, shared, err := g.repoInfoFlight.Do(ctx, cacheKey, func(ctx context.Context) (*RepositoryInfo, error) {
+		var repoInfo RepositoryInfo
+		if err := g.ClientV4.Query(ctx, &repoInfo, map[string]interface{}{
+			"owner": githubv4.String(owner),
+			"name":  githubv4.String(name),
+		}); err != nil {
+			return nil, fmt.Errorf("unable to query graphql for repository info: %w", err)
+		}
+		cacheSet(ctx, &g.repoInfoCache, cacheKey, &repoInfo)
+		return &repoInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		g.logger(ctx).Debug("shared in flight repository info query")
 	}
-	return &repoInfo, nil
+	// Every caller gets its own copy, like cache hits do
+	ret := *info
+	return &ret, nil
 }
 
 var _ GitHub = &GithubGraphqlAPI{}
@@ -0,0 +1,68 @@
+package gogithub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// newBenchClient returns a client talking to a canned GraphQL server, so benchmarks measure the client and not GitHub
+func newBenchClient(b *testing.B) *GithubGraphqlAPI {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "pullRequests(") {
+			_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequests":{"nodes":[{"number":42}]}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_1","number":42,"baseRefName":"main","baseRefOid":"abc","headRefName":"feature","headRefOid":"def","body":"hello","state":"OPEN"}}}}`))
+	}))
+	b.Cleanup(srv.Close)
+	return createGraphqlAPI(githubv4.NewEnterpriseClient(srv.URL, srv.Client()), srv.Client(), zap.NewNop(), 0, func(_ context.Context) (string, error) {
+		return "bench-token", nil
+	})
+}
+
+func BenchmarkFindPRForBranch(b *testing.B) {
+	g := newBenchClient(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.FindPRForBranch(ctx, "cresta", "gogithub", "feature"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindPRForBranch_Cached(b *testing.B) {
+	g := newBenchClient(b)
+	g.findPrCache.DefaultExpiry = time.Hour
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.FindPRForBranch(ctx, "cresta", "gogithub", "feature"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindPullRequest(b *testing.B) {
+	g := newBenchClient(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := g.FindPullRequest(ctx, "cresta", "gogithub", 42); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// QueryRaw runs a custom GraphQL query, shaped like the githubv4 query structs, against the authenticated client
+func (g *GithubGraphqlAPI) QueryRaw(ctx context.Context, q interface{}, variables map[string]interface{}) (err error) {
+	ctx = withOperation(ctx, "QueryRaw")
+	defer annotateError(&err, OperationError{Operation: "QueryRaw"})
+	g.logger(ctx).Debug("QueryRaw", zap.Any("variables", variables))
+	defer g.logger(ctx).Debug("Done QueryRaw")
+	if err := g.ClientV4.Query(ctx, q, variables); err != nil {
+		return fmt.Errorf("failed to run raw query: %w", err)
+	}
+	return nil
+}
+
+// MutateRaw runs a custom GraphQL mutation against the authenticated client.  input is sent as the $input variable.
+func (g *GithubGraphqlAPI) MutateRaw(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) (err error) {
+	ctx = withOperation(ctx, "MutateRaw")
+	defer annotateError(&err, OperationError{Operation: "MutateRaw"})
+	g.logger(ctx).Debug("MutateRaw", zap.Any("variables", variables))
+	defer g.logger(ctx).Debug("Done MutateRaw")
+	if err := g.ClientV4.Mutate(ctx, m, input, variables); err != nil {
+		return fmt.Errorf("failed to run raw mutation: %w", err)
+	}
+	return nil
+}
+
+// ErrNodeNotFound is returned by GetNode when no node has the ID, or the client cannot see it
+var ErrNodeNotFound = errors.New("node not found")
+
+// nodeQuery builds struct { Node *T `graphql:"node(id: $id)"` } for into, a pointer to T
+func nodeQuery(into interface{}) (reflect.Value, error) {
+	t := reflect.TypeOf(into)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("into must be a pointer to a struct, got %T", into)
+	}
+	queryType := reflect.StructOf([]reflect.StructField{
+		{
+			Name: "Node",
+			Type: reflect.PointerTo(t.Elem()),
+			Tag:  `graphql:"node(id: $id)"`,
+		},
+	})
+	return reflect.New(queryType), nil
+}
+
+// GetNode fetches the node with the global ID id into into, a pointer to a struct selecting the node fields with inline
+// fragments, for example:
+//
+//	var pr struct {
+//		PullRequest struct {
+//			Number int64
+//			Title  string
+//		} `graphql:"... on PullRequest"`
+//	}
+//	err := gh.GetNode(ctx, nodeID, &pr)
+func (g *GithubGraphqlAPI) GetNode(ctx context.Context, id githubv4.ID, into interface{}) (err error) {
+	ctx = withOperation(ctx, "GetNode")
+	defer annotateError(&err, OperationError{Operation: "GetNode"})
+	g.logger(ctx).Debug("GetNode", zap.Any("id", id))
+	defer g.logger(ctx).Debug("Done GetNode")
+	query, err := nodeQuery(into)
+	if err != nil {
+		return err
+	}
+	if err := g.ClientV4.Query(ctx, query.Interface(), map[string]interface{}{
+		"id": id,
+	}); err != nil {
+		return fmt.Errorf("failed to query node: %w", err)
+	}
+	node := query.Elem().Field(0)
+	if node.IsNil() {
+		return fmt.Errorf("%w: %v", ErrNodeNotFound, id)
+	}
+	reflect.ValueOf(into).Elem().Set(node.Elem())
+	return nil
+}
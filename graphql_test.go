@@ -0,0 +1,85 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeQuery(t *testing.T) {
+	var pr struct {
+		PullRequest struct {
+			Number int64
+		} `graphql:"... on PullRequest"`
+	}
+	q, err := nodeQuery(&pr)
+	require.NoError(t, err)
+	field, ok := q.Elem().Type().FieldByName("Node")
+	require.True(t, ok)
+	require.Equal(t, `node(id: $id)`, field.Tag.Get("graphql"))
+	require.Equal(t, reflect.PointerTo(reflect.TypeOf(pr)), field.Type)
+
+	_, err = nodeQuery(pr)
+	require.Error(t, err)
+	_, err = nodeQuery(nil)
+	require.Error(t, err)
+}
+
+func TestQueryRaw(t *testing.T) {
+	var got graphqlTestRequest
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = decodeGraphQLRequest(t, r)
+		_, _ = w.Write([]byte(`{"data":{"repository":{"stargazerCount":42}}}`))
+	})
+	var q struct {
+		Repository struct {
+			StargazerCount int
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	err := g.QueryRaw(context.Background(), &q, map[string]interface{}{
+		"owner": githubv4.String("o"),
+		"name":  githubv4.String("r"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 42, q.Repository.StargazerCount)
+	require.Equal(t, "query($name:String!$owner:String!){repository(owner: $owner, name: $name){stargazerCount}}", got.Query)
+	require.Equal(t, map[string]interface{}{"owner": "o", "name": "r"}, got.Variables)
+}
+
+func TestMutateRaw(t *testing.T) {
+	var got graphqlTestRequest
+	fail := false
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = decodeGraphQLRequest(t, r)
+		if fail {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"boom"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"addStar":{"starrable":{"stargazerCount":43}}}}`))
+	})
+	var m struct {
+		AddStar struct {
+			Starrable struct {
+				StargazerCount int
+			}
+		} `graphql:"addStar(input: $input)"`
+	}
+	err := g.MutateRaw(context.Background(), &m, githubv4.AddStarInput{StarrableID: "R_1"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 43, m.AddStar.Starrable.StargazerCount)
+	require.Equal(t, "mutation($input:AddStarInput!){addStar(input: $input){starrable{stargazerCount}}}", got.Query)
+	require.Equal(t, map[string]interface{}{"input": map[string]interface{}{"starrableId": "R_1"}}, got.Variables)
+
+	// Errors carry the operation
+	fail = true
+	err = g.MutateRaw(context.Background(), &m, githubv4.AddStarInput{StarrableID: "R_1"}, nil)
+	require.ErrorContains(t, err, "boom")
+	var opErr *OperationError
+	require.True(t, errors.As(err, &opErr))
+	require.Equal(t, "MutateRaw", opErr.Operation)
+}
@@ -0,0 +1,138 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TrafficPoint is the traffic of one day
+type TrafficPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Count     int       `json:"count"`
+	Uniques   int       `json:"uniques"`
+}
+
+// TrafficSeries is the daily traffic of the last 14 days with its totals
+type TrafficSeries struct {
+	Count   int
+	Uniques int
+	Daily   []TrafficPoint
+}
+
+// TrafficReferrer is a site that sent visitors to a repository
+type TrafficReferrer struct {
+	Referrer string `json:"referrer"`
+	Count    int    `json:"count"`
+	Uniques  int    `json:"uniques"`
+}
+
+// RepositoryTraffic is what GitHub reports of the last 14 days of traffic of a repository
+type RepositoryTraffic struct {
+	Views  TrafficSeries
+	Clones TrafficSeries
+	// Referrers are the top 10 referring sites
+	Referrers []TrafficReferrer
+}
+
+// ContributorWeek is the activity of a contributor during the week starting at Start
+type ContributorWeek struct {
+	Start     time.Time
+	Additions int
+	Deletions int
+	Commits   int
+}
+
+// ContributorStats is the commit activity of a contributor to the default branch of a repository
+type ContributorStats struct {
+	Author string
+	// Total is the number of commits of Author
+	Total int
+	Weeks []ContributorWeek
+}
+
+type restContributorStats struct {
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Total int `json:"total"`
+	Weeks []struct {
+		// W is the start of the week in seconds since the epoch
+		W int64 `json:"w"`
+		A int   `json:"a"`
+		D int   `json:"d"`
+		C int   `json:"c"`
+	} `json:"weeks"`
+}
+
+func (s restContributorStats) toContributorStats() ContributorStats {
+	ret := ContributorStats{Total: s.Total, Weeks: make([]ContributorWeek, 0, len(s.Weeks))}
+	if s.Author != nil {
+		ret.Author = s.Author.Login
+	}
+	for _, w := range s.Weeks {
+		ret.Weeks = append(ret.Weeks, ContributorWeek{
+			Start:     time.Unix(w.W, 0).UTC(),
+			Additions: w.A,
+			Deletions: w.D,
+			Commits:   w.C,
+		})
+	}
+	return ret
+}
+
+// GetRepositoryTraffic returns the daily views and clones of a repository over the last 14 days and its top
+// referrers.  The client needs push access to the repository.
+func (g *GithubGraphqlAPI) GetRepositoryTraffic(ctx context.Context, owner string, name string) (_ *RepositoryTraffic, err error) {
+	ctx = withOperation(ctx, "GetRepositoryTraffic")
+	defer annotateError(&err, OperationError{Operation: "GetRepositoryTraffic", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetRepositoryTraffic", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done GetRepositoryTraffic")
+	var views struct {
+		Count   int            `json:"count"`
+		Uniques int            `json:"uniques"`
+		Views   []TrafficPoint `json:"views"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/traffic/views?per=day", owner, name), nil, &views); err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+	var clones struct {
+		Count   int            `json:"count"`
+		Uniques int            `json:"uniques"`
+		Clones  []TrafficPoint `json:"clones"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/traffic/clones?per=day", owner, name), nil, &clones); err != nil {
+		return nil, fmt.Errorf("failed to get clones: %w", err)
+	}
+	var referrers []TrafficReferrer
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/traffic/popular/referrers", owner, name), nil, &referrers); err != nil {
+		return nil, fmt.Errorf("failed to get referrers: %w", err)
+	}
+	return &RepositoryTraffic{
+		Views:     TrafficSeries{Count: views.Count, Uniques: views.Uniques, Daily: views.Views},
+		Clones:    TrafficSeries{Count: clones.Count, Uniques: clones.Uniques, Daily: clones.Clones},
+		Referrers: referrers,
+	}, nil
+}
+
+// GetContributorStats returns the weekly additions, deletions and commits of every contributor to the default branch
+// of a repository.  GitHub computes the statistics in the background on first request, so this waits, as configured
+// by NewGQLClientConfig.AcceptedBackoff, until they are ready.
+func (g *GithubGraphqlAPI) GetContributorStats(ctx context.Context, owner string, name string) (_ []ContributorStats, err error) {
+	ctx = withOperation(ctx, "GetContributorStats")
+	defer annotateError(&err, OperationError{Operation: "GetContributorStats", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetContributorStats", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done GetContributorStats")
+	var stats []restContributorStats
+	if err := g.doRESTWaitAccepted(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/stats/contributors", owner, name), nil, &stats); err != nil {
+		return nil, fmt.Errorf("failed to get contributor stats: %w", err)
+	}
+	ret := make([]ContributorStats, 0, len(stats))
+	for _, s := range stats {
+		ret = append(ret, s.toContributorStats())
+	}
+	return ret, nil
+}
@@ -0,0 +1,57 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRepositoryTraffic(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/traffic/views":
+			require.Equal(t, "day", r.URL.Query().Get("per"))
+			_, _ = w.Write([]byte(`{"count":14,"uniques":3,"views":[{"timestamp":"2024-05-01T00:00:00Z","count":14,"uniques":3}]}`))
+		case "/repos/o/r/traffic/clones":
+			_, _ = w.Write([]byte(`{"count":2,"uniques":1,"clones":[{"timestamp":"2024-05-01T00:00:00Z","count":2,"uniques":1}]}`))
+		case "/repos/o/r/traffic/popular/referrers":
+			_, _ = w.Write([]byte(`[{"referrer":"github.com","count":10,"uniques":2}]`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	traffic, err := g.GetRepositoryTraffic(context.Background(), "o", "r")
+	require.NoError(t, err)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, TrafficSeries{Count: 14, Uniques: 3, Daily: []TrafficPoint{{Timestamp: day, Count: 14, Uniques: 3}}}, traffic.Views)
+	require.Equal(t, TrafficSeries{Count: 2, Uniques: 1, Daily: []TrafficPoint{{Timestamp: day, Count: 2, Uniques: 1}}}, traffic.Clones)
+	require.Equal(t, []TrafficReferrer{{Referrer: "github.com", Count: 10, Uniques: 2}}, traffic.Referrers)
+}
+
+func TestGetContributorStats(t *testing.T) {
+	calls := 0
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/o/r/stats/contributors" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"author":{"login":"alice"},"total":3,"weeks":[{"w":1714262400,"a":10,"d":2,"c":3}]},
+			{"author":null,"total":1,"weeks":[]}]`))
+	})
+	g.acceptedBackoff = AcceptedBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 3}
+	stats, err := g.GetContributorStats(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, []ContributorStats{
+		{Author: "alice", Total: 3, Weeks: []ContributorWeek{{Start: time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC), Additions: 10, Deletions: 2, Commits: 3}}},
+		{Total: 1, Weeks: []ContributorWeek{}},
+	}, stats)
+}
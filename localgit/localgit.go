@@ -0,0 +1,163 @@
+// Package localgit reads the branch and remotes of a local git checkout through the git CLI, so command line tools
+// can act on "the pull request of what I have checked out".
+package localgit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/cresta/gogithub"
+)
+
+// DefaultRemote is the remote used when Client.Remote is empty
+const DefaultRemote = "origin"
+
+var (
+	// ErrDetachedHead is returned when HEAD is not on a branch
+	ErrDetachedHead = errors.New("HEAD is not on a branch")
+	// ErrOnDefaultBranch is returned when asked to open a pull request from the default branch into itself
+	ErrOnDefaultBranch = errors.New("current branch is the default branch")
+	// ErrBranchNotPushed is returned when the current branch has no remote tracking branch of the same name
+	ErrBranchNotPushed = errors.New("current branch is not pushed")
+)
+
+// Repo is a local git checkout
+type Repo struct {
+	// Dir is any directory inside the checkout.  Empty means the working directory.
+	Dir string
+	// Git is the git binary.  Empty means "git" from PATH.
+	Git string
+}
+
+func (r *Repo) git(ctx context.Context, args ...string) (string, error) {
+	bin := r.Git
+	if bin == "" {
+		bin = "git"
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = r.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// CurrentBranch returns the short name of the checked out branch
+func (r *Repo) CurrentBranch(ctx context.Context) (string, error) {
+	// rev-parse prints HEAD when detached, and works before the first commit unlike symbolic-ref --short on old gits
+	branch, err := r.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return "", ErrDetachedHead
+	}
+	return branch, nil
+}
+
+// RemoteURL returns the fetch URL of remote
+func (r *Repo) RemoteURL(ctx context.Context, remote string) (string, error) {
+	u, err := r.git(ctx, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to read remote %s: %w", remote, err)
+	}
+	return u, nil
+}
+
+// Remote returns the GitHub repository remote points at
+func (r *Repo) Remote(ctx context.Context, remote string) (gogithub.Remote, error) {
+	u, err := r.RemoteURL(ctx, remote)
+	if err != nil {
+		return gogithub.Remote{}, err
+	}
+	return gogithub.ParseRemote(u)
+}
+
+// HasRemoteBranch reports whether the remote tracking branch remote/branch exists locally, which is the case once
+// the branch was pushed or fetched
+func (r *Repo) HasRemoteBranch(ctx context.Context, remote string, branch string) (bool, error) {
+	_, err := r.git(ctx, "rev-parse", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to verify remote branch: %w", err)
+}
+
+// Client opens pull requests for the branch checked out in Repo
+type Client struct {
+	GitHub gogithub.GitHub
+	Repo   *Repo
+	// Remote is the git remote the branch is pushed to.  Empty means DefaultRemote.
+	Remote string
+}
+
+// New returns a Client for the checkout containing dir
+func New(gh gogithub.GitHub, dir string) *Client {
+	return &Client{
+		GitHub: gh,
+		Repo:   &Repo{Dir: dir},
+	}
+}
+
+func (c *Client) remote() string {
+	if c.Remote == "" {
+		return DefaultRemote
+	}
+	return c.Remote
+}
+
+// CurrentRepository returns the GitHub repository of the configured remote
+func (c *Client) CurrentRepository(ctx context.Context) (*gogithub.Repository, error) {
+	remote, err := c.Repo.Remote(ctx, c.remote())
+	if err != nil {
+		return nil, err
+	}
+	repo, err := c.GitHub.GetRepository(ctx, remote.RepoRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", remote.RepoRef, err)
+	}
+	return repo, nil
+}
+
+// CreatePullRequestForCurrentBranch opens a pull request of the checked out branch into the default branch of the
+// remote repository.  The branch must already be pushed under the same name.  If an open pull request already exists
+// for the branch, its number is returned instead.
+func (c *Client) CreatePullRequestForCurrentBranch(ctx context.Context, title string, body string) (int64, error) {
+	branch, err := c.Repo.CurrentBranch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	repo, err := c.CurrentRepository(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if branch == repo.DefaultBranch {
+		return 0, fmt.Errorf("%w: %s", ErrOnDefaultBranch, branch)
+	}
+	pushed, err := c.Repo.HasRemoteBranch(ctx, c.remote(), branch)
+	if err != nil {
+		return 0, err
+	}
+	if !pushed {
+		return 0, fmt.Errorf("%w: no %s/%s", ErrBranchNotPushed, c.remote(), branch)
+	}
+	existing, err := c.GitHub.FindPRForBranch(ctx, repo.Owner, repo.Name, branch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look for an existing pull request: %w", err)
+	}
+	if existing != 0 {
+		return existing, nil
+	}
+	return c.GitHub.CreatePullRequest(ctx, repo.ID, repo.DefaultBranch, branch, title, body)
+}
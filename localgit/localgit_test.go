@@ -0,0 +1,94 @@
+package localgit
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/cresta/gogithub"
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/require"
+)
+
+func initRepo(t *testing.T) *Repo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	r := &Repo{Dir: t.TempDir()}
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"remote", "add", "origin", "git@github.com:cresta/gogithub.git"},
+	} {
+		_, err := r.git(ctx, args...)
+		require.NoError(t, err)
+	}
+	return r
+}
+
+type fakeGitHub struct {
+	gogithub.GitHub
+	existing int64
+	created  []string
+}
+
+func (f *fakeGitHub) GetRepository(_ context.Context, ref gogithub.RepoRef) (*gogithub.Repository, error) {
+	return &gogithub.Repository{RepoRef: ref, ID: "R_1", DefaultBranch: "main"}, nil
+}
+
+func (f *fakeGitHub) FindPRForBranch(_ context.Context, _ string, _ string, _ string) (int64, error) {
+	return f.existing, nil
+}
+
+func (f *fakeGitHub) CreatePullRequest(_ context.Context, _ graphql.ID, baseRefName string, remoteRefName string, _ string, _ string) (int64, error) {
+	f.created = append(f.created, baseRefName+"<-"+remoteRefName)
+	return 7, nil
+}
+
+func TestRepo(t *testing.T) {
+	r := initRepo(t)
+	ctx := context.Background()
+	branch, err := r.CurrentBranch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "main", branch)
+	remote, err := r.Remote(ctx, "origin")
+	require.NoError(t, err)
+	require.Equal(t, gogithub.RepoRef{Owner: "cresta", Name: "gogithub"}, remote.RepoRef)
+	pushed, err := r.HasRemoteBranch(ctx, "origin", "main")
+	require.NoError(t, err)
+	require.False(t, pushed)
+
+	_, err = r.git(ctx, "checkout", "-q", "--detach")
+	require.NoError(t, err)
+	_, err = r.CurrentBranch(ctx)
+	require.ErrorIs(t, err, ErrDetachedHead)
+}
+
+func TestCreatePullRequestForCurrentBranch(t *testing.T) {
+	r := initRepo(t)
+	ctx := context.Background()
+	gh := &fakeGitHub{}
+	c := &Client{GitHub: gh, Repo: r}
+	_, err := c.CreatePullRequestForCurrentBranch(ctx, "title", "body")
+	require.ErrorIs(t, err, ErrOnDefaultBranch)
+
+	_, err = r.git(ctx, "checkout", "-q", "-b", "feature")
+	require.NoError(t, err)
+	_, err = c.CreatePullRequestForCurrentBranch(ctx, "title", "body")
+	require.ErrorIs(t, err, ErrBranchNotPushed)
+
+	_, err = r.git(ctx, "update-ref", "refs/remotes/origin/feature", "HEAD")
+	require.NoError(t, err)
+	number, err := c.CreatePullRequestForCurrentBranch(ctx, "title", "body")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), number)
+	require.Equal(t, []string{"main<-feature"}, gh.created)
+
+	gh.existing = 3
+	number, err = c.CreatePullRequestForCurrentBranch(ctx, "title", "body")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), number)
+	require.Len(t, gh.created, 1)
+}
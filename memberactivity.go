@@ -0,0 +1,281 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// ErrOrgPlanNotVisible is returned by GetOrgPlan when the client is not an owner of the organization
+var ErrOrgPlanNotVisible = errors.New("organization plan is only visible to owners")
+
+const (
+	// ActivitySourceAuditLog marks activity found in the organization audit log
+	ActivitySourceAuditLog = "audit_log"
+	// ActivitySourceContributions marks activity found in the contributions of the member to the organization
+	ActivitySourceContributions = "contributions"
+)
+
+// OrgPlan is the plan of an organization with its seat usage
+type OrgPlan struct {
+	Name         string `json:"name"`
+	Seats        int    `json:"seats"`
+	FilledSeats  int    `json:"filled_seats"`
+	PrivateRepos int    `json:"private_repos"`
+}
+
+// MemberActivity is the last known activity of an organization member
+type MemberActivity struct {
+	Login string
+	// LastActiveAt is zero when no activity was found since the lookup cutoff
+	LastActiveAt time.Time
+	// Source is ActivitySourceAuditLog or ActivitySourceContributions.  Empty when no activity was found.
+	Source string
+}
+
+func (g *GithubGraphqlAPI) GetOrgPlan(ctx context.Context, org string) (_ *OrgPlan, err error) {
+	ctx = withOperation(ctx, "GetOrgPlan")
+	defer annotateError(&err, OperationError{Operation: "GetOrgPlan", Owner: org})
+	g.logger(ctx).Debug("GetOrgPlan", zap.String("org", org))
+	defer g.logger(ctx).Debug("Done GetOrgPlan")
+	var ret struct {
+		Plan *OrgPlan `json:"plan"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/orgs/%s", org), nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	if ret.Plan == nil {
+		return nil, ErrOrgPlanNotVisible
+	}
+	return ret.Plan, nil
+}
+
+type auditLogActorEvent struct {
+	// Timestamp is in milliseconds since the epoch
+	Timestamp int64 `json:"@timestamp"`
+}
+
+func (g *GithubGraphqlAPI) OrgMemberActivity(ctx context.Context, org string, login string, since time.Time) (_ *MemberActivity, err error) {
+	ctx = withOperation(ctx, "OrgMemberActivity")
+	defer annotateError(&err, OperationError{Operation: "OrgMemberActivity", Owner: org})
+	g.logger(ctx).Debug("OrgMemberActivity", zap.String("org", org), zap.String("login", login), zap.Time("since", since))
+	defer g.logger(ctx).Debug("Done OrgMemberActivity")
+	ret := &MemberActivity{Login: login}
+	var events []auditLogActorEvent
+	phrase := fmt.Sprintf("actor:%s created:>=%s", login, since.UTC().Format("2006-01-02"))
+	err = g.doREST(ctx, http.MethodGet, fmt.Sprintf("/orgs/%s/audit-log?phrase=%s&order=desc&per_page=1", org, url.QueryEscape(phrase)), nil, &events)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		if len(events) > 0 {
+			ret.LastActiveAt = time.UnixMilli(events[0].Timestamp).UTC()
+			ret.Source = ActivitySourceAuditLog
+		}
+		return ret, nil
+	case errors.As(err, &restErr) && (restErr.StatusCode == http.StatusNotFound || restErr.StatusCode == http.StatusForbidden):
+		// The audit log API needs GitHub Enterprise Cloud.  Contributions are the best signal left.
+	default:
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	lastContribution, err := g.lastContribution(ctx, org, login, since)
+	if err != nil {
+		return nil, err
+	}
+	if !lastContribution.IsZero() {
+		ret.LastActiveAt = lastContribution
+		ret.Source = ActivitySourceContributions
+	}
+	return ret, nil
+}
+
+// lastContribution returns the day of the latest contribution of login to org since since.  GitHub only reports a
+// year of contributions at a time, so since is capped to a year ago.
+func (g *GithubGraphqlAPI) lastContribution(ctx context.Context, org string, login string, since time.Time) (time.Time, error) {
+	var orgQuery struct {
+		Organization struct {
+			ID githubv4.ID
+		} `graphql:"organization(login: $org)"`
+	}
+	if err := g.ClientV4.Query(ctx, &orgQuery, map[string]interface{}{
+		"org": githubv4.String(org),
+	}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to query organization: %w", err)
+	}
+	if yearAgo := time.Now().AddDate(-1, 0, 1); since.Before(yearAgo) {
+		since = yearAgo
+	}
+	var query struct {
+		User struct {
+			ContributionsCollection struct {
+				ContributionCalendar struct {
+					Weeks []struct {
+						ContributionDays []struct {
+							Date              string
+							ContributionCount int
+						}
+					}
+				}
+			} `graphql:"contributionsCollection(organizationID: $orgID, from: $from)"`
+		} `graphql:"user(login: $login)"`
+	}
+	if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+		"login": githubv4.String(login),
+		"orgID": orgQuery.Organization.ID,
+		"from":  githubv4.DateTime{Time: since},
+	}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to query contributions: %w", err)
+	}
+	var ret time.Time
+	for _, w := range query.User.ContributionsCollection.ContributionCalendar.Weeks {
+		for _, d := range w.ContributionDays {
+			if d.ContributionCount == 0 {
+				continue
+			}
+			day, err := time.Parse("2006-01-02", d.Date)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed to parse contribution date %q: %w", d.Date, err)
+			}
+			if day.After(ret) {
+				ret = day
+			}
+		}
+	}
+	return ret, nil
+}
+
+// MemberActivityError collects the members whose activity could not be looked up
+type MemberActivityError struct {
+	Errors map[string]error
+	Total  int
+}
+
+func (e *MemberActivityError) Error() string {
+	logins := make([]string, 0, len(e.Errors))
+	for login := range e.Errors {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	msgs := make([]string, 0, len(logins))
+	for _, login := range logins {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", login, e.Errors[login]))
+	}
+	return fmt.Sprintf("%d of %d members failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// DormantMembersOptions configures FindDormantMembers
+type DormantMembersOptions struct {
+	Org string
+	// InactiveFor is how long without activity makes a member dormant.  Defaults to 90 days.
+	InactiveFor time.Duration
+	// Concurrency is how many members are looked up at once.  Defaults to 5.
+	Concurrency int
+	// Now is the time the report is made at.  Defaults to time.Now.
+	Now time.Time
+}
+
+func (o DormantMembersOptions) withDefaults() DormantMembersOptions {
+	if o.InactiveFor == 0 {
+		o.InactiveFor = 90 * 24 * time.Hour
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = 5
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	return o
+}
+
+// MemberReportEntry is a member of the organization with their last known activity
+type MemberReportEntry struct {
+	OrgMember
+	LastActiveAt time.Time
+	Source       string
+}
+
+// DormantMembersReport lists the members of an organization by last activity, for access reviews
+type DormantMembersReport struct {
+	Org string
+	// Cutoff is the time before which a member's last activity makes them dormant
+	Cutoff time.Time
+	// Plan is the seat usage of the organization, or nil if the client cannot see it
+	Plan *OrgPlan
+	// Members are every member, least recently active first
+	Members []MemberReportEntry
+}
+
+// Dormant returns the members without activity since Cutoff, least recently active first
+func (r *DormantMembersReport) Dormant() []MemberReportEntry {
+	var ret []MemberReportEntry
+	for _, m := range r.Members {
+		if m.LastActiveAt.Before(r.Cutoff) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// FindDormantMembers looks up the last activity of every member of opts.Org.  A member whose lookup fails does not
+// abort the report: the report of the other members is returned along with a *MemberActivityError.
+func FindDormantMembers(ctx context.Context, gh GitHub, opts DormantMembersOptions) (*DormantMembersReport, error) {
+	opts = opts.withDefaults()
+	report := &DormantMembersReport{
+		Org:    opts.Org,
+		Cutoff: opts.Now.Add(-opts.InactiveFor),
+	}
+	plan, err := gh.GetOrgPlan(ctx, opts.Org)
+	switch {
+	case err == nil:
+		report.Plan = plan
+	case !errors.Is(err, ErrOrgPlanNotVisible):
+		return nil, err
+	}
+	members, err := gh.ListOrgMembers(ctx, opts.Org)
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for _, m := range members {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(m OrgMember) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			activity, err := gh.OrgMemberActivity(ctx, opts.Org, m.Login, report.Cutoff)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[m.Login] = err
+				return
+			}
+			report.Members = append(report.Members, MemberReportEntry{
+				OrgMember:    m,
+				LastActiveAt: activity.LastActiveAt,
+				Source:       activity.Source,
+			})
+		}(m)
+	}
+	wg.Wait()
+	sort.Slice(report.Members, func(i, j int) bool {
+		if !report.Members[i].LastActiveAt.Equal(report.Members[j].LastActiveAt) {
+			return report.Members[i].LastActiveAt.Before(report.Members[j].LastActiveAt)
+		}
+		return report.Members[i].Login < report.Members[j].Login
+	})
+	if len(errs) > 0 {
+		return report, &MemberActivityError{Errors: errs, Total: len(members)}
+	}
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrgPlan(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/owned":
+			_, _ = w.Write([]byte(`{"login":"owned","plan":{"name":"team","seats":10,"filled_seats":7,"private_repos":999}}`))
+		default:
+			_, _ = w.Write([]byte(`{"login":"other"}`))
+		}
+	})
+	plan, err := g.GetOrgPlan(context.Background(), "owned")
+	require.NoError(t, err)
+	require.Equal(t, OrgPlan{Name: "team", Seats: 10, FilledSeats: 7, PrivateRepos: 999}, *plan)
+
+	_, err = g.GetOrgPlan(context.Background(), "other")
+	require.ErrorIs(t, err, ErrOrgPlanNotVisible)
+}
+
+func TestOrgMemberActivity_AuditLog(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/orgs/o/audit-log", r.URL.Path)
+		require.Equal(t, "actor:alice created:>=2024-03-01", r.URL.Query().Get("phrase"))
+		require.Equal(t, "desc", r.URL.Query().Get("order"))
+		_, _ = w.Write([]byte(`[{"@timestamp":1717243200000,"action":"git.push","actor":"alice"}]`))
+	})
+	activity, err := g.OrgMemberActivity(context.Background(), "o", "alice", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, ActivitySourceAuditLog, activity.Source)
+	require.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), activity.LastActiveAt)
+}
+
+type dormantGitHub struct {
+	GitHub
+	activity map[string]time.Time
+}
+
+func (d *dormantGitHub) GetOrgPlan(_ context.Context, _ string) (*OrgPlan, error) {
+	return nil, ErrOrgPlanNotVisible
+}
+
+func (d *dormantGitHub) ListOrgMembers(_ context.Context, _ string) ([]OrgMember, error) {
+	return []OrgMember{{Login: "active"}, {Login: "idle"}, {Login: "never"}, {Login: "broken"}}, nil
+}
+
+func (d *dormantGitHub) OrgMemberActivity(_ context.Context, _ string, login string, _ time.Time) (*MemberActivity, error) {
+	if login == "broken" {
+		return nil, errors.New("boom")
+	}
+	ret := &MemberActivity{Login: login, LastActiveAt: d.activity[login]}
+	if !ret.LastActiveAt.IsZero() {
+		ret.Source = ActivitySourceAuditLog
+	}
+	return ret, nil
+}
+
+func TestFindDormantMembers(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	gh := &dormantGitHub{activity: map[string]time.Time{
+		"active": now.Add(-24 * time.Hour),
+		"idle":   now.Add(-120 * 24 * time.Hour),
+	}}
+	report, err := FindDormantMembers(context.Background(), gh, DormantMembersOptions{Org: "o", Now: now})
+	var activityErr *MemberActivityError
+	require.True(t, errors.As(err, &activityErr))
+	require.Equal(t, 4, activityErr.Total)
+	require.Contains(t, activityErr.Errors, "broken")
+
+	require.Nil(t, report.Plan)
+	require.Equal(t, now.Add(-90*24*time.Hour), report.Cutoff)
+	require.Len(t, report.Members, 3)
+	require.Equal(t, "never", report.Members[0].Login)
+	require.Equal(t, "active", report.Members[2].Login)
+	dormant := report.Dormant()
+	require.Len(t, dormant, 2)
+	require.Equal(t, "never", dormant[0].Login)
+	require.Equal(t, "idle", dormant[1].Login)
+}
@@ -0,0 +1,212 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// MergeQueueEntryState is where a pull request stands in a merge queue
+type MergeQueueEntryState string
+
+const (
+	MergeQueueEntryQueued MergeQueueEntryState = "QUEUED"
+	// MergeQueueEntryAwaitingChecks means the queue is running the required checks on the merge group
+	MergeQueueEntryAwaitingChecks MergeQueueEntryState = "AWAITING_CHECKS"
+	MergeQueueEntryMergeable      MergeQueueEntryState = "MERGEABLE"
+	// MergeQueueEntryUnmergeable means the entry failed and will be removed from the queue
+	MergeQueueEntryUnmergeable MergeQueueEntryState = "UNMERGEABLE"
+	// MergeQueueEntryLocked means the entry is being merged
+	MergeQueueEntryLocked MergeQueueEntryState = "LOCKED"
+)
+
+// MergeQueueEntry is a pull request waiting in a merge queue
+type MergeQueueEntry struct {
+	ID githubv4.ID
+	// Position is the place in the queue, starting at 0 for the next to merge
+	Position          int
+	State             MergeQueueEntryState
+	PullRequestNumber int64
+	// HeadCommitOID is the commit of the merge group the queue tests for this entry
+	HeadCommitOID string
+	EnqueuedAt    time.Time
+	// Enqueuer is the login of who added the pull request to the queue
+	Enqueuer string
+	// EstimatedTimeToMerge is zero when GitHub has no estimate
+	EstimatedTimeToMerge time.Duration
+	// Jump is set for entries added to the front of the queue
+	Jump bool
+}
+
+// MergeQueue is the merge queue of a branch
+type MergeQueue struct {
+	ID  githubv4.ID
+	URL string
+	// MergingStrategy is ALLGREEN or HEADGREEN
+	MergingStrategy string
+	// Entries are the first entries of the queue, in order
+	Entries []MergeQueueEntry
+}
+
+// EnqueueOptions are the options of EnqueuePullRequest
+type EnqueueOptions struct {
+	// Jump adds the pull request to the front of the queue
+	Jump bool
+	// ExpectedHeadOID, if set, refuses to enqueue a pull request whose head moved since
+	ExpectedHeadOID string
+}
+
+const mergeQueueMaxEntries = 100
+
+type mergeQueueEntryQuery struct {
+	ID          githubv4.ID
+	Position    int
+	State       MergeQueueEntryState
+	PullRequest *struct {
+		Number int64
+	}
+	HeadCommit *struct {
+		Oid string
+	}
+	EnqueuedAt githubv4.DateTime
+	Enqueuer   struct {
+		Login string
+	}
+	EstimatedTimeToMerge *int
+	Jump                 bool
+}
+
+func (e *mergeQueueEntryQuery) toMergeQueueEntry() MergeQueueEntry {
+	ret := MergeQueueEntry{
+		ID:         e.ID,
+		Position:   e.Position,
+		State:      e.State,
+		EnqueuedAt: e.EnqueuedAt.Time,
+		Enqueuer:   e.Enqueuer.Login,
+		Jump:       e.Jump,
+	}
+	if e.PullRequest != nil {
+		ret.PullRequestNumber = e.PullRequest.Number
+	}
+	if e.HeadCommit != nil {
+		ret.HeadCommitOID = e.HeadCommit.Oid
+	}
+	if e.EstimatedTimeToMerge != nil {
+		ret.EstimatedTimeToMerge = time.Duration(*e.EstimatedTimeToMerge) * time.Second
+	}
+	return ret
+}
+
+type mergeQueueQuery struct {
+	ID            githubv4.ID
+	URL           string `graphql:"url"`
+	Configuration *struct {
+		MergingStrategy string
+	}
+	Entries struct {
+		Nodes []mergeQueueEntryQuery
+	} `graphql:"entries(first: $maxEntries)"`
+}
+
+func (q *mergeQueueQuery) toMergeQueue() *MergeQueue {
+	ret := &MergeQueue{
+		ID:  q.ID,
+		URL: q.URL,
+	}
+	if q.Configuration != nil {
+		ret.MergingStrategy = q.Configuration.MergingStrategy
+	}
+	for i := range q.Entries.Nodes {
+		ret.Entries = append(ret.Entries, q.Entries.Nodes[i].toMergeQueueEntry())
+	}
+	return ret
+}
+
+// GetMergeQueue returns the merge queue of branch, or nil if the branch does not use one.  MergePullRequest and
+// EnablePullRequestAutoMerge fail, or enqueue, on branches with a merge queue; use EnqueuePullRequest there.
+func (g *GithubGraphqlAPI) GetMergeQueue(ctx context.Context, owner string, name string, branch string) (_ *MergeQueue, err error) {
+	ctx = withOperation(ctx, "GetMergeQueue")
+	defer annotateError(&err, OperationError{Operation: "GetMergeQueue", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetMergeQueue", zap.String("owner", owner), zap.String("name", name), zap.String("branch", branch))
+	defer g.logger(ctx).Debug("Done GetMergeQueue")
+	var query struct {
+		Repository struct {
+			MergeQueue *mergeQueueQuery `graphql:"mergeQueue(branch: $branch)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"name":       githubv4.String(name),
+		"branch":     githubv4.String(branch),
+		"maxEntries": githubv4.Int(mergeQueueMaxEntries),
+	}
+	if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query merge queue: %w", err)
+	}
+	if query.Repository.MergeQueue == nil {
+		return nil, nil
+	}
+	return query.Repository.MergeQueue.toMergeQueue(), nil
+}
+
+// EnqueuePullRequest adds a pull request to the merge queue of its base branch.  The queue merges it once the
+// required checks pass on the merge group.
+func (g *GithubGraphqlAPI) EnqueuePullRequest(ctx context.Context, owner string, name string, number int64, opts EnqueueOptions) (_ *MergeQueueEntry, err error) {
+	ctx = withOperation(ctx, "EnqueuePullRequest")
+	defer annotateError(&err, OperationError{Operation: "EnqueuePullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("EnqueuePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid), zap.Bool("jump", opts.Jump))
+	defer g.logger(ctx).Debug("Done EnqueuePullRequest")
+	var ret struct {
+		EnqueuePullRequest struct {
+			MergeQueueEntry mergeQueueEntryQuery
+		} `graphql:"enqueuePullRequest(input: $input)"`
+	}
+	input := githubv4.EnqueuePullRequestInput{
+		PullRequestID: prid,
+	}
+	if opts.Jump {
+		input.Jump = githubv4.NewBoolean(true)
+	}
+	if opts.ExpectedHeadOID != "" {
+		input.ExpectedHeadOid = githubv4.NewGitObjectID(githubv4.GitObjectID(opts.ExpectedHeadOID))
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, input, nil); err != nil {
+		return nil, fmt.Errorf("unable to enqueue PR: %w", err)
+	}
+	entry := ret.EnqueuePullRequest.MergeQueueEntry.toMergeQueueEntry()
+	return &entry, nil
+}
+
+// DequeuePullRequest removes a pull request from the merge queue
+func (g *GithubGraphqlAPI) DequeuePullRequest(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "DequeuePullRequest")
+	defer annotateError(&err, OperationError{Operation: "DequeuePullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("DequeuePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done DequeuePullRequest")
+	var ret struct {
+		DequeuePullRequest struct {
+			MergeQueueEntry struct {
+				ID githubv4.ID
+			}
+		} `graphql:"dequeuePullRequest(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.DequeuePullRequestInput{
+		ID: prid,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to dequeue PR: %w", err)
+	}
+	return nil
+}
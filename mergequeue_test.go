@@ -0,0 +1,36 @@
+package gogithub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeQueueQuery_ToMergeQueue(t *testing.T) {
+	var q mergeQueueQuery
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"url": "https://github.com/o/r/queue/main",
+		"configuration": {"mergingStrategy": "ALLGREEN"},
+		"entries": {"nodes": [
+			{"position": 0, "state": "AWAITING_CHECKS", "pullRequest": {"number": 7}, "headCommit": {"oid": "abc"}, "enqueuedAt": "2024-05-01T10:00:00Z", "enqueuer": {"login": "octocat"}, "estimatedTimeToMerge": 600},
+			{"position": 1, "state": "QUEUED", "pullRequest": {"number": 9}, "enqueuedAt": "2024-05-01T10:05:00Z", "enqueuer": {"login": "hubot"}, "estimatedTimeToMerge": null, "jump": true}
+		]}
+	}`), &q))
+	mq := q.toMergeQueue()
+	require.Equal(t, "ALLGREEN", mq.MergingStrategy)
+	require.Len(t, mq.Entries, 2)
+	require.Equal(t, MergeQueueEntry{
+		Position:             0,
+		State:                MergeQueueEntryAwaitingChecks,
+		PullRequestNumber:    7,
+		HeadCommitOID:        "abc",
+		EnqueuedAt:           time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Enqueuer:             "octocat",
+		EstimatedTimeToMerge: 10 * time.Minute,
+	}, mq.Entries[0])
+	require.Equal(t, int64(9), mq.Entries[1].PullRequestNumber)
+	require.Equal(t, time.Duration(0), mq.Entries[1].EstimatedTimeToMerge)
+	require.True(t, mq.Entries[1].Jump)
+}
@@ -0,0 +1,57 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+)
+
+// Metrics receives an observation for every HTTP request the client sends.  Implement it to feed Prometheus collectors
+// (or any other metrics system) with GitHub API consumption per method.
+type Metrics interface {
+	// ObserveCall is called once per request.  statusCode is 0 when err is a transport error.
+	ObserveCall(operation string, statusCode int, duration time.Duration, err error)
+	// ObserveRateLimit is called for every response that carries an X-RateLimit-Remaining header
+	ObserveRateLimit(operation string, remaining int)
+}
+
+// withOperation tags ctx with the client method name, used to label metrics
+func withOperation(ctx context.Context, operation string) context.Context {
+	return reqmeta.WithOperation(ctx, operation)
+}
+
+type MetricsTransport struct {
+	Base    http.RoundTripper
+	Metrics Metrics
+}
+
+func (m *MetricsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	operation := reqmeta.Operation(request.Context())
+	start := time.Now()
+	resp, err := m.Base.RoundTrip(request)
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+		if remaining, parseErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); parseErr == nil {
+			m.Metrics.ObserveRateLimit(operation, remaining)
+		}
+	}
+	m.Metrics.ObserveCall(operation, statusCode, time.Since(start), err)
+	return resp, err
+}
+
+// InstrumentedTransport wraps base so every request is reported to metrics.  It returns base unchanged if metrics is nil.
+func InstrumentedTransport(base http.RoundTripper, metrics Metrics) http.RoundTripper {
+	if metrics == nil {
+		return base
+	}
+	return &MetricsTransport{
+		Base:    base,
+		Metrics: metrics,
+	}
+}
+
+var _ http.RoundTripper = &MetricsTransport{}
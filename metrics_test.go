@@ -0,0 +1,48 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	operations []string
+	statuses   []int
+	remaining  []int
+}
+
+func (r *recordingMetrics) ObserveCall(operation string, statusCode int, _ time.Duration, _ error) {
+	r.operations = append(r.operations, operation)
+	r.statuses = append(r.statuses, statusCode)
+}
+
+func (r *recordingMetrics) ObserveRateLimit(_ string, remaining int) {
+	r.remaining = append(r.remaining, remaining)
+}
+
+func TestInstrumentedTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+	m := &recordingMetrics{}
+	client := &http.Client{Transport: InstrumentedTransport(http.DefaultTransport, m)}
+	req, err := http.NewRequestWithContext(withOperation(context.Background(), "FindPullRequest"), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, []string{"FindPullRequest"}, m.operations)
+	require.Equal(t, []int{http.StatusTeapot}, m.statuses)
+	require.Equal(t, []int{4999}, m.remaining)
+}
+
+func TestInstrumentedTransport_NilMetrics(t *testing.T) {
+	require.Equal(t, http.DefaultTransport, InstrumentedTransport(http.DefaultTransport, nil))
+}
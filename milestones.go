@@ -0,0 +1,87 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Milestone groups issues and pull requests, for example those of a release
+type Milestone struct {
+	// Number identifies the milestone in its repository, as SetIssueMilestone takes it
+	Number      int64      `json:"number"`
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	HTMLURL     string     `json:"html_url"`
+	DueOn       *time.Time `json:"due_on"`
+	// OpenIssues and ClosedIssues count the issues and pull requests of the milestone
+	OpenIssues   int `json:"open_issues"`
+	ClosedIssues int `json:"closed_issues"`
+}
+
+// MilestoneInput is the milestone CreateMilestone creates
+type MilestoneInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
+	// State is open or closed.  Defaults to open.
+	State string `json:"state,omitempty"`
+}
+
+// CreateMilestone creates a milestone in a repository
+func (g *GithubGraphqlAPI) CreateMilestone(ctx context.Context, owner string, name string, input MilestoneInput) (_ *Milestone, err error) {
+	ctx = withOperation(ctx, "CreateMilestone")
+	defer annotateError(&err, OperationError{Operation: "CreateMilestone", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateMilestone", zap.String("owner", owner), zap.String("name", name), zap.String("title", input.Title))
+	defer g.logger(ctx).Debug("Done CreateMilestone")
+	var ret Milestone
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/milestones", owner, name), input, &ret); err != nil {
+		return nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+	return &ret, nil
+}
+
+// ListMilestones returns the milestones of a repository in state, open, closed or all, ordered by due date
+func (g *GithubGraphqlAPI) ListMilestones(ctx context.Context, owner string, name string, state string) ([]Milestone, error) {
+	if state == "" {
+		state = "open"
+	}
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[Milestone], err error) {
+		ctx = withOperation(ctx, "ListMilestones")
+		defer annotateError(&err, OperationError{Operation: "ListMilestones", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListMilestones", zap.String("owner", owner), zap.String("name", name), zap.String("state", state), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListMilestones")
+		var milestones []Milestone
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/milestones?state=%s&per_page=100&page=%d", owner, name, state, restPageNumber(cursor)), nil, &milestones); err != nil {
+			return Page[Milestone]{}, fmt.Errorf("failed to list milestones: %w", err)
+		}
+		return restPage(milestones, cursor, 100), nil
+	}).All(ctx)
+}
+
+// SetIssueMilestone puts an issue in the milestone numbered milestoneNumber, or takes it out of its milestone when
+// milestoneNumber is 0
+func (g *GithubGraphqlAPI) SetIssueMilestone(ctx context.Context, owner string, name string, number int64, milestoneNumber int64) (err error) {
+	ctx = withOperation(ctx, "SetIssueMilestone")
+	defer annotateError(&err, OperationError{Operation: "SetIssueMilestone", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("SetIssueMilestone", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Int64("milestone", milestoneNumber))
+	defer g.logger(ctx).Debug("Done SetIssueMilestone")
+	body := map[string]interface{}{"milestone": nil}
+	if milestoneNumber != 0 {
+		body["milestone"] = milestoneNumber
+	}
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/issues/%d", owner, name, number), body, nil); err != nil {
+		return fmt.Errorf("failed to set milestone: %w", err)
+	}
+	return nil
+}
+
+// SetPullRequestMilestone is SetIssueMilestone for a pull request
+func (g *GithubGraphqlAPI) SetPullRequestMilestone(ctx context.Context, owner string, name string, number int64, milestoneNumber int64) error {
+	return g.SetIssueMilestone(ctx, owner, name, number, milestoneNumber)
+}
@@ -0,0 +1,48 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilestones(t *testing.T) {
+	var created map[string]interface{}
+	var patches []map[string]interface{}
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/milestones":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":3,"id":30,"title":"v1.2.0","state":"open","due_on":"2024-06-01T00:00:00Z"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/milestones":
+			require.Equal(t, "all", r.URL.Query().Get("state"))
+			_, _ = w.Write([]byte(`[{"number":3,"title":"v1.2.0","state":"open","open_issues":2,"closed_issues":5}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/issues/9":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			patches = append(patches, body)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	m, err := g.CreateMilestone(ctx, "o", "r", MilestoneInput{Title: "v1.2.0", DueOn: &due})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), m.Number)
+	require.Equal(t, map[string]interface{}{"title": "v1.2.0", "due_on": "2024-06-01T00:00:00Z"}, created)
+
+	list, err := g.ListMilestones(ctx, "o", "r", "all")
+	require.NoError(t, err)
+	require.Equal(t, []Milestone{{Number: 3, Title: "v1.2.0", State: "open", OpenIssues: 2, ClosedIssues: 5}}, list)
+
+	require.NoError(t, g.SetPullRequestMilestone(ctx, "o", "r", 9, 3))
+	require.NoError(t, g.SetIssueMilestone(ctx, "o", "r", 9, 0))
+	require.Equal(t, []map[string]interface{}{{"milestone": float64(3)}, {"milestone": nil}}, patches)
+}
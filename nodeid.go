@@ -0,0 +1,161 @@
+package gogithub
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NodeIDPrefixes maps GraphQL type names to the prefix of their next format global node IDs
+var NodeIDPrefixes = map[string]string{
+	"User":                     "U",
+	"Organization":             "O",
+	"Team":                     "T",
+	"Repository":               "R",
+	"Issue":                    "I",
+	"IssueComment":             "IC",
+	"PullRequest":              "PR",
+	"PullRequestReview":        "PRR",
+	"PullRequestReviewThread":  "PRRT",
+	"PullRequestReviewComment": "PRRC",
+	"Label":                    "LA",
+	"Milestone":                "MI",
+	"Discussion":               "D",
+	"Release":                  "RE",
+	"Deployment":               "DE",
+	"CheckRun":                 "CR",
+	"CheckSuite":               "CS",
+	"WorkflowRun":              "WFR",
+	"Workflow":                 "W",
+	"Gist":                     "G",
+}
+
+// ErrInvalidNodeID is returned when a node ID is in neither the legacy nor the next format
+var ErrInvalidNodeID = errors.New("invalid node ID")
+
+// NodeID is a decoded global node ID
+type NodeID struct {
+	// TypeName is set for legacy IDs, such as User
+	TypeName string
+	// Prefix is set for next format IDs, such as U
+	Prefix string
+	// IDs are the database IDs encoded in the node ID.  Objects scoped by a repository, such as pull requests in the
+	// next format, carry the repository ID first.
+	IDs []int64
+}
+
+// DatabaseID is the REST numeric ID of the object itself
+func (n NodeID) DatabaseID() int64 {
+	if len(n.IDs) == 0 {
+		return 0
+	}
+	return n.IDs[len(n.IDs)-1]
+}
+
+// LegacyNodeID returns the legacy global node ID of the REST object typeName with numeric ID id
+func LegacyNodeID(typeName string, id int64) string {
+	raw := fmt.Sprintf("0%d:%s%d", len(typeName), typeName, id)
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// NextNodeID returns the next format global node ID of typeName for ids.  Objects scoped by a repository, such as pull
+// requests, need the repository ID and then their own ID.
+func NextNodeID(typeName string, ids ...int64) (string, error) {
+	prefix, exists := NodeIDPrefixes[typeName]
+	if !exists {
+		return "", fmt.Errorf("no known node ID prefix for %s", typeName)
+	}
+	if len(ids) == 0 || len(ids) > 14 {
+		return "", fmt.Errorf("%w: need 1 to 14 IDs", ErrInvalidNodeID)
+	}
+	// A msgpack array of the format version, 0, then the IDs
+	b := []byte{0x90 | byte(len(ids)+1), 0}
+	for _, id := range ids {
+		b = appendMsgpackUint(b, uint64(id))
+	}
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 0x80:
+		return append(b, byte(v))
+	case v <= 0xff:
+		return append(b, 0xcc, byte(v))
+	case v <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+	}
+}
+
+// ParseNodeID decodes a legacy or next format global node ID
+func ParseNodeID(nodeID string) (NodeID, error) {
+	if prefix, encoded, ok := strings.Cut(nodeID, "_"); ok && prefix != "" {
+		return parseNextNodeID(prefix, encoded)
+	}
+	return parseLegacyNodeID(nodeID)
+}
+
+func parseLegacyNodeID(nodeID string) (NodeID, error) {
+	raw, err := base64.StdEncoding.DecodeString(nodeID)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, nodeID)
+	}
+	lenStr, rest, ok := strings.Cut(string(raw), ":")
+	if !ok || !strings.HasPrefix(lenStr, "0") {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, nodeID)
+	}
+	typeLen, err := strconv.Atoi(lenStr[1:])
+	if err != nil || typeLen <= 0 || typeLen >= len(rest) {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, nodeID)
+	}
+	id, err := strconv.ParseInt(rest[typeLen:], 10, 64)
+	if err != nil {
+		return NodeID{}, fmt.Errorf("%w: %s", ErrInvalidNodeID, nodeID)
+	}
+	return NodeID{TypeName: rest[:typeLen], IDs: []int64{id}}, nil
+}
+
+func parseNextNodeID(prefix string, encoded string) (NodeID, error) {
+	invalid := fmt.Errorf("%w: %s_%s", ErrInvalidNodeID, prefix, encoded)
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(b) < 2 || b[0]&0xf0 != 0x90 {
+		return NodeID{}, invalid
+	}
+	count := int(b[0] & 0x0f)
+	b = b[1:]
+	values := make([]int64, 0, count)
+	for i := 0; i < count; i++ {
+		var v uint64
+		var n int
+		switch {
+		case len(b) == 0:
+			return NodeID{}, invalid
+		case b[0] < 0x80:
+			v, n = uint64(b[0]), 1
+		case b[0] == 0xcc && len(b) >= 2:
+			v, n = uint64(b[1]), 2
+		case b[0] == 0xcd && len(b) >= 3:
+			v, n = uint64(binary.BigEndian.Uint16(b[1:])), 3
+		case b[0] == 0xce && len(b) >= 5:
+			v, n = uint64(binary.BigEndian.Uint32(b[1:])), 5
+		case b[0] == 0xcf && len(b) >= 9:
+			v, n = binary.BigEndian.Uint64(b[1:]), 9
+		default:
+			// Strings, such as commit SHAs, are not database IDs
+			return NodeID{}, invalid
+		}
+		values = append(values, int64(v))
+		b = b[n:]
+	}
+	if len(values) < 2 || values[0] != 0 {
+		return NodeID{}, invalid
+	}
+	return NodeID{Prefix: prefix, IDs: values[1:]}, nil
+}
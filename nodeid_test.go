@@ -0,0 +1,41 @@
+package gogithub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyNodeID(t *testing.T) {
+	require.Equal(t, "MDQ6VXNlcjU4MzIzMQ==", LegacyNodeID("User", 583231))
+	id, err := ParseNodeID("MDQ6VXNlcjU4MzIzMQ==")
+	require.NoError(t, err)
+	require.Equal(t, "User", id.TypeName)
+	require.Equal(t, int64(583231), id.DatabaseID())
+
+	id, err = ParseNodeID(LegacyNodeID("Repository", 1296269))
+	require.NoError(t, err)
+	require.Equal(t, NodeID{TypeName: "Repository", IDs: []int64{1296269}}, id)
+}
+
+func TestNextNodeID(t *testing.T) {
+	user, err := NextNodeID("User", 583231)
+	require.NoError(t, err)
+	require.Equal(t, "U_kgDOAAjmPw", user)
+
+	for _, ids := range [][]int64{{5}, {200}, {40000}, {1296269, 8}, {1 << 40}} {
+		encoded, err := NextNodeID("PullRequest", ids...)
+		require.NoError(t, err)
+		decoded, err := ParseNodeID(encoded)
+		require.NoError(t, err)
+		require.Equal(t, NodeID{Prefix: "PR", IDs: ids}, decoded)
+		require.Equal(t, ids[len(ids)-1], decoded.DatabaseID())
+	}
+
+	_, err = NextNodeID("Unknown", 1)
+	require.Error(t, err)
+	_, err = ParseNodeID("PR_!!!")
+	require.ErrorIs(t, err, ErrInvalidNodeID)
+	_, err = ParseNodeID("bm90IGFuIGlk")
+	require.ErrorIs(t, err, ErrInvalidNodeID)
+}
@@ -0,0 +1,162 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NotificationSubject is the issue, pull request, release, etc. a notification thread is about
+type NotificationSubject struct {
+	Title string `json:"title"`
+	// URL is the API URL of the subject
+	URL              string `json:"url"`
+	LatestCommentURL string `json:"latest_comment_url"`
+	// Type is for example Issue, PullRequest, Release or CheckSuite
+	Type string `json:"type"`
+}
+
+// Notification is a notification thread of the authenticated user's inbox
+type Notification struct {
+	ID      string              `json:"id"`
+	Unread  bool                `json:"unread"`
+	Reason  string              `json:"reason"`
+	Subject NotificationSubject `json:"subject"`
+	// Repository is the full name (owner/name) of the repository of the thread
+	Repository string     `json:"-"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastReadAt *time.Time `json:"last_read_at"`
+}
+
+type restNotification struct {
+	Notification
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// NotificationOptions filters ListNotifications
+type NotificationOptions struct {
+	// Owner and Name, if set, only list the notifications of that repository
+	Owner string
+	Name  string
+	// All also lists notifications already marked as read
+	All bool
+	// Participating only lists notifications where the user is directly participating or mentioned
+	Participating bool
+	// Since, if not zero, only lists notifications updated after it
+	Since time.Time
+}
+
+func (o NotificationOptions) path(page int) string {
+	q := url.Values{}
+	if o.All {
+		q.Set("all", "true")
+	}
+	if o.Participating {
+		q.Set("participating", "true")
+	}
+	if !o.Since.IsZero() {
+		q.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+	q.Set("per_page", "100")
+	q.Set("page", fmt.Sprint(page))
+	if o.Owner != "" {
+		return fmt.Sprintf("/repos/%s/%s/notifications?%s", o.Owner, o.Name, q.Encode())
+	}
+	return "/notifications?" + q.Encode()
+}
+
+// Subscription is how the authenticated user is subscribed to a repository or notification thread
+type Subscription string
+
+const (
+	// SubscriptionWatch notifies the user of all activity
+	SubscriptionWatch Subscription = "watch"
+	// SubscriptionIgnore never notifies the user
+	SubscriptionIgnore Subscription = "ignore"
+	// SubscriptionDefault removes the subscription, so the user is only notified when participating or mentioned
+	SubscriptionDefault Subscription = ""
+)
+
+// ListNotifications returns the notification threads of the authenticated user, most recently updated first
+func (g *GithubGraphqlAPI) ListNotifications(ctx context.Context, opts NotificationOptions) ([]Notification, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[Notification], err error) {
+		ctx = withOperation(ctx, "ListNotifications")
+		defer annotateError(&err, OperationError{Operation: "ListNotifications", Owner: opts.Owner, Repo: opts.Name})
+		g.logger(ctx).Debug("ListNotifications", zap.String("owner", opts.Owner), zap.String("name", opts.Name), zap.Bool("all", opts.All), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListNotifications")
+		var notifications []restNotification
+		if err := g.doREST(ctx, http.MethodGet, opts.path(restPageNumber(cursor)), nil, &notifications); err != nil {
+			return Page[Notification]{}, fmt.Errorf("failed to list notifications: %w", err)
+		}
+		items := make([]Notification, 0, len(notifications))
+		for _, n := range notifications {
+			n.Notification.Repository = n.Repository.FullName
+			items = append(items, n.Notification)
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// MarkThreadRead marks a notification thread as read
+func (g *GithubGraphqlAPI) MarkThreadRead(ctx context.Context, threadID string) (err error) {
+	ctx = withOperation(ctx, "MarkThreadRead")
+	defer annotateError(&err, OperationError{Operation: "MarkThreadRead"})
+	g.logger(ctx).Debug("MarkThreadRead", zap.String("thread", threadID))
+	defer g.logger(ctx).Debug("Done MarkThreadRead")
+	if err := g.doREST(ctx, http.MethodPatch, "/notifications/threads/"+url.PathEscape(threadID), nil, nil); err != nil {
+		return fmt.Errorf("failed to mark thread read: %w", err)
+	}
+	return nil
+}
+
+// SetRepositorySubscription watches, ignores or resets the authenticated user's subscription to a repository
+func (g *GithubGraphqlAPI) SetRepositorySubscription(ctx context.Context, owner string, name string, subscription Subscription) (err error) {
+	ctx = withOperation(ctx, "SetRepositorySubscription")
+	defer annotateError(&err, OperationError{Operation: "SetRepositorySubscription", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("SetRepositorySubscription", zap.String("owner", owner), zap.String("name", name), zap.String("subscription", string(subscription)))
+	defer g.logger(ctx).Debug("Done SetRepositorySubscription")
+	path := fmt.Sprintf("/repos/%s/%s/subscription", owner, name)
+	switch subscription {
+	case SubscriptionWatch:
+		err = g.doREST(ctx, http.MethodPut, path, map[string]bool{"subscribed": true}, nil)
+	case SubscriptionIgnore:
+		err = g.doREST(ctx, http.MethodPut, path, map[string]bool{"ignored": true}, nil)
+	case SubscriptionDefault:
+		err = g.doREST(ctx, http.MethodDelete, path, nil, nil)
+	default:
+		return fmt.Errorf("unknown subscription %q", subscription)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set repository subscription: %w", err)
+	}
+	return nil
+}
+
+// SetThreadSubscription watches, ignores or resets the authenticated user's subscription to a notification thread
+func (g *GithubGraphqlAPI) SetThreadSubscription(ctx context.Context, threadID string, subscription Subscription) (err error) {
+	ctx = withOperation(ctx, "SetThreadSubscription")
+	defer annotateError(&err, OperationError{Operation: "SetThreadSubscription"})
+	g.logger(ctx).Debug("SetThreadSubscription", zap.String("thread", threadID), zap.String("subscription", string(subscription)))
+	defer g.logger(ctx).Debug("Done SetThreadSubscription")
+	path := "/notifications/threads/" + url.PathEscape(threadID) + "/subscription"
+	switch subscription {
+	case SubscriptionWatch:
+		err = g.doREST(ctx, http.MethodPut, path, map[string]bool{"ignored": false}, nil)
+	case SubscriptionIgnore:
+		err = g.doREST(ctx, http.MethodPut, path, map[string]bool{"ignored": true}, nil)
+	case SubscriptionDefault:
+		err = g.doREST(ctx, http.MethodDelete, path, nil, nil)
+	default:
+		return fmt.Errorf("unknown subscription %q", subscription)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set thread subscription: %w", err)
+	}
+	return nil
+}
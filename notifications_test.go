@@ -0,0 +1,61 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications(t *testing.T) {
+	var calls []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]bool
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/notifications":
+			require.Equal(t, "true", r.URL.Query().Get("participating"))
+			require.Equal(t, "", r.URL.Query().Get("all"))
+			_, _ = w.Write([]byte(`[{"id":"12","unread":true,"reason":"mention","updated_at":"2024-01-02T03:04:05Z",
+				"subject":{"title":"Fix it","type":"PullRequest"},"repository":{"full_name":"o/r"}}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/notifications/threads/12":
+			w.WriteHeader(http.StatusResetContent)
+		case r.Method == http.MethodPut && r.URL.Path == "/notifications/threads/12/subscription":
+			require.Equal(t, map[string]bool{"ignored": true}, body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/o/r/subscription":
+			require.Equal(t, map[string]bool{"subscribed": true}, body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/o/r/subscription":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	list, err := g.ListNotifications(ctx, NotificationOptions{Owner: "o", Name: "r", Participating: true})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "12", list[0].ID)
+	require.Equal(t, "o/r", list[0].Repository)
+	require.Equal(t, "PullRequest", list[0].Subject.Type)
+	require.True(t, list[0].Unread)
+
+	require.NoError(t, g.MarkThreadRead(ctx, "12"))
+	require.NoError(t, g.SetThreadSubscription(ctx, "12", SubscriptionIgnore))
+	require.NoError(t, g.SetRepositorySubscription(ctx, "o", "r", SubscriptionWatch))
+	require.NoError(t, g.SetRepositorySubscription(ctx, "o", "r", SubscriptionDefault))
+	require.Error(t, g.SetRepositorySubscription(ctx, "o", "r", "mute"))
+	require.Equal(t, []string{
+		"GET /repos/o/r/notifications",
+		"PATCH /notifications/threads/12",
+		"PUT /notifications/threads/12/subscription",
+		"PUT /repos/o/r/subscription",
+		"DELETE /repos/o/r/subscription",
+	}, calls)
+}
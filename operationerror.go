@@ -0,0 +1,59 @@
+package gogithub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperationError is the error every client method returns.  It tells which operation failed and what it was about,
+// so callers can group failures with errors.As instead of parsing messages.  Unwrap returns the underlying error, so
+// errors.Is and errors.As still reach a *RESTError or a sentinel error.
+type OperationError struct {
+	// Operation is the client method, as reported to Metrics
+	Operation string
+	// Owner is the owner of the repository or the organization the call was about.  Empty if none.
+	Owner string
+	// Repo is the name of the repository the call was about.  Empty if none.
+	Repo string
+	// Number is the pull request or issue number.  0 if none.
+	Number int64
+	// WorkflowID is the workflow ID or file name.  Empty if none.
+	WorkflowID string
+	// Target is anything else the call was about, such as "branch feature" or the base and head of a new pull
+	// request.  Empty if none.
+	Target string
+	Err    error
+}
+
+func (e *OperationError) Error() string {
+	target := e.Owner
+	if e.Repo != "" {
+		target += "/" + e.Repo
+	}
+	if e.Number != 0 {
+		target += fmt.Sprintf("#%d", e.Number)
+	}
+	if e.WorkflowID != "" {
+		target += " workflow " + e.WorkflowID
+	}
+	if e.Target != "" {
+		target = strings.TrimPrefix(target+" "+e.Target, " ")
+	}
+	if target == "" {
+		return fmt.Sprintf("%s: %v", e.Operation, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Operation, target, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// annotateError wraps *err, if set, into op.  Client methods defer it with their named error result.
+func annotateError(err *error, op OperationError) {
+	if *err == nil {
+		return
+	}
+	op.Err = *err
+	*err = &op
+}
@@ -0,0 +1,59 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationError(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+	err := g.TriggerWorkflow(context.Background(), "cresta", "gogithub", "ci.yml", "main", nil)
+	var opErr *OperationError
+	require.True(t, errors.As(err, &opErr))
+	require.Equal(t, "TriggerWorkflow", opErr.Operation)
+	require.Equal(t, "cresta", opErr.Owner)
+	require.Equal(t, "gogithub", opErr.Repo)
+	require.Equal(t, "ci.yml", opErr.WorkflowID)
+	var restErr *RESTError
+	require.True(t, errors.As(err, &restErr))
+	require.Equal(t, http.StatusNotFound, restErr.StatusCode)
+	require.Contains(t, err.Error(), "TriggerWorkflow cresta/gogithub workflow ci.yml: failed to trigger workflow")
+}
+
+func TestOperationError_Error(t *testing.T) {
+	err := &OperationError{Operation: "MergePullRequest", Owner: "o", Repo: "r", Number: 5, Err: errors.New("boom")}
+	require.Equal(t, "MergePullRequest o/r#5: boom", err.Error())
+	err = &OperationError{Operation: "Self", Err: errors.New("boom")}
+	require.Equal(t, "Self: boom", err.Error())
+	err = &OperationError{Operation: "FindPRForBranch", Owner: "o", Repo: "r", Target: "branch feature", Err: errors.New("boom")}
+	require.Equal(t, "FindPRForBranch o/r branch feature: boom", err.Error())
+	err = &OperationError{Operation: "CreatePullRequest", Target: "repository R_1 base main head feature", Err: errors.New("boom")}
+	require.Equal(t, "CreatePullRequest repository R_1 base main head feature: boom", err.Error())
+
+	var nilErr error
+	annotateError(&nilErr, OperationError{Operation: "Self"})
+	require.NoError(t, nilErr)
+}
+
+func TestOperationError_Target(t *testing.T) {
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"boom"}]}`))
+	})
+	ctx := context.Background()
+	_, err := g.FindPRForBranch(ctx, "o", "r", "feature")
+	var opErr *OperationError
+	require.True(t, errors.As(err, &opErr))
+	require.Equal(t, "branch feature", opErr.Target)
+
+	_, err = g.CreatePullRequest(ctx, "R_1", "main", "feature", "title", "body")
+	require.True(t, errors.As(err, &opErr))
+	require.Equal(t, "CreatePullRequest", opErr.Operation)
+	require.Equal(t, "repository R_1 base main head feature", opErr.Target)
+}
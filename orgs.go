@@ -0,0 +1,306 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// OrgRepositoryFilter narrows ListOrgRepositories.  The zero value lists every repository that is not archived.
+type OrgRepositoryFilter struct {
+	IncludeArchived bool
+	// ExcludeForks leaves out forked repositories
+	ExcludeForks bool
+	// Privacy is PUBLIC or PRIVATE.  Empty lists both.
+	Privacy githubv4.RepositoryPrivacy
+}
+
+type OrgRepository struct {
+	ID            githubv4.ID
+	DatabaseID    int64
+	Name          string
+	NameWithOwner string
+	URL           string
+	IsPrivate     bool
+	IsArchived    bool
+	IsFork        bool
+	DefaultBranch string
+	PushedAt      time.Time
+}
+
+// RepoRef returns the owner and name of the repository
+func (r OrgRepository) RepoRef() RepoRef {
+	owner, _, _ := strings.Cut(r.NameWithOwner, "/")
+	return RepoRef{Owner: owner, Name: r.Name}
+}
+
+type orgRepositoryNode struct {
+	ID               githubv4.ID
+	DatabaseID       int64 `graphql:"databaseId"`
+	Name             string
+	NameWithOwner    string
+	URL              string `graphql:"url"`
+	IsPrivate        bool
+	IsArchived       bool
+	IsFork           bool
+	PushedAt         githubv4.DateTime
+	DefaultBranchRef struct {
+		Name string
+	}
+}
+
+func (n *orgRepositoryNode) toOrgRepository() OrgRepository {
+	return OrgRepository{
+		ID:            n.ID,
+		DatabaseID:    n.DatabaseID,
+		Name:          n.Name,
+		NameWithOwner: n.NameWithOwner,
+		URL:           n.URL,
+		IsPrivate:     n.IsPrivate,
+		IsArchived:    n.IsArchived,
+		IsFork:        n.IsFork,
+		DefaultBranch: n.DefaultBranchRef.Name,
+		PushedAt:      n.PushedAt.Time,
+	}
+}
+
+type OrgMember struct {
+	ID         githubv4.ID
+	DatabaseID int64
+	Login      string
+	Name       string
+	// Role is ADMIN or MEMBER
+	Role string
+}
+
+type Team struct {
+	ID          githubv4.ID
+	DatabaseID  int64
+	Slug        string
+	Name        string
+	Description string
+	// Privacy is VISIBLE or SECRET
+	Privacy string
+	// ParentSlug is empty for top level teams
+	ParentSlug string
+}
+
+type teamNode struct {
+	ID          githubv4.ID
+	DatabaseID  int64 `graphql:"databaseId"`
+	Slug        string
+	Name        string
+	Description string
+	Privacy     string
+	ParentTeam  struct {
+		Slug string
+	}
+}
+
+func (n *teamNode) toTeam() Team {
+	return Team{
+		ID:          n.ID,
+		DatabaseID:  n.DatabaseID,
+		Slug:        n.Slug,
+		Name:        n.Name,
+		Description: n.Description,
+		Privacy:     n.Privacy,
+		ParentSlug:  n.ParentTeam.Slug,
+	}
+}
+
+// TeamRepository is a repository a team can access, with the team's permission on it
+type TeamRepository struct {
+	OrgRepository
+	// Permission is READ, TRIAGE, WRITE, MAINTAIN or ADMIN
+	Permission string
+}
+
+// OrgRepositories pages through the repositories of org matching filter, ordered by name
+func (g *GithubGraphqlAPI) OrgRepositories(org string, filter OrgRepositoryFilter, opts ...PaginatorOption) *Paginator[OrgRepository] {
+	return NewPaginator(func(ctx context.Context, cursor string, pageSize int) (_ Page[OrgRepository], err error) {
+		ctx = withOperation(ctx, "OrgRepositories")
+		defer annotateError(&err, OperationError{Operation: "OrgRepositories", Owner: org})
+		g.logger(ctx).Debug("OrgRepositories", zap.String("org", org), zap.Any("filter", filter), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done OrgRepositories")
+		var query struct {
+			Organization struct {
+				Repositories struct {
+					Nodes    []orgRepositoryNode
+					PageInfo GraphQLPageInfo
+				} `graphql:"repositories(first: $first, after: $cursor, isFork: $isFork, isArchived: $isArchived, privacy: $privacy, orderBy: {field: NAME, direction: ASC})"`
+			} `graphql:"organization(login: $org)"`
+		}
+		variables := map[string]interface{}{
+			"org":        githubv4.String(org),
+			"first":      githubv4.Int(pageSize),
+			"cursor":     graphqlCursor(cursor),
+			"isFork":     (*githubv4.Boolean)(nil),
+			"isArchived": (*githubv4.Boolean)(nil),
+			"privacy":    (*githubv4.RepositoryPrivacy)(nil),
+		}
+		if filter.ExcludeForks {
+			variables["isFork"] = githubv4.NewBoolean(false)
+		}
+		if !filter.IncludeArchived {
+			variables["isArchived"] = githubv4.NewBoolean(false)
+		}
+		if filter.Privacy != "" {
+			privacy := filter.Privacy
+			variables["privacy"] = &privacy
+		}
+		if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+			return Page[OrgRepository]{}, fmt.Errorf("failed to query org repositories: %w", err)
+		}
+		items := make([]OrgRepository, 0, len(query.Organization.Repositories.Nodes))
+		for i := range query.Organization.Repositories.Nodes {
+			items = append(items, query.Organization.Repositories.Nodes[i].toOrgRepository())
+		}
+		return graphqlPage(items, query.Organization.Repositories.PageInfo), nil
+	}, opts...)
+}
+
+func (g *GithubGraphqlAPI) ListOrgRepositories(ctx context.Context, org string, filter OrgRepositoryFilter) ([]OrgRepository, error) {
+	return g.OrgRepositories(org, filter).All(ctx)
+}
+
+func (g *GithubGraphqlAPI) ListOrgMembers(ctx context.Context, org string) ([]OrgMember, error) {
+	return NewPaginator(func(ctx context.Context, cursor string, pageSize int) (_ Page[OrgMember], err error) {
+		ctx = withOperation(ctx, "ListOrgMembers")
+		defer annotateError(&err, OperationError{Operation: "ListOrgMembers", Owner: org})
+		g.logger(ctx).Debug("ListOrgMembers", zap.String("org", org), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListOrgMembers")
+		var query struct {
+			Organization struct {
+				MembersWithRole struct {
+					Edges []struct {
+						Role string
+						Node struct {
+							ID         githubv4.ID
+							DatabaseID int64 `graphql:"databaseId"`
+							Login      string
+							Name       string
+						}
+					}
+					PageInfo GraphQLPageInfo
+				} `graphql:"membersWithRole(first: $first, after: $cursor)"`
+			} `graphql:"organization(login: $org)"`
+		}
+		if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+			"org":    githubv4.String(org),
+			"first":  githubv4.Int(pageSize),
+			"cursor": graphqlCursor(cursor),
+		}); err != nil {
+			return Page[OrgMember]{}, fmt.Errorf("failed to query org members: %w", err)
+		}
+		items := make([]OrgMember, 0, len(query.Organization.MembersWithRole.Edges))
+		for _, e := range query.Organization.MembersWithRole.Edges {
+			items = append(items, OrgMember{
+				ID:         e.Node.ID,
+				DatabaseID: e.Node.DatabaseID,
+				Login:      e.Node.Login,
+				Name:       e.Node.Name,
+				Role:       e.Role,
+			})
+		}
+		return graphqlPage(items, query.Organization.MembersWithRole.PageInfo), nil
+	}).All(ctx)
+}
+
+func (g *GithubGraphqlAPI) ListTeams(ctx context.Context, org string) ([]Team, error) {
+	return NewPaginator(func(ctx context.Context, cursor string, pageSize int) (_ Page[Team], err error) {
+		ctx = withOperation(ctx, "ListTeams")
+		defer annotateError(&err, OperationError{Operation: "ListTeams", Owner: org})
+		g.logger(ctx).Debug("ListTeams", zap.String("org", org), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListTeams")
+		var query struct {
+			Organization struct {
+				Teams struct {
+					Nodes    []teamNode
+					PageInfo GraphQLPageInfo
+				} `graphql:"teams(first: $first, after: $cursor)"`
+			} `graphql:"organization(login: $org)"`
+		}
+		if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+			"org":    githubv4.String(org),
+			"first":  githubv4.Int(pageSize),
+			"cursor": graphqlCursor(cursor),
+		}); err != nil {
+			return Page[Team]{}, fmt.Errorf("failed to query teams: %w", err)
+		}
+		items := make([]Team, 0, len(query.Organization.Teams.Nodes))
+		for i := range query.Organization.Teams.Nodes {
+			items = append(items, query.Organization.Teams.Nodes[i].toTeam())
+		}
+		return graphqlPage(items, query.Organization.Teams.PageInfo), nil
+	}).All(ctx)
+}
+
+func (g *GithubGraphqlAPI) GetTeamBySlug(ctx context.Context, org string, slug string) (_ *Team, err error) {
+	ctx = withOperation(ctx, "GetTeamBySlug")
+	defer annotateError(&err, OperationError{Operation: "GetTeamBySlug", Owner: org})
+	g.logger(ctx).Debug("GetTeamBySlug", zap.String("org", org), zap.String("slug", slug))
+	defer g.logger(ctx).Debug("Done GetTeamBySlug")
+	var query struct {
+		Organization struct {
+			Team *teamNode `graphql:"team(slug: $slug)"`
+		} `graphql:"organization(login: $org)"`
+	}
+	if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+		"org":  githubv4.String(org),
+		"slug": githubv4.String(slug),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query team: %w", err)
+	}
+	if query.Organization.Team == nil {
+		return nil, fmt.Errorf("failed to find team %s", slug)
+	}
+	ret := query.Organization.Team.toTeam()
+	return &ret, nil
+}
+
+func (g *GithubGraphqlAPI) ListTeamRepositories(ctx context.Context, org string, slug string) ([]TeamRepository, error) {
+	return NewPaginator(func(ctx context.Context, cursor string, pageSize int) (_ Page[TeamRepository], err error) {
+		ctx = withOperation(ctx, "ListTeamRepositories")
+		defer annotateError(&err, OperationError{Operation: "ListTeamRepositories", Owner: org})
+		g.logger(ctx).Debug("ListTeamRepositories", zap.String("org", org), zap.String("slug", slug), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListTeamRepositories")
+		var query struct {
+			Organization struct {
+				Team *struct {
+					Repositories struct {
+						Edges []struct {
+							Permission string
+							Node       orgRepositoryNode
+						}
+						PageInfo GraphQLPageInfo
+					} `graphql:"repositories(first: $first, after: $cursor)"`
+				} `graphql:"team(slug: $slug)"`
+			} `graphql:"organization(login: $org)"`
+		}
+		if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+			"org":    githubv4.String(org),
+			"slug":   githubv4.String(slug),
+			"first":  githubv4.Int(pageSize),
+			"cursor": graphqlCursor(cursor),
+		}); err != nil {
+			return Page[TeamRepository]{}, fmt.Errorf("failed to query team repositories: %w", err)
+		}
+		if query.Organization.Team == nil {
+			return Page[TeamRepository]{}, fmt.Errorf("failed to find team %s", slug)
+		}
+		items := make([]TeamRepository, 0, len(query.Organization.Team.Repositories.Edges))
+		for i := range query.Organization.Team.Repositories.Edges {
+			e := &query.Organization.Team.Repositories.Edges[i]
+			items = append(items, TeamRepository{
+				OrgRepository: e.Node.toOrgRepository(),
+				Permission:    e.Permission,
+			})
+		}
+		return graphqlPage(items, query.Organization.Team.Repositories.PageInfo), nil
+	}).All(ctx)
+}
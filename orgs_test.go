@@ -0,0 +1,132 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newPagedGraphQLClient serves the page of pages keyed by the $cursor variable of each request, "" for the first
+// page.  Every request must contain query.
+func newPagedGraphQLClient(t *testing.T, query string, pages map[string]string) (*GithubGraphqlAPI, *[]graphqlTestRequest) {
+	var requests []graphqlTestRequest
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.Contains(t, req.Query, query)
+		requests = append(requests, req)
+		cursor, _ := req.Variables["cursor"].(string)
+		page, ok := pages[cursor]
+		if !ok {
+			t.Fatalf("unexpected cursor %q", cursor)
+		}
+		_, _ = w.Write([]byte(page))
+	})
+	return g, &requests
+}
+
+func TestListOrgRepositories(t *testing.T) {
+	g, requests := newPagedGraphQLClient(t, "repositories(first: $first, after: $cursor", map[string]string{
+		"": `{"data":{"organization":{"repositories":{
+			"nodes":[{"id":"R_1","databaseId":1,"name":"api","nameWithOwner":"acme/api","isPrivate":true,"defaultBranchRef":{"name":"main"},"pushedAt":"2024-05-01T10:00:00Z"}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}`,
+		"C1": `{"data":{"organization":{"repositories":{
+			"nodes":[{"id":"R_2","databaseId":2,"name":"web","nameWithOwner":"acme/web","defaultBranchRef":{"name":"trunk"}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"C2"}}}}}`,
+	})
+	repos, err := g.ListOrgRepositories(context.Background(), "acme", OrgRepositoryFilter{ExcludeForks: true})
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Equal(t, "acme/api", repos[0].NameWithOwner)
+	require.Equal(t, "main", repos[0].DefaultBranch)
+	require.True(t, repos[0].IsPrivate)
+	require.Equal(t, 2024, repos[0].PushedAt.Year())
+	require.Equal(t, RepoRef{Owner: "acme", Name: "web"}, repos[1].RepoRef())
+	require.Equal(t, "trunk", repos[1].DefaultBranch)
+
+	require.Len(t, *requests, 2)
+	first := (*requests)[0].Variables
+	require.Equal(t, "acme", first["org"])
+	require.Equal(t, false, first["isFork"])
+	require.Equal(t, false, first["isArchived"])
+	require.Nil(t, first["privacy"])
+	require.Nil(t, first["cursor"])
+	require.Equal(t, "C1", (*requests)[1].Variables["cursor"])
+}
+
+func TestListOrgMembers(t *testing.T) {
+	g, _ := newPagedGraphQLClient(t, "membersWithRole(first: $first, after: $cursor)", map[string]string{
+		"": `{"data":{"organization":{"membersWithRole":{
+			"edges":[{"role":"ADMIN","node":{"id":"U_1","databaseId":1,"login":"alice","name":"Alice"}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}`,
+		"C1": `{"data":{"organization":{"membersWithRole":{
+			"edges":[{"role":"MEMBER","node":{"id":"U_2","databaseId":2,"login":"bob","name":""}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"C2"}}}}}`,
+	})
+	members, err := g.ListOrgMembers(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, []OrgMember{
+		{ID: "U_1", DatabaseID: 1, Login: "alice", Name: "Alice", Role: "ADMIN"},
+		{ID: "U_2", DatabaseID: 2, Login: "bob", Role: "MEMBER"},
+	}, members)
+}
+
+func TestListTeams(t *testing.T) {
+	g, _ := newPagedGraphQLClient(t, "teams(first: $first, after: $cursor)", map[string]string{
+		"": `{"data":{"organization":{"teams":{
+			"nodes":[{"id":"T_1","databaseId":1,"slug":"platform","name":"Platform","privacy":"VISIBLE","parentTeam":null}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}`,
+		"C1": `{"data":{"organization":{"teams":{
+			"nodes":[{"id":"T_2","databaseId":2,"slug":"sre","name":"SRE","privacy":"SECRET","parentTeam":{"slug":"platform"}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"C2"}}}}}`,
+	})
+	teams, err := g.ListTeams(context.Background(), "acme")
+	require.NoError(t, err)
+	require.Equal(t, []Team{
+		{ID: "T_1", DatabaseID: 1, Slug: "platform", Name: "Platform", Privacy: "VISIBLE"},
+		{ID: "T_2", DatabaseID: 2, Slug: "sre", Name: "SRE", Privacy: "SECRET", ParentSlug: "platform"},
+	}, teams)
+}
+
+func TestListTeamRepositories(t *testing.T) {
+	g, requests := newPagedGraphQLClient(t, "team(slug: $slug)", map[string]string{
+		"": `{"data":{"organization":{"team":{"repositories":{
+			"edges":[{"permission":"ADMIN","node":{"id":"R_1","name":"api","nameWithOwner":"acme/api"}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}}`,
+		"C1": `{"data":{"organization":{"team":{"repositories":{
+			"edges":[{"permission":"READ","node":{"id":"R_2","name":"web","nameWithOwner":"acme/web"}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"C2"}}}}}}`,
+	})
+	repos, err := g.ListTeamRepositories(context.Background(), "acme", "platform")
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	require.Equal(t, "acme/api", repos[0].NameWithOwner)
+	require.Equal(t, "ADMIN", repos[0].Permission)
+	require.Equal(t, "acme/web", repos[1].NameWithOwner)
+	require.Equal(t, "READ", repos[1].Permission)
+	require.Equal(t, "platform", (*requests)[1].Variables["slug"])
+}
+
+func TestOrgListErrors(t *testing.T) {
+	g, _ := newPagedGraphQLClient(t, "team(slug: $slug)", map[string]string{
+		"": `{"data":{"organization":{"team":null}}}`,
+	})
+	_, err := g.ListTeamRepositories(context.Background(), "acme", "missing")
+	require.ErrorContains(t, err, "failed to find team missing")
+	var opErr *OperationError
+	require.ErrorAs(t, err, &opErr)
+	require.Equal(t, "ListTeamRepositories", opErr.Operation)
+	_, err = g.GetTeamBySlug(context.Background(), "acme", "missing")
+	require.ErrorContains(t, err, "failed to find team missing")
+
+	g, _ = newPagedGraphQLClient(t, "membersWithRole", map[string]string{
+		"": `{"data":{"organization":{"membersWithRole":{
+			"edges":[{"role":"MEMBER","node":{"login":"alice"}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"C1"}}}}}`,
+		"C1": `{"errors":[{"message":"Something went wrong"}]}`,
+	})
+	_, err = g.ListOrgMembers(context.Background(), "acme")
+	require.ErrorContains(t, err, "failed to query org members")
+	require.ErrorContains(t, err, "Something went wrong")
+}
@@ -0,0 +1,247 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// DefaultPageSize is how many items a Paginator asks for per page
+	DefaultPageSize = 100
+	// minPageSize is the smallest page a Paginator shrinks to when GitHub times out on larger ones
+	minPageSize = 10
+)
+
+// Page is one page of a list.  Cursor is passed back to fetch the next page.
+type Page[T any] struct {
+	Items   []T
+	HasNext bool
+	Cursor  string
+}
+
+// PageFetcher fetches up to pageSize items after cursor.  cursor is empty for the first page.  Fetchers of REST lists,
+// whose page numbers depend on a fixed page size, ignore pageSize and are never retried with smaller pages.
+type PageFetcher[T any] func(ctx context.Context, cursor string, pageSize int) (Page[T], error)
+
+// ErrPartialResults matches, with errors.Is, the error of a listing whose context ended after some items were listed
+var ErrPartialResults = errors.New("partial results")
+
+// PartialResultsError is returned by All, with the items listed so far, when the context ends midway.  Pass Cursor to
+// WithStartCursor to resume the listing where it stopped.
+type PartialResultsError struct {
+	// Cursor is the cursor of the first page not listed
+	Cursor string
+	// Items is how many items were listed before the context ended
+	Items int
+	Err   error
+}
+
+func (e *PartialResultsError) Error() string {
+	return fmt.Sprintf("partial results after %d items: %v", e.Items, e.Err)
+}
+
+func (e *PartialResultsError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PartialResultsError) Is(target error) bool {
+	return target == ErrPartialResults
+}
+
+type paginatorConfig struct {
+	pageSize    int
+	maxItems    int
+	startCursor string
+	// fixedPageSize stops the page size from shrinking on timeouts, for fetchers that ignore it
+	fixedPageSize bool
+}
+
+// PaginatorOption configures a Paginator
+type PaginatorOption func(*paginatorConfig)
+
+// WithPageSize sets how many items are requested per page.  GitHub allows at most 100.
+func WithPageSize(n int) PaginatorOption {
+	return func(c *paginatorConfig) {
+		c.pageSize = n
+	}
+}
+
+// WithMaxItems stops paging once n items were returned
+func WithMaxItems(n int) PaginatorOption {
+	return func(c *paginatorConfig) {
+		c.maxItems = n
+	}
+}
+
+// WithStartCursor starts paging at cursor, such as the Cursor of a PartialResultsError, instead of the first page
+func WithStartCursor(cursor string) PaginatorOption {
+	return func(c *paginatorConfig) {
+		c.startCursor = cursor
+	}
+}
+
+// Paginator walks a paged list.  It handles cursors, trims the last page to the item limit, and halves the page size
+// when GitHub times out computing a GraphQL page.
+type Paginator[T any] struct {
+	fetch    PageFetcher[T]
+	cfg      paginatorConfig
+	cursor   string
+	fetched  int
+	finished bool
+}
+
+// NewPaginator returns a Paginator that pages through fetch, starting at the first page unless WithStartCursor is given
+func NewPaginator[T any](fetch PageFetcher[T], opts ...PaginatorOption) *Paginator[T] {
+	cfg := paginatorConfig{
+		pageSize: DefaultPageSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.pageSize <= 0 || cfg.pageSize > DefaultPageSize {
+		cfg.pageSize = DefaultPageSize
+	}
+	return &Paginator[T]{
+		fetch:  fetch,
+		cfg:    cfg,
+		cursor: cfg.startCursor,
+	}
+}
+
+// newRESTPaginator returns a Paginator for a REST list fetcher.  REST page numbers depend on the page size, so a
+// timed out page fails instead of being retried smaller.
+func newRESTPaginator[T any](fetch PageFetcher[T], opts ...PaginatorOption) *Paginator[T] {
+	p := NewPaginator(fetch, opts...)
+	p.cfg.fixedPageSize = true
+	return p
+}
+
+// Cursor returns the cursor of the next page
+func (p *Paginator[T]) Cursor() string {
+	return p.cursor
+}
+
+// HasNext reports whether Next may return more items
+func (p *Paginator[T]) HasNext() bool {
+	return !p.finished
+}
+
+// Next returns the next page of items.  It returns nil without error once the list is exhausted.
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.finished {
+		return nil, nil
+	}
+	pageSize := p.cfg.pageSize
+	if p.cfg.maxItems > 0 && p.cfg.maxItems-p.fetched < pageSize {
+		pageSize = p.cfg.maxItems - p.fetched
+	}
+	for {
+		page, err := p.fetch(ctx, p.cursor, pageSize)
+		if err != nil {
+			if isPageTimeout(err) && !p.cfg.fixedPageSize && pageSize > minPageSize {
+				pageSize /= 2
+				if pageSize < minPageSize {
+					pageSize = minPageSize
+				}
+				// Later pages would time out as well
+				p.cfg.pageSize = pageSize
+				continue
+			}
+			return nil, err
+		}
+		if p.cfg.maxItems > 0 && len(page.Items) > p.cfg.maxItems-p.fetched {
+			page.Items = page.Items[:p.cfg.maxItems-p.fetched]
+		}
+		p.cursor = page.Cursor
+		p.fetched += len(page.Items)
+		if !page.HasNext || len(page.Items) == 0 || (p.cfg.maxItems > 0 && p.fetched >= p.cfg.maxItems) {
+			p.finished = true
+		}
+		return page.Items, nil
+	}
+}
+
+// All returns every remaining item.  When ctx ends after some items were listed, they are returned with a
+// *PartialResultsError.
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var ret []T
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			if len(ret) > 0 && isContextError(err) {
+				return ret, &PartialResultsError{Cursor: p.cursor, Items: len(ret), Err: err}
+			}
+			return ret, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+// isPageTimeout reports whether err looks like GitHub gave up computing a page, which smaller pages usually fix
+func isPageTimeout(err error) bool {
+	if isContextError(err) {
+		return false
+	}
+	var restErr *RESTError
+	if errors.As(err, &restErr) {
+		return restErr.StatusCode == http.StatusBadGateway || restErr.StatusCode == http.StatusGatewayTimeout
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "timedout") || strings.Contains(msg, "couldn't respond to your request in time") || strings.Contains(msg, "502 bad gateway")
+}
+
+// GraphQLPageInfo is the pageInfo selection paged GraphQL queries need
+type GraphQLPageInfo struct {
+	HasNextPage bool
+	EndCursor   githubv4.String
+}
+
+// graphqlCursor converts a Paginator cursor into the $cursor variable of a GraphQL query
+func graphqlCursor(cursor string) *githubv4.String {
+	if cursor == "" {
+		return nil
+	}
+	return githubv4.NewString(githubv4.String(cursor))
+}
+
+// graphqlPage builds a Page of a GraphQL connection
+func graphqlPage[T any](items []T, info GraphQLPageInfo) Page[T] {
+	return Page[T]{
+		Items:   items,
+		HasNext: info.HasNextPage,
+		Cursor:  string(info.EndCursor),
+	}
+}
+
+// restPageNumber returns the page number a REST cursor stands for
+func restPageNumber(cursor string) int {
+	if page, err := strconv.Atoi(cursor); err == nil && page > 0 {
+		return page
+	}
+	return 1
+}
+
+// restPage builds a Page of a REST list fetched with the page number of cursor and perPage items per page
+func restPage[T any](items []T, cursor string, perPage int) Page[T] {
+	return Page[T]{
+		Items:   items,
+		HasNext: len(items) >= perPage,
+		Cursor:  strconv.Itoa(restPageNumber(cursor) + 1),
+	}
+}
+
+// linkPage builds a Page of a REST list paged through Link headers.  The cursor is the URL of the next page.
+func linkPage[T any](items []T, next string) Page[T] {
+	return Page[T]{
+		Items:   items,
+		HasNext: next != "",
+		Cursor:  next,
+	}
+}
@@ -0,0 +1,152 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingFetcher serves total items as a cursor paged list, recording the requested page sizes
+func countingFetcher(total int, pageSizes *[]int) PageFetcher[int] {
+	return func(_ context.Context, cursor string, pageSize int) (Page[int], error) {
+		*pageSizes = append(*pageSizes, pageSize)
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		var items []int
+		for i := start; i < total && len(items) < pageSize; i++ {
+			items = append(items, i)
+		}
+		end := start + len(items)
+		return Page[int]{Items: items, HasNext: end < total, Cursor: strconv.Itoa(end)}, nil
+	}
+}
+
+func TestPaginator_All(t *testing.T) {
+	var sizes []int
+	items, err := NewPaginator(countingFetcher(25, &sizes), WithPageSize(10)).All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 25)
+	require.Equal(t, 24, items[24])
+	require.Equal(t, []int{10, 10, 10}, sizes)
+}
+
+func TestPaginator_Next(t *testing.T) {
+	var sizes []int
+	p := NewPaginator(countingFetcher(15, &sizes), WithPageSize(10))
+	items, err := p.Next(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 10)
+	require.True(t, p.HasNext())
+	items, err = p.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{10, 11, 12, 13, 14}, items)
+	require.False(t, p.HasNext())
+	items, err = p.Next(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, items)
+}
+
+func TestPaginator_MaxItems(t *testing.T) {
+	var sizes []int
+	items, err := NewPaginator(countingFetcher(500, &sizes), WithMaxItems(130)).All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 130)
+	require.Equal(t, []int{100, 30}, sizes)
+}
+
+func TestPaginator_ShrinksOnTimeout(t *testing.T) {
+	var sizes []int
+	inner := countingFetcher(60, &sizes)
+	fetch := func(ctx context.Context, cursor string, pageSize int) (Page[int], error) {
+		if pageSize > 25 {
+			sizes = append(sizes, pageSize)
+			return Page[int]{}, &RESTError{StatusCode: http.StatusBadGateway}
+		}
+		return inner(ctx, cursor, pageSize)
+	}
+	items, err := NewPaginator(fetch).All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 60)
+	require.Equal(t, []int{100, 50, 25, 25, 25}, sizes)
+}
+
+func TestPaginator_RESTDoesNotShrink(t *testing.T) {
+	var sizes []int
+	fetch := func(_ context.Context, _ string, pageSize int) (Page[int], error) {
+		sizes = append(sizes, pageSize)
+		return Page[int]{}, &RESTError{StatusCode: http.StatusBadGateway}
+	}
+	_, err := newRESTPaginator(fetch).All(context.Background())
+	var restErr *RESTError
+	require.True(t, errors.As(err, &restErr))
+	require.Equal(t, http.StatusBadGateway, restErr.StatusCode)
+	require.Equal(t, []int{100}, sizes)
+}
+
+func TestPaginator_Error(t *testing.T) {
+	fetch := func(_ context.Context, _ string, _ int) (Page[int], error) {
+		return Page[int]{}, &RESTError{StatusCode: http.StatusNotFound}
+	}
+	_, err := NewPaginator(fetch).All(context.Background())
+	var restErr *RESTError
+	require.True(t, errors.As(err, &restErr))
+	require.Equal(t, http.StatusNotFound, restErr.StatusCode)
+}
+
+func TestPaginator_PartialResults(t *testing.T) {
+	var sizes []int
+	inner := countingFetcher(25, &sizes)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fetch := func(ctx context.Context, cursor string, pageSize int) (Page[int], error) {
+		if cursor == "20" {
+			cancel()
+			return Page[int]{}, ctx.Err()
+		}
+		return inner(ctx, cursor, pageSize)
+	}
+	items, err := NewPaginator(fetch, WithPageSize(10)).All(ctx)
+	require.Len(t, items, 20)
+	require.ErrorIs(t, err, ErrPartialResults)
+	require.ErrorIs(t, err, context.Canceled)
+	var partial *PartialResultsError
+	require.True(t, errors.As(err, &partial))
+	require.Equal(t, "20", partial.Cursor)
+	require.Equal(t, 20, partial.Items)
+
+	items, err = NewPaginator(inner, WithPageSize(10), WithStartCursor(partial.Cursor)).All(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{20, 21, 22, 23, 24}, items)
+}
+
+func TestListSecrets_Pages(t *testing.T) {
+	var pages []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			_, _ = w.Write([]byte(`{"secrets":[` + repeatJSON(`{"name":"S"}`, 100) + `]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"secrets":[{"name":"LAST"}]}`))
+	})
+	secrets, err := g.ListSecrets(context.Background(), RepoScope("o", "r"))
+	require.NoError(t, err)
+	require.Len(t, secrets, 101)
+	require.Equal(t, "LAST", secrets[100].Name)
+	require.Equal(t, []string{"1", "2"}, pages)
+}
+
+func repeatJSON(item string, n int) string {
+	ret := item
+	for i := 1; i < n; i++ {
+		ret += "," + item
+	}
+	return ret
+}
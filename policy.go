@@ -0,0 +1,197 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// DefaultConventionalCommitTypes are the commit types accepted by PolicyConfig.ConventionalCommits when
+// ConventionalCommitTypes is empty
+var DefaultConventionalCommitTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// PolicyConfig configures what PolicyChecker enforces.  Empty patterns are not checked.
+type PolicyConfig struct {
+	// BranchPattern is a regular expression head branch names must match
+	BranchPattern string `yaml:"branchPattern" json:"branchPattern"`
+	// CommitPattern is a regular expression the first line of every commit message must match
+	CommitPattern string `yaml:"commitPattern" json:"commitPattern"`
+	// ConventionalCommits requires the first line of every commit message to be a conventional commit header
+	ConventionalCommits     bool     `yaml:"conventionalCommits" json:"conventionalCommits"`
+	ConventionalCommitTypes []string `yaml:"conventionalCommitTypes" json:"conventionalCommitTypes"`
+	// IgnoreMergeCommits skips commits whose message starts with "Merge ", like the ones "Update branch" creates
+	IgnoreMergeCommits bool `yaml:"ignoreMergeCommits" json:"ignoreMergeCommits"`
+	// CheckName is the check run name used by PolicyReportCheckRun.  Defaults to "policy".
+	CheckName string `yaml:"checkName" json:"checkName"`
+}
+
+// PolicyViolation is one branch name or commit message breaking the policy
+type PolicyViolation struct {
+	// Subject is the branch name or the commit OID
+	Subject string
+	Message string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Subject, v.Message)
+}
+
+// PolicyReport selects how Enforce reports violations on the pull request
+type PolicyReport int
+
+const (
+	// PolicyReportCheckRun completes a check run on the head commit.  It requires GitHub App credentials.
+	PolicyReportCheckRun PolicyReport = iota
+	// PolicyReportReview requests changes with a review listing the violations
+	PolicyReportReview
+)
+
+// PolicyChecker validates pull request branch names and commit messages
+type PolicyChecker struct {
+	cfg          PolicyConfig
+	branch       *regexp.Regexp
+	commit       *regexp.Regexp
+	conventional *regexp.Regexp
+}
+
+// NewPolicyChecker compiles cfg.  It fails if a pattern is not a valid regular expression.
+func NewPolicyChecker(cfg PolicyConfig) (*PolicyChecker, error) {
+	ret := &PolicyChecker{cfg: cfg}
+	var err error
+	if cfg.BranchPattern != "" {
+		if ret.branch, err = regexp.Compile(cfg.BranchPattern); err != nil {
+			return nil, fmt.Errorf("invalid branch pattern: %w", err)
+		}
+	}
+	if cfg.CommitPattern != "" {
+		if ret.commit, err = regexp.Compile(cfg.CommitPattern); err != nil {
+			return nil, fmt.Errorf("invalid commit pattern: %w", err)
+		}
+	}
+	if cfg.ConventionalCommits {
+		types := cfg.ConventionalCommitTypes
+		if len(types) == 0 {
+			types = DefaultConventionalCommitTypes
+		}
+		quoted := make([]string, 0, len(types))
+		for _, t := range types {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+		ret.conventional = regexp.MustCompile(`^(` + strings.Join(quoted, "|") + `)(\([\w\-./ ]+\))?!?: \S`)
+	}
+	return ret, nil
+}
+
+// CheckBranch validates a head branch name
+func (p *PolicyChecker) CheckBranch(branch string) []PolicyViolation {
+	if p.branch != nil && !p.branch.MatchString(branch) {
+		return []PolicyViolation{{Subject: branch, Message: fmt.Sprintf("branch name does not match %s", p.cfg.BranchPattern)}}
+	}
+	return nil
+}
+
+// CheckCommitMessage validates the first line of a commit message
+func (p *PolicyChecker) CheckCommitMessage(oid string, message string) []PolicyViolation {
+	header := strings.SplitN(message, "\n", 2)[0]
+	if p.cfg.IgnoreMergeCommits && strings.HasPrefix(header, "Merge ") {
+		return nil
+	}
+	var ret []PolicyViolation
+	if p.commit != nil && !p.commit.MatchString(header) {
+		ret = append(ret, PolicyViolation{Subject: oid, Message: fmt.Sprintf("commit message %q does not match %s", header, p.cfg.CommitPattern)})
+	}
+	if p.conventional != nil && !p.conventional.MatchString(header) {
+		ret = append(ret, PolicyViolation{Subject: oid, Message: fmt.Sprintf("commit message %q is not a conventional commit", header)})
+	}
+	return ret
+}
+
+// CheckPullRequest validates the head branch and every commit of a pull request
+func (p *PolicyChecker) CheckPullRequest(ctx context.Context, gh GitHub, owner string, name string, number int64) ([]PolicyViolation, error) {
+	violations, _, err := p.checkPullRequest(ctx, gh, owner, name, number)
+	return violations, err
+}
+
+func (p *PolicyChecker) checkPullRequest(ctx context.Context, gh GitHub, owner string, name string, number int64) ([]PolicyViolation, string, error) {
+	pr, err := gh.FindPullRequest(ctx, owner, name, number)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find PR: %w", err)
+	}
+	commits, err := gh.ListPullRequestCommits(ctx, owner, name, number)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list PR commits: %w", err)
+	}
+	ret := p.CheckBranch(pr.HeadRefName)
+	headOid := ""
+	for _, c := range commits {
+		ret = append(ret, p.CheckCommitMessage(c.Oid, c.Message)...)
+		headOid = c.Oid
+	}
+	return ret, headOid, nil
+}
+
+// Enforce checks a pull request and reports the result with report.  Check runs are reported on success too, so the
+// check can be required by branch protection.  Reviews are only posted when there are violations.
+func (p *PolicyChecker) Enforce(ctx context.Context, gh GitHub, owner string, name string, number int64, report PolicyReport) ([]PolicyViolation, error) {
+	violations, headOid, err := p.checkPullRequest(ctx, gh, owner, name, number)
+	if err != nil {
+		return nil, err
+	}
+	summary := policySummary(violations)
+	switch report {
+	case PolicyReportCheckRun:
+		if headOid == "" {
+			return violations, nil
+		}
+		conclusion := CheckRunSuccess
+		title := "Branch and commit policy passed"
+		if len(violations) > 0 {
+			conclusion = CheckRunFailure
+			title = fmt.Sprintf("%d policy violation(s)", len(violations))
+		}
+		checkName := p.cfg.CheckName
+		if checkName == "" {
+			checkName = "policy"
+		}
+		if _, err := gh.CreateCheckRun(ctx, owner, name, CheckRunInput{
+			Name:       checkName,
+			HeadSHA:    headOid,
+			Status:     CheckRunCompleted,
+			Conclusion: conclusion,
+			Output: &CheckRunOutput{
+				Title:   title,
+				Summary: summary,
+			},
+		}); err != nil {
+			return violations, fmt.Errorf("failed to create policy check run: %w", err)
+		}
+	case PolicyReportReview:
+		if len(violations) == 0 {
+			return violations, nil
+		}
+		if _, err := gh.CreateReview(ctx, owner, name, number, ReviewInput{
+			Body:  summary,
+			Event: githubv4.PullRequestReviewEventRequestChanges,
+		}); err != nil {
+			return violations, fmt.Errorf("failed to post policy review: %w", err)
+		}
+	}
+	return violations, nil
+}
+
+func policySummary(violations []PolicyViolation) string {
+	if len(violations) == 0 {
+		return "The branch name and every commit message follow the policy."
+	}
+	var sb strings.Builder
+	sb.WriteString("This pull request does not follow the branch and commit policy:\n\n")
+	for _, v := range violations {
+		sb.WriteString("- ")
+		sb.WriteString(v.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package gogithub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyChecker_CheckBranch(t *testing.T) {
+	p, err := NewPolicyChecker(PolicyConfig{BranchPattern: `^(feature|fix)/[a-z0-9-]+$`})
+	require.NoError(t, err)
+	require.Empty(t, p.CheckBranch("feature/add-thing"))
+	require.Len(t, p.CheckBranch("my-branch"), 1)
+}
+
+func TestPolicyChecker_CheckCommitMessage(t *testing.T) {
+	p, err := NewPolicyChecker(PolicyConfig{ConventionalCommits: true, IgnoreMergeCommits: true})
+	require.NoError(t, err)
+	require.Empty(t, p.CheckCommitMessage("a", "feat(api): add search\n\nlong body"))
+	require.Empty(t, p.CheckCommitMessage("b", "fix!: drop support for v1"))
+	require.Empty(t, p.CheckCommitMessage("c", "Merge branch 'main' into feature/x"))
+	require.Len(t, p.CheckCommitMessage("d", "added search"), 1)
+	require.Len(t, p.CheckCommitMessage("e", "feature: add search"), 1)
+
+	p, err = NewPolicyChecker(PolicyConfig{CommitPattern: `^[A-Z]+-\d+ `, ConventionalCommits: true, ConventionalCommitTypes: []string{"feat"}})
+	require.NoError(t, err)
+	require.Len(t, p.CheckCommitMessage("f", "fix: thing"), 2)
+}
+
+func TestNewPolicyChecker_InvalidPattern(t *testing.T) {
+	_, err := NewPolicyChecker(PolicyConfig{BranchPattern: "["})
+	require.Error(t, err)
+}
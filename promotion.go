@@ -0,0 +1,109 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PromotionService tells PromoteDeployment where a service is deployed from
+type PromotionService struct {
+	Repo RepoRef
+	// Workflow is the file name or ID of the deploy workflow.  It must accept workflow_dispatch.
+	Workflow string
+	// Ref is the branch the workflow is dispatched on.  Defaults to main.
+	Ref string
+	// EnvironmentInput and SHAInput name the workflow inputs receiving the target environment and the SHA to deploy.
+	// They default to environment and sha.
+	EnvironmentInput string
+	SHAInput         string
+	// ExtraInputs are passed to every dispatch
+	ExtraInputs map[string]string
+}
+
+// ErrNoDeployment is returned when an environment has no successful deployment to promote
+var ErrNoDeployment = errors.New("no successful deployment")
+
+// Promoter promotes what is deployed in one environment to the next one
+type Promoter struct {
+	gh       GitHub
+	services map[string]PromotionService
+}
+
+func NewPromoter(gh GitHub, services map[string]PromotionService) *Promoter {
+	return &Promoter{
+		gh:       gh,
+		services: services,
+	}
+}
+
+// PromoteDeployment finds the SHA of the latest successful deployment of service to fromEnv and dispatches the deploy
+// workflow to deploy it to toEnv.  It returns the promoted SHA.
+func (p *Promoter) PromoteDeployment(ctx context.Context, service string, fromEnv string, toEnv string) (string, error) {
+	svc, exists := p.services[service]
+	if !exists {
+		return "", fmt.Errorf("unknown service %s", service)
+	}
+	sha, err := LatestDeployedSHA(ctx, p.gh, svc.Repo, fromEnv)
+	if err != nil {
+		return "", err
+	}
+	ref := svc.Ref
+	if ref == "" {
+		ref = "main"
+	}
+	envInput := svc.EnvironmentInput
+	if envInput == "" {
+		envInput = "environment"
+	}
+	shaInput := svc.SHAInput
+	if shaInput == "" {
+		shaInput = "sha"
+	}
+	inputs := make(map[string]string, len(svc.ExtraInputs)+2)
+	for k, v := range svc.ExtraInputs {
+		inputs[k] = v
+	}
+	inputs[envInput] = toEnv
+	inputs[shaInput] = sha
+	if err := p.gh.TriggerWorkflow(ctx, svc.Repo.Owner, svc.Repo.Name, svc.Workflow, ref, inputs); err != nil {
+		return "", fmt.Errorf("failed to trigger deploy of %s to %s: %w", sha, toEnv, err)
+	}
+	return sha, nil
+}
+
+// LatestDeployedSHA returns the SHA of the most recent deployment to environment whose latest status is success
+func LatestDeployedSHA(ctx context.Context, gh GitHub, repo RepoRef, environment string) (string, error) {
+	type deployment struct {
+		ID  int64  `json:"id"`
+		SHA string `json:"sha"`
+	}
+	deployments := newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[deployment], error) {
+		var ret []deployment
+		path := fmt.Sprintf("/repos/%s/%s/deployments?environment=%s&per_page=30&page=%d", repo.Owner, repo.Name, url.QueryEscape(environment), restPageNumber(cursor))
+		if err := gh.DoREST(ctx, http.MethodGet, path, nil, &ret); err != nil {
+			return Page[deployment]{}, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		return restPage(ret, cursor, 30), nil
+	})
+	for deployments.HasNext() {
+		page, err := deployments.Next(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, d := range page {
+			var statuses []struct {
+				State string `json:"state"`
+			}
+			if err := gh.DoREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/deployments/%d/statuses?per_page=1", repo.Owner, repo.Name, d.ID), nil, &statuses); err != nil {
+				return "", fmt.Errorf("failed to list deployment statuses: %w", err)
+			}
+			if len(statuses) > 0 && statuses[0].State == "success" {
+				return d.SHA, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w in %s", ErrNoDeployment, environment)
+}
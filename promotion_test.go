@@ -0,0 +1,41 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoteDeployment(t *testing.T) {
+	var dispatched triggerWorkflowBody
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/api/deployments":
+			require.Equal(t, "staging", r.URL.Query().Get("environment"))
+			_, _ = w.Write([]byte(`[{"id":2,"sha":"broken"},{"id":1,"sha":"good"}]`))
+		case "/repos/o/api/deployments/2/statuses":
+			_, _ = w.Write([]byte(`[{"state":"failure"}]`))
+		case "/repos/o/api/deployments/1/statuses":
+			_, _ = w.Write([]byte(`[{"state":"success"}]`))
+		case "/repos/o/api/actions/workflows/deploy.yaml/dispatches":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&dispatched))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	})
+	p := NewPromoter(g, map[string]PromotionService{
+		"api": {Repo: RepoRef{Owner: "o", Name: "api"}, Workflow: "deploy.yaml", ExtraInputs: map[string]string{"notify": "true"}},
+	})
+	sha, err := p.PromoteDeployment(context.Background(), "api", "staging", "production")
+	require.NoError(t, err)
+	require.Equal(t, "good", sha)
+	require.Equal(t, "main", dispatched.Ref)
+	require.Equal(t, map[string]string{"environment": "production", "sha": "good", "notify": "true"}, dispatched.Inputs)
+
+	_, err = p.PromoteDeployment(context.Background(), "web", "staging", "production")
+	require.Error(t, err)
+}
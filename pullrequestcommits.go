@@ -0,0 +1,96 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// PullRequestCommit is one commit of a pull request
+type PullRequestCommit struct {
+	Oid     string
+	Message string
+	// Author is the git author.  Login is empty when the email is not linked to a GitHub user.
+	Author        CommitAuthor
+	CommittedDate time.Time
+	// CheckRollup is the combined check and status state of the commit.  It is nil if the commit has no checks.
+	CheckRollup *CheckRollup
+}
+
+type CommitAuthor struct {
+	Name  string
+	Email string
+	Login string
+}
+
+func (g *GithubGraphqlAPI) ListPullRequestCommits(ctx context.Context, owner string, name string, number int64) (_ []PullRequestCommit, err error) {
+	ctx = withOperation(ctx, "ListPullRequestCommits")
+	defer annotateError(&err, OperationError{Operation: "ListPullRequestCommits", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("ListPullRequestCommits", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done ListPullRequestCommits")
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ID      githubv4.ID
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							Oid     string
+							Message string
+							Author  struct {
+								Name  string
+								Email string
+								User  struct {
+									Login string
+								}
+							}
+							CommittedDate     githubv4.DateTime
+							StatusCheckRollup *checkRollupQuery
+						}
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+				} `graphql:"commits(first: 100, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":     githubv4.String(owner),
+		"name":      githubv4.String(name),
+		"number":    githubv4.Int(number),
+		"maxChecks": githubv4.Int(fullPRMaxChecks),
+		"cursor":    (*githubv4.String)(nil),
+	}
+	var ret []PullRequestCommit
+	for {
+		if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query for PR commits: %w", err)
+		}
+		if query.Repository.PullRequest.ID == nil {
+			return nil, fmt.Errorf("failed to find PR %d", number)
+		}
+		for _, n := range query.Repository.PullRequest.Commits.Nodes {
+			c := n.Commit
+			ret = append(ret, PullRequestCommit{
+				Oid:     c.Oid,
+				Message: c.Message,
+				Author: CommitAuthor{
+					Name:  c.Author.Name,
+					Email: c.Author.Email,
+					Login: c.Author.User.Login,
+				},
+				CommittedDate: c.CommittedDate.Time,
+				CheckRollup:   c.StatusCheckRollup.toCheckRollup(),
+			})
+		}
+		if !query.Repository.PullRequest.Commits.PageInfo.HasNextPage {
+			return ret, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Repository.PullRequest.Commits.PageInfo.EndCursor)
+	}
+}
@@ -0,0 +1,45 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPullRequestCommits_Pages(t *testing.T) {
+	var cursors []interface{}
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		cursors = append(cursors, req.Variables["cursor"])
+		switch req.Variables["cursor"] {
+		case nil:
+			_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_1","commits":{
+				"nodes":[
+					{"commit":{"oid":"a1","message":"first","author":{"name":"Alice","email":"a@example.com","user":{"login":"alice"}},"committedDate":"2024-05-01T10:00:00Z","statusCheckRollup":null}},
+					{"commit":{"oid":"b2","message":"second","author":{"name":"Bob","email":"b@example.com","user":null},"committedDate":"2024-05-01T11:00:00Z","statusCheckRollup":{"state":"SUCCESS","contexts":{"nodes":[]}}}}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"CUR1"}}}}}}`))
+		case "CUR1":
+			_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_1","commits":{
+				"nodes":[
+					{"commit":{"oid":"c3","message":"third","author":{"name":"Alice","email":"a@example.com","user":{"login":"alice"}},"committedDate":"2024-05-01T12:00:00Z","statusCheckRollup":null}}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"CUR2"}}}}}}`))
+		default:
+			t.Fatalf("unexpected cursor %v", req.Variables["cursor"])
+		}
+	})
+	commits, err := g.ListPullRequestCommits(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{nil, "CUR1"}, cursors)
+	oids := make([]string, 0, len(commits))
+	for _, c := range commits {
+		oids = append(oids, c.Oid)
+	}
+	require.Equal(t, []string{"a1", "b2", "c3"}, oids)
+	require.Equal(t, CommitAuthor{Name: "Alice", Email: "a@example.com", Login: "alice"}, commits[0].Author)
+	require.Equal(t, "", commits[1].Author.Login)
+	require.Nil(t, commits[0].CheckRollup)
+	require.Equal(t, "SUCCESS", commits[1].CheckRollup.State)
+	require.Nil(t, commits[2].CheckRollup)
+}
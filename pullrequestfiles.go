@@ -0,0 +1,54 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// PullRequestFile is one file changed by a pull request
+type PullRequestFile struct {
+	Path      string `json:"filename"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Changes   int    `json:"changes"`
+	// Status is added, removed, modified, renamed, copied, changed or unchanged
+	Status string `json:"status"`
+	// Patch is the unified diff of the file.  GitHub omits it for binary and very large files.
+	Patch            string `json:"patch"`
+	PreviousFilename string `json:"previous_filename"`
+}
+
+const pullRequestFilesPerPage = 100
+
+// ListPullRequestFiles returns every file changed by a pull request, following pagination.  GitHub caps the list at
+// 3000 files.
+func (g *GithubGraphqlAPI) ListPullRequestFiles(ctx context.Context, owner string, name string, number int64) ([]PullRequestFile, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[PullRequestFile], err error) {
+		ctx = withOperation(ctx, "ListPullRequestFiles")
+		defer annotateError(&err, OperationError{Operation: "ListPullRequestFiles", Owner: owner, Repo: name, Number: number})
+		g.logger(ctx).Debug("ListPullRequestFiles", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListPullRequestFiles")
+		var files []PullRequestFile
+		path := fmt.Sprintf("/repos/%s/%s/pulls/%d/files?per_page=%d&page=%d", owner, name, number, pullRequestFilesPerPage, restPageNumber(cursor))
+		if err := g.doREST(ctx, http.MethodGet, path, nil, &files); err != nil {
+			return Page[PullRequestFile]{}, fmt.Errorf("failed to list PR files: %w", err)
+		}
+		return restPage(files, cursor, pullRequestFilesPerPage), nil
+	}).All(ctx)
+}
+
+// GetPullRequestDiff returns the unified diff of a pull request
+func (g *GithubGraphqlAPI) GetPullRequestDiff(ctx context.Context, owner string, name string, number int64) (_ string, err error) {
+	ctx = withOperation(ctx, "GetPullRequestDiff")
+	defer annotateError(&err, OperationError{Operation: "GetPullRequestDiff", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("GetPullRequestDiff", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done GetPullRequestDiff")
+	b, err := g.doRESTRaw(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, name, number), "application/vnd.github.v3.diff")
+	if err != nil {
+		return "", fmt.Errorf("failed to get PR diff: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,39 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPullRequestFiles_Paginates(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/pulls/1/files", r.URL.Path)
+		count := pullRequestFilesPerPage
+		if r.URL.Query().Get("page") == "2" {
+			count = 3
+		}
+		files := make([]PullRequestFile, 0, count)
+		for i := 0; i < count; i++ {
+			files = append(files, PullRequestFile{Path: fmt.Sprintf("f%d", i), Status: "modified"})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(files))
+	})
+	files, err := g.ListPullRequestFiles(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.Len(t, files, pullRequestFilesPerPage+3)
+}
+
+func TestGetPullRequestDiff(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/vnd.github.v3.diff", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte("diff --git a/x b/x\n"))
+	})
+	diff, err := g.GetPullRequestDiff(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.Equal(t, "diff --git a/x b/x\n", diff)
+}
@@ -0,0 +1,214 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// PullRequestFull is a pull request with everything bots usually need to decide what to do with it, fetched in a
+// single round trip by GetPullRequestFull
+type PullRequestFull struct {
+	PullRequest
+	// Additions, Deletions and ChangedFiles summarize the whole diff, even when Files is truncated
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	// Labels are the names of PullRequest.Labels
+	Labels []string
+	// Reviews are the most recent reviews, oldest first
+	Reviews []PullRequestReview
+	// CheckRollup is the combined check and status state of the head commit.  It is nil if the head commit has no checks.
+	CheckRollup *CheckRollup
+	// Files are the first files of the diff.  Use ChangedFiles to know if the list is complete.
+	Files []PullRequestFileSummary
+	// LinkedIssues are the issues this pull request will close when merged
+	LinkedIssues []LinkedIssue
+}
+
+type PullRequestReview struct {
+	ID          githubv4.ID
+	Author      string
+	State       string
+	Body        string
+	SubmittedAt time.Time
+	// CommitOid is the head commit the review was made on.  Reviews of an older commit are stale after a push.
+	CommitOid string
+}
+
+type CheckRollup struct {
+	// State is the combined state, for example SUCCESS, FAILURE or PENDING
+	State    string
+	Contexts []CheckContext
+}
+
+type CheckContext struct {
+	// Kind is either CheckRun or StatusContext
+	Kind string
+	// Name is the check run name or the status context
+	Name string
+	// State is the conclusion of completed check runs, the status of running ones, or the state of a status context
+	State string
+}
+
+type PullRequestFileSummary struct {
+	Path       string
+	Additions  int
+	Deletions  int
+	ChangeType string
+}
+
+type LinkedIssue struct {
+	Number int64
+	Title  string
+	State  string
+	URL    string
+}
+
+const (
+	fullPRMaxReviews      = 50
+	fullPRMaxChecks       = 100
+	fullPRMaxFiles        = 100
+	fullPRMaxLinkedIssues = 25
+)
+
+type checkRollupQuery struct {
+	State    string
+	Contexts struct {
+		Nodes []struct {
+			Typename string `graphql:"__typename"`
+			CheckRun struct {
+				Name       string
+				Status     string
+				Conclusion string
+			} `graphql:"... on CheckRun"`
+			StatusContext struct {
+				Context string
+				State   string
+			} `graphql:"... on StatusContext"`
+		}
+	} `graphql:"contexts(first: $maxChecks)"`
+}
+
+func (c *checkRollupQuery) toCheckRollup() *CheckRollup {
+	if c == nil {
+		return nil
+	}
+	ret := &CheckRollup{State: c.State}
+	for _, n := range c.Contexts.Nodes {
+		switch n.Typename {
+		case "CheckRun":
+			state := n.CheckRun.Conclusion
+			if state == "" {
+				state = n.CheckRun.Status
+			}
+			ret.Contexts = append(ret.Contexts, CheckContext{Kind: n.Typename, Name: n.CheckRun.Name, State: state})
+		case "StatusContext":
+			ret.Contexts = append(ret.Contexts, CheckContext{Kind: n.Typename, Name: n.StatusContext.Context, State: n.StatusContext.State})
+		}
+	}
+	return ret
+}
+
+type pullRequestFullQuery struct {
+	PullRequest
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	Reviews      struct {
+		Nodes []struct {
+			ID     githubv4.ID
+			Author struct {
+				Login string
+			}
+			State       string
+			Body        string
+			SubmittedAt githubv4.DateTime
+			Commit      struct {
+				Oid string
+			}
+		}
+	} `graphql:"reviews(last: $maxReviews)"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *checkRollupQuery
+			}
+		}
+	} `graphql:"commits(last: 1)"`
+	Files struct {
+		Nodes []PullRequestFileSummary
+	} `graphql:"files(first: $maxFiles)"`
+	ClosingIssuesReferences struct {
+		Nodes []struct {
+			Number int64
+			Title  string
+			State  string
+			URL    string `graphql:"url"`
+		}
+	} `graphql:"closingIssuesReferences(first: $maxLinkedIssues)"`
+}
+
+func (q *pullRequestFullQuery) toPullRequestFull() *PullRequestFull {
+	ret := &PullRequestFull{
+		PullRequest:  q.PullRequest,
+		Additions:    q.Additions,
+		Deletions:    q.Deletions,
+		ChangedFiles: q.ChangedFiles,
+		Labels:       q.PullRequest.Labels.Names(),
+		Files:        q.Files.Nodes,
+	}
+	for _, r := range q.Reviews.Nodes {
+		ret.Reviews = append(ret.Reviews, PullRequestReview{
+			ID:          r.ID,
+			Author:      r.Author.Login,
+			State:       r.State,
+			Body:        r.Body,
+			SubmittedAt: r.SubmittedAt.Time,
+			CommitOid:   r.Commit.Oid,
+		})
+	}
+	if len(q.Commits.Nodes) > 0 {
+		ret.CheckRollup = q.Commits.Nodes[0].Commit.StatusCheckRollup.toCheckRollup()
+	}
+	for _, i := range q.ClosingIssuesReferences.Nodes {
+		ret.LinkedIssues = append(ret.LinkedIssues, LinkedIssue{
+			Number: i.Number,
+			Title:  i.Title,
+			State:  i.State,
+			URL:    i.URL,
+		})
+	}
+	return ret
+}
+
+func (g *GithubGraphqlAPI) GetPullRequestFull(ctx context.Context, owner string, name string, number int64) (_ *PullRequestFull, err error) {
+	ctx = withOperation(ctx, "GetPullRequestFull")
+	defer annotateError(&err, OperationError{Operation: "GetPullRequestFull", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("GetPullRequestFull", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done GetPullRequestFull")
+	var query struct {
+		Repository struct {
+			PullRequest pullRequestFullQuery `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":           githubv4.String(owner),
+		"name":            githubv4.String(name),
+		"number":          githubv4.Int(number),
+		"maxReviews":      githubv4.Int(fullPRMaxReviews),
+		"maxChecks":       githubv4.Int(fullPRMaxChecks),
+		"maxFiles":        githubv4.Int(fullPRMaxFiles),
+		"maxLinkedIssues": githubv4.Int(fullPRMaxLinkedIssues),
+	}
+	if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query for PR: %w", err)
+	}
+	if query.Repository.PullRequest.ID == nil {
+		return nil, fmt.Errorf("failed to find PR %d", number)
+	}
+	return query.Repository.PullRequest.toPullRequestFull(), nil
+}
@@ -0,0 +1,84 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPullRequestFull(t *testing.T) {
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.True(t, strings.Contains(req.Query, "closingIssuesReferences(first: $maxLinkedIssues)"))
+		require.Equal(t, "o", req.Variables["owner"])
+		require.Equal(t, float64(fullPRMaxFiles), req.Variables["maxFiles"])
+		_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{
+			"id":"PR_1","number":7,"title":"Add feature","state":"OPEN","headRefName":"feature","headRefOid":"def",
+			"labels":{"nodes":[{"name":"bug"},{"name":"size/S"}]},
+			"additions":10,"deletions":2,"changedFiles":1,
+			"reviews":{"nodes":[{"id":"R_1","author":{"login":"alice"},"state":"APPROVED","body":"lgtm",
+				"submittedAt":"2024-05-01T10:00:00Z","commit":{"oid":"def"}}]},
+			"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE","contexts":{"nodes":[
+				{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"},
+				{"__typename":"CheckRun","name":"lint","status":"IN_PROGRESS","conclusion":""},
+				{"__typename":"StatusContext","context":"ci/legacy","state":"FAILURE"}]}}}}]},
+			"files":{"nodes":[{"path":"main.go","additions":10,"deletions":2,"changeType":"MODIFIED"}]},
+			"closingIssuesReferences":{"nodes":[{"number":3,"title":"Broken","state":"OPEN","url":"https://github.com/o/r/issues/3"}]}
+		}}}}`))
+	})
+	pr, err := g.GetPullRequestFull(context.Background(), "o", "r", 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), pr.Number)
+	require.Equal(t, "Add feature", pr.Title)
+	require.Equal(t, 10, pr.Additions)
+	require.Equal(t, 1, pr.ChangedFiles)
+	require.Equal(t, []string{"bug", "size/S"}, pr.Labels)
+	require.Equal(t, []PullRequestReview{{
+		ID:          "R_1",
+		Author:      "alice",
+		State:       "APPROVED",
+		Body:        "lgtm",
+		SubmittedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		CommitOid:   "def",
+	}}, pr.Reviews)
+	require.Equal(t, &CheckRollup{State: "FAILURE", Contexts: []CheckContext{
+		{Kind: "CheckRun", Name: "build", State: "SUCCESS"},
+		{Kind: "CheckRun", Name: "lint", State: "IN_PROGRESS"},
+		{Kind: "StatusContext", Name: "ci/legacy", State: "FAILURE"},
+	}}, pr.CheckRollup)
+	require.Equal(t, []PullRequestFileSummary{{Path: "main.go", Additions: 10, Deletions: 2, ChangeType: "MODIFIED"}}, pr.Files)
+	require.Equal(t, []LinkedIssue{{Number: 3, Title: "Broken", State: "OPEN", URL: "https://github.com/o/r/issues/3"}}, pr.LinkedIssues)
+}
+
+func TestGetPullRequestFull_Empty(t *testing.T) {
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		decodeGraphQLRequest(t, r)
+		_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{
+			"id":"PR_2","number":8,"state":"OPEN","labels":{"nodes":[]},
+			"reviews":{"nodes":[]},
+			"commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]},
+			"files":{"nodes":[]},
+			"closingIssuesReferences":{"nodes":[]}
+		}}}}`))
+	})
+	pr, err := g.GetPullRequestFull(context.Background(), "o", "r", 8)
+	require.NoError(t, err)
+	require.Nil(t, pr.CheckRollup)
+	require.Empty(t, pr.Files)
+	require.Empty(t, pr.Reviews)
+	require.Empty(t, pr.Labels)
+	require.Empty(t, pr.LinkedIssues)
+}
+
+func TestGetPullRequestFull_NotFound(t *testing.T) {
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		decodeGraphQLRequest(t, r)
+		_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":null}}}`))
+	})
+	_, err := g.GetPullRequestFull(context.Background(), "o", "r", 9)
+	require.Error(t, err)
+}
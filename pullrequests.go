@@ -0,0 +1,203 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// PullRequestUpdate lists the changes UpdatePullRequest applies.  Nil fields are left untouched.
+type PullRequestUpdate struct {
+	Title       *string
+	Body        *string
+	BaseRefName *string
+	// Draft converts the pull request to a draft when true and marks it ready for review when false
+	Draft *bool
+}
+
+func (g *GithubGraphqlAPI) UpdatePullRequest(ctx context.Context, owner string, name string, number int64, updates PullRequestUpdate) (err error) {
+	ctx = withOperation(ctx, "UpdatePullRequest")
+	defer annotateError(&err, OperationError{Operation: "UpdatePullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("UpdatePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done UpdatePullRequest")
+	if updates.Title != nil || updates.Body != nil || updates.BaseRefName != nil {
+		input := githubv4.UpdatePullRequestInput{
+			PullRequestID: prid,
+		}
+		if updates.Title != nil {
+			input.Title = githubv4.NewString(githubv4.String(*updates.Title))
+		}
+		if updates.Body != nil {
+			input.Body = githubv4.NewString(githubv4.String(*updates.Body))
+		}
+		if updates.BaseRefName != nil {
+			input.BaseRefName = githubv4.NewString(githubv4.String(*updates.BaseRefName))
+		}
+		var ret struct {
+			UpdatePullRequest struct {
+				PullRequest struct {
+					ID githubv4.ID
+				}
+			} `graphql:"updatePullRequest(input: $input)"`
+		}
+		if err := g.ClientV4.Mutate(ctx, &ret, input, nil); err != nil {
+			return fmt.Errorf("unable to update PR: %w", err)
+		}
+	}
+	if updates.Draft != nil {
+		if err := g.setPullRequestDraft(ctx, prid, *updates.Draft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) setPullRequestDraft(ctx context.Context, prid githubv4.ID, draft bool) error {
+	if draft {
+		var ret struct {
+			ConvertPullRequestToDraft struct {
+				PullRequest struct {
+					ID githubv4.ID
+				}
+			} `graphql:"convertPullRequestToDraft(input: $input)"`
+		}
+		if err := g.ClientV4.Mutate(ctx, &ret, githubv4.ConvertPullRequestToDraftInput{
+			PullRequestID: prid,
+		}, nil); err != nil {
+			return fmt.Errorf("unable to convert PR to draft: %w", err)
+		}
+		return nil
+	}
+	var ret struct {
+		MarkPullRequestReadyForReview struct {
+			PullRequest struct {
+				ID githubv4.ID
+			}
+		} `graphql:"markPullRequestReadyForReview(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.MarkPullRequestReadyForReviewInput{
+		PullRequestID: prid,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to mark PR ready for review: %w", err)
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) ClosePullRequest(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "ClosePullRequest")
+	defer annotateError(&err, OperationError{Operation: "ClosePullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("ClosePullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done ClosePullRequest")
+	var ret struct {
+		ClosePullRequest struct {
+			PullRequest struct {
+				ID githubv4.ID
+			}
+		} `graphql:"closePullRequest(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.ClosePullRequestInput{
+		PullRequestID: prid,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to close PR: %w", err)
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) ReopenPullRequest(ctx context.Context, owner string, name string, number int64) (err error) {
+	ctx = withOperation(ctx, "ReopenPullRequest")
+	defer annotateError(&err, OperationError{Operation: "ReopenPullRequest", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("ReopenPullRequest", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid))
+	defer g.logger(ctx).Debug("Done ReopenPullRequest")
+	var ret struct {
+		ReopenPullRequest struct {
+			PullRequest struct {
+				ID githubv4.ID
+			}
+		} `graphql:"reopenPullRequest(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.ReopenPullRequestInput{
+		PullRequestID: prid,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to reopen PR: %w", err)
+	}
+	return nil
+}
+
+// ErrPullRequestHeadChanged is returned by UpdatePullRequestBranch when commits were pushed to the PR head between
+// looking it up and updating it.  Calling it again updates the new head.
+var ErrPullRequestHeadChanged = errors.New("pull request head changed during the update")
+
+// isHeadChangedMessage reports whether GitHub refused a branch update because the head moved.  REST says "expected head
+// sha didn't match current head ref" and GraphQL that the expected head OID does not match.
+func isHeadChangedMessage(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "expected head")
+}
+
+// UpdatePullRequestBranch merges or rebases the base branch into the PR head.  Merges go through the REST API, which
+// GitHub answers with 202 Accepted and completes in the background; the REST API cannot rebase, so rebases go through
+// GraphQL.  Both only apply to the head commit looked up first, returning ErrPullRequestHeadChanged otherwise.
+func (g *GithubGraphqlAPI) UpdatePullRequestBranch(ctx context.Context, owner string, name string, number int64, method githubv4.PullRequestBranchUpdateMethod) (err error) {
+	ctx = withOperation(ctx, "UpdatePullRequestBranch")
+	defer annotateError(&err, OperationError{Operation: "UpdatePullRequestBranch", Owner: owner, Repo: name, Number: number})
+	defer g.clearPRCache()
+	pr, err := g.FindPullRequest(ctx, owner, name, number)
+	if err != nil {
+		return fmt.Errorf("failed to find PR: %w", err)
+	}
+	headOid := fmt.Sprint(pr.HeadRefOid)
+	g.logger(ctx).Debug("UpdatePullRequestBranch", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("head", headOid), zap.String("method", string(method)))
+	defer g.logger(ctx).Debug("Done UpdatePullRequestBranch")
+	if method == githubv4.PullRequestBranchUpdateMethodRebase {
+		var ret struct {
+			UpdatePullRequestBranch struct {
+				PullRequest struct {
+					ID githubv4.ID
+				}
+			} `graphql:"updatePullRequestBranch(input: $input)"`
+		}
+		expected := githubv4.GitObjectID(headOid)
+		if err := g.ClientV4.Mutate(ctx, &ret, githubv4.UpdatePullRequestBranchInput{
+			PullRequestID:   pr.ID,
+			ExpectedHeadOid: &expected,
+			UpdateMethod:    &method,
+		}, nil); err != nil {
+			if isHeadChangedMessage(err.Error()) {
+				return fmt.Errorf("%w: %s", ErrPullRequestHeadChanged, err)
+			}
+			return fmt.Errorf("unable to update PR branch: %w", err)
+		}
+		return nil
+	}
+	err = g.doREST(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/%s/pulls/%d/update-branch", owner, name, number), map[string]string{
+		"expected_head_sha": headOid,
+	}, nil)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusUnprocessableEntity && isHeadChangedMessage(restErr.Message):
+		return fmt.Errorf("%w: %s", ErrPullRequestHeadChanged, restErr.Message)
+	default:
+		return fmt.Errorf("unable to update PR branch: %w", err)
+	}
+}
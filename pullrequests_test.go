@@ -0,0 +1,300 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedMutation struct {
+	Name  string
+	Input map[string]interface{}
+}
+
+// newPRMutationClient returns a client whose server finds PR_1 for any pull request number and records every
+// mutation.  The mutation named fail gets a GraphQL error.
+func newPRMutationClient(t *testing.T, fail string) (*GithubGraphqlAPI, *[]recordedMutation) {
+	return newMutationClient(t, fail, nil)
+}
+
+// newMutationClient is newPRMutationClient, answering the mutations named in responses with their data instead of
+// the pull request
+func newMutationClient(t *testing.T, fail string, responses map[string]string) (*GithubGraphqlAPI, *[]recordedMutation) {
+	var mutations []recordedMutation
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		if !strings.HasPrefix(req.Query, "mutation") {
+			require.Contains(t, req.Query, "pullRequest(number: $number)")
+			_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_1"}}}}`))
+			return
+		}
+		body := req.Query[strings.Index(req.Query, "{")+1:]
+		name := body[:strings.Index(body, "(")]
+		input, _ := req.Variables["input"].(map[string]interface{})
+		mutations = append(mutations, recordedMutation{Name: name, Input: input})
+		if name == fail {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"boom"}]}`))
+			return
+		}
+		if data, ok := responses[name]; ok {
+			_, _ = w.Write([]byte(`{"data":{"` + name + `":` + data + `}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"` + name + `":{"pullRequest":{"id":"PR_1"}}}}`))
+	})
+	return g, &mutations
+}
+
+func TestUpdatePullRequest_OnlySetFields(t *testing.T) {
+	g, mutations := newPRMutationClient(t, "")
+	title := "New title"
+	require.NoError(t, g.UpdatePullRequest(context.Background(), "o", "r", 1, PullRequestUpdate{Title: &title}))
+	require.Equal(t, []recordedMutation{{
+		Name:  "updatePullRequest",
+		Input: map[string]interface{}{"pullRequestId": "PR_1", "title": "New title"},
+	}}, *mutations)
+
+	*mutations = nil
+	empty := ""
+	require.NoError(t, g.UpdatePullRequest(context.Background(), "o", "r", 1, PullRequestUpdate{Body: &empty}))
+	require.Equal(t, []recordedMutation{{
+		Name:  "updatePullRequest",
+		Input: map[string]interface{}{"pullRequestId": "PR_1", "body": ""},
+	}}, *mutations)
+}
+
+func TestUpdatePullRequest_Draft(t *testing.T) {
+	g, mutations := newPRMutationClient(t, "")
+	draft := true
+	require.NoError(t, g.UpdatePullRequest(context.Background(), "o", "r", 1, PullRequestUpdate{Draft: &draft}))
+	ready := false
+	require.NoError(t, g.UpdatePullRequest(context.Background(), "o", "r", 1, PullRequestUpdate{Draft: &ready}))
+	require.Equal(t, []recordedMutation{
+		{Name: "convertPullRequestToDraft", Input: map[string]interface{}{"pullRequestId": "PR_1"}},
+		{Name: "markPullRequestReadyForReview", Input: map[string]interface{}{"pullRequestId": "PR_1"}},
+	}, *mutations)
+}
+
+func TestUpdatePullRequest_TitleAndDraft(t *testing.T) {
+	g, mutations := newPRMutationClient(t, "markPullRequestReadyForReview")
+	title := "Ready"
+	ready := false
+	err := g.UpdatePullRequest(context.Background(), "o", "r", 1, PullRequestUpdate{Title: &title, Draft: &ready})
+	require.ErrorContains(t, err, "boom")
+	require.Len(t, *mutations, 2)
+	require.Equal(t, "updatePullRequest", (*mutations)[0].Name)
+	require.Equal(t, "markPullRequestReadyForReview", (*mutations)[1].Name)
+}
+
+func TestCloseAndReopenPullRequest(t *testing.T) {
+	cases := []struct {
+		mutation string
+		call     func(g *GithubGraphqlAPI) error
+	}{
+		{"closePullRequest", func(g *GithubGraphqlAPI) error { return g.ClosePullRequest(context.Background(), "o", "r", 1) }},
+		{"reopenPullRequest", func(g *GithubGraphqlAPI) error { return g.ReopenPullRequest(context.Background(), "o", "r", 1) }},
+	}
+	cachedKey := findPrKey{owner: "o", name: "r", branch: "feature"}
+	for _, c := range cases {
+		t.Run(c.mutation, func(t *testing.T) {
+			g, mutations := newPRMutationClient(t, "")
+			g.findPrCache.DefaultExpiry = time.Hour
+			g.findPrCache.Set(cachedKey, findPrValue{prs: []BranchPullRequest{{Number: 1}}})
+			require.NoError(t, c.call(g))
+			require.Equal(t, []recordedMutation{{Name: c.mutation, Input: map[string]interface{}{"pullRequestId": "PR_1"}}}, *mutations)
+			_, exists := g.findPrCache.Get(cachedKey)
+			require.False(t, exists)
+		})
+		t.Run(c.mutation+" error", func(t *testing.T) {
+			g, _ := newPRMutationClient(t, c.mutation)
+			g.findPrCache.DefaultExpiry = time.Hour
+			g.findPrCache.Set(cachedKey, findPrValue{prs: []BranchPullRequest{{Number: 1}}})
+			err := c.call(g)
+			require.ErrorContains(t, err, "boom")
+			var opErr *OperationError
+			require.ErrorAs(t, err, &opErr)
+			require.Equal(t, int64(1), opErr.Number)
+			// The PR may have changed even if the mutation reported an error
+			_, exists := g.findPrCache.Get(cachedKey)
+			require.False(t, exists)
+		})
+	}
+}
+
+const foundPRResponse = `{"data":{"repository":{"pullRequest":{"id":"PR_1","number":1,"headRefOid":"def","state":"OPEN"}}}}`
+
+// newUpdateBranchClient returns a client whose server finds the PR with head def and answers update-branch calls with
+// status and body
+func newUpdateBranchClient(t *testing.T, status int, body string) *GithubGraphqlAPI {
+	return newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			require.Contains(t, decodeGraphQLRequest(t, r).Query, "pullRequest(number: $number)")
+			_, _ = w.Write([]byte(foundPRResponse))
+			return
+		}
+		if r.Method != http.MethodPut || r.URL.Path != "/repos/o/r/pulls/1/update-branch" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, map[string]string{"expected_head_sha": "def"}, req)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestUpdatePullRequestBranch_Accepted(t *testing.T) {
+	g := newUpdateBranchClient(t, http.StatusAccepted, `{"message":"Updating pull request branch.","url":"https://github.com/o/r/pull/1"}`)
+	g.findPrCache.DefaultExpiry = time.Hour
+	g.findPrCache.Set(findPrKey{owner: "o", name: "r", branch: "feature"}, findPrValue{prs: []BranchPullRequest{{Number: 1}}})
+	require.NoError(t, g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodMerge))
+	_, exists := g.findPrCache.Get(findPrKey{owner: "o", name: "r", branch: "feature"})
+	require.False(t, exists)
+}
+
+func TestUpdatePullRequestBranch_HeadChanged(t *testing.T) {
+	g := newUpdateBranchClient(t, http.StatusUnprocessableEntity, `{"message":"expected head sha didn't match current head ref."}`)
+	err := g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodMerge)
+	require.ErrorIs(t, err, ErrPullRequestHeadChanged)
+
+	g = newUpdateBranchClient(t, http.StatusUnprocessableEntity, `{"message":"merge conflict between base and head"}`)
+	err = g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodMerge)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrPullRequestHeadChanged)
+}
+
+func TestUpdatePullRequestBranch_Rebase(t *testing.T) {
+	var input map[string]interface{}
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		if !strings.HasPrefix(req.Query, "mutation") {
+			_, _ = w.Write([]byte(foundPRResponse))
+			return
+		}
+		require.Contains(t, req.Query, "updatePullRequestBranch(input: $input)")
+		input = req.Variables["input"].(map[string]interface{})
+		_, _ = w.Write([]byte(`{"data":{"updatePullRequestBranch":{"pullRequest":{"id":"PR_1"}}}}`))
+	})
+	require.NoError(t, g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodRebase))
+	require.Equal(t, map[string]interface{}{"pullRequestId": "PR_1", "expectedHeadOid": "def", "updateMethod": "REBASE"}, input)
+}
+
+func TestUpdatePullRequestBranch_RebaseHeadChanged(t *testing.T) {
+	respond := `{"data":{"updatePullRequestBranch":null},"errors":[{"type":"UNPROCESSABLE","message":"Expected head OID def does not match the current head OID 123."}]}`
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		if !strings.HasPrefix(req.Query, "mutation") {
+			_, _ = w.Write([]byte(foundPRResponse))
+			return
+		}
+		_, _ = w.Write([]byte(respond))
+	})
+	err := g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodRebase)
+	require.ErrorIs(t, err, ErrPullRequestHeadChanged)
+
+	respond = `{"data":{"updatePullRequestBranch":null},"errors":[{"message":"Rebase failed: conflicts"}]}`
+	err = g.UpdatePullRequestBranch(context.Background(), "o", "r", 1, githubv4.PullRequestBranchUpdateMethodRebase)
+	require.ErrorContains(t, err, "unable to update PR branch")
+	require.NotErrorIs(t, err, ErrPullRequestHeadChanged)
+}
+
+func TestDisablePullRequestAutoMerge(t *testing.T) {
+	g, mutations := newPRMutationClient(t, "")
+	require.NoError(t, g.DisablePullRequestAutoMerge(context.Background(), "o", "r", 1))
+	require.Equal(t, []recordedMutation{{
+		Name:  "disablePullRequestAutoMerge",
+		Input: map[string]interface{}{"pullRequestId": "PR_1"},
+	}}, *mutations)
+
+	g, _ = newPRMutationClient(t, "disablePullRequestAutoMerge")
+	require.ErrorContains(t, g.DisablePullRequestAutoMerge(context.Background(), "o", "r", 1), "boom")
+}
+
+func TestFindPullRequest_AutoMergeRequest(t *testing.T) {
+	response := `{"data":{"repository":{"pullRequest":{"id":"PR_1","number":1,"autoMergeRequest":{"enabledAt":"2024-05-01T10:00:00Z","enabledBy":{"login":"alice"},"mergeMethod":"SQUASH"}}}}}`
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.Contains(t, req.Query, "autoMergeRequest{enabledAt,enabledBy{login},mergeMethod}")
+		_, _ = w.Write([]byte(response))
+	})
+	pr, err := g.FindPullRequest(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.NotNil(t, pr.AutoMergeRequest)
+	require.Equal(t, "alice", pr.AutoMergeRequest.EnabledBy.Login)
+	require.Equal(t, githubv4.PullRequestMergeMethodSquash, pr.AutoMergeRequest.MergeMethod)
+	require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), pr.AutoMergeRequest.EnabledAt.Time)
+
+	// Without auto-merge the request is null
+	response = `{"data":{"repository":{"pullRequest":{"id":"PR_1","number":1,"autoMergeRequest":null}}}}`
+	pr, err = g.FindPullRequest(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.Nil(t, pr.AutoMergeRequest)
+}
+
+// newBranchPRsClient returns a client whose server answers the open PR query of each branch with the nodes of prs,
+// and counts the queries it answers.  PR lookups are cached for an hour.
+func newBranchPRsClient(t *testing.T, prs map[string]string) (*GithubGraphqlAPI, *int) {
+	queries := 0
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.Contains(t, req.Query, "pullRequests(states: [OPEN], first: 50, headRefName: $branch)")
+		queries++
+		branch, _ := req.Variables["branch"].(string)
+		_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequests":{"nodes":[` + prs[branch] + `]}}}}`))
+	})
+	g.findPrCache.DefaultExpiry = time.Hour
+	return g, &queries
+}
+
+func TestFindPRsForBranch(t *testing.T) {
+	g, queries := newBranchPRsClient(t, map[string]string{
+		"stacked": `{"number":12,"baseRefName":"release"},{"number":7,"baseRefName":"main"}`,
+		"single":  `{"number":3,"baseRefName":"main"}`,
+	})
+	ctx := context.Background()
+	prs, err := g.FindPRsForBranch(ctx, "o", "r", "stacked")
+	require.NoError(t, err)
+	require.Equal(t, []BranchPullRequest{{Number: 7, BaseRefName: "main"}, {Number: 12, BaseRefName: "release"}}, prs)
+
+	// FindPRForBranch shares the cached result and refuses to pick one of several PRs
+	_, err = g.FindPRForBranch(ctx, "o", "r", "stacked")
+	require.ErrorContains(t, err, "found multiple PRs for branch stacked")
+	require.Equal(t, 1, *queries)
+
+	// Callers get their own copy of the cached PRs
+	prs[0].Number = 99
+	prs, err = g.FindPRsForBranch(ctx, "o", "r", "stacked")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), prs[0].Number)
+
+	number, err := g.FindPRForBranch(ctx, "o", "r", "single")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), number)
+	require.Equal(t, 2, *queries)
+}
+
+func TestFindPRsForBranch_NegativeTTL(t *testing.T) {
+	g, queries := newBranchPRsClient(t, map[string]string{"feature": `{"number":3,"baseRefName":"main"}`})
+	g.findPrNegativeTTL = 20 * time.Millisecond
+	ctx := context.Background()
+	prs, err := g.FindPRsForBranch(ctx, "o", "r", "none")
+	require.NoError(t, err)
+	require.Empty(t, prs)
+	_, err = g.FindPRsForBranch(ctx, "o", "r", "feature")
+	require.NoError(t, err)
+	require.Equal(t, 2, *queries)
+
+	// Once the negative TTL passed, only the branch without PR is queried again
+	time.Sleep(40 * time.Millisecond)
+	_, err = g.FindPRsForBranch(ctx, "o", "r", "none")
+	require.NoError(t, err)
+	_, err = g.FindPRsForBranch(ctx, "o", "r", "feature")
+	require.NoError(t, err)
+	require.Equal(t, 3, *queries)
+}
@@ -0,0 +1,87 @@
+package gogithub
+
+// PullRequestState is whether a pull request is open, closed without merging, or merged
+type PullRequestState string
+
+const (
+	PullRequestStateOpen   PullRequestState = "OPEN"
+	PullRequestStateClosed PullRequestState = "CLOSED"
+	PullRequestStateMerged PullRequestState = "MERGED"
+)
+
+// The misspelled names of the PullRequestState constants, kept for compatibility
+const (
+	// Deprecated: use PullRequestStateClosed
+	PullRequstClosed = PullRequestStateClosed
+	// Deprecated: use PullRequestStateMerged
+	PullRequstMerged = PullRequestStateMerged
+	// Deprecated: use PullRequestStateOpen
+	PullRequstOpen = PullRequestStateOpen
+)
+
+// IsOpen reports whether the pull request can still be merged or closed
+func (s PullRequestState) IsOpen() bool {
+	return s == PullRequestStateOpen
+}
+
+// IsMerged reports whether the pull request was merged
+func (s PullRequestState) IsMerged() bool {
+	return s == PullRequestStateMerged
+}
+
+// IsMergedOrClosed reports whether the pull request is done, merged or not
+func (s PullRequestState) IsMergedOrClosed() bool {
+	return s == PullRequestStateMerged || s == PullRequestStateClosed
+}
+
+// MergeableState is whether a pull request can be merged without conflicts
+type MergeableState string
+
+const (
+	MergeableStateMergeable   MergeableState = "MERGEABLE"
+	MergeableStateConflicting MergeableState = "CONFLICTING"
+	// MergeableStateUnknown means GitHub is still computing it, as right after a push.  Ask again shortly.
+	MergeableStateUnknown MergeableState = "UNKNOWN"
+)
+
+// MergeStateStatus is whether a pull request can be merged right now, and if not what stops it
+type MergeStateStatus string
+
+const (
+	// MergeStateBehind means the head branch is out of date with a base branch requiring up to date branches
+	MergeStateBehind MergeStateStatus = "BEHIND"
+	// MergeStateBlocked means branch protection blocks the merge, for example on missing reviews or checks
+	MergeStateBlocked MergeStateStatus = "BLOCKED"
+	// MergeStateClean means the pull request can be merged
+	MergeStateClean MergeStateStatus = "CLEAN"
+	// MergeStateDirty means the merge commit cannot be created, usually because of conflicts
+	MergeStateDirty MergeStateStatus = "DIRTY"
+	// MergeStateDraft means the pull request is a draft
+	MergeStateDraft MergeStateStatus = "DRAFT"
+	// MergeStateHasHooks means the pull request can be merged and the repository has pre-receive hooks
+	MergeStateHasHooks MergeStateStatus = "HAS_HOOKS"
+	// MergeStateUnknown means GitHub is still computing the state.  Ask again shortly.
+	MergeStateUnknown MergeStateStatus = "UNKNOWN"
+	// MergeStateUnstable means the pull request can be merged but non required checks fail
+	MergeStateUnstable MergeStateStatus = "UNSTABLE"
+)
+
+// CanMerge reports whether merging would succeed now
+func (s MergeStateStatus) CanMerge() bool {
+	return s == MergeStateClean || s == MergeStateHasHooks || s == MergeStateUnstable
+}
+
+// ReviewDecision is where a pull request stands with the reviews required by branch protection
+type ReviewDecision string
+
+const (
+	ReviewDecisionApproved         ReviewDecision = "APPROVED"
+	ReviewDecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	// ReviewDecisionReviewRequired means required reviews are still missing
+	ReviewDecisionReviewRequired ReviewDecision = "REVIEW_REQUIRED"
+)
+
+// IsApproved reports whether the required reviews approve the pull request
+func (d ReviewDecision) IsApproved() bool {
+	return d == ReviewDecisionApproved
+}
@@ -0,0 +1,25 @@
+package gogithub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestState(t *testing.T) {
+	require.True(t, PullRequestStateOpen.IsOpen())
+	require.False(t, PullRequestStateOpen.IsMergedOrClosed())
+	require.True(t, PullRequestStateClosed.IsMergedOrClosed())
+	require.False(t, PullRequestStateClosed.IsMerged())
+	require.True(t, PullRequestStateMerged.IsMerged())
+	require.Equal(t, PullRequestStateOpen, PullRequstOpen)
+}
+
+func TestMergeStateStatus_CanMerge(t *testing.T) {
+	require.True(t, MergeStateClean.CanMerge())
+	require.True(t, MergeStateUnstable.CanMerge())
+	require.False(t, MergeStateBlocked.CanMerge())
+	require.False(t, MergeStateUnknown.CanMerge())
+	require.True(t, ReviewDecisionApproved.IsApproved())
+	require.False(t, ReviewDecision("").IsApproved())
+}
@@ -0,0 +1,258 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// ReleaseTrainConfig configures a ReleaseTrain
+type ReleaseTrainConfig struct {
+	Repo RepoRef
+	// SourceBranch is where release branches are cut from.  Defaults to main.
+	SourceBranch string
+	// TargetBranch is what the release PR merges into, for example production.  It is required.
+	TargetBranch string
+	// BranchPrefix is prepended to the tag to name the release branch.  Defaults to "release/".
+	BranchPrefix string
+	// TagName names the release cut at a time.  Defaults to vYYYY.MM.DD-HHMM in UTC.
+	TagName func(t time.Time) string
+	// RequiredApprovals is how many approving reviews the release PR needs before merging.  0 merges on green checks.
+	RequiredApprovals int
+	// NoChecksExpected merges a release PR whose head commit has no checks.  By default such a PR is pending, since
+	// checks register a moment after it is opened.
+	NoChecksExpected bool
+	// MergeMethod is merge, squash or rebase.  Defaults to merge, so the target branch keeps the released commits.
+	MergeMethod string
+	// PollInterval is how often the release PR is checked while waiting.  Defaults to one minute.
+	PollInterval time.Duration
+	// Timeout bounds the wait for checks and approvals.  Defaults to 24 hours.
+	Timeout time.Duration
+	Logger  *zap.Logger
+}
+
+// ReleaseResult is what one run of the train produced
+type ReleaseResult struct {
+	Tag               string
+	Branch            string
+	PullRequestNumber int64
+	MergeCommitSHA    string
+	ReleaseURL        string
+}
+
+// ErrReleaseChecksFailed is returned when the release PR checks fail
+var ErrReleaseChecksFailed = errors.New("release checks failed")
+
+// ErrNothingToRelease is returned when the source branch has no commits the target branch lacks
+var ErrNothingToRelease = errors.New("nothing to release")
+
+// ReleaseTrain cuts a release branch, opens a release PR with generated notes, waits for checks and approvals, merges
+// it and publishes a GitHub release with a tag on the merge commit
+type ReleaseTrain struct {
+	gh  GitHub
+	cfg ReleaseTrainConfig
+	now func() time.Time
+}
+
+// NewReleaseTrain applies the defaults of cfg.  It fails if cfg has no TargetBranch or an unknown MergeMethod.
+func NewReleaseTrain(gh GitHub, cfg ReleaseTrainConfig) (*ReleaseTrain, error) {
+	if cfg.TargetBranch == "" {
+		return nil, errors.New("release train needs a target branch")
+	}
+	if cfg.SourceBranch == "" {
+		cfg.SourceBranch = "main"
+	}
+	if cfg.BranchPrefix == "" {
+		cfg.BranchPrefix = "release/"
+	}
+	if cfg.TagName == nil {
+		cfg.TagName = func(t time.Time) string {
+			return "v" + t.UTC().Format("2006.01.02-1504")
+		}
+	}
+	switch cfg.MergeMethod {
+	case "":
+		cfg.MergeMethod = "merge"
+	case "merge", "squash", "rebase":
+	default:
+		return nil, fmt.Errorf("invalid merge method %q: want merge, squash or rebase", cfg.MergeMethod)
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 24 * time.Hour
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+	return &ReleaseTrain{
+		gh:  gh,
+		cfg: cfg,
+		now: time.Now,
+	}, nil
+}
+
+func (r *ReleaseTrain) repoPath(format string, args ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", r.cfg.Repo.Owner, r.cfg.Repo.Name) + fmt.Sprintf(format, args...)
+}
+
+// Schedule runs the train every interval until ctx is done.  Errors of a run are logged and do not stop the schedule.
+func (r *ReleaseTrain) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := r.Run(ctx)
+			if err != nil {
+				r.cfg.Logger.Warn("release train failed", zap.Error(err))
+				continue
+			}
+			r.cfg.Logger.Info("released", zap.String("tag", res.Tag), zap.String("url", res.ReleaseURL))
+		}
+	}
+}
+
+// Run sends one train.  It returns ErrNothingToRelease without creating anything if the target branch is up to date.
+func (r *ReleaseTrain) Run(ctx context.Context) (*ReleaseResult, error) {
+	compare, err := r.gh.CompareCommits(ctx, r.cfg.Repo.Owner, r.cfg.Repo.Name, r.cfg.TargetBranch, r.cfg.SourceBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare branches: %w", err)
+	}
+	if compare.AheadBy == 0 {
+		return nil, ErrNothingToRelease
+	}
+	res := &ReleaseResult{Tag: r.cfg.TagName(r.now())}
+	res.Branch = r.cfg.BranchPrefix + res.Tag
+	if err := r.cutBranch(ctx, res.Branch); err != nil {
+		return nil, err
+	}
+	notes, err := r.generateNotes(ctx, res.Tag, res.Branch)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := r.gh.GetRepository(ctx, r.cfg.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	res.PullRequestNumber, err = r.gh.CreatePullRequest(ctx, repo.ID, r.cfg.TargetBranch, res.Branch, "Release "+res.Tag, notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open release PR: %w", err)
+	}
+	if err := r.waitForApproval(ctx, res.PullRequestNumber); err != nil {
+		return res, err
+	}
+	res.MergeCommitSHA, err = r.gh.MergePullRequestWithMethod(ctx, r.cfg.Repo.Owner, r.cfg.Repo.Name, res.PullRequestNumber, githubv4.PullRequestMergeMethod(strings.ToUpper(r.cfg.MergeMethod)))
+	if err != nil {
+		return res, fmt.Errorf("failed to merge release PR: %w", err)
+	}
+	var release struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := r.gh.DoREST(ctx, http.MethodPost, r.repoPath("/releases"), map[string]interface{}{
+		"tag_name":         res.Tag,
+		"target_commitish": res.MergeCommitSHA,
+		"name":             res.Tag,
+		"body":             notes,
+	}, &release); err != nil {
+		return res, fmt.Errorf("failed to publish release: %w", err)
+	}
+	res.ReleaseURL = release.HTMLURL
+	return res, nil
+}
+
+func (r *ReleaseTrain) cutBranch(ctx context.Context, branch string) error {
+	ref, err := r.gh.GetRef(ctx, r.cfg.Repo.Owner, r.cfg.Repo.Name, "heads/"+r.cfg.SourceBranch)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", r.cfg.SourceBranch, err)
+	}
+	if err := r.gh.CreateBranch(ctx, r.cfg.Repo.Owner, r.cfg.Repo.Name, branch, ref.OID); err != nil {
+		return fmt.Errorf("failed to create release branch: %w", err)
+	}
+	return nil
+}
+
+func (r *ReleaseTrain) generateNotes(ctx context.Context, tag string, branch string) (string, error) {
+	body := map[string]string{
+		"tag_name":         tag,
+		"target_commitish": branch,
+	}
+	var latest struct {
+		TagName string `json:"tag_name"`
+	}
+	err := r.gh.DoREST(ctx, http.MethodGet, r.repoPath("/releases/latest"), nil, &latest)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		body["previous_tag_name"] = latest.TagName
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+	default:
+		return "", fmt.Errorf("failed to find the latest release: %w", err)
+	}
+	var notes struct {
+		Body string `json:"body"`
+	}
+	if err := r.gh.DoREST(ctx, http.MethodPost, r.repoPath("/releases/generate-notes"), body, &notes); err != nil {
+		return "", fmt.Errorf("failed to generate release notes: %w", err)
+	}
+	return notes.Body, nil
+}
+
+// waitForApproval polls the release PR until its checks pass and it has enough approvals
+func (r *ReleaseTrain) waitForApproval(ctx context.Context, number int64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+	for {
+		pr, err := r.gh.GetPullRequestFull(ctx, r.cfg.Repo.Owner, r.cfg.Repo.Name, number)
+		if err != nil {
+			return fmt.Errorf("failed to get release PR: %w", err)
+		}
+		ready, err := releaseReady(pr, r.cfg.RequiredApprovals, r.cfg.NoChecksExpected)
+		if err != nil || ready {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting on release PR %d: %w", number, ctx.Err())
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// releaseReady reports whether the release PR can be merged.  A PR without checks is pending unless noChecksExpected.
+func releaseReady(pr *PullRequestFull, requiredApprovals int, noChecksExpected bool) (bool, error) {
+	switch {
+	case pr.CheckRollup == nil:
+		if !noChecksExpected {
+			return false, nil
+		}
+	case pr.CheckRollup.State == "FAILURE" || pr.CheckRollup.State == "ERROR":
+		return false, ErrReleaseChecksFailed
+	case pr.CheckRollup.State != "SUCCESS":
+		return false, nil
+	}
+	approvals := make(map[string]bool)
+	for _, review := range pr.Reviews {
+		// Reviews are oldest first, so the latest deciding review of each author wins
+		switch review.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			approvals[review.Author] = review.State == "APPROVED"
+		}
+	}
+	count := 0
+	for _, approved := range approvals {
+		if approved {
+			count++
+		}
+	}
+	return count >= requiredApprovals, nil
+}
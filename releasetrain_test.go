@@ -0,0 +1,141 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseReady(t *testing.T) {
+	pr := &PullRequestFull{CheckRollup: &CheckRollup{State: "PENDING"}}
+	ready, err := releaseReady(pr, 0, false)
+	require.NoError(t, err)
+	require.False(t, ready)
+
+	pr.CheckRollup.State = "FAILURE"
+	_, err = releaseReady(pr, 0, false)
+	require.ErrorIs(t, err, ErrReleaseChecksFailed)
+
+	pr.CheckRollup.State = "SUCCESS"
+	pr.Reviews = []PullRequestReview{
+		{Author: "alice", State: "APPROVED"},
+		{Author: "alice", State: "COMMENTED"},
+		{Author: "bob", State: "APPROVED"},
+		{Author: "bob", State: "CHANGES_REQUESTED"},
+	}
+	ready, err = releaseReady(pr, 1, false)
+	require.NoError(t, err)
+	require.True(t, ready)
+	ready, err = releaseReady(pr, 2, false)
+	require.NoError(t, err)
+	require.False(t, ready)
+}
+
+func TestReleaseReady_NoChecks(t *testing.T) {
+	pr := &PullRequestFull{}
+	ready, err := releaseReady(pr, 0, false)
+	require.NoError(t, err)
+	require.False(t, ready, "checks may not have registered yet")
+	ready, err = releaseReady(pr, 0, true)
+	require.NoError(t, err)
+	require.True(t, ready)
+}
+
+func TestNewReleaseTrain_Invalid(t *testing.T) {
+	_, err := NewReleaseTrain(nil, ReleaseTrainConfig{Repo: RepoRef{Owner: "o", Name: "r"}})
+	require.ErrorContains(t, err, "target branch")
+	_, err = NewReleaseTrain(nil, ReleaseTrainConfig{Repo: RepoRef{Owner: "o", Name: "r"}, TargetBranch: "production", MergeMethod: "fast-forward"})
+	require.ErrorContains(t, err, "invalid merge method")
+}
+
+func TestReleaseTrain_Run(t *testing.T) {
+	var polls int
+	var steps []string
+	g := newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			steps = append(steps, r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.URL.Path == "/graphql":
+			req := decodeGraphQLRequest(t, r)
+			switch {
+			case strings.Contains(req.Query, "mergePullRequest(input: $input)"):
+				steps = append(steps, "merge")
+				require.Equal(t, "MERGE", req.Variables["input"].(map[string]interface{})["mergeMethod"])
+				_, _ = w.Write([]byte(`{"data":{"mergePullRequest":{"pullRequest":{"id":"PR_5","mergeCommit":{"oid":"merge1"}}}}}`))
+			case strings.Contains(req.Query, "createPullRequest(input: $input)"):
+				steps = append(steps, "create PR")
+				input := req.Variables["input"].(map[string]interface{})
+				require.Equal(t, "production", input["baseRefName"])
+				require.Equal(t, "release/v1", input["headRefName"])
+				_, _ = w.Write([]byte(`{"data":{"createPullRequest":{"pullRequest":{"number":5}}}}`))
+			case strings.Contains(req.Query, "statusCheckRollup"):
+				// No check suite has registered on the first poll
+				polls++
+				rollup := `null`
+				if polls > 1 {
+					rollup = `{"state":"SUCCESS","contexts":{"nodes":[]}}`
+				}
+				_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_5","number":5,"state":"OPEN",
+					"labels":{"nodes":[]},"reviews":{"nodes":[]},"files":{"nodes":[]},"closingIssuesReferences":{"nodes":[]},
+					"commits":{"nodes":[{"commit":{"statusCheckRollup":` + rollup + `}}]}}}}}`))
+			case strings.Contains(req.Query, "pullRequest(number: $number)"):
+				_, _ = w.Write([]byte(`{"data":{"repository":{"pullRequest":{"id":"PR_5"}}}}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":{"repository":{"id":"R_1","defaultBranchRef":{"name":"main","id":"REF_1"}}}}`))
+			}
+		case r.URL.Path == "/repos/o/r/compare/production...main":
+			_, _ = w.Write([]byte(`{"status":"ahead","ahead_by":1,"total_commits":1,"commits":[{"sha":"abc"}]}`))
+		case r.URL.Path == "/repos/o/r/git/ref/heads/main":
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"type":"commit","sha":"abc"}}`))
+		case r.URL.Path == "/repos/o/r/git/refs":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"ref": "refs/heads/release/v1", "sha": "abc"}, body)
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/repos/o/r/releases/latest":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/repos/o/r/releases/generate-notes":
+			_, _ = w.Write([]byte(`{"body":"notes"}`))
+		case r.URL.Path == "/repos/o/r/releases":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "merge1", body["target_commitish"])
+			_, _ = w.Write([]byte(`{"html_url":"https://github.com/o/r/releases/v1"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	train, err := NewReleaseTrain(g, ReleaseTrainConfig{
+		Repo:         RepoRef{Owner: "o", Name: "r"},
+		TargetBranch: "production",
+		TagName:      func(time.Time) string { return "v1" },
+		PollInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+	res, err := train.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &ReleaseResult{
+		Tag:               "v1",
+		Branch:            "release/v1",
+		PullRequestNumber: 5,
+		MergeCommitSHA:    "merge1",
+		ReleaseURL:        "https://github.com/o/r/releases/v1",
+	}, res)
+	require.Equal(t, 2, polls, "the PR is not merged before its checks registered")
+	require.Equal(t, []string{
+		"GET /repos/o/r/compare/production...main",
+		"GET /repos/o/r/git/ref/heads/main",
+		"POST /repos/o/r/git/refs",
+		"GET /repos/o/r/releases/latest",
+		"POST /repos/o/r/releases/generate-notes",
+		"create PR",
+		"merge",
+		"POST /repos/o/r/releases",
+	}, steps)
+}
@@ -0,0 +1,63 @@
+package gogithub
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote is a repository on a GitHub host, as named by a git remote URL
+type Remote struct {
+	RepoRef
+	// Host is the host name without port, such as github.com or ghe.example.com
+	Host string
+}
+
+// IsGitHubCom reports whether the remote is on github.com rather than GitHub Enterprise Server
+func (r Remote) IsGitHubCom() bool {
+	return strings.EqualFold(r.Host, "github.com") || strings.EqualFold(r.Host, "www.github.com")
+}
+
+// BaseURL is the REST API root of the remote host, suitable for WithBaseURL
+func (r Remote) BaseURL() string {
+	if r.IsGitHubCom() {
+		return defaultRESTBaseURL
+	}
+	return "https://" + r.Host + "/api/v3"
+}
+
+// ParseRemote parses a git remote URL: https://host/owner/name.git, ssh://git@host:port/owner/name.git,
+// git@host:owner/name.git or git://host/owner/name.git
+func ParseRemote(remote string) (Remote, error) {
+	s := strings.TrimSpace(remote)
+	var host, path string
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote %q: %w", remote, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax, [user@]host:owner/name
+		i := strings.Index(s, ":")
+		if i < 0 {
+			return Remote{}, fmt.Errorf("invalid remote %q: no host", remote)
+		}
+		host, path = s[:i], s[i+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	}
+	if host == "" {
+		return Remote{}, fmt.Errorf("invalid remote %q: no host", remote)
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Remote{}, fmt.Errorf("invalid remote %q: want owner/name", remote)
+	}
+	return Remote{
+		RepoRef: RepoRef{Owner: parts[0], Name: parts[1]},
+		Host:    strings.ToLower(host),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type repositoryTopics struct {
+	Names []string `json:"names"`
+}
+
+// GetTopics returns the topics of a repository
+func (g *GithubGraphqlAPI) GetTopics(ctx context.Context, owner string, name string) (_ []string, err error) {
+	ctx = withOperation(ctx, "GetTopics")
+	defer annotateError(&err, OperationError{Operation: "GetTopics", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetTopics", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done GetTopics")
+	var ret repositoryTopics
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/topics", owner, name), nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get topics: %w", err)
+	}
+	return ret.Names, nil
+}
+
+// ReplaceTopics sets the topics of a repository to exactly topics, removing the others.  GitHub only accepts lower
+// case topics, so topics are lower cased.
+func (g *GithubGraphqlAPI) ReplaceTopics(ctx context.Context, owner string, name string, topics []string) (err error) {
+	ctx = withOperation(ctx, "ReplaceTopics")
+	defer annotateError(&err, OperationError{Operation: "ReplaceTopics", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ReplaceTopics", zap.String("owner", owner), zap.String("name", name), zap.Strings("topics", topics))
+	defer g.logger(ctx).Debug("Done ReplaceTopics")
+	body := repositoryTopics{Names: make([]string, 0, len(topics))}
+	for _, t := range topics {
+		body.Names = append(body.Names, strings.ToLower(t))
+	}
+	if err := g.doREST(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/%s/topics", owner, name), body, nil); err != nil {
+		return fmt.Errorf("failed to replace topics: %w", err)
+	}
+	return nil
+}
+
+// UpdateRepositoryDescription sets the description of a repository.  An empty description clears it.
+func (g *GithubGraphqlAPI) UpdateRepositoryDescription(ctx context.Context, owner string, name string, description string) (err error) {
+	ctx = withOperation(ctx, "UpdateRepositoryDescription")
+	defer annotateError(&err, OperationError{Operation: "UpdateRepositoryDescription", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateRepositoryDescription", zap.String("owner", owner), zap.String("name", name), zap.String("description", description))
+	defer g.logger(ctx).Debug("Done UpdateRepositoryDescription")
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s", owner, name), map[string]string{"description": description}, nil); err != nil {
+		return fmt.Errorf("failed to update description: %w", err)
+	}
+	return nil
+}
+
+// UpdateRepositoryHomepage sets the homepage URL of a repository.  An empty homepage clears it.
+func (g *GithubGraphqlAPI) UpdateRepositoryHomepage(ctx context.Context, owner string, name string, homepage string) (err error) {
+	ctx = withOperation(ctx, "UpdateRepositoryHomepage")
+	defer annotateError(&err, OperationError{Operation: "UpdateRepositoryHomepage", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateRepositoryHomepage", zap.String("owner", owner), zap.String("name", name), zap.String("homepage", homepage))
+	defer g.logger(ctx).Debug("Done UpdateRepositoryHomepage")
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s", owner, name), map[string]string{"homepage": homepage}, nil); err != nil {
+		return fmt.Errorf("failed to update homepage: %w", err)
+	}
+	return nil
+}
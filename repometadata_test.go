@@ -0,0 +1,42 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopics(t *testing.T) {
+	var put repositoryTopics
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/topics", r.URL.Path)
+		if r.Method == http.MethodPut {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&put))
+		}
+		_, _ = w.Write([]byte(`{"names":["go","github"]}`))
+	})
+	topics, err := g.GetTopics(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, []string{"go", "github"}, topics)
+
+	require.NoError(t, g.ReplaceTopics(context.Background(), "o", "r", []string{"Go", "team-platform"}))
+	require.Equal(t, []string{"go", "team-platform"}, put.Names)
+}
+
+func TestUpdateRepositoryMetadata(t *testing.T) {
+	var bodies []map[string]string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/repos/o/r", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	require.NoError(t, g.UpdateRepositoryDescription(context.Background(), "o", "r", "Payments API"))
+	require.NoError(t, g.UpdateRepositoryHomepage(context.Background(), "o", "r", ""))
+	require.Equal(t, []map[string]string{{"description": "Payments API"}, {"homepage": ""}}, bodies)
+}
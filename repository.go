@@ -0,0 +1,104 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// Repository is a repository with the identifiers most operations need
+type Repository struct {
+	RepoRef
+	ID            githubv4.ID
+	DefaultBranch string
+}
+
+// ParseRepoRef parses "owner/name" or any git remote URL ParseRemote accepts
+func ParseRepoRef(s string) (RepoRef, error) {
+	if strings.Contains(s, ":") {
+		remote, err := ParseRemote(s)
+		if err != nil {
+			return RepoRef{}, err
+		}
+		return remote.RepoRef, nil
+	}
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return RepoRef{}, fmt.Errorf("invalid repository %q: want owner/name", s)
+	}
+	return RepoRef{Owner: parts[0], Name: parts[1]}, nil
+}
+
+// ToRepository combines the queried info with the owner and name it was queried for
+func (r *RepositoryInfo) ToRepository(owner string, name string) *Repository {
+	return &Repository{
+		RepoRef:       RepoRef{Owner: owner, Name: name},
+		ID:            r.Repository.ID,
+		DefaultBranch: string(r.Repository.DefaultBranchRef.Name),
+	}
+}
+
+// GetRepository returns the ID and default branch of ref.  It shares the RepositoryInfo cache.
+func (g *GithubGraphqlAPI) GetRepository(ctx context.Context, ref RepoRef) (*Repository, error) {
+	info, err := g.RepositoryInfo(ctx, ref.Owner, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	return info.ToRepository(ref.Owner, ref.Name), nil
+}
+
+type forkRepositoryBody struct {
+	Organization string `json:"organization,omitempty"`
+}
+
+type restRepository struct {
+	NodeID string `json:"node_id"`
+	Name   string `json:"name"`
+	Owner  struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// ForkRepository forks owner/name into the organization intoOrg, or into the account of the client when intoOrg is
+// empty, and returns the fork.  Forking an already forked repository returns the existing fork.  GitHub creates the
+// fork in the background, so ForkRepository waits, with the AcceptedBackoff of the client, until the default branch of
+// the fork can be read.
+func (g *GithubGraphqlAPI) ForkRepository(ctx context.Context, owner string, name string, intoOrg string) (_ *Repository, err error) {
+	ctx = withOperation(ctx, "ForkRepository")
+	defer annotateError(&err, OperationError{Operation: "ForkRepository", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ForkRepository", zap.String("owner", owner), zap.String("name", name), zap.String("intoOrg", intoOrg))
+	defer g.logger(ctx).Debug("Done ForkRepository")
+	var ret restRepository
+	if err := g.doRESTAccepted(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/forks", owner, name), forkRepositoryBody{Organization: intoOrg}, &ret); err != nil {
+		return nil, fmt.Errorf("failed to fork repository: %w", err)
+	}
+	if err := g.waitForFork(ctx, ret); err != nil {
+		return nil, fmt.Errorf("failed to wait for fork %s/%s: %w", ret.Owner.Login, ret.Name, err)
+	}
+	return &Repository{
+		RepoRef:       RepoRef{Owner: ret.Owner.Login, Name: ret.Name},
+		ID:            githubv4.ID(ret.NodeID),
+		DefaultBranch: ret.DefaultBranch,
+	}, nil
+}
+
+// waitForFork polls the default branch of fork until GitHub finished copying it
+func (g *GithubGraphqlAPI) waitForFork(ctx context.Context, fork restRepository) error {
+	path := fmt.Sprintf("/repos/%s/%s/branches/%s", fork.Owner.Login, fork.Name, url.PathEscape(fork.DefaultBranch))
+	return g.acceptedBackoff.retry(ctx, func(ctx context.Context) (bool, error) {
+		err := g.doREST(ctx, http.MethodGet, path, nil, nil)
+		var restErr *RESTError
+		if err == nil || !errors.As(err, &restErr) || restErr.StatusCode != http.StatusNotFound {
+			return true, err
+		}
+		g.logger(ctx).Debug("fork not ready yet, retrying")
+		return false, nil
+	})
+}
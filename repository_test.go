@@ -0,0 +1,126 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoRef(t *testing.T) {
+	want := RepoRef{Owner: "cresta", Name: "gogithub"}
+	for _, s := range []string{
+		"cresta/gogithub",
+		"https://github.com/cresta/gogithub",
+		"https://github.com/cresta/gogithub.git",
+		"git@github.com:cresta/gogithub.git",
+		"ssh://git@ghe.example.com/cresta/gogithub.git",
+	} {
+		got, err := ParseRepoRef(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"", "gogithub", "https://github.com/cresta", "a/b/c"} {
+		_, err := ParseRepoRef(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestRepositoryInfo_ToRepository(t *testing.T) {
+	info := &RepositoryInfo{}
+	info.Repository.ID = "R_1"
+	info.Repository.DefaultBranchRef.Name = "main"
+	require.Equal(t, &Repository{RepoRef: RepoRef{Owner: "o", Name: "r"}, ID: "R_1", DefaultBranch: "main"}, info.ToRepository("o", "r"))
+}
+
+func TestForkRepository(t *testing.T) {
+	var polls int
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/cresta/gogithub/forks":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"organization": "bots"}, body)
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"node_id":"R_fork","name":"gogithub","owner":{"login":"bots"},"default_branch":"main"}`))
+		case "GET /repos/bots/gogithub/branches/main":
+			// The fork is still being copied for the first two polls
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Branch not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"main"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	g.acceptedBackoff = AcceptedBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 5}
+	fork, err := g.ForkRepository(context.Background(), "cresta", "gogithub", "bots")
+	require.NoError(t, err)
+	require.Equal(t, &Repository{RepoRef: RepoRef{Owner: "bots", Name: "gogithub"}, ID: "R_fork", DefaultBranch: "main"}, fork)
+	require.Equal(t, 3, polls)
+}
+
+func TestForkRepository_NeverReady(t *testing.T) {
+	var polls int
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"node_id":"R_fork","name":"gogithub","owner":{"login":"me"},"default_branch":"main"}`))
+			return
+		}
+		polls++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	g.acceptedBackoff = AcceptedBackoff{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 3}
+	_, err := g.ForkRepository(context.Background(), "cresta", "gogithub", "")
+	require.ErrorIs(t, err, ErrStillComputing)
+	require.ErrorContains(t, err, "failed to wait for fork me/gogithub")
+	require.Equal(t, 3, polls)
+}
+
+// newCreatePRClient answers createPullRequest mutations with PR 7 and stores the input of the last one in input
+func newCreatePRClient(t *testing.T, input *map[string]interface{}) *GithubGraphqlAPI {
+	return newTestGraphQLClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := decodeGraphQLRequest(t, r)
+		require.Contains(t, req.Query, "createPullRequest(input: $input)")
+		*input = req.Variables["input"].(map[string]interface{})
+		_, _ = w.Write([]byte(`{"data":{"createPullRequest":{"pullRequest":{"number":7}}}}`))
+	})
+}
+
+func TestCreatePullRequest_OwnerQualifiedHead(t *testing.T) {
+	var input map[string]interface{}
+	g := newCreatePRClient(t, &input)
+	n, err := g.CreatePullRequest(context.Background(), "R_upstream", "main", "bots:fix-typo", "Fix typo", "body")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+	require.Equal(t, "bots:fix-typo", input["headRefName"])
+	require.Equal(t, "R_upstream", input["repositoryId"])
+	require.NotContains(t, input, "headRepositoryId")
+}
+
+func TestCreateForkPullRequest(t *testing.T) {
+	var input map[string]interface{}
+	g := newCreatePRClient(t, &input)
+	g.findPrCache.DefaultExpiry = time.Hour
+	g.findPrCache.Set(findPrKey{owner: "cresta", name: "gogithub", branch: "fix-typo"}, findPrValue{})
+	n, err := g.CreateForkPullRequest(context.Background(), "R_upstream", "R_fork", "main", "fix-typo", "Fix typo", "body")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+	require.Equal(t, map[string]interface{}{
+		"repositoryId":     "R_upstream",
+		"headRepositoryId": "R_fork",
+		"baseRefName":      "main",
+		"headRefName":      "fix-typo",
+		"title":            "Fix typo",
+		"body":             "body",
+	}, input)
+	_, cached := g.findPrCache.Get(findPrKey{owner: "cresta", name: "gogithub", branch: "fix-typo"})
+	require.False(t, cached, "creating a pull request clears the pull request cache")
+}
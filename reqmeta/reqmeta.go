@@ -0,0 +1,167 @@
+// Package reqmeta holds the request metadata gogithub reads from a context.  Transports, hooks and client methods all
+// use these accessors, so cross-cutting features compose instead of each inventing its own context keys.
+package reqmeta
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type key int
+
+const (
+	requestIDKey key = iota
+	actorKey
+	dryRunKey
+	loggerKey
+	operationKey
+	cacheControlKey
+	priorityKey
+	fieldsKey
+)
+
+// CacheMode selects how a call uses the client's lookup caches
+type CacheMode int
+
+const (
+	// CacheDefault serves cached results and caches fresh ones
+	CacheDefault CacheMode = iota
+	// CacheBypass ignores the cache: the call always queries GitHub and its result is not stored
+	CacheBypass
+	// CacheRefresh always queries GitHub and replaces the cached result, invalidating what was cached
+	CacheRefresh
+)
+
+// Priority ranks calls competing for the client's concurrency slots
+type Priority int
+
+const (
+	// PriorityBackground is for bulk work such as syncs and sweeps, which waits for every other call
+	PriorityBackground Priority = -1
+	// PriorityDefault is the priority of calls that set none
+	PriorityDefault Priority = 0
+	// PriorityInteractive is for user facing lookups, which go before every other call
+	PriorityInteractive Priority = 1
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityInteractive:
+		return "interactive"
+	default:
+		return "default"
+	}
+}
+
+// WithRequestID tags ctx with the caller's request ID, used to correlate GitHub calls with application logs
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID of ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey).(string)
+	return v
+}
+
+// WithActor tags ctx with who, or what, a call is made on behalf of
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor of ctx, or "" if there is none
+func Actor(ctx context.Context) string {
+	v, _ := ctx.Value(actorKey).(string)
+	return v
+}
+
+// WithDryRun marks ctx as a dry run.  Operations that honor it report what they would change without changing it.
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return context.WithValue(ctx, dryRunKey, dryRun)
+}
+
+// IsDryRun reports whether ctx is marked as a dry run
+func IsDryRun(ctx context.Context) bool {
+	v, _ := ctx.Value(dryRunKey).(bool)
+	return v
+}
+
+// WithLogger attaches a request scoped logger to ctx
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Logger returns the logger of ctx, or fallback if there is none
+func Logger(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if v, ok := ctx.Value(loggerKey).(*zap.Logger); ok && v != nil {
+		return v
+	}
+	return fallback
+}
+
+// WithOperation tags ctx with the client method issuing a request
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey, operation)
+}
+
+// Operation returns the operation of ctx, or "unknown" if there is none
+func Operation(ctx context.Context) string {
+	if v, ok := ctx.Value(operationKey).(string); ok {
+		return v
+	}
+	return "unknown"
+}
+
+// WithCacheControl sets how calls made with ctx use cached lookups
+func WithCacheControl(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheControlKey, mode)
+}
+
+// CacheControl returns the cache mode of ctx, or CacheDefault if there is none
+func CacheControl(ctx context.Context) CacheMode {
+	v, _ := ctx.Value(cacheControlKey).(CacheMode)
+	return v
+}
+
+// WithPriority sets the priority of calls made with ctx
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey, priority)
+}
+
+// RequestPriority returns the priority of ctx, or PriorityDefault if there is none
+func RequestPriority(ctx context.Context) Priority {
+	v, _ := ctx.Value(priorityKey).(Priority)
+	return v
+}
+
+// WithFields adds fields, such as a tenant or a job name, to the log fields of ctx
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	prev, _ := ctx.Value(fieldsKey).([]zap.Field)
+	all := make([]zap.Field, 0, len(prev)+len(fields))
+	all = append(append(all, prev...), fields...)
+	return context.WithValue(ctx, fieldsKey, all)
+}
+
+// Fields returns the metadata of ctx as log fields
+func Fields(ctx context.Context) []zap.Field {
+	var ret []zap.Field
+	if v := RequestID(ctx); v != "" {
+		ret = append(ret, zap.String("request_id", v))
+	}
+	if v := Actor(ctx); v != "" {
+		ret = append(ret, zap.String("actor", v))
+	}
+	if IsDryRun(ctx) {
+		ret = append(ret, zap.Bool("dry_run", true))
+	}
+	if v := RequestPriority(ctx); v != PriorityDefault {
+		ret = append(ret, zap.Stringer("priority", v))
+	}
+	if v, ok := ctx.Value(fieldsKey).([]zap.Field); ok {
+		ret = append(ret, v...)
+	}
+	return ret
+}
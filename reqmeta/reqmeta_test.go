@@ -0,0 +1,53 @@
+package reqmeta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMetadata(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, "", RequestID(ctx))
+	require.Equal(t, "unknown", Operation(ctx))
+	require.False(t, IsDryRun(ctx))
+	require.Empty(t, Fields(ctx))
+
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithActor(ctx, "bot")
+	ctx = WithDryRun(ctx, true)
+	ctx = WithOperation(ctx, "Self")
+	require.Equal(t, "req-1", RequestID(ctx))
+	require.Equal(t, "bot", Actor(ctx))
+	require.True(t, IsDryRun(ctx))
+	require.Equal(t, "Self", Operation(ctx))
+	require.Len(t, Fields(ctx), 3)
+}
+
+func TestFields(t *testing.T) {
+	ctx := WithFields(context.Background(), zap.String("job", "sync"))
+	ctx = WithFields(ctx, zap.Int("attempt", 2))
+	ctx = WithRequestID(ctx, "req-1")
+	require.Equal(t, []zap.Field{zap.String("request_id", "req-1"), zap.String("job", "sync"), zap.Int("attempt", 2)}, Fields(ctx))
+}
+
+func TestCacheControl(t *testing.T) {
+	require.Equal(t, CacheDefault, CacheControl(context.Background()))
+	require.Equal(t, CacheRefresh, CacheControl(WithCacheControl(context.Background(), CacheRefresh)))
+}
+
+func TestPriority(t *testing.T) {
+	require.Equal(t, PriorityDefault, RequestPriority(context.Background()))
+	ctx := WithPriority(context.Background(), PriorityBackground)
+	require.Equal(t, PriorityBackground, RequestPriority(ctx))
+	require.Len(t, Fields(ctx), 1)
+}
+
+func TestLogger(t *testing.T) {
+	fallback := zap.NewNop()
+	require.Same(t, fallback, Logger(context.Background(), fallback))
+	l := zap.NewNop()
+	require.Same(t, l, Logger(WithLogger(context.Background(), l), fallback))
+}
@@ -0,0 +1,52 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"go.uber.org/zap"
+)
+
+// DefaultRequestIDHeader is the header outbound requests carry the reqmeta.RequestID of their context in
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDTransport sets Header to the reqmeta.RequestID of the requests that have one, so GitHub API activity,
+// and any proxy in front of GitHub, can be correlated with the application request that caused it
+type RequestIDTransport struct {
+	Base   http.RoundTripper
+	Header string
+}
+
+var _ http.RoundTripper = &RequestIDTransport{}
+
+func (r *RequestIDTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	requestID := reqmeta.RequestID(request.Context())
+	if requestID == "" || request.Header.Get(r.Header) != "" {
+		return r.Base.RoundTrip(request)
+	}
+	request = request.Clone(request.Context())
+	request.Header.Set(r.Header, requestID)
+	return r.Base.RoundTrip(request)
+}
+
+// RequestIDHeaderTransport wraps base so its requests carry their request ID in header.  It returns base unchanged if
+// header is empty.
+func RequestIDHeaderTransport(base http.RoundTripper, header string) http.RoundTripper {
+	if header == "" {
+		return base
+	}
+	return &RequestIDTransport{
+		Base:   base,
+		Header: header,
+	}
+}
+
+// logger returns the logger of ctx, or the client's, with the reqmeta fields of ctx
+func (g *GithubGraphqlAPI) logger(ctx context.Context) *zap.Logger {
+	logger := reqmeta.Logger(ctx, g.Logger)
+	if fields := reqmeta.Fields(ctx); len(fields) > 0 {
+		return logger.With(fields...)
+	}
+	return logger
+}
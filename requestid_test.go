@@ -0,0 +1,47 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRequestIDHeader(t *testing.T) {
+	var headers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("X-Correlation-ID"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	gh, err := NewGQLClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{
+		Token:           "t",
+		BaseURL:         srv.URL,
+		Rt:              srv.Client().Transport,
+		RequestIDHeader: "X-Correlation-ID",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, gh.DoREST(reqmeta.WithRequestID(context.Background(), "req-1"), http.MethodGet, "/user", nil, nil))
+	require.NoError(t, gh.DoREST(context.Background(), http.MethodGet, "/user", nil, nil))
+	require.Equal(t, []string{"req-1", ""}, headers)
+}
+
+func TestRequestIDHeaderTransport_Default(t *testing.T) {
+	var header string
+	rt := RequestIDHeaderTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		header = req.Header.Get(DefaultRequestIDHeader)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), DefaultRequestIDHeader)
+	req, err := http.NewRequestWithContext(reqmeta.WithRequestID(context.Background(), "req-2"), http.MethodGet, "https://api.github.com/user", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "req-2", header)
+	require.Empty(t, req.Header.Get(DefaultRequestIDHeader))
+}
@@ -0,0 +1,160 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// RequiredStatusCheck is a check or status context a branch requires before merging
+type RequiredStatusCheck struct {
+	Context string
+	// AppID, if set, is the GitHub App the check must come from
+	AppID int64
+}
+
+// RequiredStatusChecks are the checks required to merge into a branch, from branch protection and rulesets combined
+type RequiredStatusChecks struct {
+	// Strict requires the head branch to be up to date with the base branch
+	Strict bool
+	Checks []RequiredStatusCheck
+}
+
+// BlockingCheck is a required check that keeps a pull request from merging
+type BlockingCheck struct {
+	Context string
+	// State is the state of the check on the head commit, such as FAILURE or PENDING, or empty if it never reported
+	State string
+}
+
+type restRequiredStatusChecks struct {
+	Strict bool `json:"strict"`
+	Checks []struct {
+		Context string `json:"context"`
+		AppID   *int64 `json:"app_id"`
+	} `json:"checks"`
+}
+
+type restBranchRule struct {
+	Type       string `json:"type"`
+	Parameters struct {
+		RequiredStatusChecks []struct {
+			Context       string `json:"context"`
+			IntegrationID int64  `json:"integration_id"`
+		} `json:"required_status_checks"`
+		StrictRequiredStatusChecksPolicy bool `json:"strict_required_status_checks_policy"`
+	} `json:"parameters"`
+}
+
+// GetRequiredStatusChecks returns the checks required to merge into baseBranch, from both classic branch protection
+// and the active rulesets of the branch.  A branch without any is not an error.
+func (g *GithubGraphqlAPI) GetRequiredStatusChecks(ctx context.Context, owner string, name string, baseBranch string) (_ *RequiredStatusChecks, err error) {
+	ctx = withOperation(ctx, "GetRequiredStatusChecks")
+	defer annotateError(&err, OperationError{Operation: "GetRequiredStatusChecks", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetRequiredStatusChecks", zap.String("owner", owner), zap.String("name", name), zap.String("baseBranch", baseBranch))
+	defer g.logger(ctx).Debug("Done GetRequiredStatusChecks")
+	ret := &RequiredStatusChecks{}
+	seen := make(map[string]struct{})
+	add := func(check RequiredStatusCheck) {
+		if _, ok := seen[check.Context]; ok {
+			return
+		}
+		seen[check.Context] = struct{}{}
+		ret.Checks = append(ret.Checks, check)
+	}
+
+	var protection restRequiredStatusChecks
+	err = g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/branches/%s/protection/required_status_checks", owner, name, escapePath(baseBranch)), nil, &protection)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		ret.Strict = protection.Strict
+		for _, c := range protection.Checks {
+			check := RequiredStatusCheck{Context: c.Context}
+			if c.AppID != nil {
+				check.AppID = *c.AppID
+			}
+			add(check)
+		}
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		// The branch is not protected, or requires no status checks
+	default:
+		return nil, fmt.Errorf("failed to get branch protection of %s: %w", baseBranch, err)
+	}
+
+	rules, err := newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[restBranchRule], error) {
+		var rules []restBranchRule
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/rules/branches/%s?per_page=100&page=%d", owner, name, escapePath(baseBranch), restPageNumber(cursor)), nil, &rules); err != nil {
+			return Page[restBranchRule]{}, fmt.Errorf("failed to get rules of %s: %w", baseBranch, err)
+		}
+		return restPage(rules, cursor, 100), nil
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		if r.Type != RuleRequiredStatusChecks {
+			continue
+		}
+		ret.Strict = ret.Strict || r.Parameters.StrictRequiredStatusChecksPolicy
+		for _, c := range r.Parameters.RequiredStatusChecks {
+			add(RequiredStatusCheck{Context: c.Context, AppID: c.IntegrationID})
+		}
+	}
+	return ret, nil
+}
+
+// IsPullRequestBlockedBy returns the required checks of the base branch that are missing, pending or failing on the
+// head commit of a pull request: the reasons MergePullRequest would be refused.  An empty result means the required
+// checks pass.  It does not check that the head branch is up to date, reviews, or which app reported a check.
+func (g *GithubGraphqlAPI) IsPullRequestBlockedBy(ctx context.Context, owner string, name string, number int64) (_ []BlockingCheck, err error) {
+	ctx = withOperation(ctx, "IsPullRequestBlockedBy")
+	defer annotateError(&err, OperationError{Operation: "IsPullRequestBlockedBy", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("IsPullRequestBlockedBy", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done IsPullRequestBlockedBy")
+	pr, err := g.GetPullRequestFull(ctx, owner, name, number)
+	if err != nil {
+		return nil, err
+	}
+	required, err := g.GetRequiredStatusChecks(ctx, owner, name, pr.BaseRefName)
+	if err != nil {
+		return nil, err
+	}
+	return blockingChecks(required, pr.CheckRollup), nil
+}
+
+// blockingChecks diffs the required checks against a check rollup, in the order they are required
+func blockingChecks(required *RequiredStatusChecks, rollup *CheckRollup) []BlockingCheck {
+	states := make(map[string]string)
+	if rollup != nil {
+		for _, c := range rollup.Contexts {
+			// A check can run more than once, as on a re-run; any passing run satisfies the requirement
+			if checkStatePasses(states[c.Name]) {
+				continue
+			}
+			states[c.Name] = c.State
+		}
+	}
+	var ret []BlockingCheck
+	for _, check := range required.Checks {
+		state := states[check.Context]
+		if !checkStatePasses(state) {
+			ret = append(ret, BlockingCheck{Context: check.Context, State: state})
+		}
+	}
+	return ret
+}
+
+// checkStatePasses is whether branch protection accepts a check in state: neutral and skipped check runs count as
+// passing
+func checkStatePasses(state string) bool {
+	switch state {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRequiredStatusChecks(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/branches/main/protection/required_status_checks":
+			_, _ = w.Write([]byte(`{"strict":false,"checks":[{"context":"build","app_id":15368},{"context":"lint","app_id":null}]}`))
+		case "/repos/o/r/rules/branches/main":
+			_, _ = w.Write([]byte(`[{"type":"deletion"},{"type":"required_status_checks","parameters":{"strict_required_status_checks_policy":true,"required_status_checks":[{"context":"lint"},{"context":"deploy-gate","integration_id":7}]}}]`))
+		case "/repos/o/r/branches/dev/protection/required_status_checks":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Branch not protected"}`))
+		case "/repos/o/r/rules/branches/dev":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Fatalf("unexpected %s", r.URL.Path)
+		}
+	})
+	checks, err := g.GetRequiredStatusChecks(context.Background(), "o", "r", "main")
+	require.NoError(t, err)
+	require.Equal(t, &RequiredStatusChecks{
+		Strict: true,
+		Checks: []RequiredStatusCheck{{Context: "build", AppID: 15368}, {Context: "lint"}, {Context: "deploy-gate", AppID: 7}},
+	}, checks)
+
+	checks, err = g.GetRequiredStatusChecks(context.Background(), "o", "r", "dev")
+	require.NoError(t, err)
+	require.Empty(t, checks.Checks)
+}
+
+func TestBlockingChecks(t *testing.T) {
+	required := &RequiredStatusChecks{Checks: []RequiredStatusCheck{{Context: "build"}, {Context: "lint"}, {Context: "docs"}, {Context: "deploy-gate"}}}
+	rollup := &CheckRollup{Contexts: []CheckContext{
+		{Kind: "CheckRun", Name: "build", State: "FAILURE"},
+		{Kind: "CheckRun", Name: "build", State: "SUCCESS"},
+		{Kind: "StatusContext", Name: "lint", State: "PENDING"},
+		{Kind: "CheckRun", Name: "docs", State: "SKIPPED"},
+	}}
+	require.Equal(t, []BlockingCheck{{Context: "lint", State: "PENDING"}, {Context: "deploy-gate"}}, blockingChecks(required, rollup))
+	require.Equal(t, []BlockingCheck{{Context: "build"}}, blockingChecks(&RequiredStatusChecks{Checks: []RequiredStatusCheck{{Context: "build"}}}, nil))
+	require.Empty(t, blockingChecks(&RequiredStatusChecks{}, rollup))
+}
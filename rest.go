@@ -0,0 +1,261 @@
+package gogithub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const defaultRESTBaseURL = "https://api.github.com"
+
+// maxErrorBodySize caps how much of an error response body is read when building a RESTError
+const maxErrorBodySize = 64 * 1024
+
+// RESTError is returned when a REST v3 call gets a non 2xx response.  It carries GitHub's request ID so failures can be
+// quoted when filing support tickets.
+type RESTError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	// RequestID is the X-GitHub-Request-Id header of the failed response
+	RequestID string
+	// Message is the "message" field of GitHub's error body
+	Message string
+	// Errors is the "errors" array of GitHub's error body, if any
+	Errors           []RESTErrorDetail
+	DocumentationURL string
+}
+
+// RESTErrorDetail is a single entry of the "errors" array GitHub returns on validation failures
+type RESTErrorDetail struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// UnmarshalJSON accepts both the object form and the plain string form GitHub sometimes uses for errors
+func (d *RESTErrorDetail) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		d.Message = s
+		return nil
+	}
+	type plain RESTErrorDetail
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+	*d = RESTErrorDetail(p)
+	return nil
+}
+
+func (d RESTErrorDetail) String() string {
+	if d.Message != "" {
+		return d.Message
+	}
+	return fmt.Sprintf("%s.%s: %s", d.Resource, d.Field, d.Code)
+}
+
+func (e *RESTError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s: %s", e.Method, e.URL, e.Status)
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+	if len(e.Errors) > 0 {
+		details := make([]string, 0, len(e.Errors))
+		for _, d := range e.Errors {
+			details = append(details, d.String())
+		}
+		fmt.Fprintf(&sb, " [%s]", strings.Join(details, "; "))
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " (request id %s)", e.RequestID)
+	}
+	return sb.String()
+}
+
+// newRESTError builds a RESTError from a failed response.  It consumes, but does not close, the body.
+func newRESTError(resp *http.Response) *RESTError {
+	ret := &RESTError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-GitHub-Request-Id"),
+	}
+	if resp.Request != nil {
+		ret.Method = resp.Request.Method
+		ret.URL = resp.Request.URL.String()
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil || len(b) == 0 {
+		return ret
+	}
+	var body struct {
+		Message          string            `json:"message"`
+		Errors           []RESTErrorDetail `json:"errors"`
+		DocumentationURL string            `json:"documentation_url"`
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		ret.Message = strings.TrimSpace(string(b))
+		return ret
+	}
+	ret.Message = body.Message
+	ret.Errors = body.Errors
+	ret.DocumentationURL = body.DocumentationURL
+	return ret
+}
+
+// restURL resolves path against the REST base URL.  Absolute URLs, such as the ones GitHub returns in Link headers
+// and *_url fields, are used as is.
+func (g *GithubGraphqlAPI) restURL(path string) string {
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		return path
+	}
+	base := g.restBaseURL
+	if base == "" {
+		base = defaultRESTBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// DoREST sends an arbitrary REST v3 request through the client's authenticated transport, for endpoints this package
+// does not wrap yet.  path is relative to the API root (for example /repos/cresta/gogithub/topics).  body, if not nil,
+// is JSON encoded and the response is JSON decoded into out, if not nil.  Non 2xx responses are returned as *RESTError.
+func (g *GithubGraphqlAPI) DoREST(ctx context.Context, method string, path string, body interface{}, out interface{}) (err error) {
+	ctx = withOperation(ctx, "DoREST")
+	defer annotateError(&err, OperationError{Operation: "DoREST"})
+	g.logger(ctx).Debug("DoREST", zap.String("method", method), zap.String("path", path))
+	defer g.logger(ctx).Debug("Done DoREST")
+	return g.doREST(ctx, method, path, body, out)
+}
+
+// doREST sends a REST v3 request to path with body JSON encoded (if not nil) and decodes the response into out
+// (if not nil).  Any non 2xx response is returned as a *RESTError.
+func (g *GithubGraphqlAPI) doREST(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	_, err := g.sendREST(ctx, method, path, body, out)
+	return err
+}
+
+// newRESTRequest builds an authenticated REST v3 request with body JSON encoded, if not nil
+func (g *GithubGraphqlAPI) newRESTRequest(ctx context.Context, method string, path string, body interface{}) (*http.Request, error) {
+	token, err := g.GetAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		encodedBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encodedBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.restURL(path), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	return req, nil
+}
+
+// sendREST is doREST but also returns the response status code.  A 202 Accepted response is not decoded into out,
+// since GitHub only sends it while the real result is still being computed.
+func (g *GithubGraphqlAPI) sendREST(ctx context.Context, method string, path string, body interface{}, out interface{}) (int, error) {
+	return g.sendRESTDecoding(ctx, method, path, body, out, false)
+}
+
+// doRESTAccepted is doREST for endpoints, such as forks, that start work in the background and describe its result
+// in their 202 Accepted response
+func (g *GithubGraphqlAPI) doRESTAccepted(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	_, err := g.sendRESTDecoding(ctx, method, path, body, out, true)
+	return err
+}
+
+// sendRESTDecoding is sendREST, also decoding 202 Accepted responses when decodeAccepted is set
+func (g *GithubGraphqlAPI) sendRESTDecoding(ctx context.Context, method string, path string, body interface{}, out interface{}, decodeAccepted bool) (int, error) {
+	status, _, err := g.sendRESTHeader(ctx, method, path, body, out, decodeAccepted)
+	return status, err
+}
+
+// sendRESTHeader is sendRESTDecoding, also returning the response headers
+func (g *GithubGraphqlAPI) sendRESTHeader(ctx context.Context, method string, path string, body interface{}, out interface{}, decodeAccepted bool) (int, http.Header, error) {
+	req, err := g.newRESTRequest(ctx, method, path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := g.HttpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, resp.Header, newRESTError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent || (resp.StatusCode == http.StatusAccepted && !decodeAccepted) {
+		return resp.StatusCode, resp.Header, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, resp.Header, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return resp.StatusCode, resp.Header, nil
+}
+
+// doRESTLinkPage GETs one page of a REST list paged through Link headers and returns the URL of the next page, or
+// empty on the last page
+func (g *GithubGraphqlAPI) doRESTLinkPage(ctx context.Context, path string, out interface{}) (string, error) {
+	_, header, err := g.sendRESTHeader(ctx, http.MethodGet, path, nil, out, false)
+	if err != nil {
+		return "", err
+	}
+	return nextLink(header.Get("Link")), nil
+}
+
+// nextLink returns the rel="next" URL of a Link header
+func nextLink(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+			}
+		}
+	}
+	return ""
+}
+
+// doRESTRaw sends a body-less REST v3 request asking for the accept media type and returns the raw response body
+func (g *GithubGraphqlAPI) doRESTRaw(ctx context.Context, method string, path string, accept string) ([]byte, error) {
+	req, err := g.newRESTRequest(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := g.HttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newRESTError(resp)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,90 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestRESTClient(t *testing.T, handler http.HandlerFunc) *GithubGraphqlAPI {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	g := createGraphqlAPI(nil, srv.Client(), zaptest.NewLogger(t), 0, func(_ context.Context) (string, error) {
+		return "test-token", nil
+	})
+	g.restBaseURL = srv.URL
+	return g
+}
+
+// newTestGraphQLClient is newTestRESTClient for GraphQL calls too.  GraphQL requests are sent to /graphql.
+func newTestGraphQLClient(t *testing.T, handler http.HandlerFunc) *GithubGraphqlAPI {
+	g := newTestRESTClient(t, handler)
+	g.ClientV4 = githubv4.NewEnterpriseClient(g.restBaseURL+"/graphql", g.HttpClient)
+	return g
+}
+
+// graphqlTestRequest is a GraphQL request as received by a test server
+type graphqlTestRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func decodeGraphQLRequest(t *testing.T, r *http.Request) graphqlTestRequest {
+	require.Equal(t, "/graphql", r.URL.Path)
+	var req graphqlTestRequest
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	return req
+}
+
+func TestDoREST_Error(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-GitHub-Request-Id", "ABCD:1234")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"Validation Failed","errors":[{"resource":"Issue","field":"title","code":"missing_field"},"plain error"],"documentation_url":"https://docs.github.com"}`))
+	})
+	err := g.doREST(context.Background(), http.MethodPost, "/repos/o/r/issues", map[string]string{}, nil)
+	var restErr *RESTError
+	require.True(t, errors.As(err, &restErr))
+	require.Equal(t, http.StatusUnprocessableEntity, restErr.StatusCode)
+	require.Equal(t, "ABCD:1234", restErr.RequestID)
+	require.Equal(t, "Validation Failed", restErr.Message)
+	require.Len(t, restErr.Errors, 2)
+	require.Equal(t, "missing_field", restErr.Errors[0].Code)
+	require.Equal(t, "plain error", restErr.Errors[1].Message)
+	require.Contains(t, restErr.Error(), "request id ABCD:1234")
+}
+
+func TestDoREST_Success(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"name":"gogithub"}`))
+	})
+	var out struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, g.doREST(context.Background(), http.MethodGet, "/repos/cresta/gogithub", nil, &out))
+	require.Equal(t, "gogithub", out.Name)
+}
+
+func TestDoREST_AbsoluteURL(t *testing.T) {
+	var gotPath string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+	require.NoError(t, g.DoREST(context.Background(), http.MethodDelete, g.restBaseURL+"/repos/o/r/hooks/1", nil, nil))
+	require.Equal(t, "/repos/o/r/hooks/1", gotPath)
+}
+
+func TestNextLink(t *testing.T) {
+	require.Equal(t, "https://api.github.com/orgs/o/dependabot/alerts?after=abc", nextLink(`<https://api.github.com/orgs/o/dependabot/alerts?before=xyz>; rel="prev", <https://api.github.com/orgs/o/dependabot/alerts?after=abc>; rel="next"`))
+	require.Equal(t, "", nextLink(`<https://api.github.com/orgs/o/dependabot/alerts?before=xyz>; rel="prev"`))
+	require.Equal(t, "", nextLink(""))
+}
@@ -0,0 +1,353 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// ReviewComment is an inline comment on a line, or range of lines, of a pull request diff
+type ReviewComment struct {
+	Path string
+	// Line is the last line of the commented range in the diff
+	Line int
+	// StartLine is the first line of a multi-line comment.  0 comments on Line only.
+	StartLine int
+	// Side is which side of the diff Line refers to.  Defaults to RIGHT, the new version of the file.
+	Side githubv4.DiffSide
+	Body string
+}
+
+// ReviewInput is a full review submitted at once by CreateReview
+type ReviewInput struct {
+	Body string
+	// Event is APPROVE, REQUEST_CHANGES or COMMENT.  Defaults to COMMENT.
+	Event    githubv4.PullRequestReviewEvent
+	Comments []ReviewComment
+}
+
+func (c ReviewComment) toThread() *githubv4.DraftPullRequestReviewThread {
+	side := c.Side
+	if side == "" {
+		side = githubv4.DiffSideRight
+	}
+	ret := &githubv4.DraftPullRequestReviewThread{
+		Path: githubv4.String(c.Path),
+		Line: githubv4.Int(c.Line),
+		Side: &side,
+		Body: githubv4.String(c.Body),
+	}
+	if c.StartLine != 0 && c.StartLine != c.Line {
+		ret.StartLine = githubv4.NewInt(githubv4.Int(c.StartLine))
+		ret.StartSide = &side
+	}
+	return ret
+}
+
+// CreateReview submits a review with all its inline comments as a single review, so the author gets one notification
+func (g *GithubGraphqlAPI) CreateReview(ctx context.Context, owner string, name string, number int64, review ReviewInput) (_ githubv4.ID, err error) {
+	ctx = withOperation(ctx, "CreateReview")
+	defer annotateError(&err, OperationError{Operation: "CreateReview", Owner: owner, Repo: name, Number: number})
+	prid, err := g.FindPullRequestOid(ctx, owner, name, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find PR: %w", err)
+	}
+	g.logger(ctx).Debug("CreateReview", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Any("prid", prid), zap.Int("comments", len(review.Comments)))
+	defer g.logger(ctx).Debug("Done CreateReview")
+	event := review.Event
+	if event == "" {
+		event = githubv4.PullRequestReviewEventComment
+	}
+	input := githubv4.AddPullRequestReviewInput{
+		PullRequestID: prid,
+		Event:         &event,
+	}
+	if review.Body != "" {
+		input.Body = githubv4.NewString(githubv4.String(review.Body))
+	}
+	if len(review.Comments) > 0 {
+		threads := make([]*githubv4.DraftPullRequestReviewThread, 0, len(review.Comments))
+		for _, c := range review.Comments {
+			threads = append(threads, c.toThread())
+		}
+		input.Threads = &threads
+	}
+	var ret struct {
+		AddPullRequestReview struct {
+			PullRequestReview struct {
+				ID githubv4.ID
+			}
+		} `graphql:"addPullRequestReview(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, input, nil); err != nil {
+		return nil, fmt.Errorf("unable to add PR review: %w", err)
+	}
+	return ret.AddPullRequestReview.PullRequestReview.ID, nil
+}
+
+// AddReviewComment adds a single inline comment on line of path
+func (g *GithubGraphqlAPI) AddReviewComment(ctx context.Context, owner string, name string, number int64, path string, line int, side githubv4.DiffSide, body string) error {
+	_, err := g.CreateReview(ctx, owner, name, number, ReviewInput{
+		Comments: []ReviewComment{
+			{
+				Path: path,
+				Line: line,
+				Side: side,
+				Body: body,
+			},
+		},
+	})
+	return err
+}
+
+// ReviewThread is a conversation on a line of a pull request diff
+type ReviewThread struct {
+	ID         githubv4.ID
+	IsResolved bool
+	IsOutdated bool
+	Path       string
+	Line       int
+	// ResolvedBy is the login of who resolved the thread, if it is resolved
+	ResolvedBy string
+	Comments   []ReviewThreadComment
+}
+
+type ReviewThreadComment struct {
+	ID        githubv4.ID
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	URL       string
+}
+
+const reviewThreadCommentsPerThread = 50
+
+type reviewThreadNode struct {
+	ID         githubv4.ID
+	IsResolved bool
+	IsOutdated bool
+	Path       string
+	Line       int
+	ResolvedBy struct {
+		Login string
+	}
+	Comments struct {
+		Nodes []struct {
+			ID     githubv4.ID
+			Author struct {
+				Login string
+			}
+			Body      string
+			CreatedAt githubv4.DateTime
+			URL       string `graphql:"url"`
+		}
+	} `graphql:"comments(first: $commentsPerThread)"`
+}
+
+func (n reviewThreadNode) toReviewThread() ReviewThread {
+	ret := ReviewThread{
+		ID:         n.ID,
+		IsResolved: n.IsResolved,
+		IsOutdated: n.IsOutdated,
+		Path:       n.Path,
+		Line:       n.Line,
+		ResolvedBy: n.ResolvedBy.Login,
+	}
+	for _, c := range n.Comments.Nodes {
+		ret.Comments = append(ret.Comments, ReviewThreadComment{
+			ID:        c.ID,
+			Author:    c.Author.Login,
+			Body:      c.Body,
+			CreatedAt: c.CreatedAt.Time,
+			URL:       c.URL,
+		})
+	}
+	return ret
+}
+
+// ListReviewThreads returns every review thread of a pull request with its first comments
+func (g *GithubGraphqlAPI) ListReviewThreads(ctx context.Context, owner string, name string, number int64) (_ []ReviewThread, err error) {
+	ctx = withOperation(ctx, "ListReviewThreads")
+	defer annotateError(&err, OperationError{Operation: "ListReviewThreads", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("ListReviewThreads", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done ListReviewThreads")
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes    []reviewThreadNode
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+				} `graphql:"reviewThreads(first: 100, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":             githubv4.String(owner),
+		"name":              githubv4.String(name),
+		"number":            githubv4.Int(number),
+		"commentsPerThread": githubv4.Int(reviewThreadCommentsPerThread),
+		"cursor":            (*githubv4.String)(nil),
+	}
+	var ret []ReviewThread
+	for {
+		if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query for review threads: %w", err)
+		}
+		for _, n := range query.Repository.PullRequest.ReviewThreads.Nodes {
+			ret = append(ret, n.toReviewThread())
+		}
+		if !query.Repository.PullRequest.ReviewThreads.PageInfo.HasNextPage {
+			return ret, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Repository.PullRequest.ReviewThreads.PageInfo.EndCursor)
+	}
+}
+
+// ResolveReviewThread marks a review thread as resolved
+func (g *GithubGraphqlAPI) ResolveReviewThread(ctx context.Context, threadID githubv4.ID) (err error) {
+	ctx = withOperation(ctx, "ResolveReviewThread")
+	defer annotateError(&err, OperationError{Operation: "ResolveReviewThread"})
+	g.logger(ctx).Debug("ResolveReviewThread", zap.Any("threadID", threadID))
+	defer g.logger(ctx).Debug("Done ResolveReviewThread")
+	var ret struct {
+		ResolveReviewThread struct {
+			Thread struct {
+				ID githubv4.ID
+			}
+		} `graphql:"resolveReviewThread(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.ResolveReviewThreadInput{
+		ThreadID: threadID,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to resolve review thread: %w", err)
+	}
+	return nil
+}
+
+// UnresolveReviewThread reopens a resolved review thread
+func (g *GithubGraphqlAPI) UnresolveReviewThread(ctx context.Context, threadID githubv4.ID) (err error) {
+	ctx = withOperation(ctx, "UnresolveReviewThread")
+	defer annotateError(&err, OperationError{Operation: "UnresolveReviewThread"})
+	g.logger(ctx).Debug("UnresolveReviewThread", zap.Any("threadID", threadID))
+	defer g.logger(ctx).Debug("Done UnresolveReviewThread")
+	var ret struct {
+		UnresolveReviewThread struct {
+			Thread struct {
+				ID githubv4.ID
+			}
+		} `graphql:"unresolveReviewThread(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.UnresolveReviewThreadInput{
+		ThreadID: threadID,
+	}, nil); err != nil {
+		return fmt.Errorf("unable to unresolve review thread: %w", err)
+	}
+	return nil
+}
+
+// ListReviews returns every review of a pull request, oldest first
+func (g *GithubGraphqlAPI) ListReviews(ctx context.Context, owner string, name string, number int64) (_ []PullRequestReview, err error) {
+	ctx = withOperation(ctx, "ListReviews")
+	defer annotateError(&err, OperationError{Operation: "ListReviews", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("ListReviews", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number))
+	defer g.logger(ctx).Debug("Done ListReviews")
+	var query struct {
+		Repository struct {
+			PullRequest struct {
+				ID      githubv4.ID
+				Reviews struct {
+					Nodes []struct {
+						ID     githubv4.ID
+						Author struct {
+							Login string
+						}
+						State       string
+						Body        string
+						SubmittedAt githubv4.DateTime
+						Commit      struct {
+							Oid string
+						}
+					}
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+				} `graphql:"reviews(first: 100, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+		"cursor": (*githubv4.String)(nil),
+	}
+	var ret []PullRequestReview
+	for {
+		if err := g.ClientV4.Query(ctx, &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to query for reviews: %w", err)
+		}
+		if query.Repository.PullRequest.ID == nil {
+			return nil, fmt.Errorf("failed to find PR %d", number)
+		}
+		for _, r := range query.Repository.PullRequest.Reviews.Nodes {
+			ret = append(ret, PullRequestReview{
+				ID:          r.ID,
+				Author:      r.Author.Login,
+				State:       r.State,
+				Body:        r.Body,
+				SubmittedAt: r.SubmittedAt.Time,
+				CommitOid:   r.Commit.Oid,
+			})
+		}
+		if !query.Repository.PullRequest.Reviews.PageInfo.HasNextPage {
+			return ret, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Repository.PullRequest.Reviews.PageInfo.EndCursor)
+	}
+}
+
+// DismissReview dismisses an approving or change requesting review, leaving message as the reason
+func (g *GithubGraphqlAPI) DismissReview(ctx context.Context, reviewID githubv4.ID, message string) (err error) {
+	ctx = withOperation(ctx, "DismissReview")
+	defer annotateError(&err, OperationError{Operation: "DismissReview"})
+	defer g.clearPRCache()
+	g.logger(ctx).Debug("DismissReview", zap.Any("reviewID", reviewID))
+	defer g.logger(ctx).Debug("Done DismissReview")
+	var ret struct {
+		DismissPullRequestReview struct {
+			PullRequestReview struct {
+				ID githubv4.ID
+			}
+		} `graphql:"dismissPullRequestReview(input: $input)"`
+	}
+	if err := g.ClientV4.Mutate(ctx, &ret, githubv4.DismissPullRequestReviewInput{
+		PullRequestReviewID: reviewID,
+		Message:             githubv4.String(message),
+	}, nil); err != nil {
+		return fmt.Errorf("unable to dismiss review: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers asks users for a review.  Requesting a user who already reviewed re-requests their review.
+func (g *GithubGraphqlAPI) RequestReviewers(ctx context.Context, owner string, name string, number int64, logins []string) (err error) {
+	ctx = withOperation(ctx, "RequestReviewers")
+	defer annotateError(&err, OperationError{Operation: "RequestReviewers", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("RequestReviewers", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.Strings("logins", logins))
+	defer g.logger(ctx).Debug("Done RequestReviewers")
+	body := map[string]interface{}{
+		"reviewers": logins,
+	}
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", owner, name, number), body, nil); err != nil {
+		return fmt.Errorf("unable to request reviewers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestReviewers(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/repos/o/r/pulls/7/requested_reviewers", r.URL.Path)
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, []string{"alice"}, body.Reviewers)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	require.NoError(t, g.RequestReviewers(context.Background(), "o", "r", 7, []string{"alice"}))
+}
+
+// reviewResponses answers addPullRequestReview with the review it created
+var reviewResponses = map[string]string{"addPullRequestReview": `{"pullRequestReview":{"id":"PRR_1"}}`}
+
+func TestCreateReview(t *testing.T) {
+	g, mutations := newMutationClient(t, "", reviewResponses)
+	id, err := g.CreateReview(context.Background(), "o", "r", 1, ReviewInput{
+		Body:  "Looks good",
+		Event: githubv4.PullRequestReviewEventApprove,
+		Comments: []ReviewComment{
+			{Path: "main.go", Line: 12, Body: "nit"},
+			{Path: "old.go", Line: 8, StartLine: 5, Side: githubv4.DiffSideLeft, Body: "why remove this?"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, githubv4.ID("PRR_1"), id)
+	require.Equal(t, []recordedMutation{{
+		Name: "addPullRequestReview",
+		Input: map[string]interface{}{
+			"pullRequestId": "PR_1",
+			"body":          "Looks good",
+			"event":         "APPROVE",
+			"threads": []interface{}{
+				map[string]interface{}{"path": "main.go", "line": float64(12), "side": "RIGHT", "body": "nit"},
+				map[string]interface{}{"path": "old.go", "line": float64(8), "side": "LEFT", "startLine": float64(5), "startSide": "LEFT", "body": "why remove this?"},
+			},
+		},
+	}}, *mutations)
+}
+
+func TestAddReviewComment(t *testing.T) {
+	g, mutations := newMutationClient(t, "", reviewResponses)
+	require.NoError(t, g.AddReviewComment(context.Background(), "o", "r", 1, "main.go", 3, "", "typo"))
+	// A single comment is a review with the default COMMENT event and no body
+	require.Equal(t, []recordedMutation{{
+		Name: "addPullRequestReview",
+		Input: map[string]interface{}{
+			"pullRequestId": "PR_1",
+			"event":         "COMMENT",
+			"threads": []interface{}{
+				map[string]interface{}{"path": "main.go", "line": float64(3), "side": "RIGHT", "body": "typo"},
+			},
+		},
+	}}, *mutations)
+}
+
+func TestCreateReview_Error(t *testing.T) {
+	g, _ := newPRMutationClient(t, "addPullRequestReview")
+	_, err := g.CreateReview(context.Background(), "o", "r", 1, ReviewInput{Body: "x"})
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestListReviewThreads(t *testing.T) {
+	g, requests := newPagedGraphQLClient(t, "reviewThreads(first: 100, after: $cursor)", map[string]string{
+		"": `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[{"id":"RT_1","isResolved":true,"isOutdated":false,"path":"main.go","line":12,"resolvedBy":{"login":"alice"},
+				"comments":{"nodes":[{"id":"C_1","author":{"login":"bob"},"body":"nit","createdAt":"2024-05-01T10:00:00Z","url":"https://github.com/o/r/pull/1#discussion_r1"}]}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"T1"}}}}}}`,
+		"T1": `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[{"id":"RT_2","isResolved":false,"isOutdated":true,"path":"old.go","line":3,"resolvedBy":null,"comments":{"nodes":[]}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"T2"}}}}}}`,
+	})
+	threads, err := g.ListReviewThreads(context.Background(), "o", "r", 1)
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+	require.Equal(t, githubv4.ID("RT_1"), threads[0].ID)
+	require.True(t, threads[0].IsResolved)
+	require.Equal(t, "alice", threads[0].ResolvedBy)
+	require.Equal(t, "main.go", threads[0].Path)
+	require.Equal(t, 12, threads[0].Line)
+	require.Len(t, threads[0].Comments, 1)
+	require.Equal(t, "bob", threads[0].Comments[0].Author)
+	require.Equal(t, "nit", threads[0].Comments[0].Body)
+	require.Equal(t, "https://github.com/o/r/pull/1#discussion_r1", threads[0].Comments[0].URL)
+	require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), threads[0].Comments[0].CreatedAt)
+	require.Equal(t, githubv4.ID("RT_2"), threads[1].ID)
+	require.True(t, threads[1].IsOutdated)
+	require.Empty(t, threads[1].ResolvedBy)
+	require.Empty(t, threads[1].Comments)
+
+	// The second request follows the end cursor of the first page
+	require.Len(t, *requests, 2)
+	require.Nil(t, (*requests)[0].Variables["cursor"])
+	require.Equal(t, "T1", (*requests)[1].Variables["cursor"])
+	require.Equal(t, float64(reviewThreadCommentsPerThread), (*requests)[1].Variables["commentsPerThread"])
+	require.Equal(t, float64(1), (*requests)[1].Variables["number"])
+}
+
+func TestResolveAndUnresolveReviewThread(t *testing.T) {
+	g, mutations := newMutationClient(t, "", map[string]string{
+		"resolveReviewThread":   `{"thread":{"id":"RT_1"}}`,
+		"unresolveReviewThread": `{"thread":{"id":"RT_1"}}`,
+	})
+	require.NoError(t, g.ResolveReviewThread(context.Background(), "RT_1"))
+	require.NoError(t, g.UnresolveReviewThread(context.Background(), "RT_1"))
+	require.Equal(t, []recordedMutation{
+		{Name: "resolveReviewThread", Input: map[string]interface{}{"threadId": "RT_1"}},
+		{Name: "unresolveReviewThread", Input: map[string]interface{}{"threadId": "RT_1"}},
+	}, *mutations)
+
+	g, _ = newMutationClient(t, "resolveReviewThread", nil)
+	require.ErrorContains(t, g.ResolveReviewThread(context.Background(), "RT_1"), "boom")
+}
@@ -0,0 +1,204 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// RulesetTarget is what a ruleset applies to
+type RulesetTarget string
+
+const (
+	RulesetTargetBranch RulesetTarget = "branch"
+	RulesetTargetTag    RulesetTarget = "tag"
+	// RulesetTargetPush applies to every push, whatever the ref, and takes push restriction rules
+	RulesetTargetPush RulesetTarget = "push"
+)
+
+// RulesetEnforcement is whether a ruleset is enforced
+type RulesetEnforcement string
+
+const (
+	RulesetEnforcementActive   RulesetEnforcement = "active"
+	RulesetEnforcementDisabled RulesetEnforcement = "disabled"
+	// RulesetEnforcementEvaluate reports what the ruleset would block without blocking it.  It needs GitHub Enterprise.
+	RulesetEnforcementEvaluate RulesetEnforcement = "evaluate"
+)
+
+// Rule types of RulesetRule
+const (
+	RuleCreation              = "creation"
+	RuleUpdate                = "update"
+	RuleDeletion              = "deletion"
+	RuleRequiredLinearHistory = "required_linear_history"
+	RuleRequiredSignatures    = "required_signatures"
+	RuleNonFastForward        = "non_fast_forward"
+	// RulePullRequest takes parameters like required_approving_review_count
+	RulePullRequest = "pull_request"
+	// RuleRequiredStatusChecks takes required_status_checks, a list of {"context": name}
+	RuleRequiredStatusChecks = "required_status_checks"
+	// RuleWorkflows requires workflows to pass.  It takes workflows, a list of {"repository_id", "path", "ref"}.
+	RuleWorkflows = "workflows"
+	// RuleFilePathRestriction is a push rule taking restricted_file_paths
+	RuleFilePathRestriction = "file_path_restriction"
+	// RuleMaxFileSize is a push rule taking max_file_size, in MB
+	RuleMaxFileSize = "max_file_size"
+)
+
+// RulesetRule is one rule of a ruleset.  Parameters depend on Type; see the GitHub rulesets documentation.
+type RulesetRule struct {
+	Type       string                 `json:"type"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// RulesetRefCondition selects the refs a branch or tag ruleset applies to.  Patterns are fnmatch globs over full ref
+// names, such as refs/heads/release/*, or ~DEFAULT_BRANCH and ~ALL.
+type RulesetRefCondition struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// RulesetConditions selects what a ruleset applies to
+type RulesetConditions struct {
+	RefName *RulesetRefCondition `json:"ref_name,omitempty"`
+}
+
+// RulesetBypassActor can bypass a ruleset
+type RulesetBypassActor struct {
+	// ActorID is the ID of the team, integration or role.  It is ignored for OrganizationAdmin.
+	ActorID int64 `json:"actor_id,omitempty"`
+	// ActorType is Team, Integration, RepositoryRole, OrganizationAdmin or DeployKey
+	ActorType string `json:"actor_type"`
+	// BypassMode is always, or pull_request to only bypass through pull requests
+	BypassMode string `json:"bypass_mode"`
+}
+
+// RulesetInput is the configuration CreateRepositoryRuleset and UpdateRuleset apply.  UpdateRuleset replaces every
+// field, so Rules and BypassActors missing from it are removed.
+type RulesetInput struct {
+	Name         string               `json:"name"`
+	Target       RulesetTarget        `json:"target"`
+	Enforcement  RulesetEnforcement   `json:"enforcement"`
+	Conditions   *RulesetConditions   `json:"conditions,omitempty"`
+	Rules        []RulesetRule        `json:"rules"`
+	BypassActors []RulesetBypassActor `json:"bypass_actors"`
+}
+
+// Ruleset is a set of rules on the refs, or pushes, of a repository
+type Ruleset struct {
+	ID          int64
+	Name        string
+	Target      RulesetTarget
+	Enforcement RulesetEnforcement
+	// SourceType is Repository, or Organization for rulesets inherited from the org
+	SourceType string
+	// Source is the repository or org the ruleset is defined on
+	Source string
+	// Conditions, Rules and BypassActors are not set by ListRulesets; use GetRuleset
+	Conditions   *RulesetConditions
+	Rules        []RulesetRule
+	BypassActors []RulesetBypassActor
+}
+
+type rulesetJSON struct {
+	ID           int64                `json:"id"`
+	Name         string               `json:"name"`
+	Target       RulesetTarget        `json:"target"`
+	Enforcement  RulesetEnforcement   `json:"enforcement"`
+	SourceType   string               `json:"source_type"`
+	Source       string               `json:"source"`
+	Conditions   *RulesetConditions   `json:"conditions"`
+	Rules        []RulesetRule        `json:"rules"`
+	BypassActors []RulesetBypassActor `json:"bypass_actors"`
+}
+
+func (r *rulesetJSON) toRuleset() Ruleset {
+	return Ruleset{
+		ID:           r.ID,
+		Name:         r.Name,
+		Target:       r.Target,
+		Enforcement:  r.Enforcement,
+		SourceType:   r.SourceType,
+		Source:       r.Source,
+		Conditions:   r.Conditions,
+		Rules:        r.Rules,
+		BypassActors: r.BypassActors,
+	}
+}
+
+// ListRulesets returns the rulesets of a repository, including the ones inherited from its org when
+// includeParents is set
+func (g *GithubGraphqlAPI) ListRulesets(ctx context.Context, owner string, name string, includeParents bool) ([]Ruleset, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[Ruleset], err error) {
+		ctx = withOperation(ctx, "ListRulesets")
+		defer annotateError(&err, OperationError{Operation: "ListRulesets", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListRulesets", zap.String("owner", owner), zap.String("name", name), zap.Bool("includeParents", includeParents), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListRulesets")
+		var resp []rulesetJSON
+		path := fmt.Sprintf("/repos/%s/%s/rulesets?includes_parents=%t&per_page=100&page=%d", owner, name, includeParents, restPageNumber(cursor))
+		if err := g.doREST(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			return Page[Ruleset]{}, fmt.Errorf("failed to list rulesets: %w", err)
+		}
+		items := make([]Ruleset, 0, len(resp))
+		for i := range resp {
+			items = append(items, resp[i].toRuleset())
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// GetRuleset returns a ruleset of a repository with its conditions, rules and bypass actors
+func (g *GithubGraphqlAPI) GetRuleset(ctx context.Context, owner string, name string, id int64) (_ *Ruleset, err error) {
+	ctx = withOperation(ctx, "GetRuleset")
+	defer annotateError(&err, OperationError{Operation: "GetRuleset", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetRuleset", zap.String("owner", owner), zap.String("name", name), zap.Int64("id", id))
+	defer g.logger(ctx).Debug("Done GetRuleset")
+	var resp rulesetJSON
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/rulesets/%d", owner, name, id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get ruleset %d: %w", id, err)
+	}
+	ret := resp.toRuleset()
+	return &ret, nil
+}
+
+// CreateRepositoryRuleset creates a ruleset on a repository
+func (g *GithubGraphqlAPI) CreateRepositoryRuleset(ctx context.Context, owner string, name string, input RulesetInput) (_ *Ruleset, err error) {
+	ctx = withOperation(ctx, "CreateRepositoryRuleset")
+	defer annotateError(&err, OperationError{Operation: "CreateRepositoryRuleset", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateRepositoryRuleset", zap.String("owner", owner), zap.String("name", name), zap.String("ruleset", input.Name))
+	defer g.logger(ctx).Debug("Done CreateRepositoryRuleset")
+	var resp rulesetJSON
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/rulesets", owner, name), input.body(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to create ruleset: %w", err)
+	}
+	ret := resp.toRuleset()
+	return &ret, nil
+}
+
+// UpdateRuleset replaces the configuration of a ruleset of a repository by input
+func (g *GithubGraphqlAPI) UpdateRuleset(ctx context.Context, owner string, name string, id int64, input RulesetInput) (_ *Ruleset, err error) {
+	ctx = withOperation(ctx, "UpdateRuleset")
+	defer annotateError(&err, OperationError{Operation: "UpdateRuleset", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateRuleset", zap.String("owner", owner), zap.String("name", name), zap.Int64("id", id))
+	defer g.logger(ctx).Debug("Done UpdateRuleset")
+	var resp rulesetJSON
+	if err := g.doREST(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/%s/rulesets/%d", owner, name, id), input.body(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to update ruleset %d: %w", id, err)
+	}
+	ret := resp.toRuleset()
+	return &ret, nil
+}
+
+// body sends empty lists rather than null, which GitHub rejects
+func (in RulesetInput) body() RulesetInput {
+	if in.Rules == nil {
+		in.Rules = []RulesetRule{}
+	}
+	if in.BypassActors == nil {
+		in.BypassActors = []RulesetBypassActor{}
+	}
+	return in
+}
@@ -0,0 +1,55 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesets(t *testing.T) {
+	var created map[string]interface{}
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/rulesets":
+			require.Equal(t, "true", r.URL.Query().Get("includes_parents"))
+			_, _ = w.Write([]byte(`[{"id":1,"name":"main","target":"branch","enforcement":"active","source_type":"Organization","source":"o"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/rulesets":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"id":2,"name":"release","target":"branch","enforcement":"evaluate","conditions":{"ref_name":{"include":["refs/heads/release/*"],"exclude":[]}},"rules":[{"type":"deletion"},{"type":"pull_request","parameters":{"required_approving_review_count":2}}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/o/r/rulesets/2":
+			_, _ = w.Write([]byte(`{"id":2,"name":"release","target":"branch","enforcement":"active"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	list, err := g.ListRulesets(ctx, "o", "r", true)
+	require.NoError(t, err)
+	require.Equal(t, []Ruleset{{ID: 1, Name: "main", Target: RulesetTargetBranch, Enforcement: RulesetEnforcementActive, SourceType: "Organization", Source: "o"}}, list)
+
+	input := RulesetInput{
+		Name:        "release",
+		Target:      RulesetTargetBranch,
+		Enforcement: RulesetEnforcementEvaluate,
+		Conditions:  &RulesetConditions{RefName: &RulesetRefCondition{Include: []string{"refs/heads/release/*"}, Exclude: []string{}}},
+		Rules: []RulesetRule{
+			{Type: RuleDeletion},
+			{Type: RulePullRequest, Parameters: map[string]interface{}{"required_approving_review_count": 2}},
+		},
+	}
+	rs, err := g.CreateRepositoryRuleset(ctx, "o", "r", input)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rs.ID)
+	require.Equal(t, []string{"refs/heads/release/*"}, rs.Conditions.RefName.Include)
+	require.Len(t, rs.Rules, 2)
+	require.Equal(t, float64(2), rs.Rules[1].Parameters["required_approving_review_count"])
+	require.Equal(t, []interface{}{}, created["bypass_actors"])
+
+	input.Enforcement = RulesetEnforcementActive
+	rs, err = g.UpdateRuleset(ctx, "o", "r", 2, input)
+	require.NoError(t, err)
+	require.Equal(t, RulesetEnforcementActive, rs.Enforcement)
+}
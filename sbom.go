@@ -0,0 +1,80 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SBOMExternalRef links an SBOM package to an identifier outside the document, such as a package URL
+type SBOMExternalRef struct {
+	// ReferenceCategory is for example PACKAGE-MANAGER or SECURITY
+	ReferenceCategory string `json:"referenceCategory"`
+	// ReferenceType is for example purl
+	ReferenceType    string `json:"referenceType"`
+	ReferenceLocator string `json:"referenceLocator"`
+}
+
+// SBOMPackage is a package of an SPDX SBOM, either the repository itself or one of its dependencies
+type SBOMPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	Supplier         string            `json:"supplier"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []SBOMExternalRef `json:"externalRefs"`
+}
+
+// PackageURL returns the purl of the package, for example pkg:golang/go.uber.org/zap@v1.27.0, or empty if it has none
+func (p SBOMPackage) PackageURL() string {
+	for _, r := range p.ExternalRefs {
+		if r.ReferenceType == "purl" {
+			return r.ReferenceLocator
+		}
+	}
+	return ""
+}
+
+// SBOMRelationship relates two elements of an SBOM, for example a repository that DEPENDS_ON a package
+type SBOMRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+	RelationshipType   string `json:"relationshipType"`
+}
+
+// SBOM is the SPDX software bill of materials GitHub builds from the dependency graph of a repository
+type SBOM struct {
+	SPDXID            string `json:"SPDXID"`
+	SPDXVersion       string `json:"spdxVersion"`
+	DataLicense       string `json:"dataLicense"`
+	Name              string `json:"name"`
+	DocumentNamespace string `json:"documentNamespace"`
+	CreationInfo      struct {
+		Created  time.Time `json:"created"`
+		Creators []string  `json:"creators"`
+	} `json:"creationInfo"`
+	Packages      []SBOMPackage      `json:"packages"`
+	Relationships []SBOMRelationship `json:"relationships"`
+}
+
+// ExportSBOM returns the SPDX SBOM of the dependency graph of a repository's default branch
+func (g *GithubGraphqlAPI) ExportSBOM(ctx context.Context, owner string, name string) (_ *SBOM, err error) {
+	ctx = withOperation(ctx, "ExportSBOM")
+	defer annotateError(&err, OperationError{Operation: "ExportSBOM", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ExportSBOM", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done ExportSBOM")
+	var resp struct {
+		SBOM SBOM `json:"sbom"`
+	}
+	if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/dependency-graph/sbom", owner, name), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to export SBOM: %w", err)
+	}
+	return &resp.SBOM, nil
+}
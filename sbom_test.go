@@ -0,0 +1,31 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSBOM(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repos/o/r/dependency-graph/sbom" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"sbom":{"SPDXID":"SPDXRef-DOCUMENT","spdxVersion":"SPDX-2.3","name":"com.github.o/r",
+			"creationInfo":{"created":"2024-01-02T03:04:05Z","creators":["Tool: GitHub.com-Dependency-Graph"]},
+			"packages":[{"SPDXID":"SPDXRef-go-zap","name":"go.uber.org/zap","versionInfo":"1.27.0","licenseConcluded":"MIT",
+				"externalRefs":[{"referenceCategory":"PACKAGE-MANAGER","referenceType":"purl","referenceLocator":"pkg:golang/go.uber.org/zap@1.27.0"}]}],
+			"relationships":[{"spdxElementId":"SPDXRef-DOCUMENT","relatedSpdxElement":"SPDXRef-go-zap","relationshipType":"DEPENDS_ON"}]}}`))
+	})
+	sbom, err := g.ExportSBOM(context.Background(), "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, "SPDX-2.3", sbom.SPDXVersion)
+	require.Equal(t, 2024, sbom.CreationInfo.Created.Year())
+	require.Len(t, sbom.Packages, 1)
+	require.Equal(t, "MIT", sbom.Packages[0].LicenseConcluded)
+	require.Equal(t, "pkg:golang/go.uber.org/zap@1.27.0", sbom.Packages[0].PackageURL())
+	require.Equal(t, "", SBOMPackage{}.PackageURL())
+	require.Equal(t, []SBOMRelationship{{SPDXElementID: "SPDXRef-DOCUMENT", RelatedSPDXElement: "SPDXRef-go-zap", RelationshipType: "DEPENDS_ON"}}, sbom.Relationships)
+}
@@ -0,0 +1,215 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CodeScanningAlertFilter narrows ListCodeScanningAlerts.  The zero value lists every alert on the default branch.
+type CodeScanningAlertFilter struct {
+	// State is open, closed, dismissed or fixed
+	State string
+	// Severity is critical, high, medium, low, warning, note or error
+	Severity string
+	// ToolName is for example CodeQL
+	ToolName string
+	// Ref is a branch (refs/heads/main) or pull request (refs/pull/42/merge).  Not supported for organizations.
+	Ref string
+}
+
+// CodeScanningAlert is a problem found by a code scanning tool such as CodeQL
+type CodeScanningAlert struct {
+	Number int64  `json:"number"`
+	State  string `json:"state"`
+	// Repository is the full name (owner/name) of the repository of the alert
+	Repository string `json:"-"`
+	Rule       struct {
+		ID                    string `json:"id"`
+		Name                  string `json:"name"`
+		Description           string `json:"description"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+	} `json:"rule"`
+	Tool struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"tool"`
+	MostRecentInstance struct {
+		Ref      string `json:"ref"`
+		State    string `json:"state"`
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+			EndLine   int    `json:"end_line"`
+		} `json:"location"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+	} `json:"most_recent_instance"`
+	HTMLURL          string     `json:"html_url"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DismissedAt      *time.Time `json:"dismissed_at"`
+	DismissedReason  string     `json:"dismissed_reason"`
+	DismissedComment string     `json:"dismissed_comment"`
+	FixedAt          *time.Time `json:"fixed_at"`
+}
+
+type restCodeScanningAlert struct {
+	CodeScanningAlert
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// CodeScanningDismissReason is why a code scanning alert is dismissed
+type CodeScanningDismissReason string
+
+const (
+	CodeScanningDismissFalsePositive CodeScanningDismissReason = "false positive"
+	CodeScanningDismissWontFix       CodeScanningDismissReason = "won't fix"
+	CodeScanningDismissUsedInTests   CodeScanningDismissReason = "used in tests"
+)
+
+// CodeScanningAlertUpdate reopens or dismisses a code scanning alert
+type CodeScanningAlertUpdate struct {
+	// State is open or dismissed
+	State string `json:"state"`
+	// DismissedReason is required when State is dismissed
+	DismissedReason  CodeScanningDismissReason `json:"dismissed_reason,omitempty"`
+	DismissedComment string                    `json:"dismissed_comment,omitempty"`
+}
+
+// SecretScanningAlertFilter narrows ListSecretScanningAlerts.  The zero value lists every alert.
+type SecretScanningAlertFilter struct {
+	// State is open or resolved
+	State string
+	// SecretTypes are for example github_personal_access_token or aws_access_key_id
+	SecretTypes []string
+	// Resolutions are false_positive, wont_fix, revoked, pattern_edited, pattern_deleted or used_in_tests
+	Resolutions []string
+}
+
+// SecretScanningAlert is a secret found committed to a repository.  The secret itself is left out.
+type SecretScanningAlert struct {
+	Number int64  `json:"number"`
+	State  string `json:"state"`
+	// Repository is the full name (owner/name) of the repository of the alert
+	Repository            string     `json:"-"`
+	SecretType            string     `json:"secret_type"`
+	SecretTypeDisplayName string     `json:"secret_type_display_name"`
+	Resolution            string     `json:"resolution"`
+	ResolvedAt            *time.Time `json:"resolved_at"`
+	ResolvedBy            *struct {
+		Login string `json:"login"`
+	} `json:"resolved_by"`
+	PushProtectionBypassed bool      `json:"push_protection_bypassed"`
+	HTMLURL                string    `json:"html_url"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+type restSecretScanningAlert struct {
+	SecretScanningAlert
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// alertsPath is the REST path of the alerts of kind (code-scanning, secret-scanning) of a repository, or of an
+// organization if name is empty
+func alertsPath(owner string, name string, kind string) string {
+	if name == "" {
+		return fmt.Sprintf("/orgs/%s/%s/alerts", owner, kind)
+	}
+	return fmt.Sprintf("/repos/%s/%s/%s/alerts", owner, name, kind)
+}
+
+// ListCodeScanningAlerts returns the code scanning alerts of a repository matching filter.  name empty lists the
+// alerts of every repository of the organization owner.
+func (g *GithubGraphqlAPI) ListCodeScanningAlerts(ctx context.Context, owner string, name string, filter CodeScanningAlertFilter) (_ []CodeScanningAlert, err error) {
+	ctx = withOperation(ctx, "ListCodeScanningAlerts")
+	defer annotateError(&err, OperationError{Operation: "ListCodeScanningAlerts", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ListCodeScanningAlerts", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done ListCodeScanningAlerts")
+	q := url.Values{}
+	for k, v := range map[string]string{"state": filter.State, "severity": filter.Severity, "tool_name": filter.ToolName, "ref": filter.Ref} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	q.Set("per_page", "100")
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[CodeScanningAlert], error) {
+		q.Set("page", fmt.Sprint(restPageNumber(cursor)))
+		var alerts []restCodeScanningAlert
+		if err := g.doREST(ctx, http.MethodGet, alertsPath(owner, name, "code-scanning")+"?"+q.Encode(), nil, &alerts); err != nil {
+			return Page[CodeScanningAlert]{}, fmt.Errorf("failed to list code scanning alerts: %w", err)
+		}
+		items := make([]CodeScanningAlert, 0, len(alerts))
+		for _, a := range alerts {
+			a.CodeScanningAlert.Repository = a.Repository.FullName
+			if a.CodeScanningAlert.Repository == "" {
+				a.CodeScanningAlert.Repository = owner + "/" + name
+			}
+			items = append(items, a.CodeScanningAlert)
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// UpdateCodeScanningAlert dismisses or reopens a code scanning alert
+func (g *GithubGraphqlAPI) UpdateCodeScanningAlert(ctx context.Context, owner string, name string, number int64, update CodeScanningAlertUpdate) (_ *CodeScanningAlert, err error) {
+	ctx = withOperation(ctx, "UpdateCodeScanningAlert")
+	defer annotateError(&err, OperationError{Operation: "UpdateCodeScanningAlert", Owner: owner, Repo: name, Number: number})
+	g.logger(ctx).Debug("UpdateCodeScanningAlert", zap.String("owner", owner), zap.String("name", name), zap.Int64("number", number), zap.String("state", update.State))
+	defer g.logger(ctx).Debug("Done UpdateCodeScanningAlert")
+	if update.State == "dismissed" && update.DismissedReason == "" {
+		return nil, fmt.Errorf("dismissing a code scanning alert needs a reason")
+	}
+	var ret restCodeScanningAlert
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", alertsPath(owner, name, "code-scanning"), number), update, &ret); err != nil {
+		return nil, fmt.Errorf("failed to update code scanning alert: %w", err)
+	}
+	ret.CodeScanningAlert.Repository = owner + "/" + name
+	return &ret.CodeScanningAlert, nil
+}
+
+// ListSecretScanningAlerts returns the secret scanning alerts of a repository matching filter.  name empty lists the
+// alerts of every repository of the organization owner.
+func (g *GithubGraphqlAPI) ListSecretScanningAlerts(ctx context.Context, owner string, name string, filter SecretScanningAlertFilter) (_ []SecretScanningAlert, err error) {
+	ctx = withOperation(ctx, "ListSecretScanningAlerts")
+	defer annotateError(&err, OperationError{Operation: "ListSecretScanningAlerts", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ListSecretScanningAlerts", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done ListSecretScanningAlerts")
+	q := url.Values{}
+	if filter.State != "" {
+		q.Set("state", filter.State)
+	}
+	if len(filter.SecretTypes) > 0 {
+		q.Set("secret_type", strings.Join(filter.SecretTypes, ","))
+	}
+	if len(filter.Resolutions) > 0 {
+		q.Set("resolution", strings.Join(filter.Resolutions, ","))
+	}
+	q.Set("per_page", "100")
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[SecretScanningAlert], error) {
+		q.Set("page", fmt.Sprint(restPageNumber(cursor)))
+		var alerts []restSecretScanningAlert
+		if err := g.doREST(ctx, http.MethodGet, alertsPath(owner, name, "secret-scanning")+"?"+q.Encode(), nil, &alerts); err != nil {
+			return Page[SecretScanningAlert]{}, fmt.Errorf("failed to list secret scanning alerts: %w", err)
+		}
+		items := make([]SecretScanningAlert, 0, len(alerts))
+		for _, a := range alerts {
+			a.SecretScanningAlert.Repository = a.Repository.FullName
+			if a.SecretScanningAlert.Repository == "" {
+				a.SecretScanningAlert.Repository = owner + "/" + name
+			}
+			items = append(items, a.SecretScanningAlert)
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
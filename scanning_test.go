@@ -0,0 +1,63 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeScanningAlerts(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/code-scanning/alerts":
+			require.Equal(t, "CodeQL", r.URL.Query().Get("tool_name"))
+			require.Equal(t, "refs/pull/7/merge", r.URL.Query().Get("ref"))
+			_, _ = w.Write([]byte(`[{"number":2,"state":"open","rule":{"id":"go/sql-injection","security_severity_level":"high"},
+				"tool":{"name":"CodeQL"},"most_recent_instance":{"location":{"path":"db.go","start_line":10}}}]`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/code-scanning/alerts/2":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"state": "dismissed", "dismissed_reason": "used in tests"}, body)
+			_, _ = w.Write([]byte(`{"number":2,"state":"dismissed","dismissed_reason":"used in tests"}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	alerts, err := g.ListCodeScanningAlerts(ctx, "o", "r", CodeScanningAlertFilter{ToolName: "CodeQL", Ref: "refs/pull/7/merge"})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "o/r", alerts[0].Repository)
+	require.Equal(t, "go/sql-injection", alerts[0].Rule.ID)
+	require.Equal(t, "db.go", alerts[0].MostRecentInstance.Location.Path)
+
+	_, err = g.UpdateCodeScanningAlert(ctx, "o", "r", 2, CodeScanningAlertUpdate{State: "dismissed"})
+	require.Error(t, err)
+	a, err := g.UpdateCodeScanningAlert(ctx, "o", "r", 2, CodeScanningAlertUpdate{State: "dismissed", DismissedReason: CodeScanningDismissUsedInTests})
+	require.NoError(t, err)
+	require.Equal(t, "dismissed", a.State)
+}
+
+func TestListSecretScanningAlerts(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/orgs/acme/secret-scanning/alerts" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		require.Equal(t, "open", r.URL.Query().Get("state"))
+		require.Equal(t, "aws_access_key_id,github_personal_access_token", r.URL.Query().Get("secret_type"))
+		_, _ = w.Write([]byte(`[{"number":1,"state":"open","secret_type":"aws_access_key_id","secret":"AKIA...",
+			"push_protection_bypassed":true,"repository":{"full_name":"acme/infra"}}]`))
+	})
+	alerts, err := g.ListSecretScanningAlerts(context.Background(), "acme", "", SecretScanningAlertFilter{
+		State:       "open",
+		SecretTypes: []string{"aws_access_key_id", "github_personal_access_token"},
+	})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "acme/infra", alerts[0].Repository)
+	require.Equal(t, "aws_access_key_id", alerts[0].SecretType)
+	require.True(t, alerts[0].PushProtectionBypassed)
+}
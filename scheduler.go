@@ -0,0 +1,233 @@
+package gogithub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/cresta/gogithub/reqmeta"
+)
+
+// FairScheduler caps how many requests are in flight across every client sharing it, and hands free slots to the
+// waiting tenants in turn.  A tenant sweeping thousands of repositories then only delays the others by one request
+// per round instead of starving them.  Give each client its own tenant with NewGQLClientConfig.Tenant.
+//
+// Requests of a higher reqmeta.Priority always go first: tenants only take turns among requests of the same priority.
+type FairScheduler struct {
+	maxConcurrent int
+
+	mu       sync.Mutex
+	inFlight int
+	classes  map[reqmeta.Priority]*schedulerClass
+}
+
+// schedulerClass is the queue of the waiting requests of one priority
+type schedulerClass struct {
+	queues map[string][]*schedulerWaiter
+	// order lists the tenants with waiters, in the order they are served
+	order []string
+	next  int
+}
+
+type schedulerWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// SchedulerStats is a snapshot of a FairScheduler
+type SchedulerStats struct {
+	InFlight int
+	// Waiting is how many requests of each tenant are queued
+	Waiting map[string]int
+}
+
+// NewFairScheduler returns a scheduler allowing maxConcurrent requests in flight at once
+func NewFairScheduler(maxConcurrent int) *FairScheduler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &FairScheduler{
+		maxConcurrent: maxConcurrent,
+		classes:       make(map[reqmeta.Priority]*schedulerClass),
+	}
+}
+
+// Acquire waits for a slot for tenant, at the priority of ctx.  The returned release must be called once the request
+// is done.
+func (s *FairScheduler) Acquire(ctx context.Context, tenant string) (func(), error) {
+	priority := reqmeta.RequestPriority(ctx)
+	s.mu.Lock()
+	if s.inFlight < s.maxConcurrent && len(s.classes) == 0 {
+		s.inFlight++
+		s.mu.Unlock()
+		return s.release, nil
+	}
+	class, ok := s.classes[priority]
+	if !ok {
+		class = &schedulerClass{queues: make(map[string][]*schedulerWaiter)}
+		s.classes[priority] = class
+	}
+	w := &schedulerWaiter{ready: make(chan struct{})}
+	if len(class.queues[tenant]) == 0 {
+		class.order = append(class.order, tenant)
+	}
+	class.queues[tenant] = append(class.queues[tenant], w)
+	s.dispatch()
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return s.release, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if w.granted {
+			// The slot was handed over while we gave up, so pass it on
+			s.inFlight--
+			s.dispatch()
+		} else {
+			s.remove(priority, tenant, w)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (s *FairScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	s.dispatch()
+}
+
+// dispatch hands free slots to the waiting requests of the highest priority, round robin across their tenants.  s.mu
+// must be held.
+func (s *FairScheduler) dispatch() {
+	for s.inFlight < s.maxConcurrent && len(s.classes) > 0 {
+		var priority reqmeta.Priority
+		first := true
+		for p := range s.classes {
+			if first || p > priority {
+				priority = p
+				first = false
+			}
+		}
+		class := s.classes[priority]
+		if class.next >= len(class.order) {
+			class.next = 0
+		}
+		tenant := class.order[class.next]
+		queue := class.queues[tenant]
+		w := queue[0]
+		class.queues[tenant] = queue[1:]
+		if len(class.queues[tenant]) == 0 {
+			delete(class.queues, tenant)
+			class.order = append(class.order[:class.next], class.order[class.next+1:]...)
+		} else {
+			class.next++
+		}
+		if len(class.order) == 0 {
+			delete(s.classes, priority)
+		}
+		w.granted = true
+		s.inFlight++
+		close(w.ready)
+	}
+}
+
+// remove drops a waiter that gave up.  s.mu must be held.
+func (s *FairScheduler) remove(priority reqmeta.Priority, tenant string, w *schedulerWaiter) {
+	class := s.classes[priority]
+	queue := class.queues[tenant]
+	for i, q := range queue {
+		if q != w {
+			continue
+		}
+		class.queues[tenant] = append(queue[:i], queue[i+1:]...)
+		if len(class.queues[tenant]) > 0 {
+			return
+		}
+		delete(class.queues, tenant)
+		for j, t := range class.order {
+			if t == tenant {
+				class.order = append(class.order[:j], class.order[j+1:]...)
+				if class.next > j {
+					class.next--
+				}
+				break
+			}
+		}
+		if len(class.order) == 0 {
+			delete(s.classes, priority)
+		}
+		return
+	}
+}
+
+// Stats returns how many requests are in flight and queued
+func (s *FairScheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := SchedulerStats{
+		InFlight: s.inFlight,
+		Waiting:  make(map[string]int),
+	}
+	for _, class := range s.classes {
+		for tenant, queue := range class.queues {
+			ret.Waiting[tenant] += len(queue)
+		}
+	}
+	return ret
+}
+
+// SchedulerTransport sends every request through a slot of a FairScheduler
+type SchedulerTransport struct {
+	Base      http.RoundTripper
+	Scheduler *FairScheduler
+	Tenant    string
+}
+
+func (t *SchedulerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	release, err := t.Scheduler.Acquire(request.Context(), t.Tenant)
+	if err != nil {
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.Base.RoundTrip(request)
+	if err != nil {
+		release()
+		return resp, err
+	}
+	// The slot is held until the body is read, since that is when the connection is free again
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// ScheduledTransport wraps base so its requests wait for a slot of scheduler.  It returns base unchanged if scheduler
+// is nil.
+func ScheduledTransport(base http.RoundTripper, scheduler *FairScheduler, tenant string) http.RoundTripper {
+	if scheduler == nil {
+		return base
+	}
+	return &SchedulerTransport{
+		Base:      base,
+		Scheduler: scheduler,
+		Tenant:    tenant,
+	}
+}
+
+var _ http.RoundTripper = &SchedulerTransport{}
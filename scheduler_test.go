@@ -0,0 +1,121 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForWaiting(t *testing.T, s *FairScheduler, tenant string, n int) {
+	require.Eventually(t, func() bool {
+		return s.Stats().Waiting[tenant] == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestFairScheduler_RoundRobin(t *testing.T) {
+	s := NewFairScheduler(1)
+	release, err := s.Acquire(context.Background(), "busy")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	queue := func(tenant string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := s.Acquire(context.Background(), tenant)
+			require.NoError(t, err)
+			mu.Lock()
+			served = append(served, tenant)
+			mu.Unlock()
+			r()
+		}()
+	}
+	for i := 1; i <= 3; i++ {
+		queue("sweep")
+		waitForWaiting(t, s, "sweep", i)
+	}
+	queue("interactive")
+	waitForWaiting(t, s, "interactive", 1)
+
+	release()
+	wg.Wait()
+	require.Equal(t, []string{"sweep", "interactive", "sweep", "sweep"}, served)
+	require.Equal(t, 0, s.Stats().InFlight)
+}
+
+func TestFairScheduler_Priority(t *testing.T) {
+	s := NewFairScheduler(1)
+	release, err := s.Acquire(context.Background(), "app")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	queue := func(name string, priority reqmeta.Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := s.Acquire(reqmeta.WithPriority(context.Background(), priority), "app")
+			require.NoError(t, err)
+			mu.Lock()
+			served = append(served, name)
+			mu.Unlock()
+			r()
+		}()
+	}
+	queue("sync", reqmeta.PriorityBackground)
+	waitForWaiting(t, s, "app", 1)
+	queue("default", reqmeta.PriorityDefault)
+	waitForWaiting(t, s, "app", 2)
+	queue("lookup", reqmeta.PriorityInteractive)
+	waitForWaiting(t, s, "app", 3)
+
+	release()
+	wg.Wait()
+	require.Equal(t, []string{"lookup", "default", "sync"}, served)
+	require.Empty(t, s.Stats().Waiting)
+}
+
+func TestFairScheduler_Cancel(t *testing.T) {
+	s := NewFairScheduler(1)
+	release, err := s.Acquire(context.Background(), "a")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := s.Acquire(ctx, "b")
+		done <- err
+	}()
+	waitForWaiting(t, s, "b", 1)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Empty(t, s.Stats().Waiting)
+
+	release()
+	release, err = s.Acquire(context.Background(), "c")
+	require.NoError(t, err)
+	release()
+}
+
+func TestSchedulerTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	s := NewFairScheduler(2)
+	client := &http.Client{Transport: ScheduledTransport(http.DefaultTransport, s, "tenant")}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Stats().InFlight)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, 0, s.Stats().InFlight)
+	require.Equal(t, http.DefaultTransport, ScheduledTransport(http.DefaultTransport, nil, "tenant"))
+}
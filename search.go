@@ -0,0 +1,216 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+)
+
+// searchMaxResults is the most results GitHub returns for a search, however many match
+const searchMaxResults = 1000
+
+type SearchOptions struct {
+	// MaxResults stops paging after this many results.  Defaults to, and is capped at, 1000.
+	MaxResults int
+	// PageSize is how many results are fetched per query, up to 100.  Defaults to 100.
+	PageSize int
+}
+
+// SearchIssue is an issue search result
+type SearchIssue struct {
+	ID     githubv4.ID
+	Number int64
+	Title  string
+	URL    string
+	State  string
+	Author string
+	// Repository is owner/name
+	Repository string
+	Labels     []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SearchPullRequest is a pull request search result
+type SearchPullRequest struct {
+	SearchIssue
+	IsDraft     bool
+	HeadRefName string
+	BaseRefName string
+	// ReviewDecision is empty when the base branch requires no review
+	ReviewDecision ReviewDecision
+}
+
+// SearchResults are the typed results of Search.  Only the slice matching the search type is filled, except issue
+// searches which return both issues and pull requests.
+type SearchResults struct {
+	// Count is how many results matched, which can be more than were returned
+	Count        int
+	Issues       []SearchIssue
+	PullRequests []SearchPullRequest
+	Repositories []OrgRepository
+}
+
+type searchIssueNode struct {
+	ID     githubv4.ID
+	Number int64
+	Title  string
+	URL    string `graphql:"url"`
+	State  string
+	Author struct {
+		Login string
+	}
+	Repository struct {
+		NameWithOwner string
+	}
+	Labels struct {
+		Nodes []struct {
+			Name string
+		}
+	} `graphql:"labels(first: 20)"`
+	CreatedAt githubv4.DateTime
+	UpdatedAt githubv4.DateTime
+}
+
+func (n *searchIssueNode) toSearchIssue() SearchIssue {
+	ret := SearchIssue{
+		ID:         n.ID,
+		Number:     n.Number,
+		Title:      n.Title,
+		URL:        n.URL,
+		State:      n.State,
+		Author:     n.Author.Login,
+		Repository: n.Repository.NameWithOwner,
+		CreatedAt:  n.CreatedAt.Time,
+		UpdatedAt:  n.UpdatedAt.Time,
+	}
+	for _, l := range n.Labels.Nodes {
+		ret.Labels = append(ret.Labels, l.Name)
+	}
+	return ret
+}
+
+// searchPullRequestNode repeats the searchIssueNode fields, as fragments cannot embed unexported structs
+type searchPullRequestNode struct {
+	ID     githubv4.ID
+	Number int64
+	Title  string
+	URL    string `graphql:"url"`
+	State  string
+	Author struct {
+		Login string
+	}
+	Repository struct {
+		NameWithOwner string
+	}
+	Labels struct {
+		Nodes []struct {
+			Name string
+		}
+	} `graphql:"labels(first: 20)"`
+	CreatedAt      githubv4.DateTime
+	UpdatedAt      githubv4.DateTime
+	IsDraft        bool
+	HeadRefName    string
+	BaseRefName    string
+	ReviewDecision string
+}
+
+func (n *searchPullRequestNode) toSearchPullRequest() SearchPullRequest {
+	issue := searchIssueNode{
+		ID:         n.ID,
+		Number:     n.Number,
+		Title:      n.Title,
+		URL:        n.URL,
+		State:      n.State,
+		Author:     n.Author,
+		Repository: n.Repository,
+		Labels:     n.Labels,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+	return SearchPullRequest{
+		SearchIssue:    issue.toSearchIssue(),
+		IsDraft:        n.IsDraft,
+		HeadRefName:    n.HeadRefName,
+		BaseRefName:    n.BaseRefName,
+		ReviewDecision: ReviewDecision(n.ReviewDecision),
+	}
+}
+
+type searchNode struct {
+	Typename    string                `graphql:"__typename"`
+	Issue       searchIssueNode       `graphql:"... on Issue"`
+	PullRequest searchPullRequestNode `graphql:"... on PullRequest"`
+	Repository  orgRepositoryNode     `graphql:"... on Repository"`
+}
+
+func (r *SearchResults) add(n *searchNode) {
+	switch n.Typename {
+	case "Issue":
+		r.Issues = append(r.Issues, n.Issue.toSearchIssue())
+	case "PullRequest":
+		r.PullRequests = append(r.PullRequests, n.PullRequest.toSearchPullRequest())
+	case "Repository":
+		r.Repositories = append(r.Repositories, n.Repository.toOrgRepository())
+	}
+}
+
+// Search runs a GitHub search query, such as "is:pr is:open author:app/my-bot org:cresta", and pages through the
+// results.  searchType is ISSUE for issues and pull requests, or REPOSITORY.
+func (g *GithubGraphqlAPI) Search(ctx context.Context, query string, searchType githubv4.SearchType, opts SearchOptions) (_ *SearchResults, err error) {
+	ctx = withOperation(ctx, "Search")
+	defer annotateError(&err, OperationError{Operation: "Search"})
+	g.logger(ctx).Debug("Search", zap.String("query", query), zap.String("type", string(searchType)))
+	defer g.logger(ctx).Debug("Done Search")
+	maxResults := opts.MaxResults
+	if maxResults <= 0 || maxResults > searchMaxResults {
+		maxResults = searchMaxResults
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+	var q struct {
+		Search struct {
+			IssueCount      int
+			RepositoryCount int
+			Nodes           []searchNode
+			PageInfo        struct {
+				HasNextPage bool
+				EndCursor   githubv4.String
+			}
+		} `graphql:"search(query: $query, type: $type, first: $first, after: $cursor)"`
+	}
+	variables := map[string]interface{}{
+		"query":  githubv4.String(query),
+		"type":   searchType,
+		"first":  githubv4.Int(pageSize),
+		"cursor": (*githubv4.String)(nil),
+	}
+	ret := &SearchResults{}
+	fetched := 0
+	for {
+		if remaining := maxResults - fetched; remaining < pageSize {
+			variables["first"] = githubv4.Int(remaining)
+		}
+		if err := g.ClientV4.Query(ctx, &q, variables); err != nil {
+			return nil, fmt.Errorf("failed to search: %w", err)
+		}
+		ret.Count = q.Search.IssueCount
+		if searchType == githubv4.SearchTypeRepository {
+			ret.Count = q.Search.RepositoryCount
+		}
+		for i := range q.Search.Nodes {
+			ret.add(&q.Search.Nodes[i])
+		}
+		fetched += len(q.Search.Nodes)
+		if !q.Search.PageInfo.HasNextPage || fetched >= maxResults || len(q.Search.Nodes) == 0 {
+			return ret, nil
+		}
+		variables["cursor"] = githubv4.NewString(q.Search.PageInfo.EndCursor)
+	}
+}
@@ -0,0 +1,32 @@
+package gogithub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchResults_Add(t *testing.T) {
+	var r SearchResults
+	pr := searchNode{Typename: "PullRequest"}
+	pr.PullRequest.Number = 4
+	pr.PullRequest.State = "OPEN"
+	pr.PullRequest.IsDraft = true
+	pr.PullRequest.Repository.NameWithOwner = "cresta/gogithub"
+	pr.PullRequest.Labels.Nodes = append(pr.PullRequest.Labels.Nodes, struct{ Name string }{Name: "bot"})
+	issue := searchNode{Typename: "Issue"}
+	issue.Issue.Number = 5
+	repo := searchNode{Typename: "Repository"}
+	repo.Repository.NameWithOwner = "cresta/api"
+	for _, n := range []searchNode{pr, issue, repo} {
+		n := n
+		r.add(&n)
+	}
+	require.Len(t, r.PullRequests, 1)
+	require.Equal(t, int64(4), r.PullRequests[0].Number)
+	require.Equal(t, "cresta/gogithub", r.PullRequests[0].Repository)
+	require.Equal(t, []string{"bot"}, r.PullRequests[0].Labels)
+	require.True(t, r.PullRequests[0].IsDraft)
+	require.Len(t, r.Issues, 1)
+	require.Equal(t, "cresta/api", r.Repositories[0].NameWithOwner)
+}
@@ -0,0 +1,249 @@
+package gogithub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// SecretSealer encrypts value for publicKey with a libsodium sealed box, the encryption GitHub requires for Actions
+// secrets.  Clients use DefaultSecretSealer unless WithSecretSealer overrides it.
+type SecretSealer func(publicKey *[32]byte, value []byte) ([]byte, error)
+
+// DefaultSecretSealer seals value with golang.org/x/crypto/nacl/box, which is compatible with libsodium sealed boxes
+func DefaultSecretSealer(publicKey *[32]byte, value []byte) ([]byte, error) {
+	return box.SealAnonymous(nil, value, publicKey, rand.Reader)
+}
+
+// SecretScope selects where Actions secrets and variables are stored: an organization, a repository or an environment
+// of a repository
+type SecretScope struct {
+	Owner string
+	// Repo is empty for organization secrets and variables
+	Repo string
+	// Environment selects the environment of Repo
+	Environment string
+	// Visibility is all, private or selected.  It only applies to organization scopes and defaults to private.
+	Visibility string
+	// SelectedRepositoryIDs are the repositories that can use an organization secret with selected visibility
+	SelectedRepositoryIDs []int64
+}
+
+func OrgScope(org string) SecretScope {
+	return SecretScope{Owner: org}
+}
+
+func RepoScope(owner string, repo string) SecretScope {
+	return SecretScope{Owner: owner, Repo: repo}
+}
+
+func EnvironmentScope(owner string, repo string, environment string) SecretScope {
+	return SecretScope{Owner: owner, Repo: repo, Environment: environment}
+}
+
+// path returns the REST root of kind, secrets or variables, in the scope
+func (s SecretScope) path(kind string) string {
+	switch {
+	case s.Repo == "":
+		return fmt.Sprintf("/orgs/%s/actions/%s", s.Owner, kind)
+	case s.Environment != "":
+		return fmt.Sprintf("/repos/%s/%s/environments/%s/%s", s.Owner, s.Repo, url.PathEscape(s.Environment), kind)
+	default:
+		return fmt.Sprintf("/repos/%s/%s/actions/%s", s.Owner, s.Repo, kind)
+	}
+}
+
+func (s SecretScope) fields() []zap.Field {
+	return []zap.Field{zap.String("owner", s.Owner), zap.String("repo", s.Repo), zap.String("environment", s.Environment)}
+}
+
+// orgFields adds the visibility fields organization secrets and variables require to body
+func (s SecretScope) orgFields(body map[string]interface{}) {
+	if s.Repo != "" {
+		return
+	}
+	visibility := s.Visibility
+	if visibility == "" {
+		visibility = "private"
+	}
+	body["visibility"] = visibility
+	if visibility == "selected" {
+		body["selected_repository_ids"] = s.SelectedRepositoryIDs
+	}
+}
+
+// ActionsSecret is the metadata of a secret.  GitHub never returns secret values.
+type ActionsSecret struct {
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Visibility string    `json:"visibility"`
+}
+
+type ActionsVariable struct {
+	Name       string    `json:"name"`
+	Value      string    `json:"value"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Visibility string    `json:"visibility"`
+}
+
+// SecretPublicKey is the key secrets of a scope are encrypted with
+type SecretPublicKey struct {
+	KeyID string `json:"key_id"`
+	// Key is base64 encoded
+	Key string `json:"key"`
+}
+
+func (g *GithubGraphqlAPI) ListSecrets(ctx context.Context, scope SecretScope) ([]ActionsSecret, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[ActionsSecret], err error) {
+		ctx = withOperation(ctx, "ListSecrets")
+		defer annotateError(&err, OperationError{Operation: "ListSecrets", Owner: scope.Owner, Repo: scope.Repo})
+		g.logger(ctx).Debug("ListSecrets", append(scope.fields(), zap.String("cursor", cursor))...)
+		defer g.logger(ctx).Debug("Done ListSecrets")
+		var resp struct {
+			Secrets []ActionsSecret `json:"secrets"`
+		}
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=100&page=%d", scope.path("secrets"), restPageNumber(cursor)), nil, &resp); err != nil {
+			return Page[ActionsSecret]{}, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		return restPage(resp.Secrets, cursor, 100), nil
+	}).All(ctx)
+}
+
+// GetSecretPublicKey returns the key to encrypt secrets of scope with
+func (g *GithubGraphqlAPI) GetSecretPublicKey(ctx context.Context, scope SecretScope) (_ *SecretPublicKey, err error) {
+	ctx = withOperation(ctx, "GetSecretPublicKey")
+	defer annotateError(&err, OperationError{Operation: "GetSecretPublicKey", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("GetSecretPublicKey", scope.fields()...)
+	defer g.logger(ctx).Debug("Done GetSecretPublicKey")
+	var ret SecretPublicKey
+	if err := g.doREST(ctx, http.MethodGet, scope.path("secrets")+"/public-key", nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get secrets public key: %w", err)
+	}
+	return &ret, nil
+}
+
+// SetSecret encrypts value with the scope public key and creates or updates the secret
+func (g *GithubGraphqlAPI) SetSecret(ctx context.Context, scope SecretScope, name string, value string) (err error) {
+	ctx = withOperation(ctx, "SetSecret")
+	defer annotateError(&err, OperationError{Operation: "SetSecret", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("SetSecret", append(scope.fields(), zap.String("secret", name))...)
+	defer g.logger(ctx).Debug("Done SetSecret")
+	sealer := g.secretSealer
+	if sealer == nil {
+		sealer = DefaultSecretSealer
+	}
+	key, err := g.GetSecretPublicKey(ctx, scope)
+	if err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil || len(decoded) != 32 {
+		return fmt.Errorf("invalid secrets public key %s", key.KeyID)
+	}
+	var publicKey [32]byte
+	copy(publicKey[:], decoded)
+	sealed, err := sealer(&publicKey, []byte(value))
+	if err != nil {
+		return fmt.Errorf("failed to seal secret: %w", err)
+	}
+	return g.SetEncryptedSecret(ctx, scope, name, key.KeyID, base64.StdEncoding.EncodeToString(sealed))
+}
+
+// SetEncryptedSecret creates or updates a secret with a value already sealed with the scope public key keyID and base64
+// encoded
+func (g *GithubGraphqlAPI) SetEncryptedSecret(ctx context.Context, scope SecretScope, name string, keyID string, encryptedValue string) (err error) {
+	ctx = withOperation(ctx, "SetEncryptedSecret")
+	defer annotateError(&err, OperationError{Operation: "SetEncryptedSecret", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("SetEncryptedSecret", append(scope.fields(), zap.String("secret", name))...)
+	defer g.logger(ctx).Debug("Done SetEncryptedSecret")
+	body := map[string]interface{}{
+		"encrypted_value": encryptedValue,
+		"key_id":          keyID,
+	}
+	scope.orgFields(body)
+	if err := g.doREST(ctx, http.MethodPut, scope.path("secrets")+"/"+url.PathEscape(name), body, nil); err != nil {
+		return fmt.Errorf("failed to set secret: %w", err)
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) DeleteSecret(ctx context.Context, scope SecretScope, name string) (err error) {
+	ctx = withOperation(ctx, "DeleteSecret")
+	defer annotateError(&err, OperationError{Operation: "DeleteSecret", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("DeleteSecret", append(scope.fields(), zap.String("secret", name))...)
+	defer g.logger(ctx).Debug("Done DeleteSecret")
+	if err := g.doREST(ctx, http.MethodDelete, scope.path("secrets")+"/"+url.PathEscape(name), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
+
+// SetRepoSecret sets a repository secret.  See SetSecret.
+func (g *GithubGraphqlAPI) SetRepoSecret(ctx context.Context, owner string, repo string, name string, value string) error {
+	return g.SetSecret(ctx, RepoScope(owner, repo), name, value)
+}
+
+// DeleteRepoSecret deletes a repository secret
+func (g *GithubGraphqlAPI) DeleteRepoSecret(ctx context.Context, owner string, repo string, name string) error {
+	return g.DeleteSecret(ctx, RepoScope(owner, repo), name)
+}
+
+func (g *GithubGraphqlAPI) ListVariables(ctx context.Context, scope SecretScope) ([]ActionsVariable, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[ActionsVariable], err error) {
+		ctx = withOperation(ctx, "ListVariables")
+		defer annotateError(&err, OperationError{Operation: "ListVariables", Owner: scope.Owner, Repo: scope.Repo})
+		g.logger(ctx).Debug("ListVariables", append(scope.fields(), zap.String("cursor", cursor))...)
+		defer g.logger(ctx).Debug("Done ListVariables")
+		var resp struct {
+			Variables []ActionsVariable `json:"variables"`
+		}
+		// Variables are paged 30 at a time at most
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=30&page=%d", scope.path("variables"), restPageNumber(cursor)), nil, &resp); err != nil {
+			return Page[ActionsVariable]{}, fmt.Errorf("failed to list variables: %w", err)
+		}
+		return restPage(resp.Variables, cursor, 30), nil
+	}).All(ctx)
+}
+
+// SetVariable updates the variable, creating it if it does not exist
+func (g *GithubGraphqlAPI) SetVariable(ctx context.Context, scope SecretScope, name string, value string) (err error) {
+	ctx = withOperation(ctx, "SetVariable")
+	defer annotateError(&err, OperationError{Operation: "SetVariable", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("SetVariable", append(scope.fields(), zap.String("variable", name))...)
+	defer g.logger(ctx).Debug("Done SetVariable")
+	body := map[string]interface{}{
+		"name":  name,
+		"value": value,
+	}
+	scope.orgFields(body)
+	err = g.doREST(ctx, http.MethodPatch, scope.path("variables")+"/"+url.PathEscape(name), body, nil)
+	var restErr *RESTError
+	if errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound {
+		err = g.doREST(ctx, http.MethodPost, scope.path("variables"), body, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set variable: %w", err)
+	}
+	return nil
+}
+
+func (g *GithubGraphqlAPI) DeleteVariable(ctx context.Context, scope SecretScope, name string) (err error) {
+	ctx = withOperation(ctx, "DeleteVariable")
+	defer annotateError(&err, OperationError{Operation: "DeleteVariable", Owner: scope.Owner, Repo: scope.Repo})
+	g.logger(ctx).Debug("DeleteVariable", append(scope.fields(), zap.String("variable", name))...)
+	defer g.logger(ctx).Debug("Done DeleteVariable")
+	if err := g.doREST(ctx, http.MethodDelete, scope.path("variables")+"/"+url.PathEscape(name), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete variable: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package gogithub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestSecretScope_Path(t *testing.T) {
+	require.Equal(t, "/orgs/o/actions/secrets", OrgScope("o").path("secrets"))
+	require.Equal(t, "/repos/o/r/actions/variables", RepoScope("o", "r").path("variables"))
+	require.Equal(t, "/repos/o/r/environments/prod%20eu/secrets", EnvironmentScope("o", "r", "prod eu").path("secrets"))
+}
+
+// newSecretServer serves publicKey as key k1 of the repository o/r and records the body of the secret PUT
+func newSecretServer(t *testing.T, publicKey []byte, body *map[string]interface{}) *GithubGraphqlAPI {
+	return newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/o/r/actions/secrets/public-key":
+			_, _ = w.Write([]byte(`{"key_id":"k1","key":"` + base64.StdEncoding.EncodeToString(publicKey) + `"}`))
+		case "PUT /repos/o/r/actions/secrets/TOKEN":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(body))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+}
+
+func TestSetSecret_DefaultSealer(t *testing.T) {
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	var body map[string]interface{}
+	g := newSecretServer(t, publicKey[:], &body)
+	require.NoError(t, g.SetRepoSecret(context.Background(), "o", "r", "TOKEN", "hunter2"))
+	require.Equal(t, "k1", body["key_id"])
+	sealed, err := base64.StdEncoding.DecodeString(body["encrypted_value"].(string))
+	require.NoError(t, err)
+	opened, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+	require.True(t, ok)
+	require.Equal(t, "hunter2", string(opened))
+}
+
+func TestSetSecret_SealerOverride(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	var body map[string]interface{}
+	g := newSecretServer(t, key, &body)
+	g.secretSealer = func(publicKey *[32]byte, value []byte) ([]byte, error) {
+		require.Equal(t, key, publicKey[:])
+		return append([]byte("sealed:"), value...), nil
+	}
+	require.NoError(t, g.SetSecret(context.Background(), RepoScope("o", "r"), "TOKEN", "hunter2"))
+	require.Equal(t, map[string]interface{}{
+		"encrypted_value": base64.StdEncoding.EncodeToString([]byte("sealed:hunter2")),
+		"key_id":          "k1",
+	}, body)
+}
+
+func TestSetSecret_OrgVisibility(t *testing.T) {
+	var body map[string]interface{}
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			require.Equal(t, "/orgs/o/actions/secrets/public-key", r.URL.Path)
+			_, _ = w.Write([]byte(`{"key_id":"k1","key":"` + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32)) + `"}`))
+			return
+		}
+		require.Equal(t, "/orgs/o/actions/secrets/TOKEN", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+	})
+	require.NoError(t, g.SetSecret(context.Background(), OrgScope("o"), "TOKEN", "hunter2"))
+	require.Equal(t, "private", body["visibility"])
+}
+
+func TestSetVariable_CreatesMissing(t *testing.T) {
+	var calls []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	require.NoError(t, g.SetVariable(context.Background(), RepoScope("o", "r"), "REGION", "us-east-1"))
+	require.Equal(t, []string{"PATCH /repos/o/r/actions/variables/REGION", "POST /repos/o/r/actions/variables"}, calls)
+}
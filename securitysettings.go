@@ -0,0 +1,111 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// vulnerabilityAlertsPath and automatedSecurityFixesPath turn the security features on (PUT) and off (DELETE)
+func vulnerabilityAlertsPath(owner string, name string) string {
+	return fmt.Sprintf("/repos/%s/%s/vulnerability-alerts", owner, name)
+}
+
+func automatedSecurityFixesPath(owner string, name string) string {
+	return fmt.Sprintf("/repos/%s/%s/automated-security-fixes", owner, name)
+}
+
+// EnableVulnerabilityAlerts turns on Dependabot alerts, and the dependency graph they need, for a repository
+func (g *GithubGraphqlAPI) EnableVulnerabilityAlerts(ctx context.Context, owner string, name string) (err error) {
+	ctx = withOperation(ctx, "EnableVulnerabilityAlerts")
+	defer annotateError(&err, OperationError{Operation: "EnableVulnerabilityAlerts", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("EnableVulnerabilityAlerts", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done EnableVulnerabilityAlerts")
+	if err := g.doREST(ctx, http.MethodPut, vulnerabilityAlertsPath(owner, name), nil, nil); err != nil {
+		return fmt.Errorf("failed to enable vulnerability alerts: %w", err)
+	}
+	return nil
+}
+
+// DisableVulnerabilityAlerts turns off Dependabot alerts for a repository
+func (g *GithubGraphqlAPI) DisableVulnerabilityAlerts(ctx context.Context, owner string, name string) (err error) {
+	ctx = withOperation(ctx, "DisableVulnerabilityAlerts")
+	defer annotateError(&err, OperationError{Operation: "DisableVulnerabilityAlerts", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("DisableVulnerabilityAlerts", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done DisableVulnerabilityAlerts")
+	if err := g.doREST(ctx, http.MethodDelete, vulnerabilityAlertsPath(owner, name), nil, nil); err != nil {
+		return fmt.Errorf("failed to disable vulnerability alerts: %w", err)
+	}
+	return nil
+}
+
+// VulnerabilityAlertsEnabled is true if Dependabot alerts are on for a repository.  The client needs admin access to
+// the repository.
+func (g *GithubGraphqlAPI) VulnerabilityAlertsEnabled(ctx context.Context, owner string, name string) (_ bool, err error) {
+	ctx = withOperation(ctx, "VulnerabilityAlertsEnabled")
+	defer annotateError(&err, OperationError{Operation: "VulnerabilityAlertsEnabled", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("VulnerabilityAlertsEnabled", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done VulnerabilityAlertsEnabled")
+	// GitHub answers 204 when enabled and 404 when not
+	err = g.doREST(ctx, http.MethodGet, vulnerabilityAlertsPath(owner, name), nil, nil)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check vulnerability alerts: %w", err)
+	}
+}
+
+// EnableAutomatedSecurityFixes turns on Dependabot security updates for a repository.  Vulnerability alerts must be
+// enabled first.
+func (g *GithubGraphqlAPI) EnableAutomatedSecurityFixes(ctx context.Context, owner string, name string) (err error) {
+	ctx = withOperation(ctx, "EnableAutomatedSecurityFixes")
+	defer annotateError(&err, OperationError{Operation: "EnableAutomatedSecurityFixes", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("EnableAutomatedSecurityFixes", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done EnableAutomatedSecurityFixes")
+	if err := g.doREST(ctx, http.MethodPut, automatedSecurityFixesPath(owner, name), nil, nil); err != nil {
+		return fmt.Errorf("failed to enable automated security fixes: %w", err)
+	}
+	return nil
+}
+
+// DisableAutomatedSecurityFixes turns off Dependabot security updates for a repository
+func (g *GithubGraphqlAPI) DisableAutomatedSecurityFixes(ctx context.Context, owner string, name string) (err error) {
+	ctx = withOperation(ctx, "DisableAutomatedSecurityFixes")
+	defer annotateError(&err, OperationError{Operation: "DisableAutomatedSecurityFixes", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("DisableAutomatedSecurityFixes", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done DisableAutomatedSecurityFixes")
+	if err := g.doREST(ctx, http.MethodDelete, automatedSecurityFixesPath(owner, name), nil, nil); err != nil {
+		return fmt.Errorf("failed to disable automated security fixes: %w", err)
+	}
+	return nil
+}
+
+// AutomatedSecurityFixesEnabled is true if Dependabot security updates are on, and not paused, for a repository
+func (g *GithubGraphqlAPI) AutomatedSecurityFixesEnabled(ctx context.Context, owner string, name string) (_ bool, err error) {
+	ctx = withOperation(ctx, "AutomatedSecurityFixesEnabled")
+	defer annotateError(&err, OperationError{Operation: "AutomatedSecurityFixesEnabled", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("AutomatedSecurityFixesEnabled", zap.String("owner", owner), zap.String("name", name))
+	defer g.logger(ctx).Debug("Done AutomatedSecurityFixesEnabled")
+	var resp struct {
+		Enabled bool `json:"enabled"`
+		Paused  bool `json:"paused"`
+	}
+	err = g.doREST(ctx, http.MethodGet, automatedSecurityFixesPath(owner, name), nil, &resp)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		return resp.Enabled && !resp.Paused, nil
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		// Also returned while vulnerability alerts are off
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check automated security fixes: %w", err)
+	}
+}
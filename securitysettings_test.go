@@ -0,0 +1,65 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySettings(t *testing.T) {
+	alerts, fixes := false, false
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /repos/o/r/vulnerability-alerts":
+			alerts = true
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /repos/o/r/vulnerability-alerts":
+			if !alerts {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "PUT /repos/o/r/automated-security-fixes":
+			fixes = true
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE /repos/o/r/automated-security-fixes":
+			fixes = false
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /repos/o/r/automated-security-fixes":
+			if !alerts {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if fixes {
+				_, _ = w.Write([]byte(`{"enabled":true,"paused":false}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"enabled":false,"paused":false}`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	enabled, err := g.VulnerabilityAlertsEnabled(ctx, "o", "r")
+	require.NoError(t, err)
+	require.False(t, enabled)
+	enabled, err = g.AutomatedSecurityFixesEnabled(ctx, "o", "r")
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	require.NoError(t, g.EnableVulnerabilityAlerts(ctx, "o", "r"))
+	require.NoError(t, g.EnableAutomatedSecurityFixes(ctx, "o", "r"))
+	enabled, err = g.VulnerabilityAlertsEnabled(ctx, "o", "r")
+	require.NoError(t, err)
+	require.True(t, enabled)
+	enabled, err = g.AutomatedSecurityFixesEnabled(ctx, "o", "r")
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	require.NoError(t, g.DisableAutomatedSecurityFixes(ctx, "o", "r"))
+	enabled, err = g.AutomatedSecurityFixesEnabled(ctx, "o", "r")
+	require.NoError(t, err)
+	require.False(t, enabled)
+}
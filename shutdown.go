@@ -0,0 +1,107 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// ErrClientShutdown is returned by calls made after Shutdown
+var ErrClientShutdown = errors.New("github client is shut down")
+
+// drainTransport refuses new requests once shut down and tracks the ones in flight so Shutdown can wait for them.  A
+// request is in flight until its response body is closed.
+type drainTransport struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	closed   bool
+	inFlight int
+	// drained is closed once the last request in flight is done after shutdown
+	drained chan struct{}
+}
+
+func newDrainTransport(base http.RoundTripper) *drainTransport {
+	return &drainTransport{
+		base:    base,
+		drained: make(chan struct{}),
+	}
+}
+
+func (d *drainTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, ErrClientShutdown
+	}
+	d.inFlight++
+	d.mu.Unlock()
+	resp, err := d.base.RoundTrip(request)
+	if err != nil {
+		d.done()
+		return resp, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: d.done}
+	return resp, nil
+}
+
+func (d *drainTransport) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.closed && d.inFlight == 0 {
+		close(d.drained)
+	}
+}
+
+// shutdown refuses new requests and waits for those in flight, or for ctx to be done
+func (d *drainTransport) shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		if d.inFlight == 0 {
+			close(d.drained)
+		}
+	}
+	d.mu.Unlock()
+	select {
+	case <-d.drained:
+		return nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return fmt.Errorf("%d requests still in flight: %w", d.inFlight, ctx.Err())
+	}
+}
+
+// Shutdown stops the client for a clean restart: calls made from now on fail with ErrClientShutdown, calls in flight
+// are waited for until ctx is done, and the background token refresh is stopped.  Idle connections are closed only
+// when the client built its own transport from TransportTuning; the shared default transport and a caller's Rt keep
+// theirs, as other clients may use them.  The client has no queued mutations to flush: every mutation is sent by the
+// call that makes it.
+func (g *GithubGraphqlAPI) Shutdown(ctx context.Context) error {
+	g.logger(ctx).Debug("Shutdown")
+	defer g.logger(ctx).Debug("Done Shutdown")
+	if g.stopTokenRefresh != nil {
+		g.stopTokenRefresh()
+	}
+	if g.drain == nil {
+		return nil
+	}
+	err := g.drain.shutdown(ctx)
+	if g.connections != nil {
+		closeIdleConnections(g.connections)
+	}
+	if err != nil {
+		g.logger(ctx).Warn("shutdown before requests drained", zap.Error(err))
+		return fmt.Errorf("failed to drain requests: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestShutdown(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	gh, err := NewGQLClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{
+		Token:   "t",
+		BaseURL: srv.URL,
+		Rt:      srv.Client().Transport,
+	})
+	require.NoError(t, err)
+
+	callDone := make(chan error)
+	go func() {
+		callDone <- gh.DoREST(context.Background(), http.MethodGet, "/slow", nil, nil)
+	}()
+	<-started
+
+	shutdownDone := make(chan error)
+	go func() {
+		shutdownDone <- gh.Shutdown(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		return errors.Is(gh.DoREST(context.Background(), http.MethodGet, "/new", nil, nil), ErrClientShutdown)
+	}, time.Second, time.Millisecond)
+	select {
+	case <-shutdownDone:
+		t.Fatal("shutdown returned before the call in flight finished")
+	default:
+	}
+
+	close(unblock)
+	require.NoError(t, <-callDone)
+	require.NoError(t, <-shutdownDone)
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	d := newDrainTransport(http.DefaultTransport)
+	d.inFlight = 1
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := d.shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), "1 requests still in flight")
+
+	d.done()
+	require.NoError(t, d.shutdown(context.Background()))
+}
+
+func TestShutdown_ClosesOnlyOwnTransport(t *testing.T) {
+	newClient := func(cfg *NewGQLClientConfig) *GithubGraphqlAPI {
+		cfg.Token = "t"
+		gh, err := NewGQLClient(context.Background(), zaptest.NewLogger(t), cfg)
+		require.NoError(t, err)
+		return gh.(*GithubGraphqlAPI)
+	}
+	require.Nil(t, newClient(&NewGQLClientConfig{}).connections, "the shared default transport is not the client's")
+	require.Nil(t, newClient(&NewGQLClientConfig{Rt: http.DefaultTransport}).connections, "a given Rt is not the client's")
+	tuned := newClient(&NewGQLClientConfig{TransportTuning: &TransportTuning{MaxIdleConnsPerHost: 5}})
+	rt, ok := tuned.connections.(*http.Transport)
+	require.True(t, ok)
+	require.NotSame(t, defaultTunedTransport, rt)
+	require.Equal(t, 5, rt.MaxIdleConnsPerHost)
+	require.NoError(t, tuned.Shutdown(context.Background()))
+}
@@ -0,0 +1,78 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// flightCall is a query in flight that later callers of the same key wait for
+type flightCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	// waiters counts the callers sharing the call
+	waiters int
+}
+
+// flightGroup collapses concurrent calls for the same key into one, like golang.org/x/sync/singleflight but typed and
+// context aware.  The zero value is ready to use.
+type flightGroup[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+// Do runs fn unless a call for key is already in flight, in which case it waits for that call and returns its result.
+// shared reports whether the result came from another caller.  fn runs with the context of the caller that started
+// it; if that context ends, waiters whose own context is still alive run the query again instead of failing with it.
+func (f *flightGroup[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	for {
+		f.mu.Lock()
+		if f.calls == nil {
+			f.calls = make(map[K]*flightCall[V])
+		}
+		if c, ok := f.calls[key]; ok {
+			c.waiters++
+			f.mu.Unlock()
+			select {
+			case <-c.done:
+				if isContextError(c.err) && ctx.Err() == nil {
+					continue
+				}
+				return c.val, true, c.err
+			case <-ctx.Done():
+				var zero V
+				return zero, false, ctx.Err()
+			}
+		}
+		c := &flightCall[V]{done: make(chan struct{})}
+		f.calls[key] = c
+		f.mu.Unlock()
+		f.run(ctx, key, c, fn)
+		return c.val, false, c.err
+	}
+}
+
+func (f *flightGroup[K, V]) run(ctx context.Context, key K, c *flightCall[V], fn func(ctx context.Context) (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("panic in shared query: %v", r)
+			f.finish(key, c)
+			panic(r)
+		}
+		f.finish(key, c)
+	}()
+	c.val, c.err = fn(ctx)
+}
+
+func (f *flightGroup[K, V]) finish(key K, c *flightCall[V]) {
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	close(c.done)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,84 @@
+package gogithub
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup_Shares(t *testing.T) {
+	var g flightGroup[string, int]
+	var calls int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	shared := make([]bool, 5)
+	for i := range results {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], shared[i], _ = g.Do(context.Background(), "k", func(_ context.Context) (int, error) {
+				atomic.AddInt32(&calls, 1)
+				close(started)
+				<-release
+				return 42, nil
+			})
+		}()
+		if i == 0 {
+			<-started
+		}
+	}
+	// Give the other callers time to join the call in flight
+	for {
+		g.mu.Lock()
+		n := g.calls["k"].waiters
+		g.mu.Unlock()
+		if n == 4 {
+			break
+		}
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	require.Equal(t, []int{42, 42, 42, 42, 42}, results)
+	require.False(t, shared[0])
+
+	// Once done, the next call runs again
+	v, wasShared, err := g.Do(context.Background(), "k", func(_ context.Context) (int, error) { return 7, nil })
+	require.NoError(t, err)
+	require.False(t, wasShared)
+	require.Equal(t, 7, v)
+}
+
+func TestFlightGroup_RetriesCanceledLeader(t *testing.T) {
+	var g flightGroup[string, int]
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, err := g.Do(leaderCtx, "k", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	}()
+	<-started
+	waiter := make(chan int)
+	go func() {
+		v, _, err := g.Do(context.Background(), "k", func(_ context.Context) (int, error) { return 3, nil })
+		require.NoError(t, err)
+		waiter <- v
+	}()
+	cancel()
+	<-done
+	require.Equal(t, 3, <-waiter)
+}
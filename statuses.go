@@ -0,0 +1,41 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// CommitStatusState is the state of a commit status context
+type CommitStatusState string
+
+const (
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+)
+
+// CreateCommitStatus sets the state of statusContext on sha.  description and targetURL are optional.
+func (g *GithubGraphqlAPI) CreateCommitStatus(ctx context.Context, owner string, name string, sha string, state CommitStatusState, statusContext string, description string, targetURL string) (err error) {
+	ctx = withOperation(ctx, "CreateCommitStatus")
+	defer annotateError(&err, OperationError{Operation: "CreateCommitStatus", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateCommitStatus", zap.String("owner", owner), zap.String("name", name), zap.String("sha", sha), zap.String("state", string(state)), zap.String("context", statusContext))
+	defer g.logger(ctx).Debug("Done CreateCommitStatus")
+	body := map[string]interface{}{
+		"state":   state,
+		"context": statusContext,
+	}
+	if description != "" {
+		body["description"] = description
+	}
+	if targetURL != "" {
+		body["target_url"] = targetURL
+	}
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, name, sha), body, nil); err != nil {
+		return fmt.Errorf("unable to create commit status: %w", err)
+	}
+	return nil
+}
@@ -1,45 +1,174 @@
 package gogithub
 
 import (
+	"container/list"
 	"sync"
 	"time"
 )
 
-type expireValues[V any] struct {
+type expireValues[K comparable, V any] struct {
+	key      K
 	value    V
 	expireAt time.Time
 }
 
+// CacheStats counts how an ExpireCache has been used since it was created
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Evictions counts entries dropped to stay under MaxEntries
+	Evictions uint64
+	// Expirations counts expired entries removed by Get or a sweep
+	Expirations uint64
+	// Size is the current number of entries, including expired ones not swept yet
+	Size int
+}
+
+// ExpireCache is a least recently used cache whose entries also expire.  The zero value is an unbounded cache whose
+// entries expire immediately, so set DefaultExpiry.
 type ExpireCache[K comparable, V any] struct {
-	cache         map[K]expireValues[V]
+	cache         map[K]*list.Element
+	lru           *list.List
 	DefaultExpiry time.Duration
-	mu            sync.Mutex
+	// MaxEntries bounds the cache.  Setting a new key beyond it evicts the least recently used entry.  0 means no limit.
+	MaxEntries int
+	// SweepInterval is how often Set removes every expired entry, so keys never read again do not pile up.  Defaults
+	// to DefaultExpiry.  Negative disables sweeping.
+	SweepInterval time.Duration
+	// Disabled turns every Get into a miss and every Set into a no-op
+	Disabled  bool
+	lastSweep time.Time
+	stats     CacheStats
+	mu        sync.Mutex
 }
 
 func (e *ExpireCache[K, V]) Get(key K) (V, bool) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	if v, ok := e.cache[key]; ok {
+	var ret V
+	if e.Disabled {
+		return ret, false
+	}
+	if el, ok := e.cache[key]; ok {
+		v := el.Value.(*expireValues[K, V])
 		if v.expireAt.After(time.Now()) {
+			e.lru.MoveToFront(el)
+			e.stats.Hits++
 			return v.value, true
 		}
-		delete(e.cache, key)
+		e.remove(el)
+		e.stats.Expirations++
 	}
-	var ret V
+	e.stats.Misses++
 	return ret, false
 }
 
 func (e *ExpireCache[K, V]) Clear() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	e.cache = make(map[K]expireValues[V])
+	e.cache = make(map[K]*list.Element)
+	e.lru = list.New()
+}
+
+func (e *ExpireCache[K, V]) Delete(key K) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if el, ok := e.cache[key]; ok {
+		e.remove(el)
+	}
+}
+
+// DeleteFunc removes every entry whose key matches and returns how many were removed
+func (e *ExpireCache[K, V]) DeleteFunc(match func(K) bool) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	removed := 0
+	for k, el := range e.cache {
+		if match(k) {
+			e.remove(el)
+			removed++
+		}
+	}
+	return removed
 }
 
 func (e *ExpireCache[K, V]) Set(key K, value V) {
+	e.SetWithTTL(key, value, e.DefaultExpiry)
+}
+
+// SetWithTTL stores value for ttl instead of DefaultExpiry
+func (e *ExpireCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.Disabled {
+		return
+	}
 	if e.cache == nil {
-		e.cache = make(map[K]expireValues[V])
+		e.cache = make(map[K]*list.Element)
+		e.lru = list.New()
+	}
+	now := time.Now()
+	e.maybeSweep(now)
+	entry := &expireValues[K, V]{key: key, value: value, expireAt: now.Add(ttl)}
+	if el, ok := e.cache[key]; ok {
+		el.Value = entry
+		e.lru.MoveToFront(el)
+		return
+	}
+	e.cache[key] = e.lru.PushFront(entry)
+	for e.MaxEntries > 0 && e.lru.Len() > e.MaxEntries {
+		e.remove(e.lru.Back())
+		e.stats.Evictions++
 	}
-	e.cache[key] = expireValues[V]{value, time.Now().Add(e.DefaultExpiry)}
+}
+
+// Sweep removes every expired entry and returns how many were removed
+func (e *ExpireCache[K, V]) Sweep() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.sweep(time.Now())
+}
+
+// Stats returns the usage counters of the cache
+func (e *ExpireCache[K, V]) Stats() CacheStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ret := e.stats
+	ret.Size = len(e.cache)
+	return ret
+}
+
+func (e *ExpireCache[K, V]) maybeSweep(now time.Time) {
+	interval := e.SweepInterval
+	if interval == 0 {
+		interval = e.DefaultExpiry
+	}
+	if interval <= 0 {
+		return
+	}
+	if e.lastSweep.IsZero() {
+		e.lastSweep = now
+		return
+	}
+	if now.Sub(e.lastSweep) >= interval {
+		e.sweep(now)
+	}
+}
+
+func (e *ExpireCache[K, V]) sweep(now time.Time) int {
+	e.lastSweep = now
+	removed := 0
+	for _, el := range e.cache {
+		if !el.Value.(*expireValues[K, V]).expireAt.After(now) {
+			e.remove(el)
+			removed++
+		}
+	}
+	e.stats.Expirations += uint64(removed)
+	return removed
+}
+
+func (e *ExpireCache[K, V]) remove(el *list.Element) {
+	e.lru.Remove(el)
+	delete(e.cache, el.Value.(*expireValues[K, V]).key)
 }
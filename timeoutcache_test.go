@@ -25,3 +25,70 @@ func TestExpireCache_Set(t *testing.T) {
 	require.True(t, exists)
 	require.Equal(t, 1, val)
 }
+
+func TestExpireCache_Delete(t *testing.T) {
+	c := ExpireCache[string, int]{
+		DefaultExpiry: time.Hour,
+	}
+	c.Set("a", 1)
+	c.Delete("a")
+	c.Delete("missing")
+	_, exists := c.Get("a")
+	require.False(t, exists)
+}
+
+func TestExpireCache_Disabled(t *testing.T) {
+	c := ExpireCache[string, int]{
+		DefaultExpiry: time.Hour,
+		Disabled:      true,
+	}
+	c.Set("a", 1)
+	_, exists := c.Get("a")
+	require.False(t, exists)
+}
+
+func TestExpireCache_MaxEntries(t *testing.T) {
+	c := ExpireCache[string, int]{
+		DefaultExpiry: time.Hour,
+		MaxEntries:    2,
+	}
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// Reading a makes b the least recently used
+	_, exists := c.Get("a")
+	require.True(t, exists)
+	c.Set("c", 3)
+	_, exists = c.Get("b")
+	require.False(t, exists)
+	_, exists = c.Get("a")
+	require.True(t, exists)
+	stats := c.Stats()
+	require.Equal(t, uint64(1), stats.Evictions)
+	require.Equal(t, uint64(2), stats.Hits)
+	require.Equal(t, uint64(1), stats.Misses)
+	require.Equal(t, 2, stats.Size)
+}
+
+func TestExpireCache_SetWithTTL(t *testing.T) {
+	c := ExpireCache[string, int]{
+		DefaultExpiry: time.Hour,
+	}
+	c.SetWithTTL("short", 1, time.Millisecond)
+	c.Set("long", 2)
+	time.Sleep(time.Millisecond * 2)
+	require.Equal(t, 1, c.Sweep())
+	_, exists := c.Get("long")
+	require.True(t, exists)
+	require.Equal(t, uint64(1), c.Stats().Expirations)
+}
+
+func TestExpireCache_SweepsOnSet(t *testing.T) {
+	c := ExpireCache[string, int]{
+		DefaultExpiry: time.Millisecond,
+	}
+	c.Set("a", 1)
+	c.Set("b", 2)
+	time.Sleep(time.Millisecond * 2)
+	c.Set("c", 3)
+	require.Equal(t, 1, c.Stats().Size)
+}
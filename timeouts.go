@@ -0,0 +1,93 @@
+package gogithub
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TimeoutPolicy bounds requests whose context has no deadline, so a hung GitHub API call cannot wedge a long running
+// service.  A request made with a deadline keeps it.  A zero duration leaves that kind of request unbounded.
+type TimeoutPolicy struct {
+	// Query bounds reads: REST GET and HEAD requests and GraphQL queries
+	Query time.Duration `yaml:"query" json:"query"`
+	// Mutation bounds writes: every other REST request and GraphQL mutations
+	Mutation time.Duration `yaml:"mutation" json:"mutation"`
+	// Dispatch bounds workflow_dispatch and repository_dispatch events
+	Dispatch time.Duration `yaml:"dispatch" json:"dispatch"`
+}
+
+func (p TimeoutPolicy) isZero() bool {
+	return p == TimeoutPolicy{}
+}
+
+// timeoutFor returns the timeout of request, reading the body of GraphQL requests to tell queries from mutations
+func (p TimeoutPolicy) timeoutFor(request *http.Request) time.Duration {
+	switch {
+	case request.Method == http.MethodGet || request.Method == http.MethodHead:
+		return p.Query
+	case strings.HasSuffix(request.URL.Path, "/dispatches"):
+		return p.Dispatch
+	case strings.HasSuffix(request.URL.Path, "/graphql") && !isGraphQLMutation(request):
+		return p.Query
+	default:
+		return p.Mutation
+	}
+}
+
+// isGraphQLMutation peeks at the query of a GraphQL request, replacing its body by a copy.  request must be a clone
+// owned by the transport.
+func isGraphQLMutation(request *http.Request) bool {
+	if request.Body == nil {
+		return false
+	}
+	b, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(b, []byte(`"query":"mutation`))
+}
+
+// TimeoutTransport applies Policy to the requests whose context has no deadline.  The deadline covers reading the
+// response body, which is cancelled once closed.
+type TimeoutTransport struct {
+	Base   http.RoundTripper
+	Policy TimeoutPolicy
+}
+
+var _ http.RoundTripper = &TimeoutTransport{}
+
+func (t *TimeoutTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if _, ok := request.Context().Deadline(); ok {
+		return t.Base.RoundTrip(request)
+	}
+	request = request.Clone(request.Context())
+	timeout := t.Policy.timeoutFor(request)
+	if timeout <= 0 {
+		return t.Base.RoundTrip(request)
+	}
+	ctx, cancel := context.WithTimeout(request.Context(), timeout)
+	resp, err := t.Base.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
+}
+
+// DeadlineTransport wraps base so its requests follow policy.  It returns base unchanged if policy sets no timeout.
+func DeadlineTransport(base http.RoundTripper, policy TimeoutPolicy) http.RoundTripper {
+	if policy.isZero() {
+		return base
+	}
+	return &TimeoutTransport{
+		Base:   base,
+		Policy: policy,
+	}
+}
@@ -0,0 +1,73 @@
+package gogithub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTimeoutPolicy_TimeoutFor(t *testing.T) {
+	p := TimeoutPolicy{Query: time.Second, Mutation: 2 * time.Second, Dispatch: 3 * time.Second}
+	newRequest := func(method string, path string, body string) *http.Request {
+		req, err := http.NewRequest(method, "https://api.github.com"+path, strings.NewReader(body))
+		require.NoError(t, err)
+		return req
+	}
+	require.Equal(t, time.Second, p.timeoutFor(newRequest(http.MethodGet, "/repos/o/r", "")))
+	require.Equal(t, 2*time.Second, p.timeoutFor(newRequest(http.MethodPut, "/repos/o/r/topics", "{}")))
+	require.Equal(t, 3*time.Second, p.timeoutFor(newRequest(http.MethodPost, "/repos/o/r/actions/workflows/w.yml/dispatches", "{}")))
+	require.Equal(t, time.Second, p.timeoutFor(newRequest(http.MethodPost, "/graphql", `{"query":"{viewer{login}}"}`)))
+
+	mutation := newRequest(http.MethodPost, "/graphql", `{"query":"mutation($input:MergePullRequestInput!){}"}`)
+	require.Equal(t, 2*time.Second, p.timeoutFor(mutation))
+	b, err := io.ReadAll(mutation.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "mutation")
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	var deadlines []time.Duration
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		deadline, ok := req.Context().Deadline()
+		if ok {
+			deadlines = append(deadlines, time.Until(deadline).Round(time.Minute))
+		} else {
+			deadlines = append(deadlines, 0)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := DeadlineTransport(base, TimeoutPolicy{Query: time.Minute})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	resp, err = rt.RoundTrip(req.WithContext(ctx))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	req, err = http.NewRequest(http.MethodDelete, "https://api.github.com/repos/o/r", nil)
+	require.NoError(t, err)
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, []time.Duration{time.Minute, time.Hour, 0}, deadlines)
+	_, wrapped := DeadlineTransport(base, TimeoutPolicy{}).(*TimeoutTransport)
+	require.False(t, wrapped)
+}
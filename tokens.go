@@ -0,0 +1,201 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"go.uber.org/zap"
+)
+
+// DefaultTokenRefreshMargin is how long before expiry an installation token is replaced.  Installation tokens live
+// for an hour, so every token the client hands out stays valid for at least this long.
+const DefaultTokenRefreshMargin = 10 * time.Minute
+
+// TokenProvider supplies access tokens from a custom source, such as Vault, a cloud secret manager or a workload
+// identity token exchange.  expiresAt is zero for tokens that do not expire.  The client caches the token and asks
+// for a new one once it is within NewGQLClientConfig.TokenRefreshMargin of expiring.
+type TokenProvider interface {
+	Token(ctx context.Context) (token string, expiresAt time.Time, err error)
+}
+
+// TokenProviderFunc adapts a function to a TokenProvider
+type TokenProviderFunc func(ctx context.Context) (string, time.Time, error)
+
+func (f TokenProviderFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return f(ctx)
+}
+
+// providerTokenMinter mints tokens with provider
+func providerTokenMinter(provider TokenProvider) func(ctx context.Context) (TokenInfo, error) {
+	return func(ctx context.Context) (TokenInfo, error) {
+		token, expiresAt, err := provider.Token(ctx)
+		if err != nil {
+			return TokenInfo{}, fmt.Errorf("token provider failed: %w", err)
+		}
+		if token == "" {
+			return TokenInfo{}, fmt.Errorf("token provider returned an empty token")
+		}
+		return TokenInfo{Token: token, ExpiresAt: expiresAt}, nil
+	}
+}
+
+// TokenInfo is an access token and when it stops working
+type TokenInfo struct {
+	Token string
+	// ExpiresAt is zero when the expiry is unknown, for example for personal access tokens
+	ExpiresAt time.Time
+}
+
+// ValidFor reports whether the token still works d from now.  Tokens without a known expiry are always valid.
+func (t TokenInfo) ValidFor(d time.Duration) bool {
+	return t.ExpiresAt.IsZero() || time.Until(t.ExpiresAt) >= d
+}
+
+// refreshingToken caches a minted token and mints a new one once the cached token is within margin of expiring
+type refreshingToken struct {
+	mint   func(ctx context.Context) (TokenInfo, error)
+	margin time.Duration
+	// minting runs one mint at a time; callers waiting for it give up when their context ends
+	minting flightGroup[struct{}, TokenInfo]
+
+	mu      sync.Mutex
+	current TokenInfo
+}
+
+func newRefreshingToken(margin time.Duration, mint func(ctx context.Context) (TokenInfo, error)) *refreshingToken {
+	if margin <= 0 {
+		margin = DefaultTokenRefreshMargin
+	}
+	return &refreshingToken{
+		mint:   mint,
+		margin: margin,
+	}
+}
+
+func (r *refreshingToken) TokenInfo(ctx context.Context) (TokenInfo, error) {
+	return r.tokenValidFor(ctx, r.margin)
+}
+
+// tokenValidFor returns the current token, minting a new one unless it is valid for d
+func (r *refreshingToken) tokenValidFor(ctx context.Context, d time.Duration) (TokenInfo, error) {
+	if info, ok := r.cached(d); ok {
+		return info, nil
+	}
+	info, _, err := r.minting.Do(ctx, struct{}{}, func(ctx context.Context) (TokenInfo, error) {
+		// A mint that finished while this caller was deciding to start one already renewed the token
+		if info, ok := r.cached(d); ok {
+			return info, nil
+		}
+		info, err := r.mint(ctx)
+		if err != nil {
+			return TokenInfo{}, err
+		}
+		r.mu.Lock()
+		r.current = info
+		r.mu.Unlock()
+		return info, nil
+	})
+	return info, err
+}
+
+func (r *refreshingToken) cached(d time.Duration) (TokenInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current, r.current.Token != "" && r.current.ValidFor(d)
+}
+
+func (r *refreshingToken) Token(ctx context.Context) (string, error) {
+	info, err := r.TokenInfo(ctx)
+	return info.Token, err
+}
+
+// backgroundRefreshLead is how long before a call would renew the token on demand the background refresh renews it
+const backgroundRefreshLead = time.Minute
+
+// backgroundRefreshRetry is how long the background refresh waits after a failed mint, and at least between mints
+const backgroundRefreshRetry = 30 * time.Second
+
+// backgroundMintTimeout bounds each mint of the background refresh, so a hung token source cannot stall it
+const backgroundMintTimeout = time.Minute
+
+// refreshInBackground mints a token right away, so the first call does not wait for it, then renews it
+// backgroundRefreshLead before calls would, so they never do.  Failures are logged and retried; calls keep minting on
+// demand meanwhile.  stop ends the refresh and waits for it to return.
+func (r *refreshingToken) refreshInBackground(logger *zap.Logger) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			mintCtx, cancelMint := context.WithTimeout(ctx, backgroundMintTimeout)
+			info, err := r.tokenValidFor(mintCtx, r.margin+backgroundRefreshLead)
+			cancelMint()
+			wait := backgroundRefreshRetry
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				logger.Warn("background token refresh failed", zap.Error(err))
+			case info.ExpiresAt.IsZero():
+				return
+			default:
+				if d := time.Until(info.ExpiresAt) - r.margin - backgroundRefreshLead; d > wait {
+					wait = d
+				}
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// installationTokenMinter mints installation tokens with transports from newTransport.  ghinstallation only renews a
+// token in the last minute of its life, so every mint uses a fresh transport, which always asks GitHub for a new one.
+func installationTokenMinter(newTransport func() *ghinstallation.Transport) func(ctx context.Context) (TokenInfo, error) {
+	return func(ctx context.Context) (TokenInfo, error) {
+		trans := newTransport()
+		token, err := trans.Token(ctx)
+		if err != nil {
+			return TokenInfo{}, fmt.Errorf("failed to create installation token: %w", err)
+		}
+		expiresAt, _, err := trans.Expiry()
+		if err != nil {
+			return TokenInfo{}, fmt.Errorf("failed to read installation token expiry: %w", err)
+		}
+		return TokenInfo{Token: token, ExpiresAt: expiresAt}, nil
+	}
+}
+
+// TokenTransport authenticates every request with the current token of a token source
+type TokenTransport struct {
+	Base  http.RoundTripper
+	Token func(ctx context.Context) (string, error)
+}
+
+func (t *TokenTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	token, err := t.Token(request.Context())
+	if err != nil {
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", "token "+token)
+	return t.Base.RoundTrip(request)
+}
+
+var _ http.RoundTripper = &TokenTransport{}
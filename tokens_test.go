@@ -0,0 +1,169 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRefreshingToken(t *testing.T) {
+	mints := 0
+	lifetime := time.Hour
+	token := newRefreshingToken(10*time.Minute, func(_ context.Context) (TokenInfo, error) {
+		mints++
+		return TokenInfo{Token: "t" + string(rune('0'+mints)), ExpiresAt: time.Now().Add(lifetime)}, nil
+	})
+	info, err := token.TokenInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t1", info.Token)
+	require.True(t, info.ValidFor(50*time.Minute))
+
+	tok, err := token.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t1", tok)
+	require.Equal(t, 1, mints)
+
+	// A token closer to expiry than the margin is replaced before it is handed out
+	token.current.ExpiresAt = time.Now().Add(5 * time.Minute)
+	tok, err = token.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t2", tok)
+	require.Equal(t, 2, mints)
+}
+
+func TestRefreshingToken_MintError(t *testing.T) {
+	token := newRefreshingToken(0, func(_ context.Context) (TokenInfo, error) {
+		return TokenInfo{}, errors.New("bad key")
+	})
+	require.Equal(t, DefaultTokenRefreshMargin, token.margin)
+	_, err := token.Token(context.Background())
+	require.Error(t, err)
+}
+
+func TestRefreshingToken_WaiterGivesUp(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var mints int32
+	token := newRefreshingToken(10*time.Minute, func(_ context.Context) (TokenInfo, error) {
+		if atomic.AddInt32(&mints, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return TokenInfo{Token: "t", ExpiresAt: time.Now().Add(time.Hour)}, nil
+	})
+	done := make(chan error, 1)
+	go func() {
+		_, err := token.Token(context.Background())
+		done <- err
+	}()
+	<-started
+
+	// A second caller does not wait past its own deadline for the hung mint, nor start another one
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := token.Token(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, <-done)
+	require.Equal(t, int32(1), atomic.LoadInt32(&mints))
+}
+
+func TestRefreshingToken_Background(t *testing.T) {
+	var mints int32
+	token := newRefreshingToken(10*time.Minute, func(_ context.Context) (TokenInfo, error) {
+		atomic.AddInt32(&mints, 1)
+		return TokenInfo{Token: "t", ExpiresAt: time.Now().Add(time.Hour)}, nil
+	})
+	stop := token.refreshInBackground(zaptest.NewLogger(t))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&mints) == 1
+	}, time.Second, time.Millisecond)
+	tok, err := token.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "t", tok)
+	stop()
+	require.Equal(t, int32(1), atomic.LoadInt32(&mints))
+}
+
+func TestNewGQLClient_BackgroundTokenRefresh(t *testing.T) {
+	provider := TokenProviderFunc(func(_ context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("vault is down")
+	})
+	gh, err := NewGQLClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{
+		TokenProvider:          provider,
+		BackgroundTokenRefresh: true,
+	})
+	require.NoError(t, err)
+	_, err = gh.GetAccessToken(context.Background())
+	require.ErrorContains(t, err, "vault is down")
+	require.NoError(t, gh.Shutdown(context.Background()))
+}
+
+func TestTokenInfo_ValidFor(t *testing.T) {
+	require.True(t, TokenInfo{Token: "pat"}.ValidFor(24*time.Hour))
+	require.False(t, TokenInfo{Token: "t", ExpiresAt: time.Now().Add(time.Minute)}.ValidFor(10*time.Minute))
+}
+
+func TestGetTokenInfo(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	info, err := g.GetTokenInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "test-token", info.Token)
+	require.True(t, info.ExpiresAt.IsZero())
+
+	expiresAt := time.Now().Add(time.Hour)
+	g.tokenInfoFunction = func(_ context.Context) (TokenInfo, error) {
+		return TokenInfo{Token: "installation-token", ExpiresAt: expiresAt}, nil
+	}
+	info, err = g.GetTokenInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "installation-token", info.Token)
+	require.Equal(t, expiresAt, info.ExpiresAt)
+}
+
+func TestTokenTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token abc", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &TokenTransport{Base: http.DefaultTransport, Token: func(_ context.Context) (string, error) {
+		return "abc", nil
+	}}}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestNewGQLClient_TokenProvider(t *testing.T) {
+	calls := 0
+	expiresAt := time.Now().Add(time.Hour)
+	gh, err := NewGQLClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{
+		TokenProvider: TokenProviderFunc(func(_ context.Context) (string, time.Time, error) {
+			calls++
+			return "vault-token", expiresAt, nil
+		}),
+	})
+	require.NoError(t, err)
+	info, err := gh.GetTokenInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "vault-token", info.Token)
+	require.Equal(t, expiresAt, info.ExpiresAt)
+	require.Equal(t, 1, calls)
+
+	_, err = NewGQLClient(context.Background(), zaptest.NewLogger(t), &NewGQLClientConfig{
+		TokenProvider: TokenProviderFunc(func(_ context.Context) (string, time.Time, error) {
+			return "", time.Time{}, nil
+		}),
+	})
+	require.Error(t, err)
+}
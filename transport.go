@@ -0,0 +1,79 @@
+package gogithub
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportTuning controls connection pooling of the base transport the client builds when no Rt is given.  The
+// defaults favor sweep jobs that make thousands of calls to the same host.
+type TransportTuning struct {
+	// MaxIdleConns caps idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections kept open to api.github.com.  Go's default of 2 forces new TLS
+	// handshakes under any concurrency.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps total connections per host.  0 means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept in the pool
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 forces HTTP/1.1
+	DisableHTTP2 bool
+}
+
+var DefaultTransportTuning = TransportTuning{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 100,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// NewTunedTransport returns a clone of http.DefaultTransport with t applied
+func NewTunedTransport(t TransportTuning) *http.Transport {
+	ret := http.DefaultTransport.(*http.Transport).Clone()
+	ret.MaxIdleConns = t.MaxIdleConns
+	ret.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	ret.MaxConnsPerHost = t.MaxConnsPerHost
+	ret.IdleConnTimeout = t.IdleConnTimeout
+	if t.DisableHTTP2 {
+		ret.ForceAttemptHTTP2 = false
+		ret.TLSNextProto = make(map[string]func(authority string, c *tls.Conn) http.RoundTripper)
+	}
+	return ret
+}
+
+// defaultTunedTransport is shared by every client using the default tuning, so they also share a connection pool
+var defaultTunedTransport = NewTunedTransport(DefaultTransportTuning)
+
+// baseTransport is the unauthenticated transport requests are sent through
+func (c *NewGQLClientConfig) baseTransport() http.RoundTripper {
+	return ConditionalCachedTransport(c.connectionTransport(), c.ConditionalCache)
+}
+
+// withConnectionTransport returns a copy of c whose Rt is the transport it connects with, so every part of a client
+// shares one connection pool.  A transport built here from TransportTuning is owned by the client, whose Shutdown
+// closes it; a given Rt or the shared default transport is not.
+func (c *NewGQLClientConfig) withConnectionTransport() *NewGQLClientConfig {
+	ret := *c
+	ret.Rt = c.connectionTransport()
+	ret.ownsTransport = c.ownsTransport || (c.Rt == nil && c.TransportTuning != nil)
+	return &ret
+}
+
+// closeIdleConnections closes the idle connections of rt, if it pools any
+func closeIdleConnections(rt http.RoundTripper) {
+	if closer, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// connectionTransport is the transport that owns the connections
+func (c *NewGQLClientConfig) connectionTransport() http.RoundTripper {
+	if c.Rt != nil {
+		return c.Rt
+	}
+	if c.TransportTuning != nil {
+		return NewTunedTransport(*c.TransportTuning)
+	}
+	return defaultTunedTransport
+}
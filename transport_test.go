@@ -0,0 +1,16 @@
+package gogithub
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGQLClientConfig_BaseTransport(t *testing.T) {
+	require.Equal(t, http.RoundTripper(defaultTunedTransport), (&NewGQLClientConfig{}).baseTransport())
+	tuned := (&NewGQLClientConfig{TransportTuning: &TransportTuning{MaxIdleConnsPerHost: 7, DisableHTTP2: true}}).baseTransport()
+	require.Equal(t, 7, tuned.(*http.Transport).MaxIdleConnsPerHost)
+	require.False(t, tuned.(*http.Transport).ForceAttemptHTTP2)
+	require.Equal(t, http.DefaultTransport, (&NewGQLClientConfig{Rt: http.DefaultTransport}).baseTransport())
+}
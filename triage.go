@@ -0,0 +1,342 @@
+package gogithub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+)
+
+// TriageIssue is the part of an issue or pull request triage rules look at
+type TriageIssue struct {
+	Repo          RepoRef
+	Number        int64
+	Title         string
+	Body          string
+	Author        string
+	Labels        []string
+	Assignees     []string
+	IsPullRequest bool
+	// Closed is set for closed issues, which are not closed again
+	Closed bool
+}
+
+// TriageRule applies Then to every issue matching When
+type TriageRule struct {
+	Name string          `yaml:"name" json:"name"`
+	When TriageCondition `yaml:"when" json:"when"`
+	Then TriageActions   `yaml:"then" json:"then"`
+	// Stop skips the remaining rules when this one matches
+	Stop bool `yaml:"stop" json:"stop"`
+}
+
+// TriageCondition matches an issue when every set field matches
+type TriageCondition struct {
+	// Title and Body are regular expressions
+	Title string `yaml:"title" json:"title"`
+	Body  string `yaml:"body" json:"body"`
+	// HasLabels must all be on the issue and MissingLabels must all be absent
+	HasLabels     []string `yaml:"hasLabels" json:"hasLabels"`
+	MissingLabels []string `yaml:"missingLabels" json:"missingLabels"`
+	// Authors matches if the issue was opened by any of them
+	Authors []string `yaml:"authors" json:"authors"`
+	// PullRequests restricts the rule to pull requests when true and to issues when false.  Nil matches both.
+	PullRequests *bool `yaml:"pullRequests" json:"pullRequests"`
+}
+
+// TriageActions are what a matching rule does to the issue
+type TriageActions struct {
+	AddLabels []string `yaml:"addLabels" json:"addLabels"`
+	Assignees []string `yaml:"assignees" json:"assignees"`
+	Comment   string   `yaml:"comment" json:"comment"`
+	Close     bool     `yaml:"close" json:"close"`
+}
+
+// IsEmpty reports whether there is nothing to do
+func (a TriageActions) IsEmpty() bool {
+	return len(a.AddLabels) == 0 && len(a.Assignees) == 0 && a.Comment == "" && !a.Close
+}
+
+func (a *TriageActions) merge(o TriageActions) {
+	a.AddLabels = appendMissing(a.AddLabels, o.AddLabels...)
+	a.Assignees = appendMissing(a.Assignees, o.Assignees...)
+	if o.Comment != "" {
+		if a.Comment != "" {
+			a.Comment += "\n\n"
+		}
+		a.Comment += o.Comment
+	}
+	a.Close = a.Close || o.Close
+}
+
+func appendMissing(to []string, values ...string) []string {
+	for _, v := range values {
+		if !containsFold(to, v) {
+			to = append(to, v)
+		}
+	}
+	return to
+}
+
+func containsFold(list []string, v string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, v) {
+			return true
+		}
+	}
+	return false
+}
+
+type compiledTriageRule struct {
+	TriageRule
+	title *regexp.Regexp
+	body  *regexp.Regexp
+}
+
+func (r *compiledTriageRule) matches(issue TriageIssue) bool {
+	w := r.When
+	if w.PullRequests != nil && *w.PullRequests != issue.IsPullRequest {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(issue.Title) {
+		return false
+	}
+	if r.body != nil && !r.body.MatchString(issue.Body) {
+		return false
+	}
+	if len(w.Authors) > 0 && !containsFold(w.Authors, issue.Author) {
+		return false
+	}
+	for _, l := range w.HasLabels {
+		if !containsFold(issue.Labels, l) {
+			return false
+		}
+	}
+	for _, l := range w.MissingLabels {
+		if containsFold(issue.Labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// TriageEngine evaluates triage rules in order against issues
+type TriageEngine struct {
+	rules []compiledTriageRule
+}
+
+// NewTriageEngine compiles rules.  It fails if a rule has an invalid regular expression.
+func NewTriageEngine(rules []TriageRule) (*TriageEngine, error) {
+	ret := &TriageEngine{}
+	for _, r := range rules {
+		c := compiledTriageRule{TriageRule: r}
+		var err error
+		if r.When.Title != "" {
+			if c.title, err = regexp.Compile(r.When.Title); err != nil {
+				return nil, fmt.Errorf("invalid title pattern in rule %q: %w", r.Name, err)
+			}
+		}
+		if r.When.Body != "" {
+			if c.body, err = regexp.Compile(r.When.Body); err != nil {
+				return nil, fmt.Errorf("invalid body pattern in rule %q: %w", r.Name, err)
+			}
+		}
+		ret.rules = append(ret.rules, c)
+	}
+	return ret, nil
+}
+
+// Evaluate returns the combined actions of every rule matching issue, leaving out labels and assignees it already has,
+// and closing only an open issue
+func (e *TriageEngine) Evaluate(issue TriageIssue) TriageActions {
+	var ret TriageActions
+	for i := range e.rules {
+		if !e.rules[i].matches(issue) {
+			continue
+		}
+		ret.merge(e.rules[i].Then)
+		if e.rules[i].Stop {
+			break
+		}
+	}
+	labels := ret.AddLabels[:0]
+	for _, l := range ret.AddLabels {
+		if !containsFold(issue.Labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	ret.AddLabels = labels
+	assignees := ret.Assignees[:0]
+	for _, a := range ret.Assignees {
+		if !containsFold(issue.Assignees, a) {
+			assignees = append(assignees, a)
+		}
+	}
+	ret.Assignees = assignees
+	ret.Close = ret.Close && !issue.Closed
+	return ret
+}
+
+// triageCommentMarker tags the comment Apply posts, so the same comment is never posted twice on an issue
+func triageCommentMarker(comment string) string {
+	sum := sha256.Sum256([]byte(comment))
+	return commentMarker("gogithub-triage:" + hex.EncodeToString(sum[:8]))
+}
+
+// Apply evaluates the rules against issue and performs the resulting actions.  A dry run context only evaluates.
+// Applying the rules again to an issue they were applied to does nothing: a comment already posted on the issue, found
+// by its hidden marker, is left out of the returned actions.
+func (e *TriageEngine) Apply(ctx context.Context, gh GitHub, issue TriageIssue) (TriageActions, error) {
+	actions := e.Evaluate(issue)
+	if actions.IsEmpty() || reqmeta.IsDryRun(ctx) {
+		return actions, nil
+	}
+	var tag string
+	if actions.Comment != "" {
+		tag = triageCommentMarker(actions.Comment)
+		comments, err := gh.ListPRComments(ctx, issue.Repo.Owner, issue.Repo.Name, issue.Number)
+		if err != nil {
+			return actions, fmt.Errorf("failed to list comments: %w", err)
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, tag) {
+				actions.Comment = ""
+				break
+			}
+		}
+		if actions.IsEmpty() {
+			return actions, nil
+		}
+	}
+	base := fmt.Sprintf("/repos/%s/%s/issues/%d", issue.Repo.Owner, issue.Repo.Name, issue.Number)
+	if len(actions.AddLabels) > 0 {
+		if err := gh.DoREST(ctx, http.MethodPost, base+"/labels", map[string]interface{}{"labels": actions.AddLabels}, nil); err != nil {
+			return actions, fmt.Errorf("failed to add labels: %w", err)
+		}
+	}
+	if len(actions.Assignees) > 0 {
+		if err := gh.DoREST(ctx, http.MethodPost, base+"/assignees", map[string]interface{}{"assignees": actions.Assignees}, nil); err != nil {
+			return actions, fmt.Errorf("failed to add assignees: %w", err)
+		}
+	}
+	if actions.Comment != "" {
+		if err := gh.DoREST(ctx, http.MethodPost, base+"/comments", map[string]interface{}{"body": actions.Comment + "\n\n" + tag}, nil); err != nil {
+			return actions, fmt.Errorf("failed to comment: %w", err)
+		}
+	}
+	if actions.Close {
+		if err := gh.DoREST(ctx, http.MethodPatch, base, map[string]interface{}{"state": "closed"}, nil); err != nil {
+			return actions, fmt.Errorf("failed to close: %w", err)
+		}
+	}
+	return actions, nil
+}
+
+type triageIssueJSON struct {
+	Number int64  `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	State       string    `json:"state"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (i *triageIssueJSON) toTriageIssue(repo RepoRef, isPullRequest bool) TriageIssue {
+	ret := TriageIssue{
+		Repo:          repo,
+		Number:        i.Number,
+		Title:         i.Title,
+		Body:          i.Body,
+		Author:        i.User.Login,
+		IsPullRequest: isPullRequest || i.PullRequest != nil,
+		Closed:        i.State == "closed",
+	}
+	for _, l := range i.Labels {
+		ret.Labels = append(ret.Labels, l.Name)
+	}
+	for _, a := range i.Assignees {
+		ret.Assignees = append(ret.Assignees, a.Login)
+	}
+	return ret
+}
+
+// ParseTriageEvent extracts the issue of an issues or pull_request webhook payload.  ok is false for other events and
+// for actions other than opened, reopened and edited.
+func ParseTriageEvent(eventType string, payload []byte) (issue TriageIssue, ok bool, err error) {
+	var event struct {
+		Action      string           `json:"action"`
+		Issue       *triageIssueJSON `json:"issue"`
+		PullRequest *triageIssueJSON `json:"pull_request"`
+		Repository  struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if eventType != "issues" && eventType != "pull_request" {
+		return TriageIssue{}, false, nil
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return TriageIssue{}, false, fmt.Errorf("failed to parse %s event: %w", eventType, err)
+	}
+	switch event.Action {
+	case "opened", "reopened", "edited":
+	default:
+		return TriageIssue{}, false, nil
+	}
+	repo := RepoRef{Owner: event.Repository.Owner.Login, Name: event.Repository.Name}
+	if event.Issue != nil {
+		return event.Issue.toTriageIssue(repo, false), true, nil
+	}
+	if event.PullRequest != nil {
+		return event.PullRequest.toTriageIssue(repo, true), true, nil
+	}
+	return TriageIssue{}, false, nil
+}
+
+// Poll applies the rules to every open issue and pull request of repo updated since since, for repositories without
+// webhooks.  It returns the actions taken per issue number.
+func (e *TriageEngine) Poll(ctx context.Context, gh GitHub, repo RepoRef, since time.Time) (map[int64]TriageActions, error) {
+	ret := make(map[int64]TriageActions)
+	pages := newRESTPaginator(func(ctx context.Context, cursor string, _ int) (Page[triageIssueJSON], error) {
+		var issues []triageIssueJSON
+		path := fmt.Sprintf("/repos/%s/%s/issues?state=open&per_page=100&page=%d&since=%s", repo.Owner, repo.Name, restPageNumber(cursor), url.QueryEscape(since.UTC().Format(time.RFC3339)))
+		if err := gh.DoREST(ctx, http.MethodGet, path, nil, &issues); err != nil {
+			return Page[triageIssueJSON]{}, fmt.Errorf("failed to list issues: %w", err)
+		}
+		return restPage(issues, cursor, 100), nil
+	})
+	for pages.HasNext() {
+		issues, err := pages.Next(ctx)
+		if err != nil {
+			return ret, err
+		}
+		for i := range issues {
+			actions, err := e.Apply(ctx, gh, issues[i].toTriageIssue(repo, false))
+			if err != nil {
+				return ret, fmt.Errorf("failed to triage #%d: %w", issues[i].Number, err)
+			}
+			if !actions.IsEmpty() {
+				ret[issues[i].Number] = actions
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,219 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cresta/gogithub/reqmeta"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTriageEngine(t *testing.T) *TriageEngine {
+	e, err := NewTriageEngine([]TriageRule{
+		{
+			Name: "bugs",
+			When: TriageCondition{Title: `(?i)\bcrash|bug\b`, MissingLabels: []string{"triaged"}},
+			Then: TriageActions{AddLabels: []string{"bug", "triaged"}, Assignees: []string{"oncall"}},
+		},
+		{
+			Name: "spam",
+			When: TriageCondition{Authors: []string{"spammer"}},
+			Then: TriageActions{Comment: "Closing as spam", Close: true},
+			Stop: true,
+		},
+		{
+			Name: "needs info",
+			When: TriageCondition{Body: `^\s*$`},
+			Then: TriageActions{AddLabels: []string{"needs-info"}},
+		},
+	})
+	require.NoError(t, err)
+	return e
+}
+
+func TestTriageEngine_Evaluate(t *testing.T) {
+	e := newTestTriageEngine(t)
+	actions := e.Evaluate(TriageIssue{Title: "App crash on start", Labels: []string{"Bug"}})
+	require.Equal(t, []string{"triaged", "needs-info"}, actions.AddLabels)
+	require.Equal(t, []string{"oncall"}, actions.Assignees)
+	require.False(t, actions.Close)
+
+	actions = e.Evaluate(TriageIssue{Title: "buy now", Author: "spammer"})
+	require.True(t, actions.Close)
+	require.Empty(t, actions.AddLabels)
+
+	require.True(t, e.Evaluate(TriageIssue{Title: "Question", Body: "How do I?", Labels: []string{"triaged"}}).IsEmpty())
+}
+
+func TestNewTriageEngine_InvalidPattern(t *testing.T) {
+	_, err := NewTriageEngine([]TriageRule{{Name: "broken", When: TriageCondition{Title: "("}}})
+	require.Error(t, err)
+}
+
+func TestParseTriageEvent(t *testing.T) {
+	issue, ok, err := ParseTriageEvent("issues", []byte(`{"action":"opened","issue":{"number":3,"title":"t","user":{"login":"alice"},"labels":[{"name":"x"}]},"repository":{"name":"r","owner":{"login":"o"}}}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, TriageIssue{Repo: RepoRef{Owner: "o", Name: "r"}, Number: 3, Title: "t", Author: "alice", Labels: []string{"x"}}, issue)
+
+	_, ok, err = ParseTriageEvent("issues", []byte(`{"action":"closed","issue":{"number":3}}`))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestTriageEngine_Apply(t *testing.T) {
+	var paths []string
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	e := newTestTriageEngine(t)
+	issue := TriageIssue{Repo: RepoRef{Owner: "o", Name: "r"}, Number: 5, Title: "buy now", Author: "spammer"}
+
+	_, err := e.Apply(reqmeta.WithDryRun(context.Background(), true), g, issue)
+	require.NoError(t, err)
+	require.Empty(t, paths)
+
+	_, err = e.Apply(context.Background(), g, issue)
+	require.NoError(t, err)
+	require.Equal(t, []string{"GET /repos/o/r/issues/5/comments", "POST /repos/o/r/issues/5/comments", "PATCH /repos/o/r/issues/5"}, paths)
+
+	paths = nil
+	issue.Closed = true
+	_, err = e.Apply(context.Background(), g, issue)
+	require.NoError(t, err)
+	require.NotContains(t, paths, "PATCH /repos/o/r/issues/5", "a closed issue is not closed again")
+}
+
+// fakeTriageRepo is a repository whose issues remember the labels, assignees, comments and state triage gives them
+type fakeTriageRepo struct {
+	t      *testing.T
+	issues map[int64]*fakeTriageIssue
+	writes []string
+}
+
+type fakeTriageIssue struct {
+	Number    int64
+	Title     string
+	Body      string
+	Author    string
+	Labels    []string
+	Assignees []string
+	Comments  []string
+	State     string
+}
+
+func (f *fakeTriageRepo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		f.writes = append(f.writes, r.Method+" "+r.URL.Path)
+	}
+	var body map[string]interface{}
+	if r.Body != nil && r.Method != http.MethodGet {
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+	var number int64
+	var rest string
+	if r.URL.Path != "/repos/o/r/issues" {
+		_, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/repos/o/r/issues/"), "%d", &number)
+		require.NoError(f.t, err)
+		rest = strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/repos/o/r/issues/%d", number))
+	}
+	issue := f.issues[number]
+	var resp interface{} = map[string]interface{}{}
+	switch {
+	case r.URL.Path == "/repos/o/r/issues":
+		require.Equal(f.t, "open", r.URL.Query().Get("state"))
+		var list []map[string]interface{}
+		for n := int64(1); n <= int64(len(f.issues)); n++ {
+			i := f.issues[n]
+			if i.State != "open" {
+				continue
+			}
+			labels := []map[string]string{}
+			for _, l := range i.Labels {
+				labels = append(labels, map[string]string{"name": l})
+			}
+			assignees := []map[string]string{}
+			for _, a := range i.Assignees {
+				assignees = append(assignees, map[string]string{"login": a})
+			}
+			list = append(list, map[string]interface{}{"number": i.Number, "title": i.Title, "body": i.Body, "state": i.State,
+				"user": map[string]string{"login": i.Author}, "labels": labels, "assignees": assignees})
+		}
+		resp = list
+	case rest == "/comments" && r.Method == http.MethodGet:
+		list := []map[string]interface{}{}
+		for id, c := range issue.Comments {
+			list = append(list, map[string]interface{}{"id": id + 1, "body": c, "user": map[string]string{"login": "triage-bot"}})
+		}
+		resp = list
+	case rest == "/comments":
+		issue.Comments = append(issue.Comments, body["body"].(string))
+	case rest == "/labels":
+		for _, l := range body["labels"].([]interface{}) {
+			issue.Labels = append(issue.Labels, l.(string))
+		}
+	case rest == "/assignees":
+		for _, a := range body["assignees"].([]interface{}) {
+			issue.Assignees = append(issue.Assignees, a.(string))
+		}
+	case rest == "" && r.Method == http.MethodPatch:
+		issue.State = body["state"].(string)
+	default:
+		f.t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+	}
+	require.NoError(f.t, json.NewEncoder(w).Encode(resp))
+}
+
+func TestTriageEngine_PollTwice(t *testing.T) {
+	repo := &fakeTriageRepo{t: t, issues: map[int64]*fakeTriageIssue{
+		1: {Number: 1, Title: "App crash on start", Body: "trace", Author: "alice", State: "open"},
+		2: {Number: 2, Title: "Hello", Body: "first issue", Author: "newbie", State: "open"},
+		3: {Number: 3, Title: "buy now", Body: "cheap", Author: "spammer", State: "open"},
+	}}
+	g := newTestRESTClient(t, repo.ServeHTTP)
+	e, err := NewTriageEngine([]TriageRule{
+		{
+			Name: "bugs",
+			When: TriageCondition{Title: `(?i)\bcrash\b`},
+			Then: TriageActions{AddLabels: []string{"bug"}, Assignees: []string{"oncall"}},
+		},
+		{
+			Name: "welcome",
+			When: TriageCondition{Authors: []string{"newbie"}},
+			Then: TriageActions{Comment: "Thanks for your first issue!"},
+		},
+		{
+			Name: "spam",
+			When: TriageCondition{Authors: []string{"spammer"}},
+			Then: TriageActions{Comment: "Closing as spam", Close: true},
+		},
+	})
+	require.NoError(t, err)
+
+	taken, err := e.Poll(context.Background(), g, RepoRef{Owner: "o", Name: "r"}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, taken, 3)
+	require.Equal(t, []string{"bug"}, repo.issues[1].Labels)
+	require.Equal(t, []string{"oncall"}, repo.issues[1].Assignees)
+	require.Len(t, repo.issues[2].Comments, 1)
+	require.True(t, strings.HasPrefix(repo.issues[2].Comments[0], "Thanks for your first issue!\n\n<!-- gogithub-triage:"))
+	require.Equal(t, "closed", repo.issues[3].State)
+
+	// The bot's comment bumped updated_at, so a second poll sees the same issues again
+	repo.writes = nil
+	taken, err = e.Poll(context.Background(), g, RepoRef{Owner: "o", Name: "r"}, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, taken)
+	require.Empty(t, repo.writes)
+	require.Len(t, repo.issues[2].Comments, 1)
+}
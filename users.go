@@ -0,0 +1,102 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// User is a GitHub account, either a person, a bot or an organization
+type User struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+	Login  string `json:"login"`
+	Name   string `json:"name"`
+	// Type is User, Bot or Organization
+	Type      string    `json:"type"`
+	SiteAdmin bool      `json:"site_admin"`
+	Company   string    `json:"company"`
+	Email     string    `json:"email"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrgMembership is the membership of a user in an organization
+type OrgMembership struct {
+	// State is active, or pending while the user has not accepted their invitation
+	State string `json:"state"`
+	// Role is admin, member or billing_manager
+	Role string `json:"role"`
+}
+
+// Active is true if the user accepted their membership
+func (m *OrgMembership) Active() bool {
+	return m != nil && m.State == "active"
+}
+
+// UserRepository is a repository owned by a user
+type UserRepository struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Archived      bool      `json:"archived"`
+	DefaultBranch string    `json:"default_branch"`
+	HTMLURL       string    `json:"html_url"`
+	PushedAt      time.Time `json:"pushed_at"`
+}
+
+// GetUser returns the account login
+func (g *GithubGraphqlAPI) GetUser(ctx context.Context, login string) (_ *User, err error) {
+	ctx = withOperation(ctx, "GetUser")
+	defer annotateError(&err, OperationError{Operation: "GetUser"})
+	g.logger(ctx).Debug("GetUser", zap.String("login", login))
+	defer g.logger(ctx).Debug("Done GetUser")
+	var ret User
+	if err := g.doREST(ctx, http.MethodGet, "/users/"+url.PathEscape(login), nil, &ret); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &ret, nil
+}
+
+// GetUserOrgMembership returns the membership of login in org, or nil if login is neither a member of org nor invited
+// to it.  Outside collaborators are not members.  The client must be a member of org to see other members.
+func (g *GithubGraphqlAPI) GetUserOrgMembership(ctx context.Context, org string, login string) (_ *OrgMembership, err error) {
+	ctx = withOperation(ctx, "GetUserOrgMembership")
+	defer annotateError(&err, OperationError{Operation: "GetUserOrgMembership", Owner: org})
+	g.logger(ctx).Debug("GetUserOrgMembership", zap.String("org", org), zap.String("login", login))
+	defer g.logger(ctx).Debug("Done GetUserOrgMembership")
+	var ret OrgMembership
+	err = g.doREST(ctx, http.MethodGet, fmt.Sprintf("/orgs/%s/memberships/%s", url.PathEscape(org), url.PathEscape(login)), nil, &ret)
+	var restErr *RESTError
+	switch {
+	case err == nil:
+		return &ret, nil
+	case errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to get org membership: %w", err)
+	}
+}
+
+// ListUserRepositories returns the public repositories owned by login, or every repository the client can see if
+// login is the authenticated user
+func (g *GithubGraphqlAPI) ListUserRepositories(ctx context.Context, login string) ([]UserRepository, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[UserRepository], err error) {
+		ctx = withOperation(ctx, "ListUserRepositories")
+		defer annotateError(&err, OperationError{Operation: "ListUserRepositories", Owner: login})
+		g.logger(ctx).Debug("ListUserRepositories", zap.String("login", login), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListUserRepositories")
+		var repos []UserRepository
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/users/%s/repos?type=owner&per_page=100&page=%d", url.PathEscape(login), restPageNumber(cursor)), nil, &repos); err != nil {
+			return Page[UserRepository]{}, fmt.Errorf("failed to list user repositories: %w", err)
+		}
+		return restPage(repos, cursor, 100), nil
+	}).All(ctx)
+}
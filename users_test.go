@@ -0,0 +1,47 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users/alice":
+			_, _ = w.Write([]byte(`{"id":1,"login":"alice","name":"Alice","type":"User"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/orgs/acme/memberships/alice":
+			_, _ = w.Write([]byte(`{"state":"active","role":"member"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/orgs/acme/memberships/bob":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/users/alice/repos":
+			require.Equal(t, "owner", r.URL.Query().Get("type"))
+			_, _ = w.Write([]byte(`[{"id":5,"name":"dotfiles","full_name":"alice/dotfiles","default_branch":"main"}]`))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	u, err := g.GetUser(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.Name)
+	require.Equal(t, "User", u.Type)
+
+	m, err := g.GetUserOrgMembership(ctx, "acme", "alice")
+	require.NoError(t, err)
+	require.True(t, m.Active())
+	require.Equal(t, "member", m.Role)
+
+	m, err = g.GetUserOrgMembership(ctx, "acme", "bob")
+	require.NoError(t, err)
+	require.Nil(t, m)
+	require.False(t, m.Active())
+
+	repos, err := g.ListUserRepositories(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []UserRepository{{ID: 5, Name: "dotfiles", FullName: "alice/dotfiles", DefaultBranch: "main"}}, repos)
+}
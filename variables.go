@@ -0,0 +1,22 @@
+package gogithub
+
+import "sync"
+
+// variablesPool recycles GraphQL variable maps for the hot lookup paths.  githubv4 does not keep the map after
+// Query returns, so it is safe to reuse.
+var variablesPool = sync.Pool{
+	New: func() interface{} {
+		return make(map[string]interface{}, 4)
+	},
+}
+
+func getVariables() map[string]interface{} {
+	return variablesPool.Get().(map[string]interface{})
+}
+
+func putVariables(m map[string]interface{}) {
+	for k := range m {
+		delete(m, k)
+	}
+	variablesPool.Put(m)
+}
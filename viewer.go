@@ -0,0 +1,94 @@
+package gogithub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// DefaultSelfCacheTTL is how long the Self result of a token without known expiry, such as a personal access token,
+// is cached.  The identity behind a token does not change, so this only bounds how long a renamed user goes unnoticed.
+const DefaultSelfCacheTTL = 24 * time.Hour
+
+// ViewerIdentity is who the client's credentials authenticate as
+type ViewerIdentity struct {
+	Login string
+	// ID is the node ID of the user or bot
+	ID githubv4.ID
+	// DatabaseID is the numeric ID of the user or bot, as used by the REST API
+	DatabaseID int64
+}
+
+// selfKey identifies the token a Self result was looked up with, without keeping the token itself
+type selfKey struct {
+	tokenHash string
+}
+
+func newSelfKey(token string) selfKey {
+	sum := sha256.Sum256([]byte(token))
+	return selfKey{tokenHash: hex.EncodeToString(sum[:])}
+}
+
+// selfCacheTTL is how long the Self result of token is cached: the lifetime of the token, capped at
+// g.selfCache.DefaultExpiry
+func (g *GithubGraphqlAPI) selfCacheTTL(token *TokenInfo) time.Duration {
+	ttl := g.selfCache.DefaultExpiry
+	if !token.ExpiresAt.IsZero() {
+		if lifetime := time.Until(token.ExpiresAt); lifetime < ttl {
+			ttl = lifetime
+		}
+	}
+	return ttl
+}
+
+func (g *GithubGraphqlAPI) Viewer(ctx context.Context) (_ *ViewerIdentity, err error) {
+	ctx = withOperation(ctx, "Viewer")
+	defer annotateError(&err, OperationError{Operation: "Viewer"})
+	return g.viewer(ctx)
+}
+
+func (g *GithubGraphqlAPI) Self(ctx context.Context) (_ string, err error) {
+	ctx = withOperation(ctx, "Self")
+	defer annotateError(&err, OperationError{Operation: "Self"})
+	viewer, err := g.viewer(ctx)
+	if err != nil {
+		return "", err
+	}
+	return viewer.Login, nil
+}
+
+// viewer looks up the identity of the current token, cached for the token's lifetime
+func (g *GithubGraphqlAPI) viewer(ctx context.Context) (*ViewerIdentity, error) {
+	g.logger(ctx).Debug("fetching viewer")
+	defer g.logger(ctx).Debug("done fetching viewer")
+	token, err := g.GetTokenInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	key := newSelfKey(token.Token)
+	if viewer, exists := cacheGet(ctx, &g.selfCache, key); exists {
+		g.logger(ctx).Debug("viewer cached value")
+		return &viewer, nil
+	}
+	var q struct {
+		Viewer struct {
+			Login      githubv4.String
+			ID         githubv4.ID
+			DatabaseID githubv4.Int `graphql:"databaseId"`
+		}
+	}
+	if err := g.ClientV4.Query(ctx, &q, nil); err != nil {
+		return nil, fmt.Errorf("unable to run graphql query viewer: %w", err)
+	}
+	viewer := ViewerIdentity{
+		Login:      string(q.Viewer.Login),
+		ID:         q.Viewer.ID,
+		DatabaseID: int64(q.Viewer.DatabaseID),
+	}
+	cacheSetWithTTL(ctx, &g.selfCache, key, viewer, g.selfCacheTTL(token))
+	return &viewer, nil
+}
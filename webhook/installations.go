@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Installation is where a GitHub App is installed
+type Installation struct {
+	ID int64
+	// Account is the login of the user or organization the app is installed on
+	Account     string
+	AccountType string
+	// AllRepositories is true when the installation covers every repository of the account, in which case Repositories
+	// only lists the repositories reported so far
+	AllRepositories bool
+	Suspended       bool
+	Repositories    []Repository
+}
+
+type Repository struct {
+	ID       int64  `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+// InstallationRegistry stores installations.  Implement it to persist them; MemoryRegistry keeps them in memory.
+type InstallationRegistry interface {
+	// PutInstallation adds or replaces an installation
+	PutInstallation(ctx context.Context, installation Installation) error
+	RemoveInstallation(ctx context.Context, installationID int64) error
+	SetSuspended(ctx context.Context, installationID int64, suspended bool) error
+	// SetAllRepositories records whether the installation covers every repository of the account
+	SetAllRepositories(ctx context.Context, installationID int64, all bool) error
+	AddRepositories(ctx context.Context, installationID int64, repos []Repository) error
+	RemoveRepositories(ctx context.Context, installationID int64, repos []Repository) error
+}
+
+// MemoryRegistry is an InstallationRegistry held in memory
+type MemoryRegistry struct {
+	mu            sync.RWMutex
+	installations map[int64]*Installation
+}
+
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		installations: make(map[int64]*Installation),
+	}
+}
+
+func (m *MemoryRegistry) PutInstallation(_ context.Context, installation Installation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	installation.Repositories = append([]Repository(nil), installation.Repositories...)
+	m.installations[installation.ID] = &installation
+	return nil
+}
+
+func (m *MemoryRegistry) RemoveInstallation(_ context.Context, installationID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.installations, installationID)
+	return nil
+}
+
+func (m *MemoryRegistry) SetSuspended(_ context.Context, installationID int64, suspended bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if inst, exists := m.installations[installationID]; exists {
+		inst.Suspended = suspended
+	}
+	return nil
+}
+
+func (m *MemoryRegistry) SetAllRepositories(_ context.Context, installationID int64, all bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if inst, exists := m.installations[installationID]; exists {
+		inst.AllRepositories = all
+	}
+	return nil
+}
+
+func (m *MemoryRegistry) AddRepositories(_ context.Context, installationID int64, repos []Repository) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst, exists := m.installations[installationID]
+	if !exists {
+		inst = &Installation{ID: installationID}
+		m.installations[installationID] = inst
+	}
+	for _, r := range repos {
+		if !containsRepo(inst.Repositories, r.ID) {
+			inst.Repositories = append(inst.Repositories, r)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRegistry) RemoveRepositories(_ context.Context, installationID int64, repos []Repository) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst, exists := m.installations[installationID]
+	if !exists {
+		return nil
+	}
+	kept := inst.Repositories[:0]
+	for _, r := range inst.Repositories {
+		if !containsRepo(repos, r.ID) {
+			kept = append(kept, r)
+		}
+	}
+	inst.Repositories = kept
+	return nil
+}
+
+func containsRepo(repos []Repository, id int64) bool {
+	for _, r := range repos {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Installations returns a copy of every installation, ordered by ID
+func (m *MemoryRegistry) Installations() []Installation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ret := make([]Installation, 0, len(m.installations))
+	for _, inst := range m.installations {
+		c := *inst
+		c.Repositories = append([]Repository(nil), inst.Repositories...)
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+// InstallationForRepository returns the ID of the active installation covering fullName, such as cresta/gogithub
+func (m *MemoryRegistry) InstallationForRepository(fullName string) (int64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, inst := range m.installations {
+		if inst.Suspended {
+			continue
+		}
+		for _, r := range inst.Repositories {
+			if r.FullName == fullName {
+				return inst.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+var _ InstallationRegistry = &MemoryRegistry{}
+
+type installationPayload struct {
+	ID      int64 `json:"id"`
+	Account struct {
+		Login string `json:"login"`
+		Type  string `json:"type"`
+	} `json:"account"`
+	RepositorySelection string `json:"repository_selection"`
+}
+
+type installationEvent struct {
+	Action              string              `json:"action"`
+	Installation        installationPayload `json:"installation"`
+	Repositories        []Repository        `json:"repositories"`
+	RepositoriesAdded   []Repository        `json:"repositories_added"`
+	RepositoriesRemoved []Repository        `json:"repositories_removed"`
+	RepositorySelection string              `json:"repository_selection"`
+}
+
+// InstallationHandler keeps an InstallationRegistry up to date from installation and installation_repositories events.
+// ServeHTTP rejects every delivery unless Secret is set; deliveries verified elsewhere go through HandleEvent.
+type InstallationHandler struct {
+	Secret   []byte
+	Registry InstallationRegistry
+	Logger   *zap.Logger
+}
+
+func NewInstallationHandler(secret []byte, registry InstallationRegistry, logger *zap.Logger) *InstallationHandler {
+	return &InstallationHandler{
+		Secret:   secret,
+		Registry: registry,
+		Logger:   logger,
+	}
+}
+
+func (h *InstallationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	eventType, payload, err := ReadDelivery(r, h.Secret)
+	if err != nil {
+		h.Logger.Warn("rejected webhook delivery", zap.Error(err), zap.String("delivery", r.Header.Get("X-GitHub-Delivery")))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := h.HandleEvent(r.Context(), eventType, payload); err != nil {
+		h.Logger.Error("failed to handle installation event", zap.Error(err), zap.String("event", eventType))
+		http.Error(w, "failed to handle event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleEvent applies an already verified delivery to the registry.  Other event types are ignored.
+func (h *InstallationHandler) HandleEvent(ctx context.Context, eventType string, payload []byte) error {
+	if eventType != "installation" && eventType != "installation_repositories" {
+		return nil
+	}
+	var ev installationEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return fmt.Errorf("failed to parse %s event: %w", eventType, err)
+	}
+	id := ev.Installation.ID
+	h.Logger.Debug("installation event", zap.String("event", eventType), zap.String("action", ev.Action), zap.Int64("installation", id))
+	switch eventType + "." + ev.Action {
+	case "installation.created":
+		return h.Registry.PutInstallation(ctx, Installation{
+			ID:              id,
+			Account:         ev.Installation.Account.Login,
+			AccountType:     ev.Installation.Account.Type,
+			AllRepositories: ev.Installation.RepositorySelection == "all",
+			Repositories:    ev.Repositories,
+		})
+	case "installation.deleted":
+		return h.Registry.RemoveInstallation(ctx, id)
+	case "installation.suspend":
+		return h.Registry.SetSuspended(ctx, id, true)
+	case "installation.unsuspend":
+		return h.Registry.SetSuspended(ctx, id, false)
+	case "installation_repositories.added", "installation_repositories.removed":
+		if err := h.Registry.AddRepositories(ctx, id, ev.RepositoriesAdded); err != nil {
+			return err
+		}
+		if err := h.Registry.RemoveRepositories(ctx, id, ev.RepositoriesRemoved); err != nil {
+			return err
+		}
+		// Switching between all and selected repositories is reported as an added or removed event
+		if ev.RepositorySelection != "" {
+			return h.Registry.SetAllRepositories(ctx, id, ev.RepositorySelection == "all")
+		}
+	}
+	return nil
+}
+
+var _ http.Handler = &InstallationHandler{}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func sign(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidateSignature(t *testing.T) {
+	secret := []byte("s3cret")
+	payload := []byte(`{"action":"created"}`)
+	require.NoError(t, ValidateSignature(secret, sign(secret, payload), payload))
+	require.ErrorIs(t, ValidateSignature(secret, sign([]byte("other"), payload), payload), ErrInvalidSignature)
+	require.ErrorIs(t, ValidateSignature(secret, "sha1=abc", payload), ErrInvalidSignature)
+}
+
+func TestInstallationHandler(t *testing.T) {
+	secret := []byte("s3cret")
+	registry := NewMemoryRegistry()
+	h := NewInstallationHandler(secret, registry, zaptest.NewLogger(t))
+	deliver := func(event string, payload string, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(payload))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	created := `{"action":"created","installation":{"id":1,"account":{"login":"cresta","type":"Organization"},"repository_selection":"selected"},"repositories":[{"id":10,"full_name":"cresta/a"}]}`
+	require.Equal(t, http.StatusUnauthorized, deliver("installation", created, "sha256=00"))
+	require.Equal(t, http.StatusNoContent, deliver("installation", created, sign(secret, []byte(created))))
+	id, ok := registry.InstallationForRepository("cresta/a")
+	require.True(t, ok)
+	require.Equal(t, int64(1), id)
+
+	added := `{"action":"added","installation":{"id":1},"repositories_added":[{"id":11,"full_name":"cresta/b"}],"repositories_removed":[{"id":10,"full_name":"cresta/a"}]}`
+	require.Equal(t, http.StatusNoContent, deliver("installation_repositories", added, sign(secret, []byte(added))))
+	_, ok = registry.InstallationForRepository("cresta/a")
+	require.False(t, ok)
+	_, ok = registry.InstallationForRepository("cresta/b")
+	require.True(t, ok)
+	require.False(t, registry.Installations()[0].AllRepositories)
+
+	all := `{"action":"added","installation":{"id":1},"repository_selection":"all","repositories_added":[{"id":12,"full_name":"cresta/c"}]}`
+	require.Equal(t, http.StatusNoContent, deliver("installation_repositories", all, sign(secret, []byte(all))))
+	require.True(t, registry.Installations()[0].AllRepositories)
+
+	require.NoError(t, h.HandleEvent(context.Background(), "installation", []byte(`{"action":"suspend","installation":{"id":1}}`)))
+	_, ok = registry.InstallationForRepository("cresta/b")
+	require.False(t, ok)
+
+	require.NoError(t, h.HandleEvent(context.Background(), "installation", []byte(`{"action":"deleted","installation":{"id":1}}`)))
+	require.Empty(t, registry.Installations())
+}
+
+func TestInstallationHandler_NoSecret(t *testing.T) {
+	registry := NewMemoryRegistry()
+	h := NewInstallationHandler(nil, registry, zaptest.NewLogger(t))
+	created := `{"action":"created","installation":{"id":1,"account":{"login":"cresta","type":"Organization"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(created))
+	req.Header.Set("X-GitHub-Event", "installation")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Empty(t, registry.Installations())
+
+	_, _, err := ReadDelivery(httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(created)), nil)
+	require.ErrorIs(t, err, ErrNoSecret)
+}
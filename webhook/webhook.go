@@ -0,0 +1,56 @@
+// Package webhook receives GitHub webhook deliveries.  It verifies signatures and routes events to handlers such as
+// the installation registry, independent of the API client.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxPayloadSize bounds how much of a delivery is read.  GitHub caps payloads at 25MB.
+const MaxPayloadSize = 25 << 20
+
+// ErrInvalidSignature is returned when a delivery is not signed with the webhook secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrNoSecret is returned when a delivery is read without a webhook secret to verify it against
+var ErrNoSecret = errors.New("no webhook secret configured")
+
+// ValidateSignature checks signature, the X-Hub-Signature-256 header, against payload signed with secret
+func ValidateSignature(secret []byte, signature string, payload []byte) error {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ReadDelivery reads and verifies the payload of a delivery.  It returns the event type from X-GitHub-Event.  An empty
+// secret fails with ErrNoSecret rather than accept unsigned deliveries.
+func ReadDelivery(r *http.Request, secret []byte) (string, []byte, error) {
+	if len(secret) == 0 {
+		return "", nil, ErrNoSecret
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, MaxPayloadSize))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	if err := ValidateSignature(secret, r.Header.Get("X-Hub-Signature-256"), payload); err != nil {
+		return "", nil, err
+	}
+	return r.Header.Get("X-GitHub-Event"), payload, nil
+}
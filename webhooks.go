@@ -0,0 +1,169 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WebhookConfig is where and how GitHub delivers the events of a webhook
+type WebhookConfig struct {
+	URL string
+	// ContentType is json or form.  Defaults to json.
+	ContentType string
+	// Secret signs the deliveries, checked by webhook receivers.  GitHub never returns it: it reads back masked.
+	Secret string
+	// InsecureSSL skips verifying the certificate of URL
+	InsecureSSL bool
+}
+
+// Webhook is a repository webhook
+type Webhook struct {
+	ID     int64
+	Active bool
+	// Events are the events delivered, such as push and pull_request, or * for all of them
+	Events    []string
+	Config    WebhookConfig
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WebhookInput is the configuration CreateWebhook and UpdateWebhook apply
+type WebhookInput struct {
+	Config WebhookConfig
+	// Events default to push when creating, and are left unchanged when updating
+	Events []string
+	Active bool
+}
+
+type webhookConfigJSON struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	InsecureSSL string `json:"insecure_ssl"`
+}
+
+type webhookJSON struct {
+	ID        int64             `json:"id"`
+	Active    bool              `json:"active"`
+	Events    []string          `json:"events"`
+	Config    webhookConfigJSON `json:"config"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func (w *webhookJSON) toWebhook() Webhook {
+	return Webhook{
+		ID:     w.ID,
+		Active: w.Active,
+		Events: w.Events,
+		Config: WebhookConfig{
+			URL:         w.Config.URL,
+			ContentType: w.Config.ContentType,
+			Secret:      w.Config.Secret,
+			InsecureSSL: w.Config.InsecureSSL == "1",
+		},
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+func (in WebhookInput) body() map[string]interface{} {
+	config := webhookConfigJSON{
+		URL:         in.Config.URL,
+		ContentType: in.Config.ContentType,
+		Secret:      in.Config.Secret,
+		InsecureSSL: "0",
+	}
+	if config.ContentType == "" {
+		config.ContentType = "json"
+	}
+	if in.Config.InsecureSSL {
+		config.InsecureSSL = "1"
+	}
+	ret := map[string]interface{}{
+		"config": config,
+		"active": in.Active,
+	}
+	if len(in.Events) > 0 {
+		ret["events"] = in.Events
+	}
+	return ret
+}
+
+// ListWebhooks returns every webhook of a repository
+func (g *GithubGraphqlAPI) ListWebhooks(ctx context.Context, owner string, name string) ([]Webhook, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[Webhook], err error) {
+		ctx = withOperation(ctx, "ListWebhooks")
+		defer annotateError(&err, OperationError{Operation: "ListWebhooks", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListWebhooks", zap.String("owner", owner), zap.String("name", name), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListWebhooks")
+		var hooks []webhookJSON
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/hooks?per_page=100&page=%d", owner, name, restPageNumber(cursor)), nil, &hooks); err != nil {
+			return Page[Webhook]{}, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+		items := make([]Webhook, 0, len(hooks))
+		for i := range hooks {
+			items = append(items, hooks[i].toWebhook())
+		}
+		return restPage(items, cursor, 100), nil
+	}).All(ctx)
+}
+
+// CreateWebhook adds a webhook to a repository.  GitHub sends it a ping event right away.
+func (g *GithubGraphqlAPI) CreateWebhook(ctx context.Context, owner string, name string, input WebhookInput) (_ *Webhook, err error) {
+	ctx = withOperation(ctx, "CreateWebhook")
+	defer annotateError(&err, OperationError{Operation: "CreateWebhook", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("CreateWebhook", zap.String("owner", owner), zap.String("name", name), zap.String("url", input.Config.URL), zap.Strings("events", input.Events))
+	defer g.logger(ctx).Debug("Done CreateWebhook")
+	body := input.body()
+	body["name"] = "web"
+	var resp webhookJSON
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/hooks", owner, name), body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	ret := resp.toWebhook()
+	return &ret, nil
+}
+
+// UpdateWebhook replaces the configuration of a webhook.  GitHub replaces the whole Config, so set Config.Secret again
+// to keep signing deliveries.
+func (g *GithubGraphqlAPI) UpdateWebhook(ctx context.Context, owner string, name string, hookID int64, input WebhookInput) (_ *Webhook, err error) {
+	ctx = withOperation(ctx, "UpdateWebhook")
+	defer annotateError(&err, OperationError{Operation: "UpdateWebhook", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("UpdateWebhook", zap.String("owner", owner), zap.String("name", name), zap.Int64("hookID", hookID))
+	defer g.logger(ctx).Debug("Done UpdateWebhook")
+	var resp webhookJSON
+	if err := g.doREST(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/hooks/%d", owner, name, hookID), input.body(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to update webhook %d: %w", hookID, err)
+	}
+	ret := resp.toWebhook()
+	return &ret, nil
+}
+
+// DeleteWebhook removes a webhook from a repository
+func (g *GithubGraphqlAPI) DeleteWebhook(ctx context.Context, owner string, name string, hookID int64) (err error) {
+	ctx = withOperation(ctx, "DeleteWebhook")
+	defer annotateError(&err, OperationError{Operation: "DeleteWebhook", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("DeleteWebhook", zap.String("owner", owner), zap.String("name", name), zap.Int64("hookID", hookID))
+	defer g.logger(ctx).Debug("Done DeleteWebhook")
+	if err := g.doREST(ctx, http.MethodDelete, fmt.Sprintf("/repos/%s/%s/hooks/%d", owner, name, hookID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook %d: %w", hookID, err)
+	}
+	return nil
+}
+
+// PingWebhook asks GitHub to deliver a ping event to a webhook, to check a receiver end to end
+func (g *GithubGraphqlAPI) PingWebhook(ctx context.Context, owner string, name string, hookID int64) (err error) {
+	ctx = withOperation(ctx, "PingWebhook")
+	defer annotateError(&err, OperationError{Operation: "PingWebhook", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("PingWebhook", zap.String("owner", owner), zap.String("name", name), zap.Int64("hookID", hookID))
+	defer g.logger(ctx).Debug("Done PingWebhook")
+	if err := g.doREST(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/hooks/%d/pings", owner, name, hookID), nil, nil); err != nil {
+		return fmt.Errorf("failed to ping webhook %d: %w", hookID, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	var created, updated map[string]interface{}
+	var pinged, deleted bool
+	hook := `{"id":5,"active":true,"events":["push","pull_request"],"config":{"url":"https://hooks.example.com/github","content_type":"json","secret":"********","insecure_ssl":"0"}}`
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/hooks":
+			_, _ = w.Write([]byte(`[` + hook + `]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/hooks":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(hook))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/hooks/5":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			_, _ = w.Write([]byte(hook))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/hooks/5/pings":
+			pinged = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/o/r/hooks/5":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	ctx := context.Background()
+	hooks, err := g.ListWebhooks(ctx, "o", "r")
+	require.NoError(t, err)
+	require.Equal(t, []Webhook{{
+		ID:     5,
+		Active: true,
+		Events: []string{"push", "pull_request"},
+		Config: WebhookConfig{URL: "https://hooks.example.com/github", ContentType: "json", Secret: "********"},
+	}}, hooks)
+
+	input := WebhookInput{
+		Config: WebhookConfig{URL: "https://hooks.example.com/github", Secret: "s3cret"},
+		Events: []string{"push", "pull_request"},
+		Active: true,
+	}
+	_, err = g.CreateWebhook(ctx, "o", "r", input)
+	require.NoError(t, err)
+	require.Equal(t, "web", created["name"])
+	require.Equal(t, map[string]interface{}{"url": "https://hooks.example.com/github", "content_type": "json", "secret": "s3cret", "insecure_ssl": "0"}, created["config"])
+
+	input.Config.InsecureSSL = true
+	input.Events = nil
+	_, err = g.UpdateWebhook(ctx, "o", "r", 5, input)
+	require.NoError(t, err)
+	require.NotContains(t, updated, "events")
+	require.Equal(t, "1", updated["config"].(map[string]interface{})["insecure_ssl"])
+
+	require.NoError(t, g.PingWebhook(ctx, "o", "r", 5))
+	require.True(t, pinged)
+	require.NoError(t, g.DeleteWebhook(ctx, "o", "r", 5))
+	require.True(t, deleted)
+}
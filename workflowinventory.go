@@ -0,0 +1,266 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shurcooL/githubv4"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowsDir is where GitHub Actions looks for workflow files
+const WorkflowsDir = ".github/workflows"
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// WorkflowFile is a workflow file with its content
+type WorkflowFile struct {
+	Path    string
+	Content []byte
+}
+
+// ActionReference is the uses of a workflow step, or of a job calling a reusable workflow
+type ActionReference struct {
+	// Job is the id of the job the reference is in
+	Job string
+	// Uses is the raw value, such as actions/checkout@v4
+	Uses string
+	// Action is the owner/repo or owner/repo/path part of Uses.  It is empty for local and docker references.
+	Action string
+	// Ref is the part after @, a tag, branch or commit SHA
+	Ref string
+	// Line and Column locate the value of uses in the file
+	Line   int
+	Column int
+}
+
+// IsLocal reports whether the reference points into the same repository, like ./.github/actions/build
+func (a ActionReference) IsLocal() bool {
+	return strings.HasPrefix(a.Uses, "./")
+}
+
+// IsDocker reports whether the reference is a docker:// image
+func (a ActionReference) IsDocker() bool {
+	return strings.HasPrefix(a.Uses, "docker://")
+}
+
+// IsPinned reports whether Ref is a full commit SHA, the only ref that cannot be moved under the workflow
+func (a ActionReference) IsPinned() bool {
+	return commitSHAPattern.MatchString(a.Ref)
+}
+
+// Repo returns the repository hosting the action.  It is empty for local and docker references.
+func (a ActionReference) Repo() RepoRef {
+	parts := strings.SplitN(a.Action, "/", 3)
+	if len(parts) < 2 {
+		return RepoRef{}
+	}
+	return RepoRef{Owner: parts[0], Name: parts[1]}
+}
+
+// IsThirdParty reports whether the action is hosted outside GitHub's own actions and github organizations and outside
+// trustedOwners
+func (a ActionReference) IsThirdParty(trustedOwners ...string) bool {
+	owner := a.Repo().Owner
+	if owner == "" {
+		return false
+	}
+	for _, t := range append([]string{"actions", "github"}, trustedOwners...) {
+		if strings.EqualFold(owner, t) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseActionReference(job string, n *yaml.Node) ActionReference {
+	ret := ActionReference{
+		Job:    job,
+		Uses:   n.Value,
+		Line:   n.Line,
+		Column: n.Column,
+	}
+	if ret.IsLocal() || ret.IsDocker() {
+		return ret
+	}
+	ret.Action, ret.Ref, _ = strings.Cut(n.Value, "@")
+	return ret
+}
+
+// WorkflowSummary is what the inventory reports about one workflow file
+type WorkflowSummary struct {
+	Repo RepoRef
+	Path string
+	// Name is the name of the workflow, or its path if it has none
+	Name string
+	// Triggers are the events the workflow runs on, sorted
+	Triggers []string
+	// Actions are every uses of the workflow, in file order
+	Actions []ActionReference
+}
+
+// UnpinnedActions returns the third party actions not pinned to a commit SHA
+func (w *WorkflowSummary) UnpinnedActions(trustedOwners ...string) []ActionReference {
+	var ret []ActionReference
+	for _, a := range w.Actions {
+		if a.IsThirdParty(trustedOwners...) && !a.IsPinned() {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// SummarizeWorkflow extracts the name, triggers and action references of a workflow file
+func SummarizeWorkflow(repo RepoRef, path string, data []byte) (*WorkflowSummary, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	ret := &WorkflowSummary{
+		Repo: repo,
+		Path: path,
+		Name: path,
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return ret, nil
+	}
+	root := doc.Content[0]
+	if _, name := yamlGet(root, "name"); name != nil && name.Value != "" {
+		ret.Name = name.Value
+	}
+	if _, on := yamlGet(root, "on"); on != nil {
+		switch on.Kind {
+		case yaml.ScalarNode:
+			ret.Triggers = []string{on.Value}
+		case yaml.SequenceNode:
+			for _, e := range on.Content {
+				ret.Triggers = append(ret.Triggers, e.Value)
+			}
+		case yaml.MappingNode:
+			yamlPairs(on, func(k *yaml.Node, _ *yaml.Node) {
+				ret.Triggers = append(ret.Triggers, k.Value)
+			})
+		}
+		sort.Strings(ret.Triggers)
+	}
+	_, jobs := yamlGet(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return ret, nil
+	}
+	yamlPairs(jobs, func(k *yaml.Node, job *yaml.Node) {
+		if _, uses := yamlGet(job, "uses"); uses != nil {
+			ret.Actions = append(ret.Actions, parseActionReference(k.Value, uses))
+		}
+		_, steps := yamlGet(job, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			return
+		}
+		for _, step := range steps.Content {
+			if _, uses := yamlGet(step, "uses"); uses != nil {
+				ret.Actions = append(ret.Actions, parseActionReference(k.Value, uses))
+			}
+		}
+	})
+	return ret, nil
+}
+
+// ListWorkflowFiles returns every workflow file of a repository on ref, or on the default branch if ref is empty.  The
+// files are fetched with a single query.
+func (g *GithubGraphqlAPI) ListWorkflowFiles(ctx context.Context, owner string, name string, ref string) (_ []WorkflowFile, err error) {
+	ctx = withOperation(ctx, "ListWorkflowFiles")
+	defer annotateError(&err, OperationError{Operation: "ListWorkflowFiles", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ListWorkflowFiles", zap.String("owner", owner), zap.String("name", name), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done ListWorkflowFiles")
+	if ref == "" {
+		ref = "HEAD"
+	}
+	var query struct {
+		Repository struct {
+			Object *struct {
+				Tree struct {
+					Entries []struct {
+						Name   string
+						Type   string
+						Object struct {
+							Blob struct {
+								Text        *string
+								IsTruncated bool
+							} `graphql:"... on Blob"`
+						}
+					}
+				} `graphql:"... on Tree"`
+			} `graphql:"object(expression: $expression)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	if err := g.ClientV4.Query(ctx, &query, map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"name":       githubv4.String(name),
+		"expression": githubv4.String(ref + ":" + WorkflowsDir),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to query workflow files: %w", err)
+	}
+	if query.Repository.Object == nil {
+		return nil, nil
+	}
+	var ret []WorkflowFile
+	for _, e := range query.Repository.Object.Tree.Entries {
+		if e.Type != "blob" || !(strings.HasSuffix(e.Name, ".yml") || strings.HasSuffix(e.Name, ".yaml")) {
+			continue
+		}
+		path := WorkflowsDir + "/" + e.Name
+		blob := e.Object.Blob
+		if blob.Text != nil && !blob.IsTruncated {
+			ret = append(ret, WorkflowFile{Path: path, Content: []byte(*blob.Text)})
+			continue
+		}
+		// GraphQL leaves out the text of large blobs
+		content, err := g.GetFileContents(ctx, owner, name, path, ref)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, WorkflowFile{Path: path, Content: content})
+	}
+	return ret, nil
+}
+
+// InventoryWorkflows summarizes every workflow on the default branch of repos, for example to audit unpinned third
+// party actions across an organization.  Workflows that fail to parse are reported in the error with the repositories
+// that failed, while the others are still returned.
+func InventoryWorkflows(ctx context.Context, gh GitHub, repos []RepoRef, opts ...FanOutOption) ([]WorkflowSummary, error) {
+	var mu sync.Mutex
+	var ret []WorkflowSummary
+	err := FanOut(ctx, repos, func(ctx context.Context, repo RepoRef) error {
+		files, err := gh.ListWorkflowFiles(ctx, repo.Owner, repo.Name, "")
+		if err != nil {
+			return err
+		}
+		var invalid []string
+		for _, f := range files {
+			summary, err := SummarizeWorkflow(repo, f.Path, f.Content)
+			if err != nil {
+				invalid = append(invalid, err.Error())
+				continue
+			}
+			mu.Lock()
+			ret = append(ret, *summary)
+			mu.Unlock()
+		}
+		if len(invalid) > 0 {
+			return errors.New(strings.Join(invalid, "; "))
+		}
+		return nil
+	}, opts...)
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Repo != ret[j].Repo {
+			return ret[i].Repo.String() < ret[j].Repo.String()
+		}
+		return ret[i].Path < ret[j].Path
+	})
+	return ret, err
+}
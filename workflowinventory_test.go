@@ -0,0 +1,76 @@
+package gogithub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const inventoryWorkflow = `name: ci
+on:
+  pull_request:
+  push:
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: docker/build-push-action@v5
+      - uses: golangci/golangci-lint-action@3cfe3a4abbb849e10058ce4af15d205b6da42804 # v4
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3
+  release:
+    uses: cresta/workflows/.github/workflows/release.yaml@main
+`
+
+func TestSummarizeWorkflow(t *testing.T) {
+	repo := RepoRef{Owner: "o", Name: "r"}
+	w, err := SummarizeWorkflow(repo, ".github/workflows/ci.yaml", []byte(inventoryWorkflow))
+	require.NoError(t, err)
+	require.Equal(t, "ci", w.Name)
+	require.Equal(t, []string{"pull_request", "push"}, w.Triggers)
+	require.Len(t, w.Actions, 6)
+	require.Equal(t, ActionReference{Job: "build", Uses: "docker/build-push-action@v5", Action: "docker/build-push-action", Ref: "v5", Line: 10, Column: 15}, w.Actions[1])
+	require.True(t, w.Actions[2].IsPinned())
+	require.True(t, w.Actions[3].IsLocal())
+	require.True(t, w.Actions[4].IsDocker())
+	require.Equal(t, RepoRef{Owner: "cresta", Name: "workflows"}, w.Actions[5].Repo())
+
+	var unpinned []string
+	for _, a := range w.UnpinnedActions("cresta") {
+		unpinned = append(unpinned, a.Uses)
+	}
+	require.Equal(t, []string{"docker/build-push-action@v5"}, unpinned)
+}
+
+type inventoryGitHub struct {
+	GitHub
+	files map[RepoRef][]WorkflowFile
+}
+
+func (f *inventoryGitHub) ListWorkflowFiles(_ context.Context, owner string, name string, _ string) ([]WorkflowFile, error) {
+	files, ok := f.files[RepoRef{Owner: owner, Name: name}]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return files, nil
+}
+
+func TestInventoryWorkflows(t *testing.T) {
+	gh := &inventoryGitHub{files: map[RepoRef][]WorkflowFile{
+		{Owner: "o", Name: "b"}: {{Path: ".github/workflows/ci.yaml", Content: []byte(inventoryWorkflow)}},
+		{Owner: "o", Name: "a"}: {
+			{Path: ".github/workflows/x.yaml", Content: []byte("on: push\njobs: {}\n")},
+			{Path: ".github/workflows/bad.yaml", Content: []byte("on: [push\n")},
+		},
+	}}
+	summaries, err := InventoryWorkflows(context.Background(), gh, []RepoRef{{Owner: "o", Name: "b"}, {Owner: "o", Name: "a"}, {Owner: "o", Name: "missing"}})
+	var fanOutErr *FanOutError
+	require.True(t, errors.As(err, &fanOutErr))
+	require.Len(t, fanOutErr.Errors, 2)
+	require.Len(t, summaries, 2)
+	require.Equal(t, "o/a", summaries[0].Repo.String())
+	require.Equal(t, "ci", summaries[1].Name)
+}
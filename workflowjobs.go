@@ -0,0 +1,98 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WorkflowStep is a step of a workflow job
+type WorkflowStep struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	// Status is queued, in_progress or completed
+	Status string `json:"status"`
+	// Conclusion is success, failure, cancelled or skipped once Status is completed
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// Duration is how long the step ran, or zero if it has not completed
+func (s WorkflowStep) Duration() time.Duration {
+	return runDuration(s.StartedAt, s.CompletedAt)
+}
+
+// WorkflowJob is a job of a workflow run, with its steps and the runner it ran on
+type WorkflowJob struct {
+	ID         int64  `json:"id"`
+	RunID      int64  `json:"run_id"`
+	RunAttempt int    `json:"run_attempt"`
+	Name       string `json:"name"`
+	HeadSHA    string `json:"head_sha"`
+	HTMLURL    string `json:"html_url"`
+	// Status is queued, in_progress, completed or waiting
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// Labels are the runs-on labels the job asked a runner for
+	Labels          []string       `json:"labels"`
+	RunnerID        int64          `json:"runner_id"`
+	RunnerName      string         `json:"runner_name"`
+	RunnerGroupName string         `json:"runner_group_name"`
+	Steps           []WorkflowStep `json:"steps"`
+}
+
+// QueueDuration is how long the job waited for a runner, or zero if it has not started
+func (j WorkflowJob) QueueDuration() time.Duration {
+	return runDuration(&j.CreatedAt, j.StartedAt)
+}
+
+// Duration is how long the job ran, or zero if it has not completed
+func (j WorkflowJob) Duration() time.Duration {
+	return runDuration(j.StartedAt, j.CompletedAt)
+}
+
+func runDuration(start *time.Time, end *time.Time) time.Duration {
+	if start == nil || end == nil || start.IsZero() || end.Before(*start) {
+		return 0
+	}
+	return end.Sub(*start)
+}
+
+// ListWorkflowJobs returns the jobs of the latest attempt of a workflow run
+func (g *GithubGraphqlAPI) ListWorkflowJobs(ctx context.Context, owner string, name string, runID int64) ([]WorkflowJob, error) {
+	return newRESTPaginator(func(ctx context.Context, cursor string, _ int) (_ Page[WorkflowJob], err error) {
+		ctx = withOperation(ctx, "ListWorkflowJobs")
+		defer annotateError(&err, OperationError{Operation: "ListWorkflowJobs", Owner: owner, Repo: name})
+		g.logger(ctx).Debug("ListWorkflowJobs", zap.String("owner", owner), zap.String("name", name), zap.Int64("run", runID), zap.String("cursor", cursor))
+		defer g.logger(ctx).Debug("Done ListWorkflowJobs")
+		var resp struct {
+			Jobs []WorkflowJob `json:"jobs"`
+		}
+		if err := g.doREST(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/actions/runs/%d/jobs?filter=latest&per_page=100&page=%d", owner, name, runID, restPageNumber(cursor)), nil, &resp); err != nil {
+			return Page[WorkflowJob]{}, fmt.Errorf("failed to list workflow jobs: %w", err)
+		}
+		return restPage(resp.Jobs, cursor, 100), nil
+	}).All(ctx)
+}
+
+// GetJobLogs returns the plain text log of a workflow job.  GitHub keeps logs for the retention period of the
+// repository, 90 days by default.
+func (g *GithubGraphqlAPI) GetJobLogs(ctx context.Context, owner string, name string, jobID int64) (_ []byte, err error) {
+	ctx = withOperation(ctx, "GetJobLogs")
+	defer annotateError(&err, OperationError{Operation: "GetJobLogs", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("GetJobLogs", zap.String("owner", owner), zap.String("name", name), zap.Int64("job", jobID))
+	defer g.logger(ctx).Debug("Done GetJobLogs")
+	// GitHub redirects to a short lived download URL, which the HTTP client follows without the Authorization header
+	b, err := g.doRESTRaw(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/actions/jobs/%d/logs", owner, name, jobID), "application/vnd.github.v3+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job logs: %w", err)
+	}
+	return b, nil
+}
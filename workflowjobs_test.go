@@ -0,0 +1,52 @@
+package gogithub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWorkflowJobs(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repos/o/r/actions/runs/77/jobs" {
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		require.Equal(t, "latest", r.URL.Query().Get("filter"))
+		_, _ = w.Write([]byte(`{"total_count":1,"jobs":[{"id":5,"run_id":77,"name":"test","status":"completed","conclusion":"success",
+			"created_at":"2024-05-01T10:00:00Z","started_at":"2024-05-01T10:00:30Z","completed_at":"2024-05-01T10:05:30Z",
+			"labels":["ubuntu-latest"],"runner_name":"GitHub Actions 12",
+			"steps":[{"number":1,"name":"Set up job","status":"completed","conclusion":"success",
+				"started_at":"2024-05-01T10:00:30Z","completed_at":"2024-05-01T10:00:32Z"},
+				{"number":2,"name":"Run tests","status":"in_progress","started_at":"2024-05-01T10:00:32Z","completed_at":null}]}]}`))
+	})
+	jobs, err := g.ListWorkflowJobs(context.Background(), "o", "r", 77)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0]
+	require.Equal(t, []string{"ubuntu-latest"}, job.Labels)
+	require.Equal(t, "GitHub Actions 12", job.RunnerName)
+	require.Equal(t, 30*time.Second, job.QueueDuration())
+	require.Equal(t, 5*time.Minute, job.Duration())
+	require.Len(t, job.Steps, 2)
+	require.Equal(t, 2*time.Second, job.Steps[0].Duration())
+	require.Equal(t, time.Duration(0), job.Steps[1].Duration())
+}
+
+func TestGetJobLogs(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/actions/jobs/5/logs":
+			http.Redirect(w, r, "/download/5.txt", http.StatusFound)
+		case "/download/5.txt":
+			_, _ = w.Write([]byte("2024-05-01T10:00:30Z Run tests\nok\n"))
+		default:
+			t.Fatalf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	logs, err := g.GetJobLogs(context.Background(), "o", "r", 5)
+	require.NoError(t, err)
+	require.Equal(t, "2024-05-01T10:00:30Z Run tests\nok\n", string(logs))
+}
@@ -0,0 +1,388 @@
+package gogithub
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowProblem is one mistake found in a workflow file.  Line and Column are 1 based, and 0 when unknown.
+type WorkflowProblem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p WorkflowProblem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+var (
+	workflowTopLevelKeys = map[string]bool{
+		"name": true, "run-name": true, "on": true, "permissions": true, "env": true, "defaults": true,
+		"concurrency": true, "jobs": true,
+	}
+	workflowEvents = map[string]bool{
+		"branch_protection_rule": true, "check_run": true, "check_suite": true, "create": true, "delete": true,
+		"deployment": true, "deployment_status": true, "discussion": true, "discussion_comment": true, "fork": true,
+		"gollum": true, "issue_comment": true, "issues": true, "label": true, "merge_group": true, "milestone": true,
+		"page_build": true, "project": true, "project_card": true, "project_column": true, "public": true,
+		"pull_request": true, "pull_request_review": true, "pull_request_review_comment": true,
+		"pull_request_target": true, "push": true, "registry_package": true, "release": true,
+		"repository_dispatch": true, "schedule": true, "status": true, "watch": true, "workflow_call": true,
+		"workflow_dispatch": true, "workflow_run": true,
+	}
+	workflowDispatchInputTypes = map[string]bool{"string": true, "choice": true, "boolean": true, "number": true, "environment": true}
+	workflowCallInputTypes     = map[string]bool{"string": true, "boolean": true, "number": true}
+	workflowJobID              = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+)
+
+// ValidateWorkflow checks the structure of a GitHub Actions workflow: its triggers, the inputs of workflow_dispatch and
+// workflow_call, and its jobs, steps and needs, including cycles.  It does not evaluate expressions.  An empty
+// result means no problem was found.
+func ValidateWorkflow(data []byte) []WorkflowProblem {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []WorkflowProblem{{Message: fmt.Sprintf("invalid YAML: %s", err)}}
+	}
+	v := &workflowValidator{}
+	if len(doc.Content) == 0 {
+		v.add(&doc, "workflow is empty")
+		return v.problems
+	}
+	v.validate(doc.Content[0])
+	return v.problems
+}
+
+// ValidateWorkflowFile fetches the workflow at path on ref and validates it with ValidateWorkflow
+func (g *GithubGraphqlAPI) ValidateWorkflowFile(ctx context.Context, owner string, name string, path string, ref string) (_ []WorkflowProblem, err error) {
+	ctx = withOperation(ctx, "ValidateWorkflowFile")
+	defer annotateError(&err, OperationError{Operation: "ValidateWorkflowFile", Owner: owner, Repo: name})
+	g.logger(ctx).Debug("ValidateWorkflowFile", zap.String("owner", owner), zap.String("name", name), zap.String("path", path), zap.String("ref", ref))
+	defer g.logger(ctx).Debug("Done ValidateWorkflowFile")
+	data, err := g.GetFileContents(ctx, owner, name, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateWorkflow(data), nil
+}
+
+type workflowValidator struct {
+	problems []WorkflowProblem
+}
+
+func (v *workflowValidator) add(n *yaml.Node, format string, args ...interface{}) {
+	v.problems = append(v.problems, WorkflowProblem{Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+}
+
+// yamlPairs calls fn with every key and value of a mapping node
+func yamlPairs(n *yaml.Node, fn func(k *yaml.Node, val *yaml.Node)) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i], n.Content[i+1])
+	}
+}
+
+// yamlGet returns the key and value nodes of key in a mapping node, or nils
+func yamlGet(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func isYAMLNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+func (v *workflowValidator) validate(root *yaml.Node) {
+	if root.Kind != yaml.MappingNode {
+		v.add(root, "workflow must be a mapping")
+		return
+	}
+	yamlPairs(root, func(k *yaml.Node, _ *yaml.Node) {
+		if !workflowTopLevelKeys[k.Value] {
+			v.add(k, "unknown top level key %q", k.Value)
+		}
+	})
+	if _, on := yamlGet(root, "on"); on == nil {
+		v.add(root, "missing \"on\": the workflow has no trigger")
+	} else {
+		v.validateTriggers(on)
+	}
+	if _, jobs := yamlGet(root, "jobs"); jobs == nil {
+		v.add(root, "missing \"jobs\"")
+	} else {
+		v.validateJobs(jobs)
+	}
+}
+
+func (v *workflowValidator) validateEventName(n *yaml.Node) bool {
+	if !workflowEvents[n.Value] {
+		v.add(n, "unknown event %q", n.Value)
+		return false
+	}
+	return true
+}
+
+func (v *workflowValidator) validateTriggers(on *yaml.Node) {
+	switch on.Kind {
+	case yaml.ScalarNode:
+		v.validateEventName(on)
+	case yaml.SequenceNode:
+		if len(on.Content) == 0 {
+			v.add(on, "\"on\" lists no event")
+		}
+		for _, e := range on.Content {
+			v.validateEventName(e)
+		}
+	case yaml.MappingNode:
+		if len(on.Content) == 0 {
+			v.add(on, "\"on\" lists no event")
+		}
+		yamlPairs(on, func(k *yaml.Node, val *yaml.Node) {
+			if !v.validateEventName(k) {
+				return
+			}
+			if isYAMLNull(val) {
+				return
+			}
+			switch k.Value {
+			case "schedule":
+				v.validateSchedule(val)
+				return
+			}
+			if val.Kind != yaml.MappingNode {
+				v.add(val, "%s configuration must be a mapping", k.Value)
+				return
+			}
+			for _, filter := range []string{"branches", "tags", "paths"} {
+				if fk, _ := yamlGet(val, filter); fk != nil {
+					if ik, _ := yamlGet(val, filter+"-ignore"); ik != nil {
+						v.add(ik, "%s cannot use both %s and %s-ignore", k.Value, filter, filter)
+					}
+				}
+			}
+			switch k.Value {
+			case "workflow_dispatch":
+				v.validateInputs(val, workflowDispatchInputTypes, false)
+			case "workflow_call":
+				v.validateInputs(val, workflowCallInputTypes, true)
+			}
+		})
+	default:
+		v.add(on, "\"on\" must be an event, a list of events or a mapping")
+	}
+}
+
+func (v *workflowValidator) validateSchedule(n *yaml.Node) {
+	if n.Kind != yaml.SequenceNode {
+		v.add(n, "schedule must be a list of cron entries")
+		return
+	}
+	for _, entry := range n.Content {
+		_, cron := yamlGet(entry, "cron")
+		if cron == nil {
+			v.add(entry, "schedule entry is missing cron")
+			continue
+		}
+		if len(strings.Fields(cron.Value)) != 5 {
+			v.add(cron, "cron %q must have 5 fields", cron.Value)
+		}
+	}
+}
+
+func (v *workflowValidator) validateInputs(event *yaml.Node, types map[string]bool, typeRequired bool) {
+	_, inputs := yamlGet(event, "inputs")
+	if inputs == nil || isYAMLNull(inputs) {
+		return
+	}
+	if inputs.Kind != yaml.MappingNode {
+		v.add(inputs, "inputs must be a mapping")
+		return
+	}
+	yamlPairs(inputs, func(k *yaml.Node, input *yaml.Node) {
+		if input.Kind != yaml.MappingNode {
+			v.add(input, "input %s must be a mapping", k.Value)
+			return
+		}
+		inputType := "string"
+		if _, t := yamlGet(input, "type"); t != nil {
+			inputType = t.Value
+			if !types[inputType] {
+				allowed := make([]string, 0, len(types))
+				for t := range types {
+					allowed = append(allowed, t)
+				}
+				sort.Strings(allowed)
+				v.add(t, "input %s has invalid type %q, want one of %s", k.Value, inputType, strings.Join(allowed, ", "))
+				return
+			}
+		} else if typeRequired {
+			v.add(k, "input %s is missing its type", k.Value)
+			return
+		}
+		_, def := yamlGet(input, "default")
+		switch inputType {
+		case "choice":
+			_, options := yamlGet(input, "options")
+			if options == nil || options.Kind != yaml.SequenceNode || len(options.Content) == 0 {
+				v.add(k, "choice input %s needs a non empty options list", k.Value)
+				return
+			}
+			if def != nil && !isYAMLNull(def) {
+				found := false
+				for _, o := range options.Content {
+					found = found || o.Value == def.Value
+				}
+				if !found {
+					v.add(def, "default %q of input %s is not one of its options", def.Value, k.Value)
+				}
+			}
+		case "boolean":
+			if def != nil && def.Kind == yaml.ScalarNode && !strings.Contains(def.Value, "${{") {
+				if _, err := strconv.ParseBool(def.Value); err != nil {
+					v.add(def, "default of boolean input %s must be true or false", k.Value)
+				}
+			}
+		case "number":
+			if def != nil && def.Kind == yaml.ScalarNode && !strings.Contains(def.Value, "${{") {
+				if _, err := strconv.ParseFloat(def.Value, 64); err != nil {
+					v.add(def, "default of number input %s must be a number", k.Value)
+				}
+			}
+		}
+	})
+}
+
+func (v *workflowValidator) validateJobs(jobs *yaml.Node) {
+	if jobs.Kind != yaml.MappingNode || len(jobs.Content) == 0 {
+		v.add(jobs, "jobs must be a non empty mapping")
+		return
+	}
+	keys := map[string]*yaml.Node{}
+	yamlPairs(jobs, func(k *yaml.Node, _ *yaml.Node) {
+		keys[k.Value] = k
+	})
+	needs := map[string][]string{}
+	var order []string
+	yamlPairs(jobs, func(k *yaml.Node, job *yaml.Node) {
+		order = append(order, k.Value)
+		if !workflowJobID.MatchString(k.Value) {
+			v.add(k, "job id %q must start with a letter or _ and contain only alphanumerics, - or _", k.Value)
+		}
+		if job.Kind != yaml.MappingNode {
+			v.add(job, "job %s must be a mapping", k.Value)
+			return
+		}
+		_, uses := yamlGet(job, "uses")
+		_, runsOn := yamlGet(job, "runs-on")
+		stepsKey, steps := yamlGet(job, "steps")
+		switch {
+		case uses != nil && steps != nil:
+			v.add(stepsKey, "job %s calls a reusable workflow and cannot have steps", k.Value)
+		case uses == nil && runsOn == nil:
+			v.add(k, "job %s is missing runs-on", k.Value)
+		case uses == nil && steps == nil:
+			v.add(k, "job %s has no steps", k.Value)
+		}
+		if steps != nil {
+			v.validateSteps(k.Value, steps)
+		}
+		_, need := yamlGet(job, "needs")
+		if need == nil {
+			return
+		}
+		var names []*yaml.Node
+		switch need.Kind {
+		case yaml.ScalarNode:
+			names = []*yaml.Node{need}
+		case yaml.SequenceNode:
+			names = need.Content
+		default:
+			v.add(need, "needs of job %s must be a job id or a list of job ids", k.Value)
+		}
+		for _, n := range names {
+			if _, ok := keys[n.Value]; !ok {
+				v.add(n, "job %s needs unknown job %q", k.Value, n.Value)
+				continue
+			}
+			needs[k.Value] = append(needs[k.Value], n.Value)
+		}
+	})
+	if cycle := findNeedsCycle(order, needs); cycle != nil {
+		v.add(keys[cycle[0]], "jobs needs cycle: %s", strings.Join(cycle, " -> "))
+	}
+}
+
+func (v *workflowValidator) validateSteps(job string, steps *yaml.Node) {
+	if steps.Kind != yaml.SequenceNode || len(steps.Content) == 0 {
+		v.add(steps, "steps of job %s must be a non empty list", job)
+		return
+	}
+	for i, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			v.add(step, "step %d of job %s must be a mapping", i+1, job)
+			continue
+		}
+		_, run := yamlGet(step, "run")
+		_, uses := yamlGet(step, "uses")
+		if (run == nil) == (uses == nil) {
+			v.add(step, "step %d of job %s must have exactly one of run or uses", i+1, job)
+		}
+	}
+}
+
+// findNeedsCycle returns the first dependency cycle among jobs, starting and ending with the same job, or nil
+func findNeedsCycle(order []string, needs map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(job string) []string
+	visit = func(job string) []string {
+		state[job] = visiting
+		stack = append(stack, job)
+		for _, dep := range needs[job] {
+			switch state[dep] {
+			case visiting:
+				for i, j := range stack {
+					if j == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[job] = done
+		return nil
+	}
+	for _, job := range order {
+		if state[job] == unvisited {
+			if cycle := visit(job); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package gogithub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func problemMessages(problems []WorkflowProblem) []string {
+	ret := make([]string, 0, len(problems))
+	for _, p := range problems {
+		ret = append(ret, p.String())
+	}
+	return ret
+}
+
+func TestValidateWorkflow_Valid(t *testing.T) {
+	problems := ValidateWorkflow([]byte(`
+name: ci
+on:
+  push:
+    branches: [main]
+  workflow_dispatch:
+    inputs:
+      env:
+        type: choice
+        options: [staging, production]
+        default: staging
+      dry-run:
+        type: boolean
+        default: true
+  schedule:
+    - cron: "0 3 * * 1"
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - run: make test
+  deploy:
+    needs: build
+    uses: ./.github/workflows/deploy.yaml
+`))
+	require.Empty(t, problemMessages(problems))
+}
+
+func TestValidateWorkflow_Problems(t *testing.T) {
+	problems := ValidateWorkflow([]byte(`on:
+  pushh:
+  pull_request:
+    branches: [main]
+    branches-ignore: [dev]
+  workflow_dispatch:
+    inputs:
+      env:
+        type: choice
+        options: [staging]
+        default: prod
+  workflow_call:
+    inputs:
+      version:
+        required: true
+jobs:
+  a:
+    runs-on: ubuntu-latest
+    needs: [c, missing]
+    steps:
+      - run: echo
+        uses: actions/checkout@v4
+  b:
+    needs: a
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+  c:
+    needs: b
+    uses: ./x.yaml
+    steps:
+      - run: echo
+`))
+	require.Equal(t, []string{
+		`2:3: unknown event "pushh"`,
+		`5:5: pull_request cannot use both branches and branches-ignore`,
+		`11:18: default "prod" of input env is not one of its options`,
+		`14:7: input version is missing its type`,
+		`21:9: step 1 of job a must have exactly one of run or uses`,
+		`19:16: job a needs unknown job "missing"`,
+		`31:5: job c calls a reusable workflow and cannot have steps`,
+		`17:3: jobs needs cycle: a -> c -> b -> a`,
+	}, problemMessages(problems))
+}
+
+func TestValidateWorkflow_Invalid(t *testing.T) {
+	require.Len(t, ValidateWorkflow([]byte("on: [push\n")), 1)
+	require.Equal(t, []string{"1:1: missing \"jobs\""}, problemMessages(ValidateWorkflow([]byte("on: push\n"))))
+}
+
+// fileContentsResponse is a contents API response for a file holding content, base64 encoded in lines the way GitHub
+// sends it
+func fileContentsResponse(content string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	var lines []string
+	for len(encoded) > 60 {
+		lines = append(lines, encoded[:60])
+		encoded = encoded[60:]
+	}
+	lines = append(lines, encoded)
+	b, _ := json.Marshal(map[string]string{"type": "file", "encoding": "base64", "content": strings.Join(lines, "\n") + "\n"})
+	return string(b)
+}
+
+func TestValidateWorkflowFile(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/contents/.github/workflows/ci.yaml", r.URL.Path)
+		require.Equal(t, "feature", r.URL.Query().Get("ref"))
+		_, _ = w.Write([]byte(fileContentsResponse("on: push\njobs:\n  a:\n    runs-on: ubuntu-latest\n")))
+	})
+	problems, err := g.ValidateWorkflowFile(context.Background(), "o", "r", ".github/workflows/ci.yaml", "feature")
+	require.NoError(t, err)
+	require.Equal(t, []string{"3:3: job a has no steps"}, problemMessages(problems))
+}
+
+func TestGetFileContents(t *testing.T) {
+	content := strings.Repeat("name: ci\non: push\n", 10)
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/repos/o/r/contents/dir/a file.txt", r.URL.Path)
+		require.Equal(t, "", r.URL.Query().Get("ref"))
+		_, _ = w.Write([]byte(fileContentsResponse(content)))
+	})
+	b, err := g.GetFileContents(context.Background(), "o", "r", "dir/a file.txt", "")
+	require.NoError(t, err)
+	require.Equal(t, content, string(b))
+}
+
+func TestGetFileContents_LargeFile(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "application/vnd.github.raw" {
+			_, _ = w.Write([]byte("big"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"type":"file","encoding":"none","content":""}`))
+	})
+	b, err := g.GetFileContents(context.Background(), "o", "r", "big.bin", "")
+	require.NoError(t, err)
+	require.Equal(t, "big", string(b))
+}
+
+func TestGetFileContents_NotAFile(t *testing.T) {
+	for name, resp := range map[string]string{
+		"directory": `[{"type":"file","name":"a.yaml"},{"type":"dir","name":"sub"}]`,
+		"symlink":   `{"type":"symlink","target":"../a.yaml"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(resp))
+			})
+			_, err := g.GetFileContents(context.Background(), "o", "r", ".github/workflows", "main")
+			require.ErrorIs(t, err, ErrNotAFile)
+		})
+	}
+}
+
+func TestGetFileContents_NotFound(t *testing.T) {
+	g := newTestRESTClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+	_, err := g.GetFileContents(context.Background(), "o", "r", "missing.yaml", "main")
+	var restErr *RESTError
+	require.ErrorAs(t, err, &restErr)
+	require.Equal(t, http.StatusNotFound, restErr.StatusCode)
+	require.NotErrorIs(t, err, ErrNotAFile)
+	require.ErrorContains(t, err, "failed to get contents of missing.yaml")
+}